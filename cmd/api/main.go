@@ -56,7 +56,11 @@ func main() {
 		log.Printf("http shutdown error: %v", err)
 	}
 
-	application.Close()
+	// Jobs and consumers finish the work in hand before the databases are closed
+	closeCtx, cancelClose := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelClose()
+
+	application.Close(closeCtx)
 
 	log.Println("server gracefully stopped")
 }
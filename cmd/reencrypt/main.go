@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/joho/godotenv"
+	"github.com/zuyatna/shop-retail-employee-service/internal/app"
+	"github.com/zuyatna/shop-retail-employee-service/internal/config"
+)
+
+// reencrypt rewrites employee PII with the current key-encryption key (PII_KEY_ID).
+// Keep the previous key in PII_KEYS until this command has finished.
+func main() {
+	batchSize := flag.Int("batch", 100, "number of employees re-encrypted per query")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		if err := godotenv.Load("../../.env"); err != nil {
+			log.Println("No .env file found, fallback to system env")
+		}
+	}
+
+	cfg := config.Load()
+
+	count, err := app.ReencryptPII(context.Background(), cfg, *batchSize)
+	if err != nil {
+		log.Fatalf("re-encryption failed after %d employees: %v", count, err)
+	}
+
+	log.Printf("re-encrypted %d employees with key %s", count, cfg.PIIKeyID)
+}
@@ -2,7 +2,11 @@ package adapterhttp
 
 import (
 	"encoding/json"
+	"errors"
+	"mime/multipart"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/zuyatna/shop-retail-employee-service/internal/dto/attendance"
 	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
@@ -19,6 +23,8 @@ func NewAttendanceHandler(uc *usecase.AttendanceUsecase) *AttendanceHandler {
 	}
 }
 
+// CheckIn accepts a JSON body, or a multipart form with the location and an optional
+// "photo" file taken at check-in. Either may carry the "qr" code scanned at a store kiosk.
 func (h *AttendanceHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
 	claims, ok := r.Context().Value(UserClaimsKey).(*jwtutil.Claims)
 	if !ok || claims == nil {
@@ -27,20 +33,45 @@ func (h *AttendanceHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req attendance.CheckInRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	var photo *usecase.PhotoUpload
+	if isMultipart(r) {
+		var err error
+		photo, err = attendancePhoto(r)
+		if err != nil {
+			WriteErrorJSON(w, http.StatusBadRequest, err, "failed to parse multipart form")
+			return
+		}
+		if photo != nil {
+			defer closePhoto(photo)
+		}
+		req.Location = r.FormValue("location")
+		req.QR = r.FormValue("qr")
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		WriteErrorJSON(w, http.StatusBadRequest, err, "invalid request payload")
 		return
 	}
 
-	id, err := h.attendanceUsecase.CheckIn(r.Context(), claims.UserID, req)
+	id, err := h.attendanceUsecase.CheckIn(r.Context(), claims.UserID, req, photo)
 	if err != nil {
-		WriteErrorJSON(w, http.StatusInternalServerError, err, err.Error())
+		switch {
+		case errors.Is(err, usecase.InvalidPhotoError),
+			errors.Is(err, usecase.InvalidKioskQRError):
+			WriteErrorJSON(w, http.StatusBadRequest, err, err.Error())
+		case errors.Is(err, usecase.KioskStoreMismatchError):
+			WriteErrorJSON(w, http.StatusForbidden, err, err.Error())
+		case isPunchConflict(err):
+			WriteErrorJSON(w, http.StatusConflict, err, err.Error())
+		default:
+			WriteErrorJSON(w, http.StatusInternalServerError, err, err.Error())
+		}
 		return
 	}
 
 	WriteJSON(w, http.StatusCreated, map[string]string{"mongo id": id}, "check-in successful")
 }
 
+// CheckOut takes no body, or a multipart form with an optional "photo" file taken at
+// check-out.
 func (h *AttendanceHandler) CheckOut(w http.ResponseWriter, r *http.Request) {
 	claims, ok := r.Context().Value(UserClaimsKey).(*jwtutil.Claims)
 	if !ok || claims == nil {
@@ -48,11 +79,197 @@ func (h *AttendanceHandler) CheckOut(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.attendanceUsecase.CheckOut(r.Context(), claims.UserID)
+	var photo *usecase.PhotoUpload
+	if isMultipart(r) {
+		var err error
+		photo, err = attendancePhoto(r)
+		if err != nil {
+			WriteErrorJSON(w, http.StatusBadRequest, err, "failed to parse multipart form")
+			return
+		}
+		if photo != nil {
+			defer closePhoto(photo)
+		}
+	}
+
+	err := h.attendanceUsecase.CheckOut(r.Context(), claims.UserID, photo)
 	if err != nil {
-		WriteErrorJSON(w, http.StatusInternalServerError, err, err.Error())
+		switch {
+		case errors.Is(err, usecase.InvalidPhotoError):
+			WriteErrorJSON(w, http.StatusBadRequest, err, err.Error())
+		case isPunchConflict(err):
+			WriteErrorJSON(w, http.StatusConflict, err, err.Error())
+		default:
+			WriteErrorJSON(w, http.StatusInternalServerError, err, err.Error())
+		}
 		return
 	}
 
 	WriteJSON(w, http.StatusOK, map[string]string{"message": "check-out successful"}, "check-out successful")
 }
+
+// StartBreak starts a break in the caller's open work session.
+func (h *AttendanceHandler) StartBreak(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(UserClaimsKey).(*jwtutil.Claims)
+	if !ok || claims == nil {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	if err := h.attendanceUsecase.StartBreak(r.Context(), claims.UserID); err != nil {
+		writeBreakError(w, err, "failed to start break")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, nil, "break started")
+}
+
+// EndBreak ends the caller's break in progress.
+func (h *AttendanceHandler) EndBreak(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(UserClaimsKey).(*jwtutil.Claims)
+	if !ok || claims == nil {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	if err := h.attendanceUsecase.EndBreak(r.Context(), claims.UserID); err != nil {
+		writeBreakError(w, err, "failed to end break")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, nil, "break ended")
+}
+
+func writeBreakError(w http.ResponseWriter, err error, fallback string) {
+	if isPunchConflict(err) {
+		WriteErrorJSON(w, http.StatusConflict, err, err.Error())
+		return
+	}
+	WriteErrorJSON(w, http.StatusInternalServerError, err, fallback)
+}
+
+// isPunchConflict tells errors of punches that do not fit the employee's day so far,
+// e.g. a check-in while checked in or a break outside a work session, or that kept
+// racing with other punches of the day.
+func isPunchConflict(err error) bool {
+	return errors.Is(err, usecase.AttendanceConflictError) ||
+		errors.Is(err, usecase.AlreadyCheckedInError) ||
+		errors.Is(err, usecase.NotCheckedInError) ||
+		errors.Is(err, usecase.AlreadyOnBreakError) ||
+		errors.Is(err, usecase.NotOnBreakError) ||
+		errors.Is(err, usecase.PunchOutOfOrderError)
+}
+
+// Review returns an employee's attendance between ?from= and ?to= (YYYY-MM-DD) with the
+// check-in and check-out photos next to the profile photo.
+func (h *AttendanceHandler) Review(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	review, err := h.attendanceUsecase.Review(r.Context(), actor, r.PathValue("id"), r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ForbiddenError):
+			WriteErrorJSON(w, http.StatusForbidden, err, "you cannot review the attendance of this employee")
+		case errors.Is(err, usecase.EmployeeNotFoundError):
+			WriteErrorJSON(w, http.StatusNotFound, err, "employee not found")
+		case errors.Is(err, usecase.InvalidDateRangeError):
+			WriteErrorJSON(w, http.StatusBadRequest, err, err.Error())
+		default:
+			WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to retrieve attendance")
+		}
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, toAttendanceReviewResponse(review), "attendance retrieved successfully")
+}
+
+func isMultipart(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data")
+}
+
+// attendancePhoto parses a multipart check-in or check-out and returns its photo, or
+// nil when none was attached.
+func attendancePhoto(r *http.Request) (*usecase.PhotoUpload, error) {
+	// Parse multipart form with a max memory of 5MB
+	if err := r.ParseMultipartForm(5 << 20); err != nil {
+		return nil, err
+	}
+
+	file, header, err := r.FormFile("photo")
+	if errors.Is(err, http.ErrMissingFile) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &usecase.PhotoUpload{
+		Content:     file,
+		Size:        header.Size,
+		ContentType: header.Header.Get("Content-Type"),
+		FileName:    header.Filename,
+	}, nil
+}
+
+func closePhoto(photo *usecase.PhotoUpload) {
+	if file, ok := photo.Content.(multipart.File); ok {
+		_ = file.Close()
+	}
+}
+
+func toAttendanceReviewResponse(review *usecase.AttendanceReview) attendance.AttendanceReviewResponse {
+	records := make([]attendance.AttendanceRecordResponse, 0, len(review.Records))
+	for _, record := range review.Records {
+		sessions := make([]attendance.SessionResponse, 0, len(record.Sessions))
+		for i, s := range record.Sessions {
+			session := attendance.SessionResponse{
+				CheckIn:  s.CheckIn.Format(time.DateTime),
+				CheckOut: formatDateTime(s.CheckOut),
+				Location: s.Location,
+			}
+			if i < len(record.PhotoURLs) {
+				session.CheckInPhotoURL = record.PhotoURLs[i].CheckIn
+				session.CheckOutPhotoURL = record.PhotoURLs[i].CheckOut
+			}
+			for _, b := range s.Breaks {
+				session.Breaks = append(session.Breaks, attendance.BreakResponse{
+					Start: b.Start.Format(time.DateTime),
+					End:   formatDateTime(b.End),
+				})
+			}
+			sessions = append(sessions, session)
+		}
+
+		records = append(records, attendance.AttendanceRecordResponse{
+			ID:            record.ID,
+			Date:          record.Date.Format(time.DateOnly),
+			Location:      record.Location,
+			CheckIn:       record.CheckIn,
+			CheckOut:      record.CheckOut,
+			IsLate:        record.IsLate,
+			WorkedMinutes: int(record.Worked.Minutes()),
+			BreakMinutes:  int(record.BreakTime.Minutes()),
+			Sessions:      sessions,
+		})
+	}
+
+	return attendance.AttendanceReviewResponse{
+		EmployeeID:   string(review.Employee.ID()),
+		EmployeeName: review.Employee.Name(),
+		StoreID:      review.Employee.StoreID(),
+		PhotoURL:     review.PhotoURL,
+		Records:      records,
+	}
+}
+
+func formatDateTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format(time.DateTime)
+	return &formatted
+}
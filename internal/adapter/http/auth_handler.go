@@ -2,8 +2,10 @@ package adapterhttp
 
 import (
 	"encoding/json"
-	"log"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/zuyatna/shop-retail-employee-service/internal/dto/auth"
 	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
@@ -26,12 +28,83 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.usecase.Login(r.Context(), req.Email, req.Password)
+	result, err := h.usecase.Login(r.Context(), req.Email, req.Password, ClientIP(r))
 	if err != nil {
-		log.Printf("login failed for email=%s: %v", req.Email, err)
+		if writeLoginLocked(w, err) {
+			return
+		}
 		WriteErrorJSON(w, http.StatusUnauthorized, err, "invalid email or password")
 		return
 	}
 
+	resp := auth.LoginResponse{
+		Token:              result.Token,
+		TwoFactorRequired:  result.TwoFactorRequired,
+		EnrollmentRequired: result.EnrollmentRequired,
+	}
+
+	switch {
+	case result.TwoFactorRequired:
+		WriteJSON(w, http.StatusOK, resp, "two factor verification required")
+	case result.EnrollmentRequired:
+		WriteJSON(w, http.StatusOK, resp, "two factor enrollment required")
+	default:
+		WriteJSON(w, http.StatusOK, resp, "login successful")
+	}
+}
+
+func (h *AuthHandler) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req auth.VerifyTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "invalid request payload")
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "validation error")
+		return
+	}
+
+	token, err := h.usecase.VerifyTwoFactor(r.Context(), req.ChallengeToken, req.Code, req.RecoveryCode, ClientIP(r))
+	if err != nil {
+		if writeLoginLocked(w, err) {
+			return
+		}
+		WriteErrorJSON(w, http.StatusUnauthorized, err, "invalid two factor code")
+		return
+	}
+
 	WriteJSON(w, http.StatusOK, auth.LoginResponse{Token: token}, "login successful")
 }
+
+func (h *AuthHandler) Unlock(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		WriteErrorJSON(w, http.StatusBadRequest, nil, "employee ID is required")
+		return
+	}
+
+	err := h.usecase.Unlock(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, usecase.EmployeeNotFoundError) {
+			WriteErrorJSON(w, http.StatusNotFound, err, "employee not found")
+			return
+		}
+		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to unlock employee account")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, nil, "employee account unlocked successfully")
+}
+
+// writeLoginLocked answers 429 with a Retry-After header when err is a throttling error.
+func writeLoginLocked(w http.ResponseWriter, err error) bool {
+	var lockedErr *usecase.LoginLockedError
+	if !errors.As(err, &lockedErr) {
+		return false
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+	WriteErrorJSON(w, http.StatusTooManyRequests, err, "too many failed login attempts, try again later")
+	return true
+}
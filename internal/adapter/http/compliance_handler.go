@@ -0,0 +1,76 @@
+package adapterhttp
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/dto/compliance"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+// maxExpiringReportDays bounds how far ahead the expiring document report looks.
+const maxExpiringReportDays = 366
+
+type ComplianceHandler struct {
+	usecase *usecase.ComplianceUsecase
+}
+
+func NewComplianceHandler(uc *usecase.ComplianceUsecase) *ComplianceHandler {
+	return &ComplianceHandler{
+		usecase: uc,
+	}
+}
+
+// Expiring lists, per store, the documents that expired or expire within ?days= days,
+// the widest reminder window by default. ?store_id= limits the report to one store.
+func (h *ComplianceHandler) Expiring(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	days := h.usecase.MaxWindow()
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 || parsed > maxExpiringReportDays {
+			WriteErrorJSON(w, http.StatusBadRequest, err, "days must be a number between 0 and 366")
+			return
+		}
+		days = parsed
+	}
+
+	stores, err := h.usecase.ExpiringReport(r.Context(), actor, days, r.URL.Query().Get("store_id"))
+	if err != nil {
+		if errors.Is(err, usecase.ForbiddenError) {
+			WriteErrorJSON(w, http.StatusForbidden, err, "you can only see the report of your own store")
+			return
+		}
+		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to build expiring document report")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, toStoreExpirationsResponses(stores), "expiring documents retrieved successfully")
+}
+
+func toStoreExpirationsResponses(stores []usecase.StoreExpirations) []compliance.StoreExpirationsResponse {
+	resp := make([]compliance.StoreExpirationsResponse, 0, len(stores))
+	for _, store := range stores {
+		documents := make([]compliance.ExpiringDocumentResponse, 0, len(store.Documents))
+		for _, item := range store.Documents {
+			documents = append(documents, compliance.ExpiringDocumentResponse{
+				DocumentID:   item.Document.ID,
+				EmployeeID:   item.Document.EmployeeID,
+				EmployeeName: item.Employee.Name(),
+				Type:         string(item.Document.Type),
+				Version:      item.Document.Version,
+				ExpiresAt:    *item.Document.ExpiresAt,
+				DaysLeft:     item.DaysLeft,
+				Expired:      item.DaysLeft <= 0,
+			})
+		}
+		resp = append(resp, compliance.StoreExpirationsResponse{StoreID: store.StoreID, Documents: documents})
+	}
+	return resp
+}
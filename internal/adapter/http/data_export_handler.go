@@ -0,0 +1,116 @@
+package adapterhttp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/dto/dataexport"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+type DataExportHandler struct {
+	usecase *usecase.DataExportUsecase
+}
+
+func NewDataExportHandler(uc *usecase.DataExportUsecase) *DataExportHandler {
+	return &DataExportHandler{
+		usecase: uc,
+	}
+}
+
+// Get returns the current data export of the employee, queueing a new one when needed.
+// It answers 202 while the archive is generated and 200 with a download URL once ready.
+func (h *DataExportHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	export, err := h.usecase.Request(r.Context(), actor, id)
+	if err != nil {
+		writeDataExportError(w, err)
+		return
+	}
+
+	resp := toDataExportResponse(export)
+	switch export.Status {
+	case domain.DataExportReady:
+		WriteJSON(w, http.StatusOK, resp, "data export is ready for download")
+	case domain.DataExportFailed:
+		WriteJSON(w, http.StatusOK, resp, "data export failed, request it again to retry")
+	default:
+		WriteJSON(w, http.StatusAccepted, resp, "data export is being generated")
+	}
+}
+
+func (h *DataExportHandler) Download(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	exportID := r.PathValue("exportID")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	file, export, err := h.usecase.Download(r.Context(), actor, id, exportID)
+	if err != nil {
+		writeDataExportError(w, err)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="data-export-%s.zip"`, export.ID))
+	w.Header().Set("Content-Length", strconv.FormatInt(export.Size, 10))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, file); err != nil {
+		slog.Log(r.Context(), slog.LevelError, "Failed to stream data export", "ID", export.ID, "error", err)
+	}
+}
+
+func writeDataExportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ForbiddenError):
+		WriteErrorJSON(w, http.StatusForbidden, err, "you are not allowed to export this employee's data")
+	case errors.Is(err, usecase.EmployeeNotFoundError):
+		WriteErrorJSON(w, http.StatusNotFound, err, "employee not found")
+	case errors.Is(err, usecase.DataExportNotFoundError):
+		WriteErrorJSON(w, http.StatusNotFound, err, "data export not found")
+	case errors.Is(err, usecase.EmployeeAlreadyErasedError):
+		WriteErrorJSON(w, http.StatusGone, err, err.Error())
+	case errors.Is(err, usecase.DataExportExpiredError):
+		WriteErrorJSON(w, http.StatusGone, err, err.Error())
+	case errors.Is(err, usecase.DataExportNotReadyError):
+		WriteErrorJSON(w, http.StatusConflict, err, err.Error())
+	default:
+		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to process data export")
+	}
+}
+
+func toDataExportResponse(export *domain.DataExport) dataexport.DataExportResponse {
+	resp := dataexport.DataExportResponse{
+		ID:          export.ID,
+		EmployeeID:  export.EmployeeID,
+		Status:      string(export.Status),
+		Size:        export.Size,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+		Error:       export.Error,
+	}
+	if export.Status == domain.DataExportReady {
+		resp.DownloadURL = fmt.Sprintf("/employees/%s/data-export/%s/download", export.EmployeeID, export.ID)
+	}
+	return resp
+}
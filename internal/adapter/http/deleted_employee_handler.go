@@ -0,0 +1,143 @@
+package adapterhttp
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/dto/employee"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+type DeletedEmployeeHandler struct {
+	usecase *usecase.DeletedEmployeeUsecase
+}
+
+func NewDeletedEmployeeHandler(uc *usecase.DeletedEmployeeUsecase) *DeletedEmployeeHandler {
+	return &DeletedEmployeeHandler{
+		usecase: uc,
+	}
+}
+
+func (h *DeletedEmployeeHandler) List(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	employees, err := h.usecase.List(r.Context(), actor)
+	if err != nil {
+		if errors.Is(err, usecase.ForbiddenError) {
+			WriteErrorJSON(w, http.StatusForbidden, err, err.Error())
+			return
+		}
+		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to retrieve deleted employees")
+		return
+	}
+
+	resp := make([]employee.DeletedEmployeeResponse, 0, len(employees))
+	for _, emp := range employees {
+		resp = append(resp, toDeletedEmployeeResponse(emp))
+	}
+
+	WriteJSON(w, http.StatusOK, resp, "deleted employees retrieved successfully")
+}
+
+func (h *DeletedEmployeeHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	restored, err := h.usecase.Restore(r.Context(), actor, id)
+	if err != nil {
+		writeDeletedEmployeeError(w, err, "failed to restore employee")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"id": string(restored.ID()), "status": string(restored.Status())}, "employee restored successfully")
+}
+
+func (h *DeletedEmployeeHandler) PurgePlan(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	plan, err := h.usecase.PurgePlan(r.Context(), actor, id)
+	if err != nil {
+		writeDeletedEmployeeError(w, err, "failed to build purge plan")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, toPurgePlanResponse(plan), "purge plan retrieved successfully")
+}
+
+func (h *DeletedEmployeeHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	plan, err := h.usecase.Purge(r.Context(), actor, id)
+	if err != nil {
+		writeDeletedEmployeeError(w, err, "failed to purge employee")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, toPurgePlanResponse(plan), "employee purged successfully")
+}
+
+func writeDeletedEmployeeError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, usecase.ForbiddenError):
+		WriteErrorJSON(w, http.StatusForbidden, err, err.Error())
+	case errors.Is(err, usecase.EmployeeNotFoundError):
+		WriteErrorJSON(w, http.StatusNotFound, err, "employee not found")
+	case errors.Is(err, usecase.EmployeeNotDeletedError),
+		errors.Is(err, usecase.EmployeeAlreadyErasedError),
+		errors.Is(err, usecase.EmployeeContactConflictError):
+		WriteErrorJSON(w, http.StatusConflict, err, err.Error())
+	default:
+		WriteErrorJSON(w, http.StatusInternalServerError, err, fallback)
+	}
+}
+
+func toDeletedEmployeeResponse(e *domain.Employee) employee.DeletedEmployeeResponse {
+	resp := employee.DeletedEmployeeResponse{
+		ID:         string(e.ID()),
+		Name:       e.Name(),
+		Email:      string(e.Email()),
+		Role:       string(e.Role()),
+		Status:     string(e.Status()),
+		StoreID:    e.StoreID(),
+		Anonymized: e.IsAnonymized(),
+	}
+	if e.DeletedAt() != nil {
+		resp.DeletedAt = *e.DeletedAt()
+	}
+	return resp
+}
+
+func toPurgePlanResponse(plan *usecase.PurgePlan) employee.PurgePlanResponse {
+	files := plan.Files
+	if files == nil {
+		files = []string{}
+	}
+	return employee.PurgePlanResponse{
+		EmployeeID:        plan.EmployeeID,
+		AttendanceRecords: plan.AttendanceRecords,
+		Files:             files,
+		Cascade:           plan.Cascade,
+	}
+}
@@ -0,0 +1,179 @@
+package adapterhttp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/dto/document"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+)
+
+type DocumentHandler struct {
+	usecase *usecase.DocumentUsecase
+	clock   clock.Clock
+}
+
+func NewDocumentHandler(uc *usecase.DocumentUsecase, clk clock.Clock) *DocumentHandler {
+	return &DocumentHandler{
+		usecase: uc,
+		clock:   clk,
+	}
+}
+
+func (h *DocumentHandler) List(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	documents, err := h.usecase.List(r.Context(), actor, r.PathValue("id"))
+	if err != nil {
+		writeDocumentError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, h.toDocumentResponses(documents), "documents retrieved successfully")
+}
+
+// Upload stores the multipart "file" field as a new version of the document of the given
+// "type". "expires_at" is an optional date in YYYY-MM-DD format.
+func (h *DocumentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	// Parse multipart form with a max memory of 5MB, larger files are buffered on disk
+	if err := r.ParseMultipartForm(5 << 20); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "failed to parse multipart form")
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "file 'file' is required")
+		return
+	}
+	defer file.Close()
+
+	params := usecase.UploadDocumentParams{
+		Type:     domain.DocumentType(r.FormValue("type")),
+		FileName: header.Filename,
+		Content:  file,
+	}
+	if raw := r.FormValue("expires_at"); raw != "" {
+		expiresAt, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			WriteErrorJSON(w, http.StatusBadRequest, err, "expires_at must be a date in YYYY-MM-DD format")
+			return
+		}
+		params.ExpiresAt = &expiresAt
+	}
+
+	created, err := h.usecase.Upload(r.Context(), actor, r.PathValue("id"), params)
+	if err != nil {
+		writeDocumentError(w, err)
+		return
+	}
+
+	resp := h.toDocumentResponse(created)
+	resp.Current = true
+	WriteJSON(w, http.StatusCreated, resp, "document uploaded successfully")
+}
+
+func (h *DocumentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	file, doc, err := h.usecase.Download(r.Context(), actor, r.PathValue("id"), r.PathValue("documentID"))
+	if err != nil {
+		writeDocumentError(w, err)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", doc.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": doc.FileName}))
+	w.Header().Set("Content-Length", strconv.FormatInt(doc.Size, 10))
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, file); err != nil {
+		slog.Log(r.Context(), slog.LevelError, "Failed to stream document", "ID", doc.ID, "error", err)
+	}
+}
+
+func (h *DocumentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	if err := h.usecase.Delete(r.Context(), actor, r.PathValue("id"), r.PathValue("documentID")); err != nil {
+		writeDocumentError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, nil, "document deleted successfully")
+}
+
+func writeDocumentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ForbiddenError):
+		WriteErrorJSON(w, http.StatusForbidden, err, "you are not allowed to access this employee's documents")
+	case errors.Is(err, usecase.EmployeeNotFoundError):
+		WriteErrorJSON(w, http.StatusNotFound, err, "employee not found")
+	case errors.Is(err, usecase.DocumentNotFoundError):
+		WriteErrorJSON(w, http.StatusNotFound, err, "document not found")
+	case errors.Is(err, usecase.InvalidDocumentError):
+		WriteErrorJSON(w, http.StatusBadRequest, err, err.Error())
+	default:
+		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to process document")
+	}
+}
+
+// toDocumentResponses maps documents ordered by type and newest version first, so the
+// first document of each type is its current version.
+func (h *DocumentHandler) toDocumentResponses(documents []*domain.Document) []document.DocumentResponse {
+	resp := make([]document.DocumentResponse, 0, len(documents))
+	seen := make(map[domain.DocumentType]bool)
+	for _, d := range documents {
+		r := h.toDocumentResponse(d)
+		r.Current = !seen[d.Type]
+		seen[d.Type] = true
+		resp = append(resp, r)
+	}
+	return resp
+}
+
+func (h *DocumentHandler) toDocumentResponse(d *domain.Document) document.DocumentResponse {
+	return document.DocumentResponse{
+		ID:          d.ID,
+		EmployeeID:  d.EmployeeID,
+		Type:        string(d.Type),
+		Version:     d.Version,
+		FileName:    d.FileName,
+		ContentType: d.ContentType,
+		Size:        d.Size,
+		ExpiresAt:   d.ExpiresAt,
+		Expired:     d.IsExpired(h.clock.Now()),
+		UploadedBy:  d.UploadedBy,
+		UploadedAt:  d.UploadedAt,
+		DownloadURL: fmt.Sprintf("/employees/%s/documents/%s/download", d.EmployeeID, d.ID),
+	}
+}
@@ -0,0 +1,91 @@
+package adapterhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/dto/employee"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+type EmailChangeHandler struct {
+	usecase *usecase.EmailChangeUsecase
+}
+
+func NewEmailChangeHandler(uc *usecase.EmailChangeUsecase) *EmailChangeHandler {
+	return &EmailChangeHandler{
+		usecase: uc,
+	}
+}
+
+func (h *EmailChangeHandler) Request(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	var req employee.EmailChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "invalid request payload")
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "validation error")
+		return
+	}
+
+	change, err := h.usecase.Request(r.Context(), actor, id, req.Email)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ForbiddenError):
+			WriteErrorJSON(w, http.StatusForbidden, err, "you are not allowed to change this employee's email")
+		case errors.Is(err, usecase.EmployeeNotFoundError):
+			WriteErrorJSON(w, http.StatusNotFound, err, "employee not found")
+		case errors.Is(err, usecase.EmailUnchangedError):
+			WriteErrorJSON(w, http.StatusBadRequest, err, err.Error())
+		case errors.Is(err, usecase.EmployeeContactConflictError):
+			WriteErrorJSON(w, http.StatusConflict, err, "email address is already in use")
+		default:
+			WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to request email change")
+		}
+		return
+	}
+
+	resp := employee.EmailChangeResponse{
+		ID:        change.ID,
+		NewEmail:  string(change.NewEmail),
+		ExpiresAt: change.ExpiresAt,
+	}
+	WriteJSON(w, http.StatusAccepted, resp, "verification link sent to the new email address")
+}
+
+// Confirm is opened from the link in the verification email, the token authenticates it.
+func (h *EmailChangeHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		WriteErrorJSON(w, http.StatusBadRequest, nil, "token is required")
+		return
+	}
+
+	emp, err := h.usecase.Confirm(r.Context(), token)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.InvalidEmailChangeTokenError):
+			WriteErrorJSON(w, http.StatusBadRequest, err, err.Error())
+		case errors.Is(err, usecase.EmployeeNotFoundError):
+			WriteErrorJSON(w, http.StatusNotFound, err, "employee not found")
+		case errors.Is(err, usecase.EmployeeContactConflictError), errors.Is(err, usecase.EmployeeVersionConflictError):
+			WriteErrorJSON(w, http.StatusConflict, err, err.Error())
+		default:
+			WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to confirm email change")
+		}
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"id": string(emp.ID()), "email": string(emp.Email())}, "email address changed successfully")
+}
@@ -8,7 +8,6 @@ import (
 	"strings"
 
 	"github.com/go-playground/validator"
-	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
 	"github.com/zuyatna/shop-retail-employee-service/internal/dto/employee"
 	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
 	"github.com/zuyatna/shop-retail-employee-service/internal/util/jwtutil"
@@ -47,7 +46,8 @@ func (h *EmployeeHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Convert domain entity to response DTO
-	resp := usecase.FromDomain(getByID)
+	resp := h.usecase.Response(ctx, getByID)
+	SetETag(w, getByID.Version())
 	WriteJSON(w, http.StatusOK, resp, "employee retrieved successfully")
 }
 
@@ -63,10 +63,24 @@ func (h *EmployeeHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
 	ctx := r.Context()
 
-	id, err := h.usecase.Register(ctx, req)
+	id, err := h.usecase.Register(ctx, actor, req)
 	if err != nil {
+		if errors.Is(err, usecase.ForbiddenError) {
+			WriteErrorJSON(w, http.StatusForbidden, err, "you can only create employees with a role below your own")
+			return
+		}
+		if errors.Is(err, usecase.InvalidRoleError) {
+			WriteErrorJSON(w, http.StatusBadRequest, err, err.Error())
+			return
+		}
 		WriteErrorJSON(w, http.StatusInternalServerError, err, err.Error())
 		return
 	}
@@ -91,8 +105,14 @@ func (h *EmployeeHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Convert domain entity to response DTO
-	resp := usecase.FromDomain(getByID)
+	viewer, ok := h.viewer(w, r)
+	if !ok {
+		return
+	}
+
+	// Convert domain entity to response DTO, hiding fields the caller may not see
+	resp := viewer.Response(ctx, getByID)
+	SetETag(w, getByID.Version())
 	WriteJSON(w, http.StatusOK, resp, "getByID retrieved successfully")
 }
 
@@ -111,8 +131,14 @@ func (h *EmployeeHandler) GetByEmail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Convert domain entity to response DTO
-	resp := usecase.FromDomain(getByEmail)
+	viewer, ok := h.viewer(w, r)
+	if !ok {
+		return
+	}
+
+	// Convert domain entity to response DTO, hiding fields the caller may not see
+	resp := viewer.Response(ctx, getByEmail)
+	SetETag(w, getByEmail.Version())
 	WriteJSON(w, http.StatusOK, resp, "getByEmail retrieved successfully")
 }
 
@@ -125,16 +151,13 @@ func (h *EmployeeHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Convert domain entities to response DTOs
-	var resp []*usecase.EmployeeResponse
-	for _, emp := range employees {
-		resp = append(resp, usecase.FromDomain(emp))
+	viewer, ok := h.viewer(w, r)
+	if !ok {
+		return
 	}
 
-	// Ensure resp is an empty array if no employees found
-	if resp == nil {
-		resp = []*usecase.EmployeeResponse{}
-	}
+	// Convert domain entities to response DTOs, always an array even if no employees found
+	resp := viewer.Responses(ctx, employees)
 
 	WriteJSON(w, http.StatusOK, resp, "employees retrieved successfully")
 }
@@ -147,12 +170,10 @@ func (h *EmployeeHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	claims, ok := r.Context().Value(UserClaimsKey).(*jwtutil.Claims)
-	if ok && claims.Role == string(domain.RoleStaff) {
-		if claims.UserID != id {
-			WriteErrorJSON(w, http.StatusForbidden, errors.New("forbidden"), "you can only update your own profile")
-			return
-		}
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
 	}
 
 	var req employee.UpdateEmployeeRequest
@@ -161,14 +182,37 @@ func (h *EmployeeHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := validate.Struct(req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "validation error")
+		return
+	}
+
+	expectedVersion, ok := IfMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
 	ctx := r.Context()
 
-	err := h.usecase.UpdateProfile(ctx, id, req)
+	version, err := h.usecase.UpdateProfile(ctx, actor, id, req, expectedVersion)
 	if err != nil {
+		if errors.Is(err, usecase.ForbiddenError) {
+			WriteErrorJSON(w, http.StatusForbidden, err, "you are not allowed to update this employee")
+			return
+		}
+		if errors.Is(err, usecase.EmployeeVersionConflictError) {
+			WriteErrorJSON(w, http.StatusPreconditionFailed, err, "employee has been modified since it was read, fetch it again and retry")
+			return
+		}
+		if errors.Is(err, usecase.InvalidRoleError) {
+			WriteErrorJSON(w, http.StatusBadRequest, err, err.Error())
+			return
+		}
 		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to update employee")
 		return
 	}
 
+	SetETag(w, version)
 	WriteJSON(w, http.StatusOK, nil, "employee updated successfully")
 }
 
@@ -204,16 +248,26 @@ func (h *EmployeeHandler) UploadPhoto(w http.ResponseWriter, r *http.Request) {
 	// parts[3] = "photo"
 	employeeID := parts[2]
 
-	claims, ok := r.Context().Value(UserClaimsKey).(*jwtutil.Claims)
-	if ok && claims.Role == string(domain.RoleStaff) {
-		if claims.UserID != employeeID {
-			WriteErrorJSON(w, http.StatusForbidden, errors.New("forbidden"), "you can only upload your own profile photo")
-			return
-		}
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
 	}
 
-	err = h.usecase.UploadPhoto(r.Context(), employeeID, file, header.Size, header.Header.Get("Content-Type"), header.Filename)
+	err = h.usecase.UploadPhoto(r.Context(), actor, employeeID, file, header.Size, header.Header.Get("Content-Type"), header.Filename)
 	if err != nil {
+		if errors.Is(err, usecase.ForbiddenError) {
+			WriteErrorJSON(w, http.StatusForbidden, err, "you can only upload your own profile photo")
+			return
+		}
+		if errors.Is(err, usecase.InvalidPhotoError) {
+			WriteErrorJSON(w, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		if errors.Is(err, usecase.EmployeeNotFoundError) {
+			WriteErrorJSON(w, http.StatusNotFound, err, "employee not found")
+			return
+		}
 		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to upload photo")
 		return
 	}
@@ -221,6 +275,35 @@ func (h *EmployeeHandler) UploadPhoto(w http.ResponseWriter, r *http.Request) {
 	WriteJSON(w, http.StatusOK, nil, "photo uploaded successfully")
 }
 
+// GetPhoto redirects to a short-lived presigned URL for the employee's photo, or for
+// its thumbnail with ?size=thumbnail.
+func (h *EmployeeHandler) GetPhoto(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	url, err := h.usecase.PhotoURL(r.Context(), actor, r.PathValue("id"), r.URL.Query().Get("size") == "thumbnail")
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ForbiddenError):
+			WriteErrorJSON(w, http.StatusForbidden, err, "you can only view your own profile photo")
+		case errors.Is(err, usecase.EmployeeNotFoundError):
+			WriteErrorJSON(w, http.StatusNotFound, err, "employee not found")
+		case errors.Is(err, usecase.EmployeePhotoNotFoundError):
+			WriteErrorJSON(w, http.StatusNotFound, err, "employee has no photo")
+		default:
+			WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to retrieve photo")
+		}
+		return
+	}
+
+	// The presigned URL expires, so the redirect must not be cached past it
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, url, http.StatusFound)
+}
+
 func (h *EmployeeHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	// Assume we get the ID from the URL path, e.g., /employees/{id}
 	id := r.URL.Path[len("/employees/"):]
@@ -229,10 +312,29 @@ func (h *EmployeeHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	expectedVersion, ok := IfMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
 	ctx := r.Context()
 
-	err := h.usecase.Delete(ctx, id)
+	err := h.usecase.Delete(ctx, actor, id, expectedVersion)
 	if err != nil {
+		if errors.Is(err, usecase.ForbiddenError) {
+			WriteErrorJSON(w, http.StatusForbidden, err, "you are not allowed to delete employees")
+			return
+		}
+		if errors.Is(err, usecase.EmployeeVersionConflictError) {
+			WriteErrorJSON(w, http.StatusPreconditionFailed, err, "employee has been modified since it was read, fetch it again and retry")
+			return
+		}
 		if errors.Is(err, usecase.EmployeeNotFoundError) {
 			WriteErrorJSON(w, http.StatusNotFound, err, "employee not found")
 			return
@@ -243,3 +345,20 @@ func (h *EmployeeHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	WriteJSON(w, http.StatusOK, nil, "employee deleted successfully")
 }
+
+// viewer resolves the response shaping for the caller, writing the error response on failure.
+func (h *EmployeeHandler) viewer(w http.ResponseWriter, r *http.Request) (*usecase.EmployeeViewer, bool) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return nil, false
+	}
+
+	viewer, err := h.usecase.Viewer(r.Context(), actor)
+	if err != nil {
+		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to resolve field visibility")
+		return nil, false
+	}
+
+	return viewer, true
+}
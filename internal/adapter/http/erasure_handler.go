@@ -0,0 +1,100 @@
+package adapterhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/dto/erasure"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+type ErasureHandler struct {
+	usecase *usecase.ErasureUsecase
+}
+
+func NewErasureHandler(uc *usecase.ErasureUsecase) *ErasureHandler {
+	return &ErasureHandler{
+		usecase: uc,
+	}
+}
+
+func (h *ErasureHandler) Erase(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	var req erasure.EraseEmployeeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "invalid request payload")
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "validation error")
+		return
+	}
+
+	record, err := h.usecase.Erase(r.Context(), actor, id, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ForbiddenError):
+			WriteErrorJSON(w, http.StatusForbidden, err, "you are not allowed to erase this employee")
+		case errors.Is(err, usecase.EmployeeNotFoundError):
+			WriteErrorJSON(w, http.StatusNotFound, err, "employee not found")
+		case errors.Is(err, usecase.EmployeeAlreadyErasedError):
+			WriteErrorJSON(w, http.StatusConflict, err, err.Error())
+		default:
+			WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to erase employee")
+		}
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, toErasureResponse(record), "employee personal data erased successfully")
+}
+
+func (h *ErasureHandler) History(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	records, err := h.usecase.History(r.Context(), actor, id)
+	if err != nil {
+		if errors.Is(err, usecase.ForbiddenError) {
+			WriteErrorJSON(w, http.StatusForbidden, err, err.Error())
+			return
+		}
+		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to retrieve erasure records")
+		return
+	}
+
+	resp := make([]erasure.ErasureResponse, 0, len(records))
+	for _, record := range records {
+		resp = append(resp, toErasureResponse(record))
+	}
+
+	WriteJSON(w, http.StatusOK, resp, "erasure records retrieved successfully")
+}
+
+func toErasureResponse(record *domain.ErasureRecord) erasure.ErasureResponse {
+	return erasure.ErasureResponse{
+		ID:                record.ID,
+		EmployeeID:        record.EmployeeID,
+		Trigger:           string(record.Trigger),
+		RequestedBy:       record.RequestedBy,
+		Reason:            record.Reason,
+		ErasedFields:      record.ErasedFields,
+		AttendanceRecords: record.AttendanceRecords,
+		PhotoDeleted:      record.PhotoDeleted,
+		ErasedAt:          record.ErasedAt,
+	}
+}
@@ -0,0 +1,66 @@
+package adapterhttp
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+// FileURLVerifier checks the signature of a file URL handed out by a storage driver that
+// does not presign URLs itself.
+type FileURLVerifier interface {
+	Verify(key string, expires string, signature string) error
+}
+
+// FileHandler serves stored files for the filesystem and memory storage drivers. The
+// signed URL is the authorisation, like a presigned object store URL.
+type FileHandler struct {
+	storage  usecase.StorageRepository
+	verifier FileURLVerifier
+}
+
+func NewFileHandler(storage usecase.StorageRepository, verifier FileURLVerifier) *FileHandler {
+	return &FileHandler{
+		storage:  storage,
+		verifier: verifier,
+	}
+}
+
+func (h *FileHandler) Get(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	query := r.URL.Query()
+
+	if err := h.verifier.Verify(key, query.Get("expires"), query.Get("signature")); err != nil {
+		WriteErrorJSON(w, http.StatusForbidden, err, "invalid or expired file link")
+		return
+	}
+
+	file, err := h.storage.DownloadFile(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, usecase.StoredFileNotFoundError) {
+			WriteErrorJSON(w, http.StatusNotFound, err, "file not found")
+			return
+		}
+		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to read file")
+		return
+	}
+	defer file.Close()
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, file); err != nil {
+		slog.Log(r.Context(), slog.LevelError, "Failed to stream file", "key", key, "error", err)
+	}
+}
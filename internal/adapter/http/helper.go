@@ -2,7 +2,12 @@ package adapterhttp
 
 import (
 	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
 )
 
 type Response struct {
@@ -36,3 +41,89 @@ func WriteErrorJSON(w http.ResponseWriter, status int, err error, msg string) {
 		return
 	}
 }
+
+// ClientIP returns the caller address resolved by ClientIPMiddleware, or the peer
+// address when the request did not pass through it.
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(ClientIPKey).(string); ok {
+		return ip
+	}
+	return remoteHost(r)
+}
+
+// resolveClientIP trusts X-Forwarded-For only when the peer is one of the trusted
+// proxies. It then walks the header from the right, where the nearest proxy appended
+// its peer, and returns the first hop that is not a trusted proxy itself. Entries left
+// of it are written by the client and never believed.
+func resolveClientIP(r *http.Request, trusted []netip.Prefix) string {
+	remote := remoteHost(r)
+	if !isTrustedProxy(remote, trusted) {
+		return remote
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if _, err := netip.ParseAddr(hop); err != nil {
+			// Garbage in the header; stop at the last address that was vouched for
+			return client
+		}
+		client = hop
+		if !isTrustedProxy(hop, trusted) {
+			return hop
+		}
+	}
+	return client
+}
+
+func isTrustedProxy(ip string, trusted []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// SetETag exposes a resource version as a strong entity tag, e.g. "3".
+func SetETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// IfMatchVersion reads the version the client expects from the If-Match header. It writes
+// 428 Precondition Required when the header is missing, and 412 Precondition Failed when
+// it holds no version (including "*", which would skip the check) or a weak tag, which
+// If-Match never matches as it needs a strong comparison.
+func IfMatchVersion(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" {
+		WriteErrorJSON(w, http.StatusPreconditionRequired, nil, "If-Match header with the ETag of the resource is required")
+		return 0, false
+	}
+
+	unquoted, err := strconv.Unquote(ifMatch)
+	if err == nil {
+		var version int64
+		version, err = strconv.ParseInt(unquoted, 10, 64)
+		if err == nil {
+			return version, true
+		}
+	}
+
+	WriteErrorJSON(w, http.StatusPreconditionFailed, errors.New("invalid If-Match header"), "If-Match does not match the current ETag")
+	return 0, false
+}
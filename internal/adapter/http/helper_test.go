@@ -0,0 +1,78 @@
+package adapterhttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	adapterhttp "github.com/zuyatna/shop-retail-employee-service/internal/adapter/http"
+)
+
+func TestClientIPMiddleware(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		want       string
+	}{
+		{"Untrusted Peer Ignores Header", "203.0.113.7:5000", "198.51.100.1", "203.0.113.7"},
+		{"Trusted Proxy Takes Right-Most Untrusted Hop", "10.0.0.2:5000", "1.2.3.4, 198.51.100.1", "198.51.100.1"},
+		{"Skips Chained Trusted Proxies", "10.0.0.2:5000", "198.51.100.1, 10.0.0.3", "198.51.100.1"},
+		{"Garbage Hop Stops At Last Vouched Address", "10.0.0.2:5000", "198.51.100.1, not-an-ip", "10.0.0.2"},
+		{"Trusted Proxy Without Header", "10.0.0.2:5000", "", "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := adapterhttp.ClientIPMiddleware(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = adapterhttp.ClientIP(r)
+			}))
+
+			r := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		ifMatch     string
+		wantVersion int64
+		wantOK      bool
+		wantStatus  int
+	}{
+		{"Strong Tag", `"3"`, 3, true, http.StatusOK},
+		{"Missing Header", "", 0, false, http.StatusPreconditionRequired},
+		{"Weak Tag", `W/"3"`, 0, false, http.StatusPreconditionFailed},
+		{"Wildcard", "*", 0, false, http.StatusPreconditionFailed},
+		{"Unquoted Version", "3", 0, false, http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPatch, "/employees/emp-1", nil)
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+
+			version, ok := adapterhttp.IfMatchVersion(w, r)
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantVersion, version)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
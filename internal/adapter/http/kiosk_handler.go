@@ -0,0 +1,316 @@
+package adapterhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/dto/kiosk"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+type KioskHandler struct {
+	usecase *usecase.KioskUsecase
+}
+
+func NewKioskHandler(uc *usecase.KioskUsecase) *KioskHandler {
+	return &KioskHandler{
+		usecase: uc,
+	}
+}
+
+// Register adds a kiosk to a store. The device token is only part of this response.
+func (h *KioskHandler) Register(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	var req kiosk.RegisterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "invalid request payload")
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "validation error")
+		return
+	}
+
+	registered, token, err := h.usecase.Register(r.Context(), actor, req.StoreID, req.Name, req.SigningKey)
+	if err != nil {
+		writeKioskError(w, err, "failed to register kiosk")
+		return
+	}
+
+	resp := toKioskResponse(registered)
+	resp.Token = token
+	WriteJSON(w, http.StatusCreated, resp, "kiosk registered successfully")
+}
+
+// List returns the kiosks of ?store_id=, or of every store the caller may see.
+func (h *KioskHandler) List(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	kiosks, err := h.usecase.List(r.Context(), actor, r.URL.Query().Get("store_id"))
+	if err != nil {
+		writeKioskError(w, err, "failed to retrieve kiosks")
+		return
+	}
+
+	resp := make([]kiosk.KioskResponse, 0, len(kiosks))
+	for _, k := range kiosks {
+		resp = append(resp, toKioskResponse(k))
+	}
+
+	WriteJSON(w, http.StatusOK, resp, "kiosks retrieved successfully")
+}
+
+func (h *KioskHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	if err := h.usecase.Revoke(r.Context(), actor, r.PathValue("id")); err != nil {
+		writeKioskError(w, err, "failed to revoke kiosk")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, nil, "kiosk revoked successfully")
+}
+
+// QR returns the code the kiosk shows until expires_at. Kiosks poll it before then.
+func (h *KioskHandler) QR(w http.ResponseWriter, r *http.Request) {
+	k, ok := KioskFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	code, expiresAt := h.usecase.IssueQR(k)
+	w.Header().Set("Cache-Control", "no-store")
+	WriteJSON(w, http.StatusOK, kiosk.QRResponse{Code: code, ExpiresAt: expiresAt}, "kiosk QR code issued")
+}
+
+// CheckIn checks in an employee who entered their PIN on the kiosk.
+func (h *KioskHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	k, ok := KioskFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	req, ok := decodePINRequest(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := h.usecase.CheckIn(r.Context(), k, req.EmployeeID, req.PIN)
+	if err != nil {
+		writeKioskError(w, err, "failed to check in")
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, map[string]string{"mongo id": id}, "check-in successful")
+}
+
+// CheckOut checks out an employee who entered their PIN on the kiosk.
+func (h *KioskHandler) CheckOut(w http.ResponseWriter, r *http.Request) {
+	k, ok := KioskFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	req, ok := decodePINRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.CheckOut(r.Context(), k, req.EmployeeID, req.PIN); err != nil {
+		writeKioskError(w, err, "failed to check out")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "check-out successful"}, "check-out successful")
+}
+
+// StartBreak starts a break of an employee who entered their PIN on the kiosk.
+func (h *KioskHandler) StartBreak(w http.ResponseWriter, r *http.Request) {
+	k, ok := KioskFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	req, ok := decodePINRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.StartBreak(r.Context(), k, req.EmployeeID, req.PIN); err != nil {
+		writeKioskError(w, err, "failed to start break")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, nil, "break started")
+}
+
+// EndBreak ends the break of an employee who entered their PIN on the kiosk.
+func (h *KioskHandler) EndBreak(w http.ResponseWriter, r *http.Request) {
+	k, ok := KioskFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	req, ok := decodePINRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.EndBreak(r.Context(), k, req.EmployeeID, req.PIN); err != nil {
+		writeKioskError(w, err, "failed to end break")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, nil, "break ended")
+}
+
+// Sync applies the events the kiosk collected while it was offline and reports the
+// outcome of each.
+func (h *KioskHandler) Sync(w http.ResponseWriter, r *http.Request) {
+	k, ok := KioskFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+	var req kiosk.SyncRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "invalid request payload")
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "validation error")
+		return
+	}
+
+	events := make([]domain.KioskEvent, 0, len(req.Events))
+	for _, e := range req.Events {
+		events = append(events, domain.KioskEvent{
+			ID:         e.ID,
+			Type:       domain.KioskEventType(e.Type),
+			EmployeeID: e.EmployeeID,
+			PIN:        e.PIN,
+			OccurredAt: e.OccurredAt,
+			Signature:  e.Signature,
+		})
+	}
+
+	results, err := h.usecase.Sync(r.Context(), k, req.SentAt, events)
+	if err != nil {
+		writeKioskError(w, err, "failed to sync kiosk events")
+		return
+	}
+
+	resp := make([]kiosk.SyncEventResult, 0, len(results))
+	for _, result := range results {
+		resp = append(resp, kiosk.SyncEventResult{
+			ID:           result.EventID,
+			Status:       string(result.Status),
+			AttendanceID: result.AttendanceID,
+			Error:        result.Error,
+		})
+	}
+
+	WriteJSON(w, http.StatusOK, resp, "kiosk events synced")
+}
+
+// SetPIN sets the PIN the caller enters on kiosks.
+func (h *KioskHandler) SetPIN(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	var req kiosk.SetPINRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "invalid request payload")
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "validation error")
+		return
+	}
+
+	if err := h.usecase.SetPIN(r.Context(), actor, req.PIN); err != nil {
+		writeKioskError(w, err, "failed to set kiosk PIN")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, nil, "kiosk PIN set successfully")
+}
+
+func decodePINRequest(w http.ResponseWriter, r *http.Request) (kiosk.PINRequest, bool) {
+	var req kiosk.PINRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "invalid request payload")
+		return req, false
+	}
+
+	if err := validate.Struct(req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "validation error")
+		return req, false
+	}
+	return req, true
+}
+
+func writeKioskError(w http.ResponseWriter, err error, fallback string) {
+	if writeLoginLocked(w, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, usecase.ForbiddenError),
+		errors.Is(err, usecase.KioskStoreMismatchError),
+		errors.Is(err, usecase.EmployeeNotActiveError):
+		WriteErrorJSON(w, http.StatusForbidden, err, err.Error())
+	case errors.Is(err, domain.ErrInvalidKioskPIN):
+		WriteErrorJSON(w, http.StatusBadRequest, err, err.Error())
+	case errors.Is(err, usecase.InvalidKioskPINError):
+		WriteErrorJSON(w, http.StatusUnauthorized, err, err.Error())
+	case errors.Is(err, usecase.KioskNotFoundError):
+		WriteErrorJSON(w, http.StatusNotFound, err, err.Error())
+	case errors.Is(err, usecase.InvalidKioskSyncError),
+		errors.Is(err, usecase.KioskClockSkewError):
+		WriteErrorJSON(w, http.StatusBadRequest, err, err.Error())
+	case errors.Is(err, usecase.InvalidKioskError):
+		WriteErrorJSON(w, http.StatusBadRequest, err, err.Error())
+	case isPunchConflict(err):
+		WriteErrorJSON(w, http.StatusConflict, err, err.Error())
+	default:
+		WriteErrorJSON(w, http.StatusInternalServerError, err, fallback)
+	}
+}
+
+func toKioskResponse(k *domain.Kiosk) kiosk.KioskResponse {
+	return kiosk.KioskResponse{
+		ID:        k.ID,
+		StoreID:   k.StoreID,
+		Name:      k.Name,
+		CreatedBy: k.CreatedBy,
+		CreatedAt: k.CreatedAt,
+		RevokedAt: k.RevokedAt,
+	}
+}
@@ -0,0 +1,123 @@
+package adapterhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/dto/lifecycle"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+type LifecycleHandler struct {
+	usecase *usecase.LifecycleUsecase
+}
+
+func NewLifecycleHandler(uc *usecase.LifecycleUsecase) *LifecycleHandler {
+	return &LifecycleHandler{
+		usecase: uc,
+	}
+}
+
+func (h *LifecycleHandler) Activate(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, domain.StatusOnboarding, domain.StatusActive)
+}
+
+func (h *LifecycleHandler) Suspend(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "", domain.StatusSuspended)
+}
+
+func (h *LifecycleHandler) Reactivate(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, domain.StatusSuspended, domain.StatusActive)
+}
+
+func (h *LifecycleHandler) Terminate(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "", domain.StatusTerminated)
+}
+
+func (h *LifecycleHandler) History(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	changes, err := h.usecase.History(r.Context(), actor, id)
+	if err != nil {
+		if errors.Is(err, usecase.ForbiddenError) {
+			WriteErrorJSON(w, http.StatusForbidden, err, err.Error())
+			return
+		}
+		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to retrieve status history")
+		return
+	}
+
+	resp := make([]lifecycle.StatusChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		resp = append(resp, toStatusChangeResponse(change))
+	}
+
+	WriteJSON(w, http.StatusOK, resp, "status history retrieved successfully")
+}
+
+func (h *LifecycleHandler) transition(w http.ResponseWriter, r *http.Request, from, to domain.Status) {
+	id := r.PathValue("id")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	var req lifecycle.StatusChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "invalid request payload")
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "validation error")
+		return
+	}
+
+	change, err := h.usecase.Transition(r.Context(), actor, id, from, to, req.Reason, req.EffectiveDate)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ForbiddenError):
+			WriteErrorJSON(w, http.StatusForbidden, err, "you are not allowed to change this employee's status")
+		case errors.Is(err, usecase.EmployeeNotFoundError):
+			WriteErrorJSON(w, http.StatusNotFound, err, "employee not found")
+		case errors.Is(err, usecase.InvalidStatusTransitionError), errors.Is(err, usecase.StatusChangeAlreadyScheduledError):
+			WriteErrorJSON(w, http.StatusConflict, err, err.Error())
+		default:
+			WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to change employee status")
+		}
+		return
+	}
+
+	if change.State == domain.StatusChangeScheduled {
+		WriteJSON(w, http.StatusAccepted, toStatusChangeResponse(change), "status change scheduled")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, toStatusChangeResponse(change), "employee status changed successfully")
+}
+
+func toStatusChangeResponse(change *domain.StatusChange) lifecycle.StatusChangeResponse {
+	return lifecycle.StatusChangeResponse{
+		ID:          change.ID,
+		EmployeeID:  change.EmployeeID,
+		From:        string(change.From),
+		To:          string(change.To),
+		Reason:      change.Reason,
+		EffectiveAt: change.EffectiveAt,
+		ActorID:     change.ActorID,
+		State:       string(change.State),
+		Error:       change.Error,
+		CreatedAt:   change.CreatedAt,
+		AppliedAt:   change.AppliedAt,
+	}
+}
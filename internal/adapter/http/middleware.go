@@ -4,16 +4,44 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"net/netip"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
 	"github.com/zuyatna/shop-retail-employee-service/internal/util/jwtutil"
 )
 
 type contextKey string
 
-const UserClaimsKey contextKey = "user_claims"
+const (
+	UserClaimsKey contextKey = "user_claims"
+	KioskKey      contextKey = "kiosk"
+	ClientIPKey   contextKey = "client_ip"
+)
+
+// ClientIPMiddleware resolves the client address of requests that throttle per IP.
+// X-Forwarded-For is only believed as far as it was written by trustedProxies.
+func ClientIPMiddleware(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), ClientIPKey, resolveClientIP(r, trustedProxies))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// SessionValidator decides whether a token that verified correctly still belongs to a
+// live session, e.g. after the employee was suspended.
+type SessionValidator interface {
+	ValidateSession(ctx context.Context, employeeID string, issuedAt time.Time) error
+}
 
-func AuthMiddleware(signer *jwtutil.Signer) func(http.Handler) http.Handler {
+// AuthMiddleware accepts regular access tokens. Restricted tokens (e.g. issued for
+// two-factor enrollment) are only accepted when their purpose is listed in allowedPurposes.
+func AuthMiddleware(signer *jwtutil.Signer, sessions SessionValidator, allowedPurposes ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -35,13 +63,64 @@ func AuthMiddleware(signer *jwtutil.Signer) func(http.Handler) http.Handler {
 				return
 			}
 
+			if claims.Purpose != "" && !slices.Contains(allowedPurposes, claims.Purpose) {
+				WriteErrorJSON(w, http.StatusUnauthorized, errors.New("restricted token"), "token is not valid for this endpoint")
+				return
+			}
+
+			var issuedAt time.Time
+			if claims.IssuedAt != nil {
+				issuedAt = claims.IssuedAt.Time
+			}
+			if err := sessions.ValidateSession(r.Context(), claims.UserID, issuedAt); err != nil {
+				if errors.Is(err, usecase.SessionRevokedError) {
+					WriteErrorJSON(w, http.StatusUnauthorized, err, "session is no longer valid")
+					return
+				}
+				WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to validate session")
+				return
+			}
+
 			ctx := context.WithValue(r.Context(), UserClaimsKey, claims)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
-func RoleMiddleware(allowedRoles ...string) func(http.Handler) http.Handler {
+// KioskAuthenticator resolves the device token a store kiosk authenticates with.
+type KioskAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*domain.Kiosk, error)
+}
+
+// KioskMiddleware accepts store kiosks authenticating with "Authorization: Kiosk <token>".
+func KioskMiddleware(kiosks KioskAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := kioskToken(r)
+			if !ok {
+				WriteErrorJSON(w, http.StatusUnauthorized, errors.New("invalid authorization header format"), "kiosk token required")
+				return
+			}
+
+			kiosk, err := kiosks.Authenticate(r.Context(), token)
+			if err != nil {
+				if errors.Is(err, usecase.InvalidKioskTokenError) {
+					WriteErrorJSON(w, http.StatusUnauthorized, err, err.Error())
+					return
+				}
+				WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to authenticate kiosk")
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), KioskKey, kiosk)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// PermissionMiddleware lets the request through when the caller's role holds at least one
+// of perms in the current policy. Finer checks (e.g. self vs. others) are done in the usecase.
+func PermissionMiddleware(authorizer *usecase.Authorizer, perms ...domain.Permission) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			claims, ok := r.Context().Value(UserClaimsKey).(*jwtutil.Claims)
@@ -50,12 +129,10 @@ func RoleMiddleware(allowedRoles ...string) func(http.Handler) http.Handler {
 				return
 			}
 
-			isAllowed := false
-			for _, role := range allowedRoles {
-				if role == claims.Role {
-					isAllowed = true
-					break
-				}
+			isAllowed, err := authorizer.HasAny(r.Context(), domain.Role(claims.Role), perms...)
+			if err != nil {
+				WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to check permissions")
+				return
 			}
 
 			if !isAllowed {
@@ -67,3 +144,24 @@ func RoleMiddleware(allowedRoles ...string) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// ActorFromRequest returns the authenticated caller set by AuthMiddleware.
+func ActorFromRequest(r *http.Request) (usecase.Actor, bool) {
+	claims, ok := r.Context().Value(UserClaimsKey).(*jwtutil.Claims)
+	if !ok || claims == nil {
+		return usecase.Actor{}, false
+	}
+	return usecase.Actor{ID: claims.UserID, Role: domain.Role(claims.Role)}, true
+}
+
+// KioskFromRequest returns the kiosk set by KioskMiddleware.
+func KioskFromRequest(r *http.Request) (*domain.Kiosk, bool) {
+	kiosk, ok := r.Context().Value(KioskKey).(*domain.Kiosk)
+	return kiosk, ok && kiosk != nil
+}
+
+// kioskToken returns the device token of a "Authorization: Kiosk <token>" header.
+func kioskToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	return token, ok && scheme == "Kiosk" && token != ""
+}
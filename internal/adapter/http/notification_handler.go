@@ -0,0 +1,174 @@
+package adapterhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/dto/notification"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+type NotificationHandler struct {
+	usecase *usecase.NotificationUsecase
+}
+
+func NewNotificationHandler(uc *usecase.NotificationUsecase) *NotificationHandler {
+	return &NotificationHandler{
+		usecase: uc,
+	}
+}
+
+// Inbox lists the caller's in-app notifications; ?unread=true leaves out read ones.
+func (h *NotificationHandler) Inbox(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+
+	notifications, unread, err := h.usecase.Inbox(r.Context(), actor, unreadOnly)
+	if err != nil {
+		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to retrieve notifications")
+		return
+	}
+
+	resp := notification.InboxResponse{
+		UnreadCount:   unread,
+		Notifications: make([]notification.NotificationResponse, 0, len(notifications)),
+	}
+	for _, n := range notifications {
+		resp.Notifications = append(resp.Notifications, toNotificationResponse(n))
+	}
+
+	WriteJSON(w, http.StatusOK, resp, "notifications retrieved successfully")
+}
+
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	n, err := h.usecase.MarkRead(r.Context(), actor, id)
+	if err != nil {
+		if errors.Is(err, usecase.NotificationNotFoundError) {
+			WriteErrorJSON(w, http.StatusNotFound, err, err.Error())
+			return
+		}
+		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to mark notification as read")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, toNotificationResponse(n), "notification marked as read")
+}
+
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	marked, err := h.usecase.MarkAllRead(r.Context(), actor)
+	if err != nil {
+		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to mark notifications as read")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]int64{"marked": marked}, "notifications marked as read")
+}
+
+func (h *NotificationHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	prefs, err := h.usecase.Preferences(r.Context(), actor)
+	if err != nil {
+		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to retrieve notification preferences")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, toPreferencesResponse(prefs), "notification preferences retrieved successfully")
+}
+
+func (h *NotificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	var req notification.PreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "invalid request payload")
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "validation error")
+		return
+	}
+
+	channels := make(map[domain.NotificationEvent][]domain.NotificationChannel, len(req.Channels))
+	for event, names := range req.Channels {
+		eventChannels := make([]domain.NotificationChannel, 0, len(names))
+		for _, name := range names {
+			eventChannels = append(eventChannels, domain.NotificationChannel(name))
+		}
+		channels[domain.NotificationEvent(event)] = eventChannels
+	}
+
+	prefs, err := h.usecase.UpdatePreferences(r.Context(), actor, req.WebhookURL, channels)
+	if err != nil {
+		if errors.Is(err, usecase.InvalidNotificationPreferencesError) {
+			WriteErrorJSON(w, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to update notification preferences")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, toPreferencesResponse(prefs), "notification preferences updated successfully")
+}
+
+func toNotificationResponse(n *domain.Notification) notification.NotificationResponse {
+	return notification.NotificationResponse{
+		ID:        n.ID,
+		Event:     string(n.Event),
+		Subject:   n.Subject,
+		Body:      n.Body,
+		Read:      n.IsRead(),
+		CreatedAt: n.CreatedAt,
+		ReadAt:    n.ReadAt,
+	}
+}
+
+func toPreferencesResponse(p *domain.NotificationPreferences) notification.PreferencesResponse {
+	resp := notification.PreferencesResponse{
+		WebhookURL: p.WebhookURL,
+		Channels:   make(map[string][]string, len(domain.NotificationEvents)),
+	}
+	if !p.UpdatedAt.IsZero() {
+		resp.UpdatedAt = &p.UpdatedAt
+	}
+
+	for _, event := range domain.NotificationEvents {
+		names := []string{}
+		for _, channel := range p.ChannelsFor(event) {
+			names = append(names, string(channel))
+		}
+		resp.Channels[string(event)] = names
+	}
+
+	return resp
+}
@@ -0,0 +1,120 @@
+package adapterhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/dto/role"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+type RoleHandler struct {
+	usecase *usecase.RoleUsecase
+}
+
+func NewRoleHandler(uc *usecase.RoleUsecase) *RoleHandler {
+	return &RoleHandler{
+		usecase: uc,
+	}
+}
+
+func (h *RoleHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.usecase.GetAll(r.Context())
+	if err != nil {
+		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to retrieve roles")
+		return
+	}
+
+	resp := make([]role.RoleResponse, 0, len(roles))
+	for _, def := range roles {
+		resp = append(resp, toRoleResponse(def))
+	}
+
+	WriteJSON(w, http.StatusOK, resp, "roles retrieved successfully")
+}
+
+func (h *RoleHandler) Save(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	var req role.SaveRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "invalid request payload")
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "validation error")
+		return
+	}
+
+	saved, err := h.usecase.Save(r.Context(), actor, name, req.Description, req.Rank, req.Permissions)
+	if err != nil {
+		if errors.Is(err, usecase.ForbiddenError) {
+			WriteErrorJSON(w, http.StatusForbidden, err, err.Error())
+			return
+		}
+		WriteErrorJSON(w, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, toRoleResponse(saved), "role saved successfully")
+}
+
+func (h *RoleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	err := h.usecase.Delete(r.Context(), actor, name)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ForbiddenError):
+			WriteErrorJSON(w, http.StatusForbidden, err, err.Error())
+		case errors.Is(err, usecase.RoleNotFoundError):
+			WriteErrorJSON(w, http.StatusNotFound, err, "role not found")
+		case errors.Is(err, usecase.BuiltInRoleError), errors.Is(err, usecase.RoleInUseError):
+			WriteErrorJSON(w, http.StatusConflict, err, err.Error())
+		default:
+			WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to delete role")
+		}
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, nil, "role deleted successfully")
+}
+
+func (h *RoleHandler) GetPermissions(w http.ResponseWriter, r *http.Request) {
+	resp := make([]string, 0, len(domain.AllPermissions))
+	for _, p := range domain.AllPermissions {
+		resp = append(resp, string(p))
+	}
+
+	WriteJSON(w, http.StatusOK, resp, "permissions retrieved successfully")
+}
+
+func toRoleResponse(def *domain.RoleDefinition) role.RoleResponse {
+	permissions := make([]string, 0, len(def.Permissions))
+	for _, p := range def.Permissions {
+		permissions = append(permissions, string(p))
+	}
+
+	return role.RoleResponse{
+		Name:        string(def.Name),
+		Description: def.Description,
+		Rank:        def.Rank,
+		BuiltIn:     def.BuiltIn,
+		Permissions: permissions,
+	}
+}
@@ -0,0 +1,144 @@
+package adapterhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/dto/auth"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/jwtutil"
+)
+
+type TwoFactorHandler struct {
+	usecase *usecase.TwoFactorUsecase
+}
+
+func NewTwoFactorHandler(uc *usecase.TwoFactorUsecase) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		usecase: uc,
+	}
+}
+
+func (h *TwoFactorHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(UserClaimsKey).(*jwtutil.Claims)
+	if !ok || claims == nil {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	enrollment, err := h.usecase.Enroll(r.Context(), claims.UserID)
+	if err != nil {
+		if errors.Is(err, usecase.TwoFactorAlreadyEnabledError) {
+			WriteErrorJSON(w, http.StatusConflict, err, err.Error())
+			return
+		}
+		WriteErrorJSON(w, http.StatusInternalServerError, err, "failed to start two factor enrollment")
+		return
+	}
+
+	resp := auth.TwoFactorEnrollResponse{
+		Secret:          enrollment.Secret,
+		ProvisioningURI: enrollment.ProvisioningURI,
+	}
+	WriteJSON(w, http.StatusOK, resp, "scan the provisioning URI and confirm with a code")
+}
+
+func (h *TwoFactorHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(UserClaimsKey).(*jwtutil.Claims)
+	if !ok || claims == nil {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	var req auth.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "invalid request payload")
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "validation error")
+		return
+	}
+
+	codes, err := h.usecase.Confirm(r.Context(), claims.UserID, req.Code)
+	if err != nil {
+		writeTwoFactorError(w, err, "failed to confirm two factor enrollment")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, auth.RecoveryCodesResponse{RecoveryCodes: codes}, "two factor authentication enabled, please log in again")
+}
+
+func (h *TwoFactorHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(UserClaimsKey).(*jwtutil.Claims)
+	if !ok || claims == nil {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	var req auth.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "invalid request payload")
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "validation error")
+		return
+	}
+
+	if err := h.usecase.Disable(r.Context(), claims.UserID, req.Code); err != nil {
+		writeTwoFactorError(w, err, "failed to disable two factor authentication")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, nil, "two factor authentication disabled")
+}
+
+func (h *TwoFactorHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		WriteErrorJSON(w, http.StatusBadRequest, nil, "employee ID is required")
+		return
+	}
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	if err := h.usecase.Reset(r.Context(), actor, id); err != nil {
+		if errors.Is(err, usecase.ForbiddenError) {
+			WriteErrorJSON(w, http.StatusForbidden, err, "you are not allowed to reset this employee's two factor authentication")
+			return
+		}
+		writeTwoFactorError(w, err, "failed to reset two factor authentication")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, nil, "two factor authentication reset successfully")
+}
+
+func writeTwoFactorError(w http.ResponseWriter, err error, fallback string) {
+	if writeLoginLocked(w, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, usecase.InvalidTwoFactorCodeError):
+		WriteErrorJSON(w, http.StatusUnprocessableEntity, err, err.Error())
+	case errors.Is(err, usecase.TwoFactorNotEnrolledError):
+		WriteErrorJSON(w, http.StatusNotFound, err, err.Error())
+	case errors.Is(err, usecase.TwoFactorAlreadyEnabledError):
+		WriteErrorJSON(w, http.StatusConflict, err, err.Error())
+	case errors.Is(err, usecase.TwoFactorRequiredError):
+		WriteErrorJSON(w, http.StatusForbidden, err, err.Error())
+	case errors.Is(err, usecase.EmployeeNotFoundError):
+		WriteErrorJSON(w, http.StatusNotFound, err, "employee not found")
+	default:
+		WriteErrorJSON(w, http.StatusInternalServerError, err, fallback)
+	}
+}
@@ -0,0 +1,266 @@
+package adapterhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/dto/webhook"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+type WebhookHandler struct {
+	usecase *usecase.WebhookUsecase
+}
+
+func NewWebhookHandler(uc *usecase.WebhookUsecase) *WebhookHandler {
+	return &WebhookHandler{
+		usecase: uc,
+	}
+}
+
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	var req webhook.CreateSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "invalid request payload")
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "validation error")
+		return
+	}
+
+	subscription, err := h.usecase.Create(r.Context(), actor, req.URL, toWebhookEvents(req.Events))
+	if err != nil {
+		writeWebhookError(w, err, "failed to create webhook subscription")
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, toSubscriptionResponse(subscription, true), "webhook subscription created successfully")
+}
+
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	subscriptions, err := h.usecase.List(r.Context(), actor)
+	if err != nil {
+		writeWebhookError(w, err, "failed to retrieve webhook subscriptions")
+		return
+	}
+
+	resp := make([]webhook.SubscriptionResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		resp = append(resp, toSubscriptionResponse(subscription, false))
+	}
+
+	WriteJSON(w, http.StatusOK, resp, "webhook subscriptions retrieved successfully")
+}
+
+func (h *WebhookHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	subscription, err := h.usecase.Get(r.Context(), actor, id)
+	if err != nil {
+		writeWebhookError(w, err, "failed to retrieve webhook subscription")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, toSubscriptionResponse(subscription, false), "webhook subscription retrieved successfully")
+}
+
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	var req webhook.UpdateSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "invalid request payload")
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		WriteErrorJSON(w, http.StatusBadRequest, err, "validation error")
+		return
+	}
+
+	subscription, err := h.usecase.Update(r.Context(), actor, id, req.URL, toWebhookEvents(req.Events), *req.Active)
+	if err != nil {
+		writeWebhookError(w, err, "failed to update webhook subscription")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, toSubscriptionResponse(subscription, false), "webhook subscription updated successfully")
+}
+
+func (h *WebhookHandler) RotateSecret(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	subscription, err := h.usecase.RotateSecret(r.Context(), actor, id)
+	if err != nil {
+		writeWebhookError(w, err, "failed to rotate webhook secret")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, toSubscriptionResponse(subscription, true), "webhook secret rotated successfully")
+}
+
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	if err := h.usecase.Delete(r.Context(), actor, id); err != nil {
+		writeWebhookError(w, err, "failed to delete webhook subscription")
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, nil, "webhook subscription deleted successfully")
+}
+
+// Deliveries returns the delivery log of a subscription; ?status= narrows it down to
+// pending, delivered or dead deliveries.
+func (h *WebhookHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	status := domain.WebhookDeliveryStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", domain.WebhookDeliveryPending, domain.WebhookDeliveryDelivered, domain.WebhookDeliveryDead:
+	default:
+		WriteErrorJSON(w, http.StatusBadRequest, nil, "status must be one of pending, delivered or dead")
+		return
+	}
+
+	deliveries, err := h.usecase.Deliveries(r.Context(), actor, id, status)
+	if err != nil {
+		writeWebhookError(w, err, "failed to retrieve webhook deliveries")
+		return
+	}
+
+	resp := make([]webhook.DeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		resp = append(resp, toDeliveryResponse(delivery))
+	}
+
+	WriteJSON(w, http.StatusOK, resp, "webhook deliveries retrieved successfully")
+}
+
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	deliveryID := r.PathValue("deliveryID")
+
+	actor, ok := ActorFromRequest(r)
+	if !ok {
+		WriteErrorJSON(w, http.StatusUnauthorized, nil, "unauthorized")
+		return
+	}
+
+	delivery, err := h.usecase.Redeliver(r.Context(), actor, id, deliveryID)
+	if err != nil {
+		writeWebhookError(w, err, "failed to redeliver webhook")
+		return
+	}
+
+	WriteJSON(w, http.StatusAccepted, toDeliveryResponse(delivery), "webhook delivery queued for redelivery")
+}
+
+func writeWebhookError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, usecase.ForbiddenError):
+		WriteErrorJSON(w, http.StatusForbidden, err, err.Error())
+	case errors.Is(err, usecase.WebhookSubscriptionNotFoundError),
+		errors.Is(err, usecase.WebhookDeliveryNotFoundError):
+		WriteErrorJSON(w, http.StatusNotFound, err, err.Error())
+	case errors.Is(err, usecase.InvalidWebhookSubscriptionError):
+		WriteErrorJSON(w, http.StatusBadRequest, err, err.Error())
+	case errors.Is(err, usecase.WebhookDeliveryNotDeadError):
+		WriteErrorJSON(w, http.StatusConflict, err, err.Error())
+	default:
+		WriteErrorJSON(w, http.StatusInternalServerError, err, fallback)
+	}
+}
+
+func toWebhookEvents(names []string) []domain.WebhookEvent {
+	events := make([]domain.WebhookEvent, 0, len(names))
+	for _, name := range names {
+		events = append(events, domain.WebhookEvent(name))
+	}
+	return events
+}
+
+func toSubscriptionResponse(s *domain.WebhookSubscription, withSecret bool) webhook.SubscriptionResponse {
+	events := make([]string, 0, len(s.Events))
+	for _, event := range s.Events {
+		events = append(events, string(event))
+	}
+
+	resp := webhook.SubscriptionResponse{
+		ID:        s.ID,
+		URL:       s.URL,
+		Events:    events,
+		Active:    s.Active,
+		CreatedBy: s.CreatedBy,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+	if withSecret {
+		resp.Secret = s.Secret
+	}
+	return resp
+}
+
+func toDeliveryResponse(d *domain.WebhookDelivery) webhook.DeliveryResponse {
+	resp := webhook.DeliveryResponse{
+		ID:             d.ID,
+		EventID:        d.EventID,
+		Event:          string(d.Event),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+	if d.Status == domain.WebhookDeliveryPending {
+		resp.NextAttemptAt = &d.NextAttemptAt
+	}
+	return resp
+}
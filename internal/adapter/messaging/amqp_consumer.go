@@ -0,0 +1,217 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/zuyatna/shop-retail-employee-service/internal/config"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+const (
+	consumerTag    = "employee-service"
+	reconnectDelay = 5 * time.Second
+	// handleTimeout bounds one message; it is not tied to shutdown, so the message in
+	// hand is finished before the consumer stops.
+	handleTimeout = time.Minute
+)
+
+// InboundEventHandler handles one decoded message. The consumer treats errors wrapping
+// usecase.UnknownInboundEventError or usecase.InvalidInboundEventError as permanent.
+type InboundEventHandler interface {
+	Handle(ctx context.Context, event *domain.InboundEvent) error
+}
+
+// envelope is the body of the messages other shop-retail services publish.
+type envelope struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Source     string          `json:"source"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// AMQPConsumer binds a durable queue to the platform's topic exchange for every inbound
+// event type and hands the messages to a handler. Messages that fail permanently, or
+// fail again after a redelivery, go to the "<queue>.dead" queue through a dead letter
+// exchange, where they can be inspected and shovelled back.
+type AMQPConsumer struct {
+	url      string
+	exchange string
+	queue    string
+	prefetch int
+	handler  InboundEventHandler
+}
+
+func NewAMQPConsumer(cfg *config.Config, handler InboundEventHandler) *AMQPConsumer {
+	return &AMQPConsumer{
+		url:      cfg.RabbitMQURL,
+		exchange: cfg.RabbitMQExchange,
+		queue:    cfg.RabbitMQQueue,
+		prefetch: cfg.RabbitMQPrefetch,
+		handler:  handler,
+	}
+}
+
+// Run consumes until ctx is cancelled and reconnects when the connection drops. On
+// cancellation it stops taking messages and returns once the message in hand has been
+// acknowledged; prefetched messages go back to the queue.
+func (c *AMQPConsumer) Run(ctx context.Context) error {
+	for {
+		err := c.consume(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		slog.Log(ctx, slog.LevelError, "AMQP consumer stopped, reconnecting", "queue", c.queue, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (c *AMQPConsumer) consume(ctx context.Context) error {
+	conn, err := amqp.Dial(c.url)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer ch.Close()
+
+	if err := c.declare(ch); err != nil {
+		return err
+	}
+	if err := ch.Qos(c.prefetch, 0, false); err != nil {
+		return fmt.Errorf("failed to set prefetch: %w", err)
+	}
+
+	deliveries, err := ch.Consume(c.queue, consumerTag, false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start consuming: %w", err)
+	}
+	closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	slog.Log(ctx, slog.LevelInfo, "AMQP consumer started", "queue", c.queue)
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := ch.Cancel(consumerTag, false); err != nil {
+				slog.Log(ctx, slog.LevelWarn, "Failed to cancel AMQP consumer", "error", err)
+			}
+			return nil
+		case amqpErr := <-closed:
+			return fmt.Errorf("connection closed: %v", amqpErr)
+		case d, ok := <-deliveries:
+			if !ok {
+				return errors.New("delivery channel closed")
+			}
+			c.process(ctx, d)
+		}
+	}
+}
+
+// declare sets up the exchange, the queue with its bindings and the dead letter queue.
+// All declarations are idempotent.
+func (c *AMQPConsumer) declare(ch *amqp.Channel) error {
+	deadLetterExchange := c.queue + ".dlx"
+	deadLetterQueue := c.queue + ".dead"
+
+	if err := ch.ExchangeDeclare(c.exchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare exchange: %w", err)
+	}
+	if err := ch.ExchangeDeclare(deadLetterExchange, amqp.ExchangeFanout, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare dead letter exchange: %w", err)
+	}
+	if _, err := ch.QueueDeclare(deadLetterQueue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare dead letter queue: %w", err)
+	}
+	if err := ch.QueueBind(deadLetterQueue, "", deadLetterExchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind dead letter queue: %w", err)
+	}
+
+	args := amqp.Table{"x-dead-letter-exchange": deadLetterExchange}
+	if _, err := ch.QueueDeclare(c.queue, true, false, false, false, args); err != nil {
+		return fmt.Errorf("failed to declare queue: %w", err)
+	}
+	for _, eventType := range domain.InboundEventTypes {
+		if err := ch.QueueBind(c.queue, string(eventType), c.exchange, false, nil); err != nil {
+			return fmt.Errorf("failed to bind queue to %s: %w", eventType, err)
+		}
+	}
+
+	return nil
+}
+
+// process acknowledges a handled message. A failed one is requeued once for errors
+// that may be temporary and dead-lettered otherwise.
+func (c *AMQPConsumer) process(ctx context.Context, d amqp.Delivery) {
+	handleCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), handleTimeout)
+	defer cancel()
+
+	event, err := decode(d)
+	if err == nil {
+		err = c.handler.Handle(handleCtx, event)
+	}
+
+	var ackErr error
+	switch {
+	case err == nil:
+		ackErr = d.Ack(false)
+	case isPermanent(err) || d.Redelivered:
+		slog.Log(ctx, slog.LevelError, "Dead-lettered inbound event", "messageID", d.MessageId, "routingKey", d.RoutingKey, "redelivered", d.Redelivered, "error", err)
+		ackErr = d.Nack(false, false)
+	default:
+		slog.Log(ctx, slog.LevelWarn, "Requeued inbound event", "messageID", d.MessageId, "routingKey", d.RoutingKey, "error", err)
+		ackErr = d.Nack(false, true)
+	}
+	if ackErr != nil {
+		slog.Log(ctx, slog.LevelError, "Failed to acknowledge inbound event", "messageID", d.MessageId, "error", ackErr)
+	}
+}
+
+// decode reads the envelope of a message. The message ID and routing key stand in for
+// an ID or type missing from the body.
+func decode(d amqp.Delivery) (*domain.InboundEvent, error) {
+	var env envelope
+	if err := json.Unmarshal(d.Body, &env); err != nil {
+		return nil, fmt.Errorf("%w: %w", usecase.InvalidInboundEventError, err)
+	}
+
+	if env.ID == "" {
+		env.ID = d.MessageId
+	}
+	if env.Type == "" {
+		env.Type = d.RoutingKey
+	}
+	if env.Source == "" {
+		env.Source = d.AppId
+	}
+	if env.OccurredAt.IsZero() {
+		env.OccurredAt = d.Timestamp
+	}
+
+	return &domain.InboundEvent{
+		ID:         env.ID,
+		Type:       domain.InboundEventType(env.Type),
+		Source:     env.Source,
+		OccurredAt: env.OccurredAt,
+		Data:       env.Data,
+	}, nil
+}
+
+func isPermanent(err error) bool {
+	return errors.Is(err, usecase.UnknownInboundEventError) || errors.Is(err, usecase.InvalidInboundEventError)
+}
@@ -0,0 +1,47 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]`)
+
+// FileSender writes every notification as an .eml file into a directory, for local
+// development without a mail server.
+type FileSender struct {
+	dir  string
+	from string
+}
+
+func NewFileSender(dir string, from string) (*FileSender, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create notification directory: %w", err)
+	}
+
+	return &FileSender{
+		dir:  dir,
+		from: from,
+	}, nil
+}
+
+func (s *FileSender) Send(ctx context.Context, n domain.MailMessage) error {
+	now := time.Now()
+	msg, err := buildMessage(s.from, n, now)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000"), unsafeFileChars.ReplaceAllString(n.To, "_"))
+	if err := os.WriteFile(filepath.Join(s.dir, name), msg, 0o640); err != nil {
+		return fmt.Errorf("failed to write notification: %w", err)
+	}
+
+	return nil
+}
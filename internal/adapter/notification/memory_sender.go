@@ -0,0 +1,36 @@
+package notification
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+// MemorySender keeps notifications in memory instead of delivering them, for tests and
+// development.
+type MemorySender struct {
+	mu   sync.Mutex
+	sent []domain.MailMessage
+}
+
+func NewMemorySender() *MemorySender {
+	return &MemorySender{}
+}
+
+func (s *MemorySender) Send(ctx context.Context, n domain.MailMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sent = append(s.sent, n)
+	return nil
+}
+
+// Sent returns a copy of the notifications sent so far, oldest first.
+func (s *MemorySender) Sent() []domain.MailMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.sent)
+}
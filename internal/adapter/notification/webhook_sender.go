@@ -0,0 +1,124 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+// sharedAddressSpace is the carrier-grade NAT range, which netip does not count as private
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// HTTPWebhookSender posts JSON payloads to webhook URLs, e.g. of employees or
+// integration partners. Any status other than 2xx counts as a failed attempt.
+type HTTPWebhookSender struct {
+	client       *http.Client
+	allowPrivate bool
+}
+
+// NewHTTPWebhookSender returns a sender that only reaches public addresses, so a webhook
+// URL cannot be used to call services on the internal network or the cloud metadata
+// endpoint. allowPrivate lifts that, e.g. for partners hosted on the internal network.
+func NewHTTPWebhookSender(timeout time.Duration, allowPrivate bool) *HTTPWebhookSender {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		// Checked on the address actually dialled, so a host that resolved to a public
+		// address when the URL was saved cannot be rebound to an internal one later
+		dialer.Control = refusePrivateAddress
+	}
+
+	return &HTTPWebhookSender{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				// No proxy from the environment: it would dial on our behalf and skip the check
+				Proxy:               nil,
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			// A redirect could point the request somewhere the employee did not choose
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		allowPrivate: allowPrivate,
+	}
+}
+
+// CheckTarget resolves the host of rawURL and refuses it when any of its addresses is
+// not public, so a bad URL is rejected when it is saved rather than on every delivery.
+func (s *HTTPWebhookSender) CheckTarget(ctx context.Context, rawURL string) error {
+	if s.allowPrivate {
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse webhook URL: %w", err)
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return fmt.Errorf("failed to resolve webhook host: %w", err)
+	}
+	for _, addr := range addrs {
+		if !isPublicAddress(addr) {
+			return domain.ErrPrivateWebhookTarget
+		}
+	}
+
+	return nil
+}
+
+func (s *HTTPWebhookSender) Post(ctx context.Context, url string, payload []byte, headers map[string]string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "shop-retail-employee-service")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func refusePrivateAddress(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("failed to parse dialled address: %w", err)
+	}
+	if !isPublicAddress(addrPort.Addr()) {
+		return domain.ErrPrivateWebhookTarget
+	}
+	return nil
+}
+
+// isPublicAddress reports whether addr is routable on the internet. Loopback, private,
+// link-local (e.g. 169.254.169.254), multicast and unspecified addresses are not.
+func isPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
@@ -0,0 +1,61 @@
+package notification_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/notification"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+func TestHTTPWebhookSender_PrivateTargets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	t.Run("Refuses Loopback At Dial Time", func(t *testing.T) {
+		sender := notification.NewHTTPWebhookSender(time.Second, false)
+
+		_, err := sender.Post(context.Background(), server.URL, []byte(`{}`), nil)
+
+		assert.ErrorIs(t, err, domain.ErrPrivateWebhookTarget)
+	})
+
+	t.Run("Refuses Metadata Endpoint", func(t *testing.T) {
+		sender := notification.NewHTTPWebhookSender(time.Second, false)
+
+		err := sender.CheckTarget(context.Background(), "http://169.254.169.254/latest/meta-data")
+
+		assert.ErrorIs(t, err, domain.ErrPrivateWebhookTarget)
+	})
+
+	t.Run("Refuses Private Address", func(t *testing.T) {
+		sender := notification.NewHTTPWebhookSender(time.Second, false)
+
+		err := sender.CheckTarget(context.Background(), "https://10.0.0.5:8443/hooks")
+
+		assert.ErrorIs(t, err, domain.ErrPrivateWebhookTarget)
+	})
+
+	t.Run("Allows Public Address", func(t *testing.T) {
+		sender := notification.NewHTTPWebhookSender(time.Second, false)
+
+		err := sender.CheckTarget(context.Background(), "https://203.0.113.10/hooks")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Allow Private Reaches Loopback", func(t *testing.T) {
+		sender := notification.NewHTTPWebhookSender(time.Second, true)
+
+		status, err := sender.Post(context.Background(), server.URL, []byte(`{}`), nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, status)
+	})
+}
@@ -1,7 +1,7 @@
 package repo
 
-import "errors"
+import "github.com/zuyatna/shop-retail-employee-service/internal/domain"
 
 var (
-	ErrEmployeeNotFound = errors.New("employee not found")
+	ErrEmployeeNotFound = domain.ErrEmployeeNotFound
 )
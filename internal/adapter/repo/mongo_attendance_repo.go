@@ -8,28 +8,158 @@ import (
 	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type MongoAttendanceRepo struct {
 	collection *mongo.Collection
+	location   *time.Location
 }
 
-func NewMongoAttendanceRepo(db *mongo.Database) *MongoAttendanceRepo {
+// NewMongoAttendanceRepo returns the attendance repository. location is the time zone
+// the check-in and check-out times of documents written before work sessions existed
+// were recorded in.
+func NewMongoAttendanceRepo(db *mongo.Database, location *time.Location) *MongoAttendanceRepo {
 	return &MongoAttendanceRepo{
 		collection: db.Collection("employee_attendances"),
+		location:   location,
 	}
 }
 
+// attendanceModel is the document of one employee and day. CheckIn, CheckOut, Location
+// and IsLate summarise Sessions so reports can query them without unwinding the array.
 type attendanceModel struct {
-	ID           string    `bson:"_id,omitempty" json:"id"`
-	EmployeeID   string    `bson:"employee_id" json:"employee_id"`
-	EmployeeName string    `bson:"employee_name" json:"employee_name"`
-	Location     string    `bson:"location" json:"location"`
-	CheckIn      string    `bson:"check_in" json:"check_in"`
-	CheckOut     *string   `bson:"check_out,omitempty" json:"check_out,omitempty"`
-	IsLate       bool      `bson:"is_late" json:"is_late"`
-	Date         time.Time `bson:"date" json:"date"`
-	UpdatedAt    time.Time `bson:"updated_at" json:"updated_at"`
+	ID           string                   `bson:"_id,omitempty" json:"id"`
+	EmployeeID   string                   `bson:"employee_id" json:"employee_id"`
+	EmployeeName string                   `bson:"employee_name" json:"employee_name"`
+	Location     string                   `bson:"location" json:"location"`
+	CheckIn      string                   `bson:"check_in" json:"check_in"`
+	CheckOut     *string                  `bson:"check_out,omitempty" json:"check_out,omitempty"`
+	IsLate       bool                     `bson:"is_late" json:"is_late"`
+	Date         time.Time                `bson:"date" json:"date"`
+	Sessions     []attendanceSessionModel `bson:"sessions,omitempty" json:"sessions,omitempty"`
+	UpdatedAt    time.Time                `bson:"updated_at" json:"updated_at"`
+	// Version is missing on documents written before punches were versioned
+	Version int64 `bson:"version" json:"-"`
+
+	// Documents written before work sessions existed keep their only session here
+	CheckInPhoto  string `bson:"check_in_photo,omitempty" json:"check_in_photo,omitempty"`
+	CheckOutPhoto string `bson:"check_out_photo,omitempty" json:"check_out_photo,omitempty"`
+	KioskID       string `bson:"kiosk_id,omitempty" json:"kiosk_id,omitempty"`
+
+	MissedCheckOutNotifiedAt *time.Time `bson:"missed_check_out_notified_at,omitempty" json:"-"`
+	// MissedCheckOutSession is the number of the session the notification was about
+	MissedCheckOutSession int `bson:"missed_check_out_session,omitempty" json:"-"`
+}
+
+type attendanceSessionModel struct {
+	CheckIn       time.Time              `bson:"check_in" json:"check_in"`
+	CheckOut      *time.Time             `bson:"check_out,omitempty" json:"check_out,omitempty"`
+	Location      string                 `bson:"location" json:"location"`
+	CheckInPhoto  string                 `bson:"check_in_photo,omitempty" json:"check_in_photo,omitempty"`
+	CheckOutPhoto string                 `bson:"check_out_photo,omitempty" json:"check_out_photo,omitempty"`
+	KioskID       string                 `bson:"kiosk_id,omitempty" json:"kiosk_id,omitempty"`
+	Breaks        []attendanceBreakModel `bson:"breaks,omitempty" json:"breaks,omitempty"`
+}
+
+type attendanceBreakModel struct {
+	Start time.Time  `bson:"start" json:"start"`
+	End   *time.Time `bson:"end,omitempty" json:"end,omitempty"`
+}
+
+func (m attendanceModel) toDomain(location *time.Location) *domain.Attendance {
+	attendance := &domain.Attendance{
+		ID:           m.ID,
+		EmployeeID:   m.EmployeeID,
+		EmployeeName: m.EmployeeName,
+		Location:     m.Location,
+		CheckIn:      m.CheckIn,
+		CheckOut:     m.CheckOut,
+		IsLate:       m.IsLate,
+		Date:         m.Date,
+		Sessions:     make([]domain.WorkSession, 0, len(m.Sessions)),
+		Version:      m.Version,
+	}
+
+	for _, s := range m.Sessions {
+		session := domain.WorkSession{
+			CheckIn:       s.CheckIn.In(location),
+			CheckOut:      inLocation(s.CheckOut, location),
+			Location:      s.Location,
+			CheckInPhoto:  s.CheckInPhoto,
+			CheckOutPhoto: s.CheckOutPhoto,
+			KioskID:       s.KioskID,
+			Breaks:        make([]domain.Break, 0, len(s.Breaks)),
+		}
+		for _, b := range s.Breaks {
+			session.Breaks = append(session.Breaks, domain.Break{Start: b.Start.In(location), End: inLocation(b.End, location)})
+		}
+		attendance.Sessions = append(attendance.Sessions, session)
+	}
+
+	if len(m.Sessions) == 0 {
+		attendance.Sessions = append(attendance.Sessions, m.legacySession(location)...)
+	}
+
+	return attendance
+}
+
+// legacySession turns the single check-in and check-out of a document written before
+// work sessions existed into its session. The next update stores it as such.
+func (m attendanceModel) legacySession(location *time.Location) []domain.WorkSession {
+	checkIn, err := time.ParseInLocation(time.DateTime, m.CheckIn, location)
+	if err != nil {
+		return nil
+	}
+
+	session := domain.WorkSession{
+		CheckIn:       checkIn,
+		Location:      m.Location,
+		CheckInPhoto:  m.CheckInPhoto,
+		CheckOutPhoto: m.CheckOutPhoto,
+		KioskID:       m.KioskID,
+	}
+	if m.CheckOut != nil {
+		if checkOut, err := time.ParseInLocation(time.DateTime, *m.CheckOut, location); err == nil {
+			session.CheckOut = &checkOut
+		}
+	}
+	return []domain.WorkSession{session}
+}
+
+func inLocation(t *time.Time, location *time.Location) *time.Time {
+	if t == nil {
+		return nil
+	}
+	in := t.In(location)
+	return &in
+}
+
+func toSessionModels(sessions []domain.WorkSession) []attendanceSessionModel {
+	models := make([]attendanceSessionModel, 0, len(sessions))
+	for _, s := range sessions {
+		model := attendanceSessionModel{
+			CheckIn:       s.CheckIn,
+			CheckOut:      s.CheckOut,
+			Location:      s.Location,
+			CheckInPhoto:  s.CheckInPhoto,
+			CheckOutPhoto: s.CheckOutPhoto,
+			KioskID:       s.KioskID,
+		}
+		for _, b := range s.Breaks {
+			model.Breaks = append(model.Breaks, attendanceBreakModel{Start: b.Start, End: b.End})
+		}
+		models = append(models, model)
+	}
+	return models
+}
+
+func (r *MongoAttendanceRepo) toDomainAttendances(models []attendanceModel) []*domain.Attendance {
+	attendances := make([]*domain.Attendance, 0, len(models))
+	for _, model := range models {
+		attendances = append(attendances, model.toDomain(r.location))
+	}
+	return attendances
 }
 
 func (r *MongoAttendanceRepo) Save(ctx context.Context, attendance *domain.Attendance) error {
@@ -42,6 +172,7 @@ func (r *MongoAttendanceRepo) Save(ctx context.Context, attendance *domain.Atten
 		CheckOut:     attendance.CheckOut,
 		IsLate:       attendance.IsLate,
 		Date:         attendance.Date,
+		Sessions:     toSessionModels(attendance.Sessions),
 		UpdatedAt:    time.Now(),
 	}
 
@@ -49,17 +180,33 @@ func (r *MongoAttendanceRepo) Save(ctx context.Context, attendance *domain.Atten
 	return err
 }
 
+// Update stores the sessions of the day and the check-out summarising them. It fails with
+// domain.ErrAttendanceConflict when the day was updated since it was read, so a punch
+// never overwrites another one made in between.
 func (r *MongoAttendanceRepo) Update(ctx context.Context, attendance *domain.Attendance) error {
-	filter := bson.M{"_id": attendance.ID}
+	filter := bson.M{"_id": attendance.ID, "version": attendance.Version}
+	if attendance.Version == 0 {
+		filter["version"] = bson.M{"$in": bson.A{0, nil}} // nil matches unversioned documents
+	}
 	update := bson.M{
 		"$set": bson.M{
 			"check_out":  attendance.CheckOut,
+			"sessions":   toSessionModels(attendance.Sessions),
 			"updated_at": time.Now(),
 		},
+		"$inc": bson.M{"version": 1},
 	}
 
-	_, err := r.collection.UpdateOne(ctx, filter, update)
-	return err
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrAttendanceConflict
+	}
+
+	attendance.Version++
+	return nil
 }
 
 func (r *MongoAttendanceRepo) FindByEmployeeIDAndDate(ctx context.Context, employeeID string, date time.Time) (*domain.Attendance, error) {
@@ -77,15 +224,143 @@ func (r *MongoAttendanceRepo) FindByEmployeeIDAndDate(ctx context.Context, emplo
 		return nil, err
 	}
 
-	// Convert to domain.Attendance
-	return &domain.Attendance{
-		ID:           model.ID,
-		EmployeeID:   model.EmployeeID,
-		EmployeeName: model.EmployeeName,
-		Location:     model.Location,
-		CheckIn:      model.CheckIn,
-		CheckOut:     model.CheckOut,
-		IsLate:       model.IsLate,
-		Date:         model.Date,
-	}, nil
+	return model.toDomain(r.location), nil
+}
+
+// FindByEmployeeID returns all attendance records of an employee, oldest first.
+func (r *MongoAttendanceRepo) FindByEmployeeID(ctx context.Context, employeeID string) ([]*domain.Attendance, error) {
+	filter := bson.M{"employee_id": employeeID}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var models []attendanceModel
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, err
+	}
+
+	return r.toDomainAttendances(models), nil
+}
+
+// FindByEmployeeIDBetween returns the attendance records of an employee dated in
+// [from, to), oldest first.
+func (r *MongoAttendanceRepo) FindByEmployeeIDBetween(ctx context.Context, employeeID string, from time.Time, to time.Time) ([]*domain.Attendance, error) {
+	filter := bson.M{
+		"employee_id": employeeID,
+		"date":        bson.M{"$gte": from, "$lt": to},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var models []attendanceModel
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, err
+	}
+
+	return r.toDomainAttendances(models), nil
+}
+
+// PseudonymizeEmployee replaces the employee reference and name on all attendance documents
+// of the employee and drops the references to their photos. Documents stay grouped under the
+// pseudonym so aggregate reports still work.
+func (r *MongoAttendanceRepo) PseudonymizeEmployee(ctx context.Context, employeeID string, pseudonym string) (int64, error) {
+	// Array updates fail on documents without the array, so sessions go first and alone
+	withSessions := bson.M{"employee_id": employeeID, "sessions": bson.M{"$exists": true}}
+	dropSessionPhotos := bson.M{
+		"$unset": bson.M{
+			"sessions.$[].check_in_photo":  "",
+			"sessions.$[].check_out_photo": "",
+		},
+		// A punch read before this update must not bring the photos back
+		"$inc": bson.M{"version": 1},
+	}
+	if _, err := r.collection.UpdateMany(ctx, withSessions, dropSessionPhotos); err != nil {
+		return 0, err
+	}
+
+	filter := bson.M{"employee_id": employeeID}
+	update := bson.M{
+		"$set": bson.M{
+			"employee_id":   pseudonym,
+			"employee_name": "",
+			"updated_at":    time.Now(),
+		},
+		"$unset": bson.M{
+			"check_in_photo":  "",
+			"check_out_photo": "",
+		},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (r *MongoAttendanceRepo) CountByEmployeeID(ctx context.Context, employeeID string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"employee_id": employeeID})
+}
+
+// DeleteByEmployeeID permanently removes all attendance documents of the employee.
+func (r *MongoAttendanceRepo) DeleteByEmployeeID(ctx context.Context, employeeID string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"employee_id": employeeID})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
+// FindMissingCheckOut returns the days dated in [from, to) whose last session was never
+// checked out and was not reported yet. A day reported for an earlier session comes back
+// once a later session was left open, e.g. after a kiosk synced it.
+func (r *MongoAttendanceRepo) FindMissingCheckOut(ctx context.Context, from time.Time, to time.Time) ([]*domain.Attendance, error) {
+	sessions := bson.M{"$size": bson.M{"$ifNull": bson.A{"$sessions", bson.A{}}}}
+	filter := bson.M{
+		"date":      bson.M{"$gte": from, "$lt": to},
+		"check_out": nil, // missing or null
+		"$or": bson.A{
+			bson.M{"missed_check_out_notified_at": bson.M{"$exists": false}},
+			// Days reported before the session was recorded count as reported for the last one
+			bson.M{"$expr": bson.M{"$lt": bson.A{bson.M{"$ifNull": bson.A{"$missed_check_out_session", sessions}}, sessions}}},
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var models []attendanceModel
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, err
+	}
+
+	return r.toDomainAttendances(models), nil
+}
+
+func (r *MongoAttendanceRepo) MarkMissedCheckOutNotified(ctx context.Context, id string, session int) error {
+	filter := bson.M{"_id": id}
+	update := bson.M{
+		"$set": bson.M{
+			"missed_check_out_notified_at": time.Now(),
+			"missed_check_out_session":     session,
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
 }
@@ -0,0 +1,144 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/repo/record"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type PostgresDataExportRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresDataExportRepo(pool *pgxpool.Pool) *PostgresDataExportRepo {
+	return &PostgresDataExportRepo{
+		pool: pool,
+	}
+}
+
+func (r *PostgresDataExportRepo) Save(ctx context.Context, export *domain.DataExport) error {
+	rec := record.DataExportFromDomain(export)
+
+	query := `
+		INSERT INTO data_exports (
+			id, employee_id, requested_by, status, file_url, size, error,
+			created_at, completed_at, expires_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7,
+			$8, $9, $10
+		)
+		ON CONFLICT (id) DO UPDATE
+		SET status = EXCLUDED.status,
+		    file_url = EXCLUDED.file_url,
+		    size = EXCLUDED.size,
+		    error = EXCLUDED.error,
+		    completed_at = EXCLUDED.completed_at,
+		    expires_at = EXCLUDED.expires_at
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		rec.ID, rec.EmployeeID, rec.RequestedBy, rec.Status, rec.FileURL, rec.Size, rec.Error,
+		rec.CreatedAt, rec.CompletedAt, rec.ExpiresAt,
+	)
+
+	return err
+}
+
+func (r *PostgresDataExportRepo) FindByID(ctx context.Context, id string) (*domain.DataExport, error) {
+	query := `
+		SELECT id, employee_id, requested_by, status, file_url, size, error,
+		       created_at, completed_at, expires_at
+		FROM data_exports
+		WHERE id = $1
+	`
+
+	rows, _ := r.pool.Query(ctx, query, id)
+
+	rec, err := pgx.CollectOneRow(rows, pgx.RowToStructByNameLax[record.DataExportRecord])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to find data export: %w", err)
+	}
+
+	return rec.ToDomain(), nil
+}
+
+// FindByEmployeeID returns the exports of an employee, newest first.
+func (r *PostgresDataExportRepo) FindByEmployeeID(ctx context.Context, employeeID string) ([]*domain.DataExport, error) {
+	query := `
+		SELECT id, employee_id, requested_by, status, file_url, size, error,
+		       created_at, completed_at, expires_at
+		FROM data_exports
+		WHERE employee_id = $1
+		ORDER BY created_at DESC
+	`
+
+	return r.collect(ctx, query, employeeID)
+}
+
+func (r *PostgresDataExportRepo) ClaimNextPending(ctx context.Context) (*domain.DataExport, error) {
+	query := `
+		UPDATE data_exports
+		SET status = 'processing'
+		WHERE id = (
+			SELECT id FROM data_exports
+			WHERE status = 'pending'
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, employee_id, requested_by, status, file_url, size, error,
+		          created_at, completed_at, expires_at
+	`
+
+	rows, _ := r.pool.Query(ctx, query)
+
+	rec, err := pgx.CollectOneRow(rows, pgx.RowToStructByNameLax[record.DataExportRecord])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // Nothing pending
+		}
+		return nil, fmt.Errorf("failed to claim data export: %w", err)
+	}
+
+	return rec.ToDomain(), nil
+}
+
+func (r *PostgresDataExportRepo) FindExpired(ctx context.Context, now time.Time) ([]*domain.DataExport, error) {
+	query := `
+		SELECT id, employee_id, requested_by, status, file_url, size, error,
+		       created_at, completed_at, expires_at
+		FROM data_exports
+		WHERE status = 'ready' AND expires_at < $1
+	`
+
+	return r.collect(ctx, query, now.UTC())
+}
+
+func (r *PostgresDataExportRepo) collect(ctx context.Context, query string, args ...any) ([]*domain.DataExport, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query data exports: %w", err)
+	}
+	defer rows.Close()
+
+	records, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[record.DataExportRecord])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect data exports: %w", err)
+	}
+
+	exports := make([]*domain.DataExport, 0, len(records))
+	for _, rec := range records {
+		exports = append(exports, rec.ToDomain())
+	}
+
+	return exports, nil
+}
@@ -0,0 +1,152 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/repo/record"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type PostgresDocumentRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresDocumentRepo(pool *pgxpool.Pool) *PostgresDocumentRepo {
+	return &PostgresDocumentRepo{
+		pool: pool,
+	}
+}
+
+// Create numbers the document after the highest version of its type, deleted versions
+// included. Two concurrent uploads of the same type collide on the unique version and
+// one of them fails.
+func (r *PostgresDocumentRepo) Create(ctx context.Context, document *domain.Document) error {
+	rec := record.DocumentFromDomain(document)
+
+	query := `
+		INSERT INTO employee_documents (
+			id, employee_id, type, version, file_name, content_type, size,
+			storage_key, expires_at, uploaded_by, uploaded_at
+		) VALUES (
+			$1, $2, $3,
+			(SELECT COALESCE(MAX(version), 0) + 1 FROM employee_documents WHERE employee_id = $2 AND type = $3),
+			$4, $5, $6, $7, $8, $9, $10
+		)
+		RETURNING version
+	`
+
+	err := r.pool.QueryRow(ctx, query,
+		rec.ID, rec.EmployeeID, rec.Type, rec.FileName, rec.ContentType, rec.Size,
+		rec.StorageKey, rec.ExpiresAt, rec.UploadedBy, rec.UploadedAt,
+	).Scan(&document.Version)
+	if err != nil {
+		return fmt.Errorf("failed to insert document: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresDocumentRepo) FindByID(ctx context.Context, id string) (*domain.Document, error) {
+	query := `
+		SELECT id, employee_id, type, version, file_name, content_type, size,
+		       storage_key, expires_at, uploaded_by, uploaded_at, reminder_window
+		FROM employee_documents
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	rows, _ := r.pool.Query(ctx, query, id)
+
+	rec, err := pgx.CollectOneRow(rows, pgx.RowToStructByNameLax[record.DocumentRecord])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to find document: %w", err)
+	}
+
+	return rec.ToDomain(), nil
+}
+
+func (r *PostgresDocumentRepo) FindByEmployeeID(ctx context.Context, employeeID string) ([]*domain.Document, error) {
+	query := `
+		SELECT id, employee_id, type, version, file_name, content_type, size,
+		       storage_key, expires_at, uploaded_by, uploaded_at, reminder_window
+		FROM employee_documents
+		WHERE employee_id = $1 AND deleted_at IS NULL
+		ORDER BY type, version DESC
+	`
+
+	return r.collect(ctx, query, employeeID)
+}
+
+// FindCurrentExpiringBefore returns the latest versions that expire before the given date,
+// expired ones included, of employees that are not deleted.
+func (r *PostgresDocumentRepo) FindCurrentExpiringBefore(ctx context.Context, before time.Time) ([]*domain.Document, error) {
+	query := `
+		SELECT d.id, d.employee_id, d.type, d.version, d.file_name, d.content_type, d.size,
+		       d.storage_key, d.expires_at, d.uploaded_by, d.uploaded_at, d.reminder_window
+		FROM employee_documents d
+		JOIN employees e ON e.id = d.employee_id AND e.deleted_at IS NULL
+		WHERE d.deleted_at IS NULL
+		  AND d.expires_at < $1
+		  AND d.version = (
+		      SELECT MAX(c.version) FROM employee_documents c
+		      WHERE c.employee_id = d.employee_id AND c.type = d.type AND c.deleted_at IS NULL
+		  )
+		ORDER BY d.expires_at, d.employee_id, d.type
+	`
+
+	return r.collect(ctx, query, before.UTC())
+}
+
+func (r *PostgresDocumentRepo) MarkReminded(ctx context.Context, id string, window int) error {
+	query := `UPDATE employee_documents SET reminder_window = $2 WHERE id = $1`
+
+	if _, err := r.pool.Exec(ctx, query, id, window); err != nil {
+		return fmt.Errorf("failed to mark document reminded: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresDocumentRepo) MarkDeleted(ctx context.Context, id string, deletedAt time.Time) error {
+	query := `UPDATE employee_documents SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`
+
+	if _, err := r.pool.Exec(ctx, query, id, deletedAt.UTC()); err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresDocumentRepo) DeleteByEmployeeID(ctx context.Context, employeeID string) error {
+	query := `DELETE FROM employee_documents WHERE employee_id = $1`
+
+	if _, err := r.pool.Exec(ctx, query, employeeID); err != nil {
+		return fmt.Errorf("failed to delete documents: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresDocumentRepo) collect(ctx context.Context, query string, args ...any) ([]*domain.Document, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query documents: %w", err)
+	}
+	defer rows.Close()
+
+	records, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[record.DocumentRecord])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect documents: %w", err)
+	}
+
+	documents := make([]*domain.Document, 0, len(records))
+	for _, rec := range records {
+		documents = append(documents, rec.ToDomain())
+	}
+
+	return documents, nil
+}
@@ -0,0 +1,81 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/repo/record"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type PostgresEmailChangeRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresEmailChangeRepo(pool *pgxpool.Pool) *PostgresEmailChangeRepo {
+	return &PostgresEmailChangeRepo{
+		pool: pool,
+	}
+}
+
+func (r *PostgresEmailChangeRepo) Save(ctx context.Context, change *domain.EmailChange) error {
+	rec := record.EmailChangeFromDomain(change)
+
+	query := `
+		INSERT INTO email_changes (
+			id, employee_id, new_email, token_hash, requested_by,
+			created_at, expires_at, confirmed_at, cancelled_at
+		) VALUES (
+			$1, $2, $3, $4, $5,
+			$6, $7, $8, $9
+		)
+		ON CONFLICT (id) DO UPDATE
+		SET confirmed_at = EXCLUDED.confirmed_at,
+		    cancelled_at = EXCLUDED.cancelled_at
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		rec.ID, rec.EmployeeID, rec.NewEmail, rec.TokenHash, rec.RequestedBy,
+		rec.CreatedAt, rec.ExpiresAt, rec.ConfirmedAt, rec.CancelledAt,
+	)
+
+	return err
+}
+
+func (r *PostgresEmailChangeRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*domain.EmailChange, error) {
+	query := `
+		SELECT id, employee_id, new_email, token_hash, requested_by,
+		       created_at, expires_at, confirmed_at, cancelled_at
+		FROM email_changes
+		WHERE token_hash = $1
+	`
+
+	rows, _ := r.pool.Query(ctx, query, tokenHash)
+
+	rec, err := pgx.CollectOneRow(rows, pgx.RowToStructByNameLax[record.EmailChangeRecord])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to find email change: %w", err)
+	}
+
+	return rec.ToDomain(), nil
+}
+
+// CancelPending cancels the unconfirmed email changes of an employee, so only the most
+// recently sent token can be used.
+func (r *PostgresEmailChangeRepo) CancelPending(ctx context.Context, employeeID string, now time.Time) error {
+	query := `
+		UPDATE email_changes
+		SET cancelled_at = $2
+		WHERE employee_id = $1 AND confirmed_at IS NULL AND cancelled_at IS NULL
+	`
+
+	_, err := r.pool.Exec(ctx, query, employeeID, now.UTC())
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -12,33 +13,41 @@ import (
 )
 
 type PostgresEmployeeRepo struct {
-	pool *pgxpool.Pool
+	pool   *pgxpool.Pool
+	cipher *record.PIICipher
 }
 
-func NewPostgresEmployeeRepo(pool *pgxpool.Pool) *PostgresEmployeeRepo {
+func NewPostgresEmployeeRepo(pool *pgxpool.Pool, cipher *record.PIICipher) *PostgresEmployeeRepo {
 	return &PostgresEmployeeRepo{
-		pool: pool,
+		pool:   pool,
+		cipher: cipher,
 	}
 }
 
 func (r *PostgresEmployeeRepo) Save(ctx context.Context, employee *domain.Employee) error {
-	rec := record.FromDomain(employee)
+	rec, err := record.FromDomain(employee, r.cipher)
+	if err != nil {
+		return err
+	}
 
 	query := `
 		INSERT INTO employees (
 			id, name, email, password, role, position, salary, status,
-			birthdate, address, city, province, phone_number,
+			birthdate, address, city, province, phone_number, store_id,
+			phone_number_bidx, data_key, data_key_id,
 			created_at, updated_at
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8,
-			$9, $10, $11, $12, $13,
+			$9, $10, $11, $12, $13, $14,
+			$15, $16, $17,
 			NOW(), NOW()
 		)
 	`
 
-	_, err := r.pool.Exec(ctx, query,
+	_, err = r.pool.Exec(ctx, query,
 		rec.ID, rec.Name, rec.Email, rec.Password, rec.Role, rec.Position, rec.Salary, rec.Status,
-		rec.BirthDate, rec.Address, rec.City, rec.Province, rec.PhoneNumber,
+		rec.BirthDate, rec.Address, rec.City, rec.Province, rec.PhoneNumber, rec.StoreID,
+		rec.PhoneNumberIndex, rec.DataKey, rec.DataKeyID,
 	)
 
 	return err
@@ -47,8 +56,10 @@ func (r *PostgresEmployeeRepo) Save(ctx context.Context, employee *domain.Employ
 func (r *PostgresEmployeeRepo) FindByID(ctx context.Context, id string) (*domain.Employee, error) {
 	query := `
 		SELECT id, name, email, password, role, position, salary, status,
-		       birthdate, address, city, province, phone_number, photo,
-		       created_at, updated_at, deleted_at
+		       birthdate, address, city, province, phone_number, photo, store_id,
+		       phone_number_bidx, data_key, data_key_id,
+		       created_at, updated_at, deleted_at, anonymized_at,
+		       terminated_at, sessions_revoked_at, version
 		FROM employees
 		WHERE id = $1 AND deleted_at IS NULL
 	`
@@ -64,14 +75,16 @@ func (r *PostgresEmployeeRepo) FindByID(ctx context.Context, id string) (*domain
 		return nil, fmt.Errorf("failed to find employee: %w", err)
 	}
 
-	return rec.ToDomain()
+	return rec.ToDomain(r.cipher)
 }
 
 func (r *PostgresEmployeeRepo) FindByEmail(ctx context.Context, email string) (*domain.Employee, error) {
 	query := `
 		SELECT id, name, email, password, role, position, salary, status,
-		       birthdate, address, city, province, phone_number, photo,
-		       created_at, updated_at, deleted_at
+		       birthdate, address, city, province, phone_number, photo, store_id,
+		       phone_number_bidx, data_key, data_key_id,
+		       created_at, updated_at, deleted_at, anonymized_at,
+		       terminated_at, sessions_revoked_at, version
 		FROM employees
 		WHERE email = $1 AND deleted_at IS NULL
 	`
@@ -87,14 +100,16 @@ func (r *PostgresEmployeeRepo) FindByEmail(ctx context.Context, email string) (*
 		return nil, fmt.Errorf("failed to find employee by email: %w", err)
 	}
 
-	return rec.ToDomain()
+	return rec.ToDomain(r.cipher)
 }
 
 func (r *PostgresEmployeeRepo) FindAll(ctx context.Context) ([]*domain.Employee, error) {
 	query := `
 		SELECT id, name, email, password, role, position, salary, status,
-		       birthdate, address, city, province, phone_number, photo,
-		       created_at, updated_at, deleted_at
+		       birthdate, address, city, province, phone_number, photo, store_id,
+		       phone_number_bidx, data_key, data_key_id,
+		       created_at, updated_at, deleted_at, anonymized_at,
+		       terminated_at, sessions_revoked_at, version
 		FROM employees
 		WHERE deleted_at IS NULL
 	`
@@ -113,7 +128,7 @@ func (r *PostgresEmployeeRepo) FindAll(ctx context.Context) ([]*domain.Employee,
 
 	var employees []*domain.Employee
 	for _, rec := range records {
-		emp, err := rec.ToDomain()
+		emp, err := rec.ToDomain(r.cipher)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert record to domain: %w", err)
 		}
@@ -123,44 +138,408 @@ func (r *PostgresEmployeeRepo) FindAll(ctx context.Context) ([]*domain.Employee,
 	return employees, nil
 }
 
+// Update stores the employee if it is still at the version it was read with, and sets
+// the new version on the employee. A concurrent write results in domain.ErrVersionConflict.
 func (r *PostgresEmployeeRepo) Update(ctx context.Context, employee *domain.Employee) error {
-	rec := record.FromDomain(employee)
+	rec, err := record.FromDomain(employee, r.cipher)
+	if err != nil {
+		return err
+	}
 
 	query := `
 		UPDATE employees
 		SET name = $1, role = $2, position = $3, salary = $4, status = $5,
 		    birthdate = $6, address = $7, city = $8, province = $9,
-		    phone_number = $10, photo = $11,
-		    updated_at = NOW()
-		WHERE id = $12 AND deleted_at IS NULL
+		    phone_number = $10, photo = $11, store_id = $12,
+		    phone_number_bidx = $13, data_key = $14, data_key_id = $15,
+		    terminated_at = $16, sessions_revoked_at = $17, email = $18,
+		    version = version + 1, updated_at = NOW()
+		WHERE id = $19 AND version = $20 AND deleted_at IS NULL
+		RETURNING version
 	`
 
-	cmdTag, err := r.pool.Exec(ctx, query,
+	var version int64
+	err = r.pool.QueryRow(ctx, query,
 		rec.Name, rec.Role, rec.Position, rec.Salary, rec.Status,
 		rec.BirthDate, rec.Address, rec.City, rec.Province,
-		rec.PhoneNumber, rec.Photo,
-		rec.ID,
-	)
+		rec.PhoneNumber, rec.Photo, rec.StoreID,
+		rec.PhoneNumberIndex, rec.DataKey, rec.DataKeyID,
+		rec.TerminatedAt, rec.SessionsRevokedAt, rec.Email,
+		rec.ID, rec.Version,
+	).Scan(&version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return r.missedWriteError(ctx, rec.ID)
+		}
+		return err
+	}
+
+	employee.SetVersion(version)
+	return nil
+}
 
+// Delete stores a soft deleted employee: the row is kept with its deletion time and
+// revoked sessions, and hidden from the regular queries until it is restored, purged
+// or anonymised by the retention policy. Like Update it checks the version.
+func (r *PostgresEmployeeRepo) Delete(ctx context.Context, employee *domain.Employee) error {
+	rec, err := record.FromDomain(employee, r.cipher)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE employees
+		SET deleted_at = $1, sessions_revoked_at = $2,
+		    version = version + 1, updated_at = NOW()
+		WHERE id = $3 AND version = $4 AND deleted_at IS NULL
+		RETURNING version
+	`
+
+	var version int64
+	err = r.pool.QueryRow(ctx, query, rec.DeletedAt, rec.SessionsRevokedAt, rec.ID, rec.Version).Scan(&version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return r.missedWriteError(ctx, rec.ID)
+		}
+		return err
+	}
+
+	employee.SetVersion(version)
+	return nil
+}
+
+// missedWriteError explains why a versioned write matched no row: the employee is gone,
+// or it was changed since it was read.
+func (r *PostgresEmployeeRepo) missedWriteError(ctx context.Context, id string) error {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM employees WHERE id = $1 AND deleted_at IS NULL)`
+	if err := r.pool.QueryRow(ctx, query, id).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check employee: %w", err)
+	}
+
+	if exists {
+		return domain.ErrVersionConflict
+	}
+	return ErrEmployeeNotFound
+}
+
+// Restore clears the deletion time of a soft deleted employee. Email and phone number
+// are only unique among employees that are not deleted, so the caller checks them first.
+func (r *PostgresEmployeeRepo) Restore(ctx context.Context, employee *domain.Employee) error {
+	query := `
+		UPDATE employees
+		SET deleted_at = NULL, version = version + 1, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NOT NULL AND anonymized_at IS NULL
+		RETURNING version
+	`
+
+	var version int64
+	if err := r.pool.QueryRow(ctx, query, string(employee.ID())).Scan(&version); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrEmployeeNotFound
+		}
+		return err
+	}
+
+	employee.SetVersion(version)
+	return nil
+}
+
+// HardDelete permanently removes a soft deleted employee. Two factor enrolments, data
+// exports, status changes and email changes are removed by the foreign key cascade.
+func (r *PostgresEmployeeRepo) HardDelete(ctx context.Context, id string) error {
+	query := `DELETE FROM employees WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	cmdTag, err := r.pool.Exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
 
 	if cmdTag.RowsAffected() == 0 {
-		return errors.New("employee not found or deleted")
+		return ErrEmployeeNotFound
+	}
+
+	return nil
+}
+
+// FindDeleted returns the soft deleted employees, most recently deleted first.
+func (r *PostgresEmployeeRepo) FindDeleted(ctx context.Context) ([]*domain.Employee, error) {
+	query := `
+		SELECT id, name, email, password, role, position, salary, status,
+		       birthdate, address, city, province, phone_number, photo, store_id,
+		       phone_number_bidx, data_key, data_key_id,
+		       created_at, updated_at, deleted_at, anonymized_at,
+		       terminated_at, sessions_revoked_at, version
+		FROM employees
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deleted employees: %w", err)
+	}
+	defer rows.Close()
+
+	records, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[record.EmployeeRecord])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect employee records: %w", err)
+	}
+
+	var employees []*domain.Employee
+	for _, rec := range records {
+		emp, err := rec.ToDomain(r.cipher)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert record to domain: %w", err)
+		}
+		employees = append(employees, emp)
+	}
+
+	return employees, nil
+}
+
+// FindContactConflicts returns which of email and phone number ("email", "phone_number")
+// are already used by another employee that is not deleted.
+func (r *PostgresEmployeeRepo) FindContactConflicts(ctx context.Context, email string, phoneNumber string, excludeID string) ([]string, error) {
+	phoneIndex := r.cipher.PhoneNumberIndex(phoneNumber)
+
+	query := `
+		SELECT
+			EXISTS (SELECT 1 FROM employees WHERE email = $1 AND id <> $3 AND deleted_at IS NULL),
+			EXISTS (SELECT 1 FROM employees WHERE phone_number_bidx = $2 AND id <> $3 AND deleted_at IS NULL)
+	`
+
+	var emailTaken, phoneTaken bool
+	if err := r.pool.QueryRow(ctx, query, email, phoneIndex, excludeID).Scan(&emailTaken, &phoneTaken); err != nil {
+		return nil, fmt.Errorf("failed to check contact conflicts: %w", err)
+	}
+
+	var conflicts []string
+	if emailTaken {
+		conflicts = append(conflicts, "email")
+	}
+	if phoneTaken {
+		conflicts = append(conflicts, "phone_number")
+	}
+
+	return conflicts, nil
+}
+
+// FindPhotoKeys returns the stored photo keys, including those of soft deleted employees.
+func (r *PostgresEmployeeRepo) FindPhotoKeys(ctx context.Context) ([]string, error) {
+	query := `SELECT photo FROM employees WHERE photo IS NOT NULL AND photo <> ''`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query photo keys: %w", err)
+	}
+
+	keys, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect photo keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// FindByIDIncludingDeleted finds an employee by ID, including soft deleted ones.
+func (r *PostgresEmployeeRepo) FindByIDIncludingDeleted(ctx context.Context, id string) (*domain.Employee, error) {
+	query := `
+		SELECT id, name, email, password, role, position, salary, status,
+		       birthdate, address, city, province, phone_number, photo, store_id,
+		       phone_number_bidx, data_key, data_key_id,
+		       created_at, updated_at, deleted_at, anonymized_at,
+		       terminated_at, sessions_revoked_at, version
+		FROM employees
+		WHERE id = $1
+	`
+
+	rows, _ := r.pool.Query(ctx, query, id)
+
+	rec, err := pgx.CollectOneRow(rows, pgx.RowToStructByNameLax[record.EmployeeRecord])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrEmployeeNotFound
+		}
+		return nil, fmt.Errorf("failed to find employee: %w", err)
 	}
 
+	return rec.ToDomain(r.cipher)
+}
+
+// FindSessionState reads only the columns that decide whether the employee's tokens are
+// still accepted, so validating a session neither loads nor decrypts personal data.
+func (r *PostgresEmployeeRepo) FindSessionState(ctx context.Context, id string) (*domain.SessionState, error) {
+	query := `SELECT status, deleted_at, sessions_revoked_at FROM employees WHERE id = $1`
+
+	var status string
+	state := &domain.SessionState{}
+	err := r.pool.QueryRow(ctx, query, id).Scan(&status, &state.DeletedAt, &state.SessionsRevokedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find session state: %w", err)
+	}
+	state.Status = domain.Status(status)
+
+	return state, nil
+}
+
+// FindRetentionExpired returns employees that were terminated or soft deleted before the
+// given time and still hold personal data.
+func (r *PostgresEmployeeRepo) FindRetentionExpired(ctx context.Context, terminatedBefore time.Time) ([]*domain.Employee, error) {
+	query := `
+		SELECT id, name, email, password, role, position, salary, status,
+		       birthdate, address, city, province, phone_number, photo, store_id,
+		       phone_number_bidx, data_key, data_key_id,
+		       created_at, updated_at, deleted_at, anonymized_at,
+		       terminated_at, sessions_revoked_at, version
+		FROM employees
+		WHERE anonymized_at IS NULL
+		  AND ((status = 'terminated' AND terminated_at < $1) OR deleted_at < $1)
+		ORDER BY COALESCE(terminated_at, deleted_at)
+	`
+
+	rows, err := r.pool.Query(ctx, query, terminatedBefore.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired employees: %w", err)
+	}
+	defer rows.Close()
+
+	records, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[record.EmployeeRecord])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect employee records: %w", err)
+	}
+
+	var employees []*domain.Employee
+	for _, rec := range records {
+		emp, err := rec.ToDomain(r.cipher)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert record to domain: %w", err)
+		}
+		employees = append(employees, emp)
+	}
+
+	return employees, nil
+}
+
+// Anonymize stores an anonymised employee. Unlike Update it also applies to soft deleted rows.
+func (r *PostgresEmployeeRepo) Anonymize(ctx context.Context, employee *domain.Employee) error {
+	rec, err := record.FromDomain(employee, r.cipher)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE employees
+		SET name = $1, email = $2, password = $3, position = $4, salary = $5, status = $6,
+		    birthdate = $7, address = $8, city = $9, province = $10,
+		    phone_number = $11, photo = $12,
+		    phone_number_bidx = $13, data_key = $14, data_key_id = $15,
+		    anonymized_at = $16, terminated_at = $17, sessions_revoked_at = $18,
+		    deleted_at = $19,
+		    version = version + 1, updated_at = NOW()
+		WHERE id = $20
+		RETURNING version
+	`
+
+	var version int64
+	err = r.pool.QueryRow(ctx, query,
+		rec.Name, rec.Email, rec.Password, rec.Position, rec.Salary, rec.Status,
+		rec.BirthDate, rec.Address, rec.City, rec.Province,
+		rec.PhoneNumber, rec.Photo,
+		rec.PhoneNumberIndex, rec.DataKey, rec.DataKeyID,
+		rec.AnonymizedAt, rec.TerminatedAt, rec.SessionsRevokedAt,
+		rec.DeletedAt, rec.ID,
+	).Scan(&version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrEmployeeNotFound
+		}
+		return err
+	}
+
+	employee.SetVersion(version)
 	return nil
 }
 
-func (r *PostgresEmployeeRepo) Delete(ctx context.Context, id string) error {
+// ReencryptPII rewrites the PII columns of every employee whose data key is not wrapped
+// with the current key-encryption key, including plaintext rows from before encryption
+// and soft deleted rows. It processes batchSize rows at a time and returns the number
+// of rewritten rows.
+func (r *PostgresEmployeeRepo) ReencryptPII(ctx context.Context, batchSize int) (int, error) {
+	return r.rewritePII(ctx, batchSize, "data_key_id IS DISTINCT FROM $2", r.cipher.Keyring.CurrentID())
+}
+
+// EncryptPlaintextPII encrypts the rows written before PII encryption existed. Their
+// phone_number_bidx is empty until then, so phone uniqueness does not cover them; it is
+// run at startup before any request is served.
+func (r *PostgresEmployeeRepo) EncryptPlaintextPII(ctx context.Context, batchSize int) (int, error) {
+	return r.rewritePII(ctx, batchSize, "data_key_id IS NULL")
+}
+
+// rewritePII encrypts the rows matching condition with the current key-encryption key,
+// batchSize rows at a time. The condition's own arguments start at $2.
+func (r *PostgresEmployeeRepo) rewritePII(ctx context.Context, batchSize int, condition string, args ...any) (int, error) {
 	query := `
+		SELECT id, name, email, password, role, position, salary, status,
+		       birthdate, address, city, province, phone_number, photo, store_id,
+		       phone_number_bidx, data_key, data_key_id,
+		       created_at, updated_at, deleted_at, anonymized_at,
+		       terminated_at, sessions_revoked_at, version
+		FROM employees
+		WHERE ` + condition + `
+		ORDER BY id
+		LIMIT $1
+	`
+
+	update := `
 		UPDATE employees
-		SET deleted_at = NOW()
-		WHERE id = $1 AND deleted_at IS NULL
+		SET birthdate = $1, address = $2, phone_number = $3,
+		    phone_number_bidx = $4, data_key = $5, data_key_id = $6
+		WHERE id = $7 AND version = $8
 	`
 
-	_, err := r.pool.Exec(ctx, query, id)
+	total := 0
+	for {
+		rows, err := r.pool.Query(ctx, query, append([]any{batchSize}, args...)...)
+		if err != nil {
+			return total, fmt.Errorf("failed to query employees: %w", err)
+		}
 
-	return err
+		records, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[record.EmployeeRecord])
+		if err != nil {
+			return total, fmt.Errorf("failed to collect employee records: %w", err)
+		}
+		if len(records) == 0 {
+			return total, nil
+		}
+
+		for _, old := range records {
+			emp, err := old.ToDomain(r.cipher)
+			if err != nil {
+				return total, fmt.Errorf("failed to decrypt employee %s: %w", old.ID, err)
+			}
+
+			rec, err := record.FromDomain(emp, r.cipher)
+			if err != nil {
+				return total, fmt.Errorf("failed to encrypt employee %s: %w", old.ID, err)
+			}
+
+			// Only the ciphertexts change, so the version stays; a row written in the meantime
+			// is already encrypted with the current key
+			cmdTag, err := r.pool.Exec(ctx, update,
+				rec.BirthDate, rec.Address, rec.PhoneNumber,
+				rec.PhoneNumberIndex, rec.DataKey, rec.DataKeyID,
+				rec.ID, rec.Version,
+			)
+			if err != nil {
+				return total, fmt.Errorf("failed to update employee %s: %w", old.ID, err)
+			}
+			if cmdTag.RowsAffected() > 0 {
+				total++
+			}
+		}
+	}
 }
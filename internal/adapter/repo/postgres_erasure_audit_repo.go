@@ -0,0 +1,70 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/repo/record"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type PostgresErasureAuditRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresErasureAuditRepo(pool *pgxpool.Pool) *PostgresErasureAuditRepo {
+	return &PostgresErasureAuditRepo{
+		pool: pool,
+	}
+}
+
+func (r *PostgresErasureAuditRepo) Save(ctx context.Context, erasure *domain.ErasureRecord) error {
+	rec := record.ErasureFromDomain(erasure)
+
+	query := `
+		INSERT INTO erasure_audits (
+			id, employee_id, trigger, requested_by, reason,
+			erased_fields, attendance_records, photo_deleted, erased_at
+		) VALUES (
+			$1, $2, $3, $4, $5,
+			$6, $7, $8, $9
+		)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		rec.ID, rec.EmployeeID, rec.Trigger, rec.RequestedBy, rec.Reason,
+		rec.ErasedFields, rec.AttendanceRecords, rec.PhotoDeleted, rec.ErasedAt,
+	)
+
+	return err
+}
+
+func (r *PostgresErasureAuditRepo) FindByEmployeeID(ctx context.Context, employeeID string) ([]*domain.ErasureRecord, error) {
+	query := `
+		SELECT id, employee_id, trigger, requested_by, reason,
+		       erased_fields, attendance_records, photo_deleted, erased_at
+		FROM erasure_audits
+		WHERE employee_id = $1
+		ORDER BY erased_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query erasure audits: %w", err)
+	}
+	defer rows.Close()
+
+	records, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[record.ErasureRecord])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect erasure audits: %w", err)
+	}
+
+	erasures := make([]*domain.ErasureRecord, 0, len(records))
+	for _, rec := range records {
+		erasures = append(erasures, rec.ToDomain())
+	}
+
+	return erasures, nil
+}
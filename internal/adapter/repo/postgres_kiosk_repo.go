@@ -0,0 +1,205 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/repo/record"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type PostgresKioskRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresKioskRepo(pool *pgxpool.Pool) *PostgresKioskRepo {
+	return &PostgresKioskRepo{
+		pool: pool,
+	}
+}
+
+func (r *PostgresKioskRepo) Create(ctx context.Context, kiosk *domain.Kiosk) error {
+	rec := record.KioskFromDomain(kiosk)
+
+	query := `
+		INSERT INTO kiosks (id, store_id, name, token_hash, signing_key, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	if _, err := r.pool.Exec(ctx, query, rec.ID, rec.StoreID, rec.Name, rec.TokenHash, rec.SigningKey, rec.CreatedBy, rec.CreatedAt); err != nil {
+		return fmt.Errorf("failed to insert kiosk: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresKioskRepo) FindByID(ctx context.Context, id string) (*domain.Kiosk, error) {
+	query := `
+		SELECT id, store_id, name, token_hash, signing_key, created_by, created_at, revoked_at
+		FROM kiosks
+		WHERE id = $1
+	`
+
+	return r.findOne(ctx, query, id)
+}
+
+func (r *PostgresKioskRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*domain.Kiosk, error) {
+	query := `
+		SELECT id, store_id, name, token_hash, signing_key, created_by, created_at, revoked_at
+		FROM kiosks
+		WHERE token_hash = $1
+	`
+
+	return r.findOne(ctx, query, tokenHash)
+}
+
+func (r *PostgresKioskRepo) FindByStoreID(ctx context.Context, storeID string) ([]*domain.Kiosk, error) {
+	query := `
+		SELECT id, store_id, name, token_hash, signing_key, created_by, created_at, revoked_at
+		FROM kiosks
+		WHERE $1 = '' OR store_id = $1
+		ORDER BY store_id, created_at
+	`
+
+	rows, _ := r.pool.Query(ctx, query, storeID)
+
+	recs, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[record.KioskRecord])
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kiosks: %w", err)
+	}
+
+	kiosks := make([]*domain.Kiosk, 0, len(recs))
+	for _, rec := range recs {
+		kiosks = append(kiosks, rec.ToDomain())
+	}
+	return kiosks, nil
+}
+
+func (r *PostgresKioskRepo) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	query := `UPDATE kiosks SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`
+
+	if _, err := r.pool.Exec(ctx, query, id, revokedAt.UTC()); err != nil {
+		return fmt.Errorf("failed to revoke kiosk: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresKioskRepo) findOne(ctx context.Context, query string, arg any) (*domain.Kiosk, error) {
+	rows, _ := r.pool.Query(ctx, query, arg)
+
+	rec, err := pgx.CollectOneRow(rows, pgx.RowToStructByNameLax[record.KioskRecord])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to find kiosk: %w", err)
+	}
+
+	return rec.ToDomain(), nil
+}
+
+type PostgresKioskPINRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresKioskPINRepo(pool *pgxpool.Pool) *PostgresKioskPINRepo {
+	return &PostgresKioskPINRepo{
+		pool: pool,
+	}
+}
+
+func (r *PostgresKioskPINRepo) Save(ctx context.Context, pin *domain.KioskPIN) error {
+	query := `
+		INSERT INTO employee_kiosk_pins (employee_id, pin_hash, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (employee_id) DO UPDATE
+		SET pin_hash = EXCLUDED.pin_hash,
+		    updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := r.pool.Exec(ctx, query, pin.EmployeeID, pin.PINHash, pin.UpdatedAt.UTC()); err != nil {
+		return fmt.Errorf("failed to save kiosk PIN: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresKioskPINRepo) FindByEmployeeID(ctx context.Context, employeeID string) (*domain.KioskPIN, error) {
+	query := `SELECT employee_id, pin_hash, updated_at FROM employee_kiosk_pins WHERE employee_id = $1`
+
+	rows, _ := r.pool.Query(ctx, query, employeeID)
+
+	rec, err := pgx.CollectOneRow(rows, pgx.RowToStructByNameLax[record.KioskPINRecord])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // No PIN set
+		}
+		return nil, fmt.Errorf("failed to find kiosk PIN: %w", err)
+	}
+
+	return rec.ToDomain(), nil
+}
+
+func (r *PostgresKioskPINRepo) DeleteByEmployeeID(ctx context.Context, employeeID string) error {
+	query := `DELETE FROM employee_kiosk_pins WHERE employee_id = $1`
+
+	if _, err := r.pool.Exec(ctx, query, employeeID); err != nil {
+		return fmt.Errorf("failed to delete kiosk PIN: %w", err)
+	}
+	return nil
+}
+
+type PostgresProcessedKioskEventRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresProcessedKioskEventRepo(pool *pgxpool.Pool) *PostgresProcessedKioskEventRepo {
+	return &PostgresProcessedKioskEventRepo{
+		pool: pool,
+	}
+}
+
+func (r *PostgresProcessedKioskEventRepo) Find(ctx context.Context, kioskID string, eventID string) (*domain.ProcessedKioskEvent, error) {
+	query := `
+		SELECT kiosk_id, event_id, type, occurred_at, status, attendance_id, error, processed_at
+		FROM kiosk_sync_events
+		WHERE kiosk_id = $1 AND event_id = $2
+	`
+
+	rows, _ := r.pool.Query(ctx, query, kioskID, eventID)
+
+	rec, err := pgx.CollectOneRow(rows, pgx.RowToStructByNameLax[record.ProcessedKioskEventRecord])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // Not synced yet
+		}
+		return nil, fmt.Errorf("failed to find kiosk sync event: %w", err)
+	}
+
+	return rec.ToDomain(), nil
+}
+
+func (r *PostgresProcessedKioskEventRepo) Save(ctx context.Context, event *domain.ProcessedKioskEvent) error {
+	rec := record.ProcessedKioskEventFromDomain(event)
+
+	query := `
+		INSERT INTO kiosk_sync_events (kiosk_id, event_id, type, occurred_at, status, attendance_id, error, processed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (kiosk_id, event_id) DO NOTHING
+	`
+
+	if _, err := r.pool.Exec(ctx, query, rec.KioskID, rec.EventID, rec.Type, rec.OccurredAt, rec.Status, rec.AttendanceID, rec.Error, rec.ProcessedAt); err != nil {
+		return fmt.Errorf("failed to save kiosk sync event: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresProcessedKioskEventRepo) DeleteOccurredBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM kiosk_sync_events WHERE occurred_at < $1`, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old kiosk sync events: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
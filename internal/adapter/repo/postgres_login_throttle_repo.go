@@ -0,0 +1,106 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/repo/record"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type PostgresLoginThrottleRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresLoginThrottleRepo(pool *pgxpool.Pool) *PostgresLoginThrottleRepo {
+	return &PostgresLoginThrottleRepo{
+		pool: pool,
+	}
+}
+
+func (r *PostgresLoginThrottleRepo) FindByKey(ctx context.Context, key string) (*domain.LoginThrottle, error) {
+	query := `
+		SELECT key, scope, failed_count, last_failed_at, locked_until
+		FROM login_throttles
+		WHERE key = $1
+	`
+
+	rows, _ := r.pool.Query(ctx, query, key)
+
+	rec, err := pgx.CollectOneRow(rows, pgx.RowToStructByNameLax[record.LoginThrottleRecord])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to find login throttle: %w", err)
+	}
+
+	return rec.ToDomain(), nil
+}
+
+// RegisterFailure applies the failure to the stored throttle while holding its row lock,
+// so parallel failures queue up instead of overwriting each other's count.
+func (r *PostgresLoginThrottleRepo) RegisterFailure(ctx context.Context, throttle *domain.LoginThrottle, now time.Time, policy domain.LoginThrottlePolicy) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO login_throttles (
+			key, scope, failed_count, last_failed_at, locked_until,
+			created_at, updated_at
+		) VALUES ($1, $2, 0, $3, NULL, NOW(), NOW())
+		ON CONFLICT (key) DO NOTHING
+	`, throttle.Key, string(throttle.Scope), now.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to create login throttle: %w", err)
+	}
+
+	rows, _ := tx.Query(ctx, `
+		SELECT key, scope, failed_count, last_failed_at, locked_until
+		FROM login_throttles
+		WHERE key = $1
+		FOR UPDATE
+	`, throttle.Key)
+	rec, err := pgx.CollectOneRow(rows, pgx.RowToStructByNameLax[record.LoginThrottleRecord])
+	if err != nil {
+		return fmt.Errorf("failed to lock login throttle: %w", err)
+	}
+
+	current := rec.ToDomain()
+	current.RegisterFailure(now, policy)
+	updated := record.LoginThrottleFromDomain(current)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE login_throttles
+		SET failed_count = $2,
+		    last_failed_at = $3,
+		    locked_until = $4,
+		    updated_at = NOW()
+		WHERE key = $1
+	`, updated.Key, updated.FailedCount, updated.LastFailedAt, updated.LockedUntil)
+	if err != nil {
+		return fmt.Errorf("failed to update login throttle: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	*throttle = *current
+	return nil
+}
+
+func (r *PostgresLoginThrottleRepo) DeleteByKey(ctx context.Context, key string) error {
+	query := `DELETE FROM login_throttles WHERE key = $1`
+
+	_, err := r.pool.Exec(ctx, query, key)
+
+	return err
+}
@@ -0,0 +1,61 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/repo/record"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type PostgresNotificationPreferenceRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresNotificationPreferenceRepo(pool *pgxpool.Pool) *PostgresNotificationPreferenceRepo {
+	return &PostgresNotificationPreferenceRepo{
+		pool: pool,
+	}
+}
+
+func (r *PostgresNotificationPreferenceRepo) FindByEmployeeID(ctx context.Context, employeeID string) (*domain.NotificationPreferences, error) {
+	query := `
+		SELECT employee_id, webhook_url, channels, updated_at
+		FROM notification_preferences
+		WHERE employee_id = $1
+	`
+
+	rows, _ := r.pool.Query(ctx, query, employeeID)
+
+	rec, err := pgx.CollectOneRow(rows, pgx.RowToStructByNameLax[record.NotificationPreferencesRecord])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to find notification preferences: %w", err)
+	}
+
+	return rec.ToDomain()
+}
+
+func (r *PostgresNotificationPreferenceRepo) Save(ctx context.Context, preferences *domain.NotificationPreferences) error {
+	rec, err := record.NotificationPreferencesFromDomain(preferences)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification preferences: %w", err)
+	}
+
+	query := `
+		INSERT INTO notification_preferences (employee_id, webhook_url, channels, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (employee_id) DO UPDATE
+		SET webhook_url = EXCLUDED.webhook_url,
+		    channels = EXCLUDED.channels,
+		    updated_at = EXCLUDED.updated_at
+	`
+
+	_, err = r.pool.Exec(ctx, query, rec.EmployeeID, rec.WebhookURL, rec.Channels, rec.UpdatedAt)
+	return err
+}
@@ -0,0 +1,209 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/repo/record"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type PostgresNotificationRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresNotificationRepo(pool *pgxpool.Pool) *PostgresNotificationRepo {
+	return &PostgresNotificationRepo{
+		pool: pool,
+	}
+}
+
+// Create inserts the notification and its deliveries in one transaction.
+func (r *PostgresNotificationRepo) Create(ctx context.Context, notification *domain.Notification, deliveries []*domain.NotificationDelivery) error {
+	rec := record.NotificationFromDomain(notification)
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO notifications (
+			id, recipient_id, event, subject, body, in_inbox, created_at, read_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, rec.ID, rec.RecipientID, rec.Event, rec.Subject, rec.Body, rec.InInbox, rec.CreatedAt, rec.ReadAt)
+	if err != nil {
+		return fmt.Errorf("failed to save notification: %w", err)
+	}
+
+	for _, delivery := range deliveries {
+		if err := insertDelivery(ctx, tx, delivery); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func insertDelivery(ctx context.Context, tx pgx.Tx, delivery *domain.NotificationDelivery) error {
+	rec := record.NotificationDeliveryFromDomain(delivery)
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO notification_deliveries (
+			id, notification_id, channel, target, status, attempts, last_error,
+			next_attempt_at, created_at, delivered_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, rec.ID, rec.NotificationID, rec.Channel, rec.Target, rec.Status, rec.Attempts, rec.LastError,
+		rec.NextAttemptAt, rec.CreatedAt, rec.DeliveredAt)
+	if err != nil {
+		return fmt.Errorf("failed to save notification delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresNotificationRepo) FindByID(ctx context.Context, id string) (*domain.Notification, error) {
+	query := `
+		SELECT id, recipient_id, event, subject, body, in_inbox, created_at, read_at
+		FROM notifications
+		WHERE id = $1
+	`
+
+	rows, _ := r.pool.Query(ctx, query, id)
+
+	rec, err := pgx.CollectOneRow(rows, pgx.RowToStructByNameLax[record.NotificationRecord])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to find notification: %w", err)
+	}
+
+	return rec.ToDomain(), nil
+}
+
+// FindInbox returns the newest in-app notifications of the recipient.
+func (r *PostgresNotificationRepo) FindInbox(ctx context.Context, recipientID string, unreadOnly bool, limit int) ([]*domain.Notification, error) {
+	query := `
+		SELECT id, recipient_id, event, subject, body, in_inbox, created_at, read_at
+		FROM notifications
+		WHERE recipient_id = $1 AND in_inbox AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, recipientID, unreadOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	records, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[record.NotificationRecord])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect notifications: %w", err)
+	}
+
+	notifications := make([]*domain.Notification, 0, len(records))
+	for _, rec := range records {
+		notifications = append(notifications, rec.ToDomain())
+	}
+
+	return notifications, nil
+}
+
+func (r *PostgresNotificationRepo) CountUnread(ctx context.Context, recipientID string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM notifications
+		WHERE recipient_id = $1 AND in_inbox AND read_at IS NULL
+	`
+
+	var count int
+	if err := r.pool.QueryRow(ctx, query, recipientID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *PostgresNotificationRepo) MarkRead(ctx context.Context, notification *domain.Notification) error {
+	rec := record.NotificationFromDomain(notification)
+
+	_, err := r.pool.Exec(ctx, `
+		UPDATE notifications
+		SET read_at = $2
+		WHERE id = $1 AND read_at IS NULL
+	`, rec.ID, rec.ReadAt)
+
+	return err
+}
+
+func (r *PostgresNotificationRepo) MarkAllRead(ctx context.Context, recipientID string, now time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE notifications
+		SET read_at = $2
+		WHERE recipient_id = $1 AND in_inbox AND read_at IS NULL
+	`, recipientID, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// ClaimDueDeliveries pushes next_attempt_at of the claimed rows past the lease, so a
+// worker that dies mid-send leaves them to be retried once the lease is over.
+func (r *PostgresNotificationRepo) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.NotificationDelivery, error) {
+	query := `
+		UPDATE notification_deliveries
+		SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM notification_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, notification_id, channel, target, status, attempts, last_error,
+		          next_attempt_at, created_at, delivered_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, now.UTC(), now.Add(lease).UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim notification deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	records, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[record.NotificationDeliveryRecord])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect notification deliveries: %w", err)
+	}
+
+	deliveries := make([]*domain.NotificationDelivery, 0, len(records))
+	for _, rec := range records {
+		deliveries = append(deliveries, rec.ToDomain())
+	}
+
+	return deliveries, nil
+}
+
+func (r *PostgresNotificationRepo) SaveDelivery(ctx context.Context, delivery *domain.NotificationDelivery) error {
+	rec := record.NotificationDeliveryFromDomain(delivery)
+
+	_, err := r.pool.Exec(ctx, `
+		UPDATE notification_deliveries
+		SET status = $2,
+		    attempts = $3,
+		    last_error = $4,
+		    next_attempt_at = $5,
+		    delivered_at = $6
+		WHERE id = $1
+	`, rec.ID, rec.Status, rec.Attempts, rec.LastError, rec.NextAttemptAt, rec.DeliveredAt)
+
+	return err
+}
@@ -0,0 +1,40 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type PostgresProcessedEventRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresProcessedEventRepo(pool *pgxpool.Pool) *PostgresProcessedEventRepo {
+	return &PostgresProcessedEventRepo{
+		pool: pool,
+	}
+}
+
+func (r *PostgresProcessedEventRepo) Exists(ctx context.Context, id string) (bool, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM processed_events WHERE id = $1)`, id).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check processed event: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *PostgresProcessedEventRepo) Save(ctx context.Context, event *domain.InboundEvent, processedAt time.Time) error {
+	query := `
+		INSERT INTO processed_events (id, type, source, processed_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	_, err := r.pool.Exec(ctx, query, event.ID, string(event.Type), event.Source, processedAt.UTC())
+	return err
+}
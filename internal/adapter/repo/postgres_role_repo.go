@@ -0,0 +1,141 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/repo/record"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type PostgresRoleRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresRoleRepo(pool *pgxpool.Pool) *PostgresRoleRepo {
+	return &PostgresRoleRepo{
+		pool: pool,
+	}
+}
+
+const selectRoles = `
+	SELECT r.name, r.description, r.rank, r.built_in,
+	       COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}') AS permissions
+	FROM roles r
+	LEFT JOIN role_permissions rp ON rp.role = r.name
+`
+
+func (r *PostgresRoleRepo) FindAll(ctx context.Context) ([]*domain.RoleDefinition, error) {
+	query := selectRoles + `
+	GROUP BY r.name, r.description, r.rank, r.built_in
+	ORDER BY r.rank DESC, r.name
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query roles: %w", err)
+	}
+	defer rows.Close()
+
+	records, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[record.RoleRecord])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect role records: %w", err)
+	}
+
+	roles := make([]*domain.RoleDefinition, 0, len(records))
+	for _, rec := range records {
+		roles = append(roles, rec.ToDomain())
+	}
+
+	return roles, nil
+}
+
+func (r *PostgresRoleRepo) FindByName(ctx context.Context, name string) (*domain.RoleDefinition, error) {
+	query := selectRoles + `
+	WHERE r.name = $1
+	GROUP BY r.name, r.description, r.rank, r.built_in
+	`
+
+	rows, _ := r.pool.Query(ctx, query, name)
+
+	rec, err := pgx.CollectOneRow(rows, pgx.RowToStructByNameLax[record.RoleRecord])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to find role: %w", err)
+	}
+
+	return rec.ToDomain(), nil
+}
+
+// Save upserts the role and replaces its permission set in one transaction.
+func (r *PostgresRoleRepo) Save(ctx context.Context, role *domain.RoleDefinition) error {
+	rec := record.RoleFromDomain(role)
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO roles (name, description, rank, built_in, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		ON CONFLICT (name) DO UPDATE
+		SET description = EXCLUDED.description,
+		    rank = EXCLUDED.rank,
+		    updated_at = NOW()
+	`, rec.Name, rec.Description, rec.Rank, rec.BuiltIn)
+	if err != nil {
+		return fmt.Errorf("failed to save role: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM role_permissions WHERE role = $1`, rec.Name); err != nil {
+		return fmt.Errorf("failed to clear role permissions: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO role_permissions (role, permission)
+		SELECT $1, unnest($2::text[])
+	`, rec.Name, rec.Permissions)
+	if err != nil {
+		return fmt.Errorf("failed to save role permissions: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Delete removes a custom role. It is kept while an employee holds it, which covers
+// employees assigned the role after the caller counted its holders.
+func (r *PostgresRoleRepo) Delete(ctx context.Context, name string) error {
+	query := `
+		DELETE FROM roles
+		WHERE name = $1 AND built_in = FALSE
+		  AND NOT EXISTS (SELECT 1 FROM employees WHERE role = $1 AND anonymized_at IS NULL)
+	`
+
+	tag, err := r.pool.Exec(ctx, query, name)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("role %s was not deleted, it is built in or still assigned", name)
+	}
+
+	return nil
+}
+
+func (r *PostgresRoleRepo) CountHolders(ctx context.Context, name string) (int, error) {
+	query := `SELECT COUNT(*) FROM employees WHERE role = $1 AND anonymized_at IS NULL`
+
+	var count int
+	if err := r.pool.QueryRow(ctx, query, name).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count role holders: %w", err)
+	}
+
+	return count, nil
+}
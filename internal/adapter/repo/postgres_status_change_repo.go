@@ -0,0 +1,94 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/repo/record"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type PostgresStatusChangeRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresStatusChangeRepo(pool *pgxpool.Pool) *PostgresStatusChangeRepo {
+	return &PostgresStatusChangeRepo{
+		pool: pool,
+	}
+}
+
+func (r *PostgresStatusChangeRepo) Save(ctx context.Context, change *domain.StatusChange) error {
+	rec := record.StatusChangeFromDomain(change)
+
+	query := `
+		INSERT INTO employee_status_changes (
+			id, employee_id, from_status, to_status, reason, effective_at, actor_id,
+			state, error, created_at, applied_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7,
+			$8, $9, $10, $11
+		)
+		ON CONFLICT (id) DO UPDATE
+		SET from_status = EXCLUDED.from_status,
+		    state = EXCLUDED.state,
+		    error = EXCLUDED.error,
+		    applied_at = EXCLUDED.applied_at
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		rec.ID, rec.EmployeeID, rec.FromStatus, rec.ToStatus, rec.Reason, rec.EffectiveAt, rec.ActorID,
+		rec.State, rec.Error, rec.CreatedAt, rec.AppliedAt,
+	)
+
+	return err
+}
+
+// FindByEmployeeID returns the status changes of an employee, newest first.
+func (r *PostgresStatusChangeRepo) FindByEmployeeID(ctx context.Context, employeeID string) ([]*domain.StatusChange, error) {
+	query := `
+		SELECT id, employee_id, from_status, to_status, reason, effective_at, actor_id,
+		       state, error, created_at, applied_at
+		FROM employee_status_changes
+		WHERE employee_id = $1
+		ORDER BY created_at DESC
+	`
+
+	return r.collect(ctx, query, employeeID)
+}
+
+// FindDue returns scheduled status changes whose effective time has been reached, oldest first.
+func (r *PostgresStatusChangeRepo) FindDue(ctx context.Context, now time.Time) ([]*domain.StatusChange, error) {
+	query := `
+		SELECT id, employee_id, from_status, to_status, reason, effective_at, actor_id,
+		       state, error, created_at, applied_at
+		FROM employee_status_changes
+		WHERE state = 'scheduled' AND effective_at <= $1
+		ORDER BY effective_at
+	`
+
+	return r.collect(ctx, query, now.UTC())
+}
+
+func (r *PostgresStatusChangeRepo) collect(ctx context.Context, query string, args ...any) ([]*domain.StatusChange, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status changes: %w", err)
+	}
+	defer rows.Close()
+
+	records, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[record.StatusChangeRecord])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect status changes: %w", err)
+	}
+
+	changes := make([]*domain.StatusChange, 0, len(records))
+	for _, rec := range records {
+		changes = append(changes, rec.ToDomain())
+	}
+
+	return changes, nil
+}
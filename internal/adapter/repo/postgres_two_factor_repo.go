@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/repo/record"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/secretbox"
+)
+
+type PostgresTwoFactorRepo struct {
+	pool *pgxpool.Pool
+	box  *secretbox.Box
+}
+
+func NewPostgresTwoFactorRepo(pool *pgxpool.Pool, box *secretbox.Box) *PostgresTwoFactorRepo {
+	return &PostgresTwoFactorRepo{
+		pool: pool,
+		box:  box,
+	}
+}
+
+func (r *PostgresTwoFactorRepo) FindByEmployeeID(ctx context.Context, employeeID string) (*domain.TwoFactor, error) {
+	query := `
+		SELECT employee_id, secret_ciphertext, recovery_code_hashes, last_used_step, confirmed_at
+		FROM employee_two_factors
+		WHERE employee_id = $1
+	`
+
+	rows, _ := r.pool.Query(ctx, query, employeeID)
+
+	rec, err := pgx.CollectOneRow(rows, pgx.RowToStructByNameLax[record.TwoFactorRecord])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // Not enrolled
+		}
+		return nil, fmt.Errorf("failed to find two factor: %w", err)
+	}
+
+	return rec.ToDomain(r.box)
+}
+
+func (r *PostgresTwoFactorRepo) Save(ctx context.Context, twoFactor *domain.TwoFactor) error {
+	rec, err := record.TwoFactorFromDomain(twoFactor, r.box)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO employee_two_factors (
+			employee_id, secret_ciphertext, recovery_code_hashes, last_used_step, confirmed_at,
+			created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5,
+			NOW(), NOW()
+		)
+		ON CONFLICT (employee_id) DO UPDATE
+		SET secret_ciphertext = EXCLUDED.secret_ciphertext,
+		    recovery_code_hashes = EXCLUDED.recovery_code_hashes,
+		    last_used_step = EXCLUDED.last_used_step,
+		    confirmed_at = EXCLUDED.confirmed_at,
+		    updated_at = NOW()
+	`
+
+	_, err = r.pool.Exec(ctx, query,
+		rec.EmployeeID, rec.SecretCiphertext, rec.RecoveryCodeHashes, rec.LastUsedStep, rec.ConfirmedAt,
+	)
+
+	return err
+}
+
+func (r *PostgresTwoFactorRepo) Delete(ctx context.Context, employeeID string) error {
+	query := `DELETE FROM employee_two_factors WHERE employee_id = $1`
+
+	_, err := r.pool.Exec(ctx, query, employeeID)
+
+	return err
+}
@@ -0,0 +1,248 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/repo/record"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/secretbox"
+)
+
+type PostgresWebhookSubscriptionRepo struct {
+	pool *pgxpool.Pool
+	box  *secretbox.Box
+}
+
+func NewPostgresWebhookSubscriptionRepo(pool *pgxpool.Pool, box *secretbox.Box) *PostgresWebhookSubscriptionRepo {
+	return &PostgresWebhookSubscriptionRepo{
+		pool: pool,
+		box:  box,
+	}
+}
+
+func (r *PostgresWebhookSubscriptionRepo) Save(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	rec, err := record.WebhookSubscriptionFromDomain(subscription, r.box)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO webhook_subscriptions (
+			id, url, events, secret_ciphertext, active, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE
+		SET url = EXCLUDED.url,
+		    events = EXCLUDED.events,
+		    secret_ciphertext = EXCLUDED.secret_ciphertext,
+		    active = EXCLUDED.active,
+		    updated_at = EXCLUDED.updated_at
+	`
+
+	_, err = r.pool.Exec(ctx, query,
+		rec.ID, rec.URL, rec.Events, rec.SecretCiphertext, rec.Active, rec.CreatedBy, rec.CreatedAt, rec.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *PostgresWebhookSubscriptionRepo) FindByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, events, secret_ciphertext, active, created_by, created_at, updated_at
+		FROM webhook_subscriptions
+		WHERE id = $1
+	`
+
+	rows, _ := r.pool.Query(ctx, query, id)
+
+	rec, err := pgx.CollectOneRow(rows, pgx.RowToStructByNameLax[record.WebhookSubscriptionRecord])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to find webhook subscription: %w", err)
+	}
+
+	return rec.ToDomain(r.box)
+}
+
+func (r *PostgresWebhookSubscriptionRepo) FindAll(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, events, secret_ciphertext, active, created_by, created_at, updated_at
+		FROM webhook_subscriptions
+		ORDER BY created_at
+	`
+
+	return r.collect(ctx, query)
+}
+
+func (r *PostgresWebhookSubscriptionRepo) FindActiveByEvent(ctx context.Context, event domain.WebhookEvent) ([]*domain.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, events, secret_ciphertext, active, created_by, created_at, updated_at
+		FROM webhook_subscriptions
+		WHERE active AND $1 = ANY(events)
+		ORDER BY created_at
+	`
+
+	return r.collect(ctx, query, string(event))
+}
+
+// Delete removes the subscription; its deliveries go with it (ON DELETE CASCADE).
+func (r *PostgresWebhookSubscriptionRepo) Delete(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	return err
+}
+
+func (r *PostgresWebhookSubscriptionRepo) collect(ctx context.Context, query string, args ...any) ([]*domain.WebhookSubscription, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	records, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[record.WebhookSubscriptionRecord])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect webhook subscriptions: %w", err)
+	}
+
+	subscriptions := make([]*domain.WebhookSubscription, 0, len(records))
+	for _, rec := range records {
+		subscription, err := rec.ToDomain(r.box)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, nil
+}
+
+type PostgresWebhookDeliveryRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresWebhookDeliveryRepo(pool *pgxpool.Pool) *PostgresWebhookDeliveryRepo {
+	return &PostgresWebhookDeliveryRepo{
+		pool: pool,
+	}
+}
+
+// Create inserts the deliveries of one event in a single batch.
+func (r *PostgresWebhookDeliveryRepo) Create(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (
+			id, subscription_id, event_id, event, payload, status, attempts, last_error,
+			response_status, next_attempt_at, created_at, delivered_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	batch := &pgx.Batch{}
+	for _, delivery := range deliveries {
+		rec := record.WebhookDeliveryFromDomain(delivery)
+		batch.Queue(query,
+			rec.ID, rec.SubscriptionID, rec.EventID, rec.Event, rec.Payload, rec.Status, rec.Attempts, rec.LastError,
+			rec.ResponseStatus, rec.NextAttemptAt, rec.CreatedAt, rec.DeliveredAt,
+		)
+	}
+
+	if err := r.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to save webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresWebhookDeliveryRepo) Save(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	rec := record.WebhookDeliveryFromDomain(delivery)
+
+	_, err := r.pool.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2,
+		    attempts = $3,
+		    last_error = $4,
+		    response_status = $5,
+		    next_attempt_at = $6,
+		    delivered_at = $7
+		WHERE id = $1
+	`, rec.ID, rec.Status, rec.Attempts, rec.LastError, rec.ResponseStatus, rec.NextAttemptAt, rec.DeliveredAt)
+
+	return err
+}
+
+func (r *PostgresWebhookDeliveryRepo) FindByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	query := `
+		SELECT id, subscription_id, event_id, event, payload, status, attempts, last_error,
+		       response_status, next_attempt_at, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE id = $1
+	`
+
+	rows, _ := r.pool.Query(ctx, query, id)
+
+	rec, err := pgx.CollectOneRow(rows, pgx.RowToStructByNameLax[record.WebhookDeliveryRecord])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, fmt.Errorf("failed to find webhook delivery: %w", err)
+	}
+
+	return rec.ToDomain(), nil
+}
+
+func (r *PostgresWebhookDeliveryRepo) FindBySubscriptionID(ctx context.Context, subscriptionID string, status domain.WebhookDeliveryStatus, limit int) ([]*domain.WebhookDelivery, error) {
+	query := `
+		SELECT id, subscription_id, event_id, event, payload, status, attempts, last_error,
+		       response_status, next_attempt_at, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE subscription_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+
+	return r.collect(ctx, query, subscriptionID, string(status), limit)
+}
+
+// ClaimDue pushes next_attempt_at of the claimed rows past the lease, so a worker that
+// dies mid-send leaves them to be retried once the lease is over.
+func (r *PostgresWebhookDeliveryRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, subscription_id, event_id, event, payload, status, attempts, last_error,
+		          response_status, next_attempt_at, created_at, delivered_at
+	`
+
+	return r.collect(ctx, query, now.UTC(), now.Add(lease).UTC(), limit)
+}
+
+func (r *PostgresWebhookDeliveryRepo) collect(ctx context.Context, query string, args ...any) ([]*domain.WebhookDelivery, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	records, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[record.WebhookDeliveryRecord])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect webhook deliveries: %w", err)
+	}
+
+	deliveries := make([]*domain.WebhookDelivery, 0, len(records))
+	for _, rec := range records {
+		deliveries = append(deliveries, rec.ToDomain())
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,60 @@
+package record
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type DataExportRecord struct {
+	ID          string         `db:"id"`
+	EmployeeID  string         `db:"employee_id"`
+	RequestedBy string         `db:"requested_by"`
+	Status      string         `db:"status"`
+	FileURL     sql.NullString `db:"file_url"`
+	Size        int64          `db:"size"`
+	Error       sql.NullString `db:"error"`
+	CreatedAt   time.Time      `db:"created_at"`
+	CompletedAt sql.NullTime   `db:"completed_at"`
+	ExpiresAt   sql.NullTime   `db:"expires_at"`
+}
+
+// DataExportFromDomain converts a domain.DataExport to DataExportRecord.
+func DataExportFromDomain(e *domain.DataExport) *DataExportRecord {
+	return &DataExportRecord{
+		ID:          e.ID,
+		EmployeeID:  e.EmployeeID,
+		RequestedBy: e.RequestedBy,
+		Status:      string(e.Status),
+		FileURL:     toNullString(e.FileURL),
+		Size:        e.Size,
+		Error:       toNullString(e.Error),
+		CreatedAt:   e.CreatedAt.UTC(),
+		CompletedAt: toNullUTCTime(e.CompletedAt),
+		ExpiresAt:   toNullUTCTime(e.ExpiresAt),
+	}
+}
+
+// ToDomain converts a DataExportRecord to domain.DataExport.
+func (r *DataExportRecord) ToDomain() *domain.DataExport {
+	return &domain.DataExport{
+		ID:          r.ID,
+		EmployeeID:  r.EmployeeID,
+		RequestedBy: r.RequestedBy,
+		Status:      domain.DataExportStatus(r.Status),
+		FileURL:     r.FileURL.String,
+		Size:        r.Size,
+		Error:       r.Error.String,
+		CreatedAt:   r.CreatedAt,
+		CompletedAt: validTimeOrNil(r.CompletedAt),
+		ExpiresAt:   validTimeOrNil(r.ExpiresAt),
+	}
+}
+
+func toNullUTCTime(t *time.Time) sql.NullTime {
+	if t != nil {
+		return sql.NullTime{Time: t.UTC(), Valid: true}
+	}
+	return sql.NullTime{Valid: false}
+}
@@ -0,0 +1,62 @@
+package record
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type DocumentRecord struct {
+	ID          string       `db:"id"`
+	EmployeeID  string       `db:"employee_id"`
+	Type        string       `db:"type"`
+	Version     int          `db:"version"`
+	FileName    string       `db:"file_name"`
+	ContentType string       `db:"content_type"`
+	Size        int64        `db:"size"`
+	StorageKey  string       `db:"storage_key"`
+	ExpiresAt   sql.NullTime `db:"expires_at"`
+	UploadedBy  string       `db:"uploaded_by"`
+	UploadedAt  time.Time    `db:"uploaded_at"`
+
+	ReminderWindow int `db:"reminder_window"`
+}
+
+// DocumentFromDomain converts a domain.Document to DocumentRecord.
+func DocumentFromDomain(d *domain.Document) *DocumentRecord {
+	return &DocumentRecord{
+		ID:          d.ID,
+		EmployeeID:  d.EmployeeID,
+		Type:        string(d.Type),
+		Version:     d.Version,
+		FileName:    d.FileName,
+		ContentType: d.ContentType,
+		Size:        d.Size,
+		StorageKey:  d.StorageKey,
+		ExpiresAt:   toNullUTCTime(d.ExpiresAt),
+		UploadedBy:  d.UploadedBy,
+		UploadedAt:  d.UploadedAt.UTC(),
+
+		ReminderWindow: d.ReminderWindow,
+	}
+}
+
+// ToDomain converts a DocumentRecord to domain.Document.
+func (r *DocumentRecord) ToDomain() *domain.Document {
+	return &domain.Document{
+		ID:          r.ID,
+		EmployeeID:  r.EmployeeID,
+		Type:        domain.DocumentType(r.Type),
+		Version:     r.Version,
+		FileName:    r.FileName,
+		ContentType: r.ContentType,
+		Size:        r.Size,
+		StorageKey:  r.StorageKey,
+		ExpiresAt:   validTimeOrNil(r.ExpiresAt),
+		UploadedBy:  r.UploadedBy,
+		UploadedAt:  r.UploadedAt,
+
+		ReminderWindow: r.ReminderWindow,
+	}
+}
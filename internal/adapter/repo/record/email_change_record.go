@@ -0,0 +1,50 @@
+package record
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type EmailChangeRecord struct {
+	ID          string       `db:"id"`
+	EmployeeID  string       `db:"employee_id"`
+	NewEmail    string       `db:"new_email"`
+	TokenHash   string       `db:"token_hash"`
+	RequestedBy string       `db:"requested_by"`
+	CreatedAt   time.Time    `db:"created_at"`
+	ExpiresAt   time.Time    `db:"expires_at"`
+	ConfirmedAt sql.NullTime `db:"confirmed_at"`
+	CancelledAt sql.NullTime `db:"cancelled_at"`
+}
+
+// EmailChangeFromDomain converts a domain.EmailChange to EmailChangeRecord.
+func EmailChangeFromDomain(c *domain.EmailChange) *EmailChangeRecord {
+	return &EmailChangeRecord{
+		ID:          c.ID,
+		EmployeeID:  c.EmployeeID,
+		NewEmail:    string(c.NewEmail),
+		TokenHash:   c.TokenHash,
+		RequestedBy: c.RequestedBy,
+		CreatedAt:   c.CreatedAt.UTC(),
+		ExpiresAt:   c.ExpiresAt.UTC(),
+		ConfirmedAt: toNullUTCTime(c.ConfirmedAt),
+		CancelledAt: toNullUTCTime(c.CancelledAt),
+	}
+}
+
+// ToDomain converts an EmailChangeRecord to domain.EmailChange.
+func (r *EmailChangeRecord) ToDomain() *domain.EmailChange {
+	return &domain.EmailChange{
+		ID:          r.ID,
+		EmployeeID:  r.EmployeeID,
+		NewEmail:    domain.Email(r.NewEmail),
+		TokenHash:   r.TokenHash,
+		RequestedBy: r.RequestedBy,
+		CreatedAt:   r.CreatedAt,
+		ExpiresAt:   r.ExpiresAt,
+		ConfirmedAt: validTimeOrNil(r.ConfirmedAt),
+		CancelledAt: validTimeOrNil(r.CancelledAt),
+	}
+}
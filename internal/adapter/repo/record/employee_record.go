@@ -4,10 +4,12 @@ import (
 	"database/sql"
 	"fmt"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/envelope"
 )
 
 type EmployeeRecord struct {
@@ -19,20 +21,73 @@ type EmployeeRecord struct {
 	Position    sql.NullString `db:"position"`
 	Salary      pgtype.Numeric `db:"salary"`
 	Status      string         `db:"status"`
-	BirthDate   sql.NullTime   `db:"birthdate"`
+	BirthDate   sql.NullString `db:"birthdate"`
 	Address     sql.NullString `db:"address"`
 	City        sql.NullString `db:"city"`
 	Province    sql.NullString `db:"province"`
 	PhoneNumber sql.NullString `db:"phone_number"`
 	Photo       sql.NullString `db:"photo"`
+	StoreID     sql.NullString `db:"store_id"`
 
-	CreatedAt time.Time    `db:"created_at"`
-	UpdatedAt time.Time    `db:"updated_at"`
-	DeletedAt sql.NullTime `db:"deleted_at"`
+	// Envelope encryption of the PII columns (birthdate, address, phone_number)
+	PhoneNumberIndex sql.NullString `db:"phone_number_bidx"`
+	DataKey          sql.NullString `db:"data_key"`
+	DataKeyID        sql.NullString `db:"data_key_id"`
+
+	CreatedAt    time.Time    `db:"created_at"`
+	UpdatedAt    time.Time    `db:"updated_at"`
+	DeletedAt    sql.NullTime `db:"deleted_at"`
+	AnonymizedAt sql.NullTime `db:"anonymized_at"`
+
+	TerminatedAt      sql.NullTime `db:"terminated_at"`
+	SessionsRevokedAt sql.NullTime `db:"sessions_revoked_at"`
+
+	Version int64 `db:"version"`
+}
+
+const birthDateLayout = "2006-01-02"
+
+// PIICipher encrypts the personal data columns of employee records. Every write
+// uses a fresh data key wrapped with the current key-encryption key.
+type PIICipher struct {
+	Keyring    *envelope.Keyring
+	BlindIndex *envelope.BlindIndex
 }
 
-// FromDomain converts a domain.Employee to EmployeeRecord.
-func FromDomain(e *domain.Employee) *EmployeeRecord {
+// PhoneNumberIndex returns the blind index used to look up and keep phone numbers unique.
+func (c *PIICipher) PhoneNumberIndex(phoneNumber string) sql.NullString {
+	phoneNumber = strings.TrimSpace(phoneNumber)
+	if phoneNumber == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: c.BlindIndex.Index(phoneNumber), Valid: true}
+}
+
+// FromDomain converts a domain.Employee to EmployeeRecord, encrypting the PII columns.
+func FromDomain(e *domain.Employee, cipher *PIICipher) (*EmployeeRecord, error) {
+	dataKey, err := cipher.Keyring.NewDataKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data key: %w", err)
+	}
+
+	var birthDate string
+	if e.BirthDate() != nil {
+		birthDate = e.BirthDate().Format(birthDateLayout)
+	}
+
+	encBirthDate, err := encryptNullString(dataKey, birthDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt birth date: %w", err)
+	}
+	encAddress, err := encryptNullString(dataKey, e.Address())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt address: %w", err)
+	}
+	encPhoneNumber, err := encryptNullString(dataKey, e.PhoneNumber())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt phone number: %w", err)
+	}
+
 	return &EmployeeRecord{
 		ID:          string(e.ID()),
 		Name:        e.Name(),
@@ -42,22 +97,63 @@ func FromDomain(e *domain.Employee) *EmployeeRecord {
 		Position:    toNullString(e.Position()),
 		Salary:      int64ToNumeric(e.Salary()),
 		Status:      string(e.Status()),
-		BirthDate:   toNullTime(e.BirthDate()),
-		Address:     toNullString(e.Address()),
+		BirthDate:   encBirthDate,
+		Address:     encAddress,
 		City:        toNullString(e.City()),
 		Province:    toNullString(e.Province()),
-		PhoneNumber: toNullString(e.PhoneNumber()),
+		PhoneNumber: encPhoneNumber,
 		Photo:       toNullString(e.Photo()),
-	}
+		StoreID:     toNullString(e.StoreID()),
+
+		DeletedAt:    toNullTime(e.DeletedAt()),
+		AnonymizedAt: toNullUTCTime(e.AnonymizedAt()),
+
+		TerminatedAt:      toNullUTCTime(e.TerminatedAt()),
+		SessionsRevokedAt: toNullUTCTime(e.SessionsRevokedAt()),
+
+		Version: e.Version(),
+
+		PhoneNumberIndex: cipher.PhoneNumberIndex(e.PhoneNumber()),
+		DataKey:          toNullString(dataKey.Wrapped),
+		DataKeyID:        toNullString(dataKey.KEKID),
+	}, nil
 }
 
-// ToDomain converts an EmployeeRecord to domain.Employee.
-func (r *EmployeeRecord) ToDomain() (*domain.Employee, error) {
+// ToDomain converts an EmployeeRecord to domain.Employee, decrypting the PII columns.
+// Rows written before encryption was introduced have no data key and are read as plaintext.
+func (r *EmployeeRecord) ToDomain(cipher *PIICipher) (*domain.Employee, error) {
 	salary, err := numericToInt64(r.Salary)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert salary: %w", err)
 	}
 
+	birthDateStr, address, phoneNumber := r.BirthDate.String, r.Address.String, r.PhoneNumber.String
+	if r.DataKey.Valid {
+		dataKey, err := cipher.Keyring.OpenDataKey(r.DataKey.String, r.DataKeyID.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open data key of employee %s: %w", r.ID, err)
+		}
+
+		if birthDateStr, err = decryptNullString(dataKey, r.BirthDate); err != nil {
+			return nil, fmt.Errorf("failed to decrypt birth date: %w", err)
+		}
+		if address, err = decryptNullString(dataKey, r.Address); err != nil {
+			return nil, fmt.Errorf("failed to decrypt address: %w", err)
+		}
+		if phoneNumber, err = decryptNullString(dataKey, r.PhoneNumber); err != nil {
+			return nil, fmt.Errorf("failed to decrypt phone number: %w", err)
+		}
+	}
+
+	var birthDate *time.Time
+	if birthDateStr != "" {
+		t, err := time.Parse(birthDateLayout, birthDateStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse birth date: %w", err)
+		}
+		birthDate = &t
+	}
+
 	return domain.ReconstituteEmployee(domain.ReconstituteEmployeeParams{
 		ID:           r.ID,
 		Name:         r.Name,
@@ -67,14 +163,22 @@ func (r *EmployeeRecord) ToDomain() (*domain.Employee, error) {
 		Position:     r.Position.String,
 		Salary:       salary,
 		Status:       r.Status,
-		BirthDate:    validTimeOrNil(r.BirthDate),
-		Address:      r.Address.String,
+		BirthDate:    birthDate,
+		Address:      address,
 		City:         r.City.String,
 		Province:     r.Province.String,
-		PhoneNumber:  r.PhoneNumber.String,
+		PhoneNumber:  phoneNumber,
 		Photo:        r.Photo.String,
+		StoreID:      r.StoreID.String,
 		CreatedAt:    r.CreatedAt,
 		UpdatedAt:    r.UpdatedAt,
+		DeletedAt:    validTimeOrNil(r.DeletedAt),
+		AnonymizedAt: validTimeOrNil(r.AnonymizedAt),
+
+		TerminatedAt:      validTimeOrNil(r.TerminatedAt),
+		SessionsRevokedAt: validTimeOrNil(r.SessionsRevokedAt),
+
+		Version: r.Version,
 	})
 }
 
@@ -82,6 +186,24 @@ func toNullString(s string) sql.NullString {
 	return sql.NullString{String: s, Valid: s != ""}
 }
 
+func encryptNullString(dataKey *envelope.DataKey, s string) (sql.NullString, error) {
+	if s == "" {
+		return sql.NullString{}, nil
+	}
+	ciphertext, err := dataKey.Encrypt(s)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: ciphertext, Valid: true}, nil
+}
+
+func decryptNullString(dataKey *envelope.DataKey, ns sql.NullString) (string, error) {
+	if !ns.Valid {
+		return "", nil
+	}
+	return dataKey.Decrypt(ns.String)
+}
+
 func int64ToNumeric(v int64) pgtype.Numeric {
 	n := pgtype.Numeric{}
 	// Represent as an integer numeric (scale 0\)
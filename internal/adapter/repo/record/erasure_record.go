@@ -0,0 +1,55 @@
+package record
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type ErasureRecord struct {
+	ID                string         `db:"id"`
+	EmployeeID        string         `db:"employee_id"`
+	Trigger           string         `db:"trigger"`
+	RequestedBy       sql.NullString `db:"requested_by"`
+	Reason            sql.NullString `db:"reason"`
+	ErasedFields      []string       `db:"erased_fields"`
+	AttendanceRecords int64          `db:"attendance_records"`
+	PhotoDeleted      bool           `db:"photo_deleted"`
+	ErasedAt          time.Time      `db:"erased_at"`
+}
+
+// ErasureFromDomain converts a domain.ErasureRecord to ErasureRecord.
+func ErasureFromDomain(e *domain.ErasureRecord) *ErasureRecord {
+	fields := e.ErasedFields
+	if fields == nil {
+		fields = []string{}
+	}
+
+	return &ErasureRecord{
+		ID:                e.ID,
+		EmployeeID:        e.EmployeeID,
+		Trigger:           string(e.Trigger),
+		RequestedBy:       toNullString(e.RequestedBy),
+		Reason:            toNullString(e.Reason),
+		ErasedFields:      fields,
+		AttendanceRecords: e.AttendanceRecords,
+		PhotoDeleted:      e.PhotoDeleted,
+		ErasedAt:          e.ErasedAt.UTC(),
+	}
+}
+
+// ToDomain converts an ErasureRecord to domain.ErasureRecord.
+func (r *ErasureRecord) ToDomain() *domain.ErasureRecord {
+	return &domain.ErasureRecord{
+		ID:                r.ID,
+		EmployeeID:        r.EmployeeID,
+		Trigger:           domain.ErasureTrigger(r.Trigger),
+		RequestedBy:       r.RequestedBy.String,
+		Reason:            r.Reason.String,
+		ErasedFields:      r.ErasedFields,
+		AttendanceRecords: r.AttendanceRecords,
+		PhotoDeleted:      r.PhotoDeleted,
+		ErasedAt:          r.ErasedAt,
+	}
+}
@@ -0,0 +1,101 @@
+package record
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type KioskRecord struct {
+	ID         string       `db:"id"`
+	StoreID    string       `db:"store_id"`
+	Name       string       `db:"name"`
+	TokenHash  string       `db:"token_hash"`
+	SigningKey []byte       `db:"signing_key"`
+	CreatedBy  string       `db:"created_by"`
+	CreatedAt  time.Time    `db:"created_at"`
+	RevokedAt  sql.NullTime `db:"revoked_at"`
+}
+
+// KioskFromDomain converts a domain.Kiosk to KioskRecord.
+func KioskFromDomain(k *domain.Kiosk) *KioskRecord {
+	return &KioskRecord{
+		ID:         k.ID,
+		StoreID:    k.StoreID,
+		Name:       k.Name,
+		TokenHash:  k.TokenHash,
+		SigningKey: k.SigningKey,
+		CreatedBy:  k.CreatedBy,
+		CreatedAt:  k.CreatedAt.UTC(),
+		RevokedAt:  toNullUTCTime(k.RevokedAt),
+	}
+}
+
+// ToDomain converts a KioskRecord to domain.Kiosk.
+func (r *KioskRecord) ToDomain() *domain.Kiosk {
+	return &domain.Kiosk{
+		ID:         r.ID,
+		StoreID:    r.StoreID,
+		Name:       r.Name,
+		TokenHash:  r.TokenHash,
+		SigningKey: r.SigningKey,
+		CreatedBy:  r.CreatedBy,
+		CreatedAt:  r.CreatedAt,
+		RevokedAt:  validTimeOrNil(r.RevokedAt),
+	}
+}
+
+type KioskPINRecord struct {
+	EmployeeID string    `db:"employee_id"`
+	PINHash    string    `db:"pin_hash"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// ToDomain converts a KioskPINRecord to domain.KioskPIN.
+func (r *KioskPINRecord) ToDomain() *domain.KioskPIN {
+	return &domain.KioskPIN{
+		EmployeeID: r.EmployeeID,
+		PINHash:    r.PINHash,
+		UpdatedAt:  r.UpdatedAt,
+	}
+}
+
+type ProcessedKioskEventRecord struct {
+	KioskID      string         `db:"kiosk_id"`
+	EventID      string         `db:"event_id"`
+	Type         string         `db:"type"`
+	OccurredAt   time.Time      `db:"occurred_at"`
+	Status       string         `db:"status"`
+	AttendanceID sql.NullString `db:"attendance_id"`
+	Error        sql.NullString `db:"error"`
+	ProcessedAt  time.Time      `db:"processed_at"`
+}
+
+// ProcessedKioskEventFromDomain converts a domain.ProcessedKioskEvent to ProcessedKioskEventRecord.
+func ProcessedKioskEventFromDomain(e *domain.ProcessedKioskEvent) *ProcessedKioskEventRecord {
+	return &ProcessedKioskEventRecord{
+		KioskID:      e.KioskID,
+		EventID:      e.EventID,
+		Type:         string(e.Type),
+		OccurredAt:   e.OccurredAt.UTC(),
+		Status:       string(e.Status),
+		AttendanceID: toNullString(e.AttendanceID),
+		Error:        toNullString(e.Error),
+		ProcessedAt:  e.ProcessedAt.UTC(),
+	}
+}
+
+// ToDomain converts a ProcessedKioskEventRecord to domain.ProcessedKioskEvent.
+func (r *ProcessedKioskEventRecord) ToDomain() *domain.ProcessedKioskEvent {
+	return &domain.ProcessedKioskEvent{
+		KioskID:      r.KioskID,
+		EventID:      r.EventID,
+		Type:         domain.KioskEventType(r.Type),
+		OccurredAt:   r.OccurredAt,
+		Status:       domain.KioskEventStatus(r.Status),
+		AttendanceID: r.AttendanceID.String,
+		Error:        r.Error.String,
+		ProcessedAt:  r.ProcessedAt,
+	}
+}
@@ -0,0 +1,42 @@
+package record
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type LoginThrottleRecord struct {
+	Key          string       `db:"key"`
+	Scope        string       `db:"scope"`
+	FailedCount  int          `db:"failed_count"`
+	LastFailedAt time.Time    `db:"last_failed_at"`
+	LockedUntil  sql.NullTime `db:"locked_until"`
+}
+
+// LoginThrottleFromDomain converts a domain.LoginThrottle to LoginThrottleRecord.
+// Times are stored in UTC because the columns are TIMESTAMP without time zone.
+func LoginThrottleFromDomain(t *domain.LoginThrottle) *LoginThrottleRecord {
+	rec := &LoginThrottleRecord{
+		Key:          t.Key,
+		Scope:        string(t.Scope),
+		FailedCount:  t.FailedCount,
+		LastFailedAt: t.LastFailedAt.UTC(),
+	}
+	if t.LockedUntil != nil {
+		rec.LockedUntil = sql.NullTime{Time: t.LockedUntil.UTC(), Valid: true}
+	}
+	return rec
+}
+
+// ToDomain converts a LoginThrottleRecord to domain.LoginThrottle.
+func (r *LoginThrottleRecord) ToDomain() *domain.LoginThrottle {
+	return &domain.LoginThrottle{
+		Key:          r.Key,
+		Scope:        domain.ThrottleScope(r.Scope),
+		FailedCount:  r.FailedCount,
+		LastFailedAt: r.LastFailedAt,
+		LockedUntil:  validTimeOrNil(r.LockedUntil),
+	}
+}
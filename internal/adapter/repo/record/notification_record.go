@@ -0,0 +1,131 @@
+package record
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type NotificationRecord struct {
+	ID          string       `db:"id"`
+	RecipientID string       `db:"recipient_id"`
+	Event       string       `db:"event"`
+	Subject     string       `db:"subject"`
+	Body        string       `db:"body"`
+	InInbox     bool         `db:"in_inbox"`
+	CreatedAt   time.Time    `db:"created_at"`
+	ReadAt      sql.NullTime `db:"read_at"`
+}
+
+// NotificationFromDomain converts a domain.Notification to NotificationRecord.
+func NotificationFromDomain(n *domain.Notification) *NotificationRecord {
+	return &NotificationRecord{
+		ID:          n.ID,
+		RecipientID: n.RecipientID,
+		Event:       string(n.Event),
+		Subject:     n.Subject,
+		Body:        n.Body,
+		InInbox:     n.InInbox,
+		CreatedAt:   n.CreatedAt.UTC(),
+		ReadAt:      toNullUTCTime(n.ReadAt),
+	}
+}
+
+// ToDomain converts a NotificationRecord to domain.Notification.
+func (r *NotificationRecord) ToDomain() *domain.Notification {
+	return &domain.Notification{
+		ID:          r.ID,
+		RecipientID: r.RecipientID,
+		Event:       domain.NotificationEvent(r.Event),
+		Subject:     r.Subject,
+		Body:        r.Body,
+		InInbox:     r.InInbox,
+		CreatedAt:   r.CreatedAt,
+		ReadAt:      validTimeOrNil(r.ReadAt),
+	}
+}
+
+type NotificationDeliveryRecord struct {
+	ID             string         `db:"id"`
+	NotificationID string         `db:"notification_id"`
+	Channel        string         `db:"channel"`
+	Target         string         `db:"target"`
+	Status         string         `db:"status"`
+	Attempts       int            `db:"attempts"`
+	LastError      sql.NullString `db:"last_error"`
+	NextAttemptAt  time.Time      `db:"next_attempt_at"`
+	CreatedAt      time.Time      `db:"created_at"`
+	DeliveredAt    sql.NullTime   `db:"delivered_at"`
+}
+
+// NotificationDeliveryFromDomain converts a domain.NotificationDelivery to NotificationDeliveryRecord.
+func NotificationDeliveryFromDomain(d *domain.NotificationDelivery) *NotificationDeliveryRecord {
+	return &NotificationDeliveryRecord{
+		ID:             d.ID,
+		NotificationID: d.NotificationID,
+		Channel:        string(d.Channel),
+		Target:         d.Target,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastError:      toNullString(d.LastError),
+		NextAttemptAt:  d.NextAttemptAt.UTC(),
+		CreatedAt:      d.CreatedAt.UTC(),
+		DeliveredAt:    toNullUTCTime(d.DeliveredAt),
+	}
+}
+
+// ToDomain converts a NotificationDeliveryRecord to domain.NotificationDelivery.
+func (r *NotificationDeliveryRecord) ToDomain() *domain.NotificationDelivery {
+	return &domain.NotificationDelivery{
+		ID:             r.ID,
+		NotificationID: r.NotificationID,
+		Channel:        domain.NotificationChannel(r.Channel),
+		Target:         r.Target,
+		Status:         domain.DeliveryStatus(r.Status),
+		Attempts:       r.Attempts,
+		LastError:      r.LastError.String,
+		NextAttemptAt:  r.NextAttemptAt,
+		CreatedAt:      r.CreatedAt,
+		DeliveredAt:    validTimeOrNil(r.DeliveredAt),
+	}
+}
+
+type NotificationPreferencesRecord struct {
+	EmployeeID string         `db:"employee_id"`
+	WebhookURL sql.NullString `db:"webhook_url"`
+	Channels   []byte         `db:"channels"`
+	UpdatedAt  time.Time      `db:"updated_at"`
+}
+
+// NotificationPreferencesFromDomain converts domain.NotificationPreferences to
+// NotificationPreferencesRecord, storing the channels per event as JSON.
+func NotificationPreferencesFromDomain(p *domain.NotificationPreferences) (*NotificationPreferencesRecord, error) {
+	channels, err := json.Marshal(p.Channels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NotificationPreferencesRecord{
+		EmployeeID: p.EmployeeID,
+		WebhookURL: toNullString(p.WebhookURL),
+		Channels:   channels,
+		UpdatedAt:  p.UpdatedAt.UTC(),
+	}, nil
+}
+
+// ToDomain converts a NotificationPreferencesRecord to domain.NotificationPreferences.
+func (r *NotificationPreferencesRecord) ToDomain() (*domain.NotificationPreferences, error) {
+	prefs := domain.NewNotificationPreferences(r.EmployeeID)
+	prefs.WebhookURL = r.WebhookURL.String
+	prefs.UpdatedAt = r.UpdatedAt
+
+	if len(r.Channels) > 0 {
+		if err := json.Unmarshal(r.Channels, &prefs.Channels); err != nil {
+			return nil, err
+		}
+	}
+
+	return prefs, nil
+}
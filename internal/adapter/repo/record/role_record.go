@@ -0,0 +1,47 @@
+package record
+
+import (
+	"database/sql"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type RoleRecord struct {
+	Name        string         `db:"name"`
+	Description sql.NullString `db:"description"`
+	Rank        int            `db:"rank"`
+	BuiltIn     bool           `db:"built_in"`
+	Permissions []string       `db:"permissions"`
+}
+
+// RoleFromDomain converts a domain.RoleDefinition to RoleRecord.
+func RoleFromDomain(r *domain.RoleDefinition) *RoleRecord {
+	permissions := make([]string, 0, len(r.Permissions))
+	for _, p := range r.Permissions {
+		permissions = append(permissions, string(p))
+	}
+
+	return &RoleRecord{
+		Name:        string(r.Name),
+		Description: toNullString(r.Description),
+		Rank:        r.Rank,
+		BuiltIn:     r.BuiltIn,
+		Permissions: permissions,
+	}
+}
+
+// ToDomain converts a RoleRecord to domain.RoleDefinition.
+func (r *RoleRecord) ToDomain() *domain.RoleDefinition {
+	permissions := make([]domain.Permission, 0, len(r.Permissions))
+	for _, p := range r.Permissions {
+		permissions = append(permissions, domain.Permission(p))
+	}
+
+	return &domain.RoleDefinition{
+		Name:        domain.Role(r.Name),
+		Description: r.Description.String,
+		Rank:        r.Rank,
+		BuiltIn:     r.BuiltIn,
+		Permissions: permissions,
+	}
+}
@@ -0,0 +1,56 @@
+package record
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type StatusChangeRecord struct {
+	ID          string         `db:"id"`
+	EmployeeID  string         `db:"employee_id"`
+	FromStatus  sql.NullString `db:"from_status"`
+	ToStatus    string         `db:"to_status"`
+	Reason      string         `db:"reason"`
+	EffectiveAt time.Time      `db:"effective_at"`
+	ActorID     sql.NullString `db:"actor_id"` // NULL for changes made by the system
+	State       string         `db:"state"`
+	Error       sql.NullString `db:"error"`
+	CreatedAt   time.Time      `db:"created_at"`
+	AppliedAt   sql.NullTime   `db:"applied_at"`
+}
+
+// StatusChangeFromDomain converts a domain.StatusChange to StatusChangeRecord.
+func StatusChangeFromDomain(c *domain.StatusChange) *StatusChangeRecord {
+	return &StatusChangeRecord{
+		ID:          c.ID,
+		EmployeeID:  c.EmployeeID,
+		FromStatus:  toNullString(string(c.From)),
+		ToStatus:    string(c.To),
+		Reason:      c.Reason,
+		EffectiveAt: c.EffectiveAt.UTC(),
+		ActorID:     toNullString(c.ActorID),
+		State:       string(c.State),
+		Error:       toNullString(c.Error),
+		CreatedAt:   c.CreatedAt.UTC(),
+		AppliedAt:   toNullUTCTime(c.AppliedAt),
+	}
+}
+
+// ToDomain converts a StatusChangeRecord to domain.StatusChange.
+func (r *StatusChangeRecord) ToDomain() *domain.StatusChange {
+	return &domain.StatusChange{
+		ID:          r.ID,
+		EmployeeID:  r.EmployeeID,
+		From:        domain.Status(r.FromStatus.String),
+		To:          domain.Status(r.ToStatus),
+		Reason:      r.Reason,
+		EffectiveAt: r.EffectiveAt,
+		ActorID:     r.ActorID.String,
+		State:       domain.StatusChangeState(r.State),
+		Error:       r.Error.String,
+		CreatedAt:   r.CreatedAt,
+		AppliedAt:   validTimeOrNil(r.AppliedAt),
+	}
+}
@@ -0,0 +1,54 @@
+package record
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/secretbox"
+)
+
+type TwoFactorRecord struct {
+	EmployeeID         string       `db:"employee_id"`
+	SecretCiphertext   string       `db:"secret_ciphertext"`
+	RecoveryCodeHashes []string     `db:"recovery_code_hashes"`
+	LastUsedStep       int64        `db:"last_used_step"`
+	ConfirmedAt        sql.NullTime `db:"confirmed_at"`
+}
+
+// TwoFactorFromDomain converts a domain.TwoFactor to TwoFactorRecord, encrypting the TOTP secret.
+func TwoFactorFromDomain(t *domain.TwoFactor, box *secretbox.Box) (*TwoFactorRecord, error) {
+	ciphertext, err := box.Seal([]byte(t.Secret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt totp secret: %w", err)
+	}
+
+	hashes := t.RecoveryCodeHashes
+	if hashes == nil {
+		hashes = []string{}
+	}
+
+	return &TwoFactorRecord{
+		EmployeeID:         t.EmployeeID,
+		SecretCiphertext:   ciphertext,
+		RecoveryCodeHashes: hashes,
+		LastUsedStep:       t.LastUsedStep,
+		ConfirmedAt:        toNullTime(t.ConfirmedAt),
+	}, nil
+}
+
+// ToDomain converts a TwoFactorRecord to domain.TwoFactor, decrypting the TOTP secret.
+func (r *TwoFactorRecord) ToDomain(box *secretbox.Box) (*domain.TwoFactor, error) {
+	secret, err := box.Open(r.SecretCiphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt totp secret: %w", err)
+	}
+
+	return &domain.TwoFactor{
+		EmployeeID:         r.EmployeeID,
+		Secret:             string(secret),
+		RecoveryCodeHashes: r.RecoveryCodeHashes,
+		LastUsedStep:       r.LastUsedStep,
+		ConfirmedAt:        validTimeOrNil(r.ConfirmedAt),
+	}, nil
+}
@@ -0,0 +1,122 @@
+package record
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/secretbox"
+)
+
+type WebhookSubscriptionRecord struct {
+	ID               string    `db:"id"`
+	URL              string    `db:"url"`
+	Events           []string  `db:"events"`
+	SecretCiphertext string    `db:"secret_ciphertext"`
+	Active           bool      `db:"active"`
+	CreatedBy        string    `db:"created_by"`
+	CreatedAt        time.Time `db:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at"`
+}
+
+// WebhookSubscriptionFromDomain converts a domain.WebhookSubscription to
+// WebhookSubscriptionRecord, encrypting the signing secret.
+func WebhookSubscriptionFromDomain(s *domain.WebhookSubscription, box *secretbox.Box) (*WebhookSubscriptionRecord, error) {
+	ciphertext, err := box.Seal([]byte(s.Secret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+
+	events := make([]string, 0, len(s.Events))
+	for _, event := range s.Events {
+		events = append(events, string(event))
+	}
+
+	return &WebhookSubscriptionRecord{
+		ID:               s.ID,
+		URL:              s.URL,
+		Events:           events,
+		SecretCiphertext: ciphertext,
+		Active:           s.Active,
+		CreatedBy:        s.CreatedBy,
+		CreatedAt:        s.CreatedAt.UTC(),
+		UpdatedAt:        s.UpdatedAt.UTC(),
+	}, nil
+}
+
+// ToDomain converts a WebhookSubscriptionRecord to domain.WebhookSubscription, decrypting
+// the signing secret.
+func (r *WebhookSubscriptionRecord) ToDomain(box *secretbox.Box) (*domain.WebhookSubscription, error) {
+	secret, err := box.Open(r.SecretCiphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt webhook secret: %w", err)
+	}
+
+	events := make([]domain.WebhookEvent, 0, len(r.Events))
+	for _, event := range r.Events {
+		events = append(events, domain.WebhookEvent(event))
+	}
+
+	return &domain.WebhookSubscription{
+		ID:        r.ID,
+		URL:       r.URL,
+		Events:    events,
+		Secret:    string(secret),
+		Active:    r.Active,
+		CreatedBy: r.CreatedBy,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}, nil
+}
+
+type WebhookDeliveryRecord struct {
+	ID             string         `db:"id"`
+	SubscriptionID string         `db:"subscription_id"`
+	EventID        string         `db:"event_id"`
+	Event          string         `db:"event"`
+	Payload        string         `db:"payload"`
+	Status         string         `db:"status"`
+	Attempts       int            `db:"attempts"`
+	LastError      sql.NullString `db:"last_error"`
+	ResponseStatus sql.NullInt32  `db:"response_status"`
+	NextAttemptAt  time.Time      `db:"next_attempt_at"`
+	CreatedAt      time.Time      `db:"created_at"`
+	DeliveredAt    sql.NullTime   `db:"delivered_at"`
+}
+
+// WebhookDeliveryFromDomain converts a domain.WebhookDelivery to WebhookDeliveryRecord.
+func WebhookDeliveryFromDomain(d *domain.WebhookDelivery) *WebhookDeliveryRecord {
+	return &WebhookDeliveryRecord{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		Event:          string(d.Event),
+		Payload:        string(d.Payload),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastError:      toNullString(d.LastError),
+		ResponseStatus: sql.NullInt32{Int32: int32(d.ResponseStatus), Valid: d.ResponseStatus != 0},
+		NextAttemptAt:  d.NextAttemptAt.UTC(),
+		CreatedAt:      d.CreatedAt.UTC(),
+		DeliveredAt:    toNullUTCTime(d.DeliveredAt),
+	}
+}
+
+// ToDomain converts a WebhookDeliveryRecord to domain.WebhookDelivery.
+func (r *WebhookDeliveryRecord) ToDomain() *domain.WebhookDelivery {
+	return &domain.WebhookDelivery{
+		ID:             r.ID,
+		SubscriptionID: r.SubscriptionID,
+		EventID:        r.EventID,
+		Event:          domain.WebhookEvent(r.Event),
+		Payload:        []byte(r.Payload),
+		Status:         domain.WebhookDeliveryStatus(r.Status),
+		Attempts:       r.Attempts,
+		LastError:      r.LastError.String,
+		ResponseStatus: int(r.ResponseStatus.Int32),
+		NextAttemptAt:  r.NextAttemptAt,
+		CreatedAt:      r.CreatedAt,
+		DeliveredAt:    validTimeOrNil(r.DeliveredAt),
+	}
+}
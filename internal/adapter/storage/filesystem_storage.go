@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+// tempFilePrefix marks files being written; they are renamed into place when complete
+// and never listed.
+const tempFilePrefix = ".upload-"
+
+// FilesystemStorage keeps files in a directory, for local development without MinIO.
+// Object keys map to paths below the directory, and the API serves the files through
+// signed URLs.
+type FilesystemStorage struct {
+	dir    string
+	signer *URLSigner
+}
+
+func NewFilesystemStorage(dir string, signer *URLSigner) (*FilesystemStorage, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	return &FilesystemStorage{
+		dir:    dir,
+		signer: signer,
+	}, nil
+}
+
+// UploadFile writes the content under fileName and returns it as the key. The file only
+// appears once it is complete.
+func (s *FilesystemStorage) UploadFile(ctx context.Context, fileName string, contentType string, content io.Reader, size int64) (string, error) {
+	path, err := s.path(fileName)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), tempFilePrefix+"*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		_ = tmp.Close()
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to move file into place: %w", err)
+	}
+
+	return fileName, nil
+}
+
+// DeleteFile removes a file; removing a file that does not exist is not an error.
+func (s *FilesystemStorage) DeleteFile(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *FilesystemStorage) DownloadFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", usecase.StoredFileNotFoundError, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func (s *FilesystemStorage) PresignGetURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	return s.signer.Sign(key, expiry), nil
+}
+
+// ListFiles returns the files whose key starts with prefix, ordered by key.
+func (s *FilesystemStorage) ListFiles(ctx context.Context, prefix string) ([]usecase.StoredFile, error) {
+	var files []usecase.StoredFile
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), tempFilePrefix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, usecase.StoredFile{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Key < files[j].Key })
+	return files, nil
+}
+
+// path maps a key to a path below the storage directory, refusing keys that would
+// leave it.
+func (s *FilesystemStorage) path(key string) (string, error) {
+	if key == "" || !filepath.IsLocal(filepath.FromSlash(key)) || strings.Contains(key, `\`) {
+		return "", fmt.Errorf("invalid file key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+)
+
+type memoryFile struct {
+	data         []byte
+	lastModified time.Time
+}
+
+// MemoryStorage keeps files in memory, for tests and development. The API serves the
+// files through signed URLs, like the filesystem driver.
+type MemoryStorage struct {
+	mu     sync.Mutex
+	files  map[string]memoryFile
+	signer *URLSigner
+	clock  clock.Clock
+}
+
+func NewMemoryStorage(signer *URLSigner, clk clock.Clock) *MemoryStorage {
+	return &MemoryStorage{
+		files:  make(map[string]memoryFile),
+		signer: signer,
+		clock:  clk,
+	}
+}
+
+func (s *MemoryStorage) UploadFile(ctx context.Context, fileName string, contentType string, content io.Reader, size int64) (string, error) {
+	if fileName == "" {
+		return "", fmt.Errorf("invalid file key %q", fileName)
+	}
+
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files[fileName] = memoryFile{data: data, lastModified: s.clock.Now()}
+	return fileName, nil
+}
+
+func (s *MemoryStorage) DeleteFile(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.files, key)
+	return nil
+}
+
+func (s *MemoryStorage) DownloadFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, ok := s.files[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", usecase.StoredFileNotFoundError, key)
+	}
+	// Stored bytes are never modified in place, an upload replaces the slice
+	return io.NopCloser(bytes.NewReader(file.data)), nil
+}
+
+func (s *MemoryStorage) PresignGetURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return s.signer.Sign(key, expiry), nil
+}
+
+// ListFiles returns the files whose key starts with prefix, ordered by key.
+func (s *MemoryStorage) ListFiles(ctx context.Context, prefix string) ([]usecase.StoredFile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var files []usecase.StoredFile
+	for key, file := range s.files {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		files = append(files, usecase.StoredFile{
+			Key:          key,
+			Size:         int64(len(file.data)),
+			LastModified: file.lastModified,
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Key < files[j].Key })
+	return files, nil
+}
@@ -4,17 +4,19 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/zuyatna/shop-retail-employee-service/internal/config"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
 )
 
 type MinioStorage struct {
 	client     *minio.Client
 	bucketName string
-	endpoint   string
-	useSSL     bool
 }
 
 func NewMinioStorage(cfg *config.Config) (*MinioStorage, error) {
@@ -29,8 +31,6 @@ func NewMinioStorage(cfg *config.Config) (*MinioStorage, error) {
 	storage := &MinioStorage{
 		client:     client,
 		bucketName: cfg.MinioBucket,
-		endpoint:   cfg.MinioEndpoint,
-		useSSL:     cfg.MinioUseSSL,
 	}
 
 	if err := storage.initBucket(context.Background()); err != nil {
@@ -53,25 +53,17 @@ func (m *MinioStorage) initBucket(ctx context.Context) error {
 		}
 	}
 
-	policy := fmt.Sprintf(`{
-	"Version":"2012-10-17",
-	"Statement":[
-		{
-			"Effect":"Allow",
-			"Principal":{"AWS":["*"]},
-			"Action":["s3:GetObject"],
-			"Resource":["arn:aws:s3:::%s/*"]
-		}
-	]}`, m.bucketName)
-
-	err = m.client.SetBucketPolicy(ctx, m.bucketName, policy)
+	// Objects are only reachable through presigned URLs; drop the public read policy
+	// earlier versions put on the bucket
+	err = m.client.SetBucketPolicy(ctx, m.bucketName, "")
 	if err != nil {
-		return fmt.Errorf("failed to set bucket policy: %w", err)
+		return fmt.Errorf("failed to remove bucket policy: %w", err)
 	}
 
 	return nil
 }
 
+// UploadFile stores the content under fileName and returns the object key.
 func (m *MinioStorage) UploadFile(ctx context.Context, fileName string, contentType string, content io.Reader, size int64) (string, error) {
 	file, err := m.client.PutObject(ctx, m.bucketName, fileName, content, size, minio.PutObjectOptions{
 		ContentType: contentType,
@@ -80,11 +72,65 @@ func (m *MinioStorage) UploadFile(ctx context.Context, fileName string, contentT
 		return "", err
 	}
 
-	protocol := "http"
-	if m.useSSL {
-		protocol = "https"
+	return file.Key, nil
+}
+
+// DeleteFile removes an object previously returned by UploadFile. Public URLs stored by
+// earlier versions are accepted as well as object keys.
+func (m *MinioStorage) DeleteFile(ctx context.Context, key string) error {
+	return m.client.RemoveObject(ctx, m.bucketName, m.objectKey(key), minio.RemoveObjectOptions{})
+}
+
+// DownloadFile opens an object previously returned by UploadFile for reading.
+func (m *MinioStorage) DownloadFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	object, err := m.client.GetObject(ctx, m.bucketName, m.objectKey(key), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// GetObject is lazy; Stat surfaces a missing object before the caller starts reading
+	if _, err := object.Stat(); err != nil {
+		_ = object.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, fmt.Errorf("%w: %s", usecase.StoredFileNotFoundError, key)
+		}
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// PresignGetURL returns a URL that lets anyone holding it read the object until expiry.
+func (m *MinioStorage) PresignGetURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	presigned, err := m.client.PresignedGetObject(ctx, m.bucketName, m.objectKey(key), expiry, url.Values{})
+	if err != nil {
+		return "", err
+	}
+
+	return presigned.String(), nil
+}
+
+// ListFiles returns every object whose key starts with prefix.
+func (m *MinioStorage) ListFiles(ctx context.Context, prefix string) ([]usecase.StoredFile, error) {
+	var files []usecase.StoredFile
+	for object := range m.client.ListObjects(ctx, m.bucketName, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		files = append(files, usecase.StoredFile{
+			Key:          object.Key,
+			Size:         object.Size,
+			LastModified: object.LastModified,
+		})
 	}
 
-	url := fmt.Sprintf("%s://%s/%s/%s", protocol, m.endpoint, m.bucketName, file.Key)
-	return url, nil
+	return files, nil
+}
+
+// objectKey extracts the object key from a public URL stored by earlier versions.
+func (m *MinioStorage) objectKey(key string) string {
+	if i := strings.Index(key, "/"+m.bucketName+"/"); i >= 0 {
+		return key[i+len(m.bucketName)+2:]
+	}
+	return key
 }
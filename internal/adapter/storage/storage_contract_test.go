@@ -0,0 +1,208 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/storage"
+	"github.com/zuyatna/shop-retail-employee-service/internal/config"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+)
+
+// testStorageContract is the behaviour every StorageRepository driver shares. newStorage
+// must return an empty storage.
+func testStorageContract(t *testing.T, newStorage func(t *testing.T) usecase.StorageRepository) {
+	ctx := context.Background()
+
+	upload := func(t *testing.T, s usecase.StorageRepository, key string, content string) string {
+		stored, err := s.UploadFile(ctx, key, "text/plain", strings.NewReader(content), int64(len(content)))
+		require.NoError(t, err)
+		return stored
+	}
+
+	read := func(t *testing.T, s usecase.StorageRepository, key string) string {
+		file, err := s.DownloadFile(ctx, key)
+		require.NoError(t, err)
+		defer file.Close()
+
+		data, err := io.ReadAll(file)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("Upload Returns The Key And Download Reads It Back", func(t *testing.T) {
+		s := newStorage(t)
+
+		key := upload(t, s, "photos/a.jpg", "first")
+
+		assert.Equal(t, "photos/a.jpg", key)
+		assert.Equal(t, "first", read(t, s, key))
+	})
+
+	t.Run("Upload Replaces Existing Content", func(t *testing.T) {
+		s := newStorage(t)
+
+		upload(t, s, "photos/a.jpg", "first")
+		upload(t, s, "photos/a.jpg", "second")
+
+		assert.Equal(t, "second", read(t, s, "photos/a.jpg"))
+	})
+
+	t.Run("Download Of Missing File Is Not Found", func(t *testing.T) {
+		s := newStorage(t)
+
+		_, err := s.DownloadFile(ctx, "photos/missing.jpg")
+
+		assert.ErrorIs(t, err, usecase.StoredFileNotFoundError)
+	})
+
+	t.Run("Delete Removes The File And Tolerates Missing Ones", func(t *testing.T) {
+		s := newStorage(t)
+		upload(t, s, "photos/a.jpg", "first")
+
+		require.NoError(t, s.DeleteFile(ctx, "photos/a.jpg"))
+		require.NoError(t, s.DeleteFile(ctx, "photos/a.jpg"))
+
+		_, err := s.DownloadFile(ctx, "photos/a.jpg")
+		assert.ErrorIs(t, err, usecase.StoredFileNotFoundError)
+	})
+
+	t.Run("List Filters By Prefix And Orders By Key", func(t *testing.T) {
+		s := newStorage(t)
+		before := time.Now().Add(-time.Minute)
+
+		upload(t, s, "photos/b.jpg", "bb")
+		upload(t, s, "photos/a.jpg", "a")
+		upload(t, s, "exports/c.zip", "ccc")
+
+		files, err := s.ListFiles(ctx, "photos/")
+		require.NoError(t, err)
+
+		require.Len(t, files, 2)
+		assert.Equal(t, "photos/a.jpg", files[0].Key)
+		assert.Equal(t, int64(1), files[0].Size)
+		assert.Equal(t, "photos/b.jpg", files[1].Key)
+		assert.Equal(t, int64(2), files[1].Size)
+		for _, file := range files {
+			assert.True(t, file.LastModified.After(before), file.Key)
+		}
+	})
+
+	t.Run("List Of Empty Prefix Returns Nothing", func(t *testing.T) {
+		s := newStorage(t)
+
+		files, err := s.ListFiles(ctx, "photos/")
+
+		assert.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("Presigned URL Names The Key", func(t *testing.T) {
+		s := newStorage(t)
+		upload(t, s, "photos/a.jpg", "first")
+
+		raw, err := s.PresignGetURL(ctx, "photos/a.jpg", time.Minute)
+		require.NoError(t, err)
+
+		u, err := url.Parse(raw)
+		require.NoError(t, err)
+		assert.True(t, strings.HasSuffix(u.Path, "/photos/a.jpg"), u.Path)
+		assert.NotEmpty(t, u.RawQuery, "the URL must carry its signature")
+	})
+}
+
+func newSigner() *storage.URLSigner {
+	return storage.NewURLSigner("http://localhost:8080", []byte("test-secret"), clock.RealClock{})
+}
+
+func TestFilesystemStorage_Contract(t *testing.T) {
+	testStorageContract(t, func(t *testing.T) usecase.StorageRepository {
+		s, err := storage.NewFilesystemStorage(t.TempDir(), newSigner())
+		require.NoError(t, err)
+		return s
+	})
+}
+
+func TestMemoryStorage_Contract(t *testing.T) {
+	testStorageContract(t, func(t *testing.T) usecase.StorageRepository {
+		return storage.NewMemoryStorage(newSigner(), clock.RealClock{})
+	})
+}
+
+// TestMinioStorage_Contract runs against a real MinIO when MINIO_TEST_ENDPOINT is set,
+// e.g. the container from the README. Every run uses a fresh bucket.
+func TestMinioStorage_Contract(t *testing.T) {
+	endpoint := os.Getenv("MINIO_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("MINIO_TEST_ENDPOINT not set")
+	}
+
+	testStorageContract(t, func(t *testing.T) usecase.StorageRepository {
+		s, err := storage.NewMinioStorage(&config.Config{
+			MinioEndpoint:  endpoint,
+			MinioAccessKey: os.Getenv("MINIO_TEST_ACCESS_KEY"),
+			MinioSecretKey: os.Getenv("MINIO_TEST_SECRET_KEY"),
+			MinioBucket:    fmt.Sprintf("contract-test-%d", time.Now().UnixNano()),
+		})
+		require.NoError(t, err)
+		return s
+	})
+}
+
+func TestFilesystemStorage_RejectsKeysOutsideItsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.NewFilesystemStorage(dir+"/files", newSigner())
+	require.NoError(t, err)
+
+	for _, key := range []string{"../escape.txt", "/etc/passwd", "photos/../../escape.txt", ""} {
+		_, err := s.UploadFile(context.Background(), key, "text/plain", bytes.NewReader([]byte("x")), 1)
+		assert.Error(t, err, key)
+	}
+
+	_, err = os.Stat(dir + "/escape.txt")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestURLSigner(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	signer := storage.NewURLSigner("http://localhost:8080", []byte("test-secret"), fixedClock{now})
+
+	raw := signer.Sign("photos/a b.jpg", time.Minute)
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "/files/photos/a b.jpg", u.Path)
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+
+	t.Run("Valid Signature", func(t *testing.T) {
+		assert.NoError(t, signer.Verify("photos/a b.jpg", expires, signature))
+	})
+
+	t.Run("Other Key", func(t *testing.T) {
+		assert.ErrorIs(t, signer.Verify("photos/b.jpg", expires, signature), storage.ErrInvalidFileURL)
+	})
+
+	t.Run("Extended Expiry", func(t *testing.T) {
+		later := strconv.FormatInt(now.Add(time.Hour).Unix(), 10)
+		assert.ErrorIs(t, signer.Verify("photos/a b.jpg", later, signature), storage.ErrInvalidFileURL)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		expired := storage.NewURLSigner("http://localhost:8080", []byte("test-secret"), fixedClock{now.Add(2 * time.Minute)})
+		assert.ErrorIs(t, expired.Verify("photos/a b.jpg", expires, signature), storage.ErrFileURLExpired)
+	})
+}
+
+type fixedClock struct{ now time.Time }
+
+func (c fixedClock) Now() time.Time { return c.now }
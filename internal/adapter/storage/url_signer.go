@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+)
+
+// FilesPath is where the API serves files of the filesystem and memory drivers.
+const FilesPath = "/files/"
+
+var (
+	ErrInvalidFileURL = errors.New("invalid file URL signature")
+	ErrFileURLExpired = errors.New("file URL has expired")
+)
+
+// URLSigner stands in for presigned object store URLs when the API serves the files
+// itself. A URL carries its expiry and an HMAC over the key and expiry.
+type URLSigner struct {
+	baseURL string
+	secret  []byte
+	clock   clock.Clock
+}
+
+func NewURLSigner(baseURL string, secret []byte, clk clock.Clock) *URLSigner {
+	return &URLSigner{
+		baseURL: baseURL,
+		secret:  secret,
+		clock:   clk,
+	}
+}
+
+// Sign returns a URL for key that is valid for expiry.
+func (s *URLSigner) Sign(key string, expiry time.Duration) string {
+	expires := strconv.FormatInt(s.clock.Now().Add(expiry).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", hex.EncodeToString(s.mac(key, expires)))
+
+	path := (&url.URL{Path: FilesPath + key}).EscapedPath()
+	return s.baseURL + path + "?" + query.Encode()
+}
+
+// Verify checks the expires and signature query parameters of a URL for key.
+func (s *URLSigner) Verify(key string, expires string, signature string) error {
+	sig, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, s.mac(key, expires)) {
+		return ErrInvalidFileURL
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidFileURL
+	}
+	if s.clock.Now().After(time.Unix(unix, 0)) {
+		return ErrFileURLExpired
+	}
+
+	return nil
+}
+
+func (s *URLSigner) mac(key string, expires string) []byte {
+	m := hmac.New(sha256.New, s.secret)
+	m.Write([]byte(key))
+	m.Write([]byte{0})
+	m.Write([]byte(expires))
+	return m.Sum(nil)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"sync"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/zuyatna/shop-retail-employee-service/internal/config"
@@ -14,6 +15,9 @@ type App struct {
 	Pool    *pgxpool.Pool
 	MongoDB *mongo.Database
 	Router  http.Handler
+
+	stopJobs context.CancelFunc
+	jobsDone *sync.WaitGroup
 }
 
 func New(cfg *config.Config) (*App, error) {
@@ -29,18 +33,38 @@ func New(cfg *config.Config) (*App, error) {
 	}
 	log.Println("Connected to MongoDB database")
 
-	handler := NewHandler(pool, mongoDB, cfg)
+	handler, jobs, workers := NewHandler(pool, mongoDB, cfg)
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
 
 	app := &App{
-		Pool:    pool,
-		MongoDB: mongoDB,
-		Router:  handler,
+		Pool:     pool,
+		MongoDB:  mongoDB,
+		Router:   handler,
+		stopJobs: stopJobs,
+		jobsDone: startJobs(jobsCtx, jobs, workers),
 	}
 
 	return app, nil
 }
 
-func (a *App) Close() {
+// Close stops the background jobs and workers, waiting for them until ctx is done, and
+// closes the database connections.
+func (a *App) Close(ctx context.Context) {
+	log.Println("stopping background jobs and consumers")
+	a.stopJobs()
+
+	done := make(chan struct{})
+	go func() {
+		a.jobsDone.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Println("background jobs did not stop in time")
+	}
+
 	log.Println("closing database connection")
 	a.Pool.Close()
 
@@ -1,23 +1,29 @@
 package app
 
 import (
+	"context"
+	"log"
 	"net/http"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	adapterhttp "github.com/zuyatna/shop-retail-employee-service/internal/adapter/http"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/messaging"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/notification"
 	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/repo"
-	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/storage"
 	"github.com/zuyatna/shop-retail-employee-service/internal/config"
 	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
 	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
 	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
 	"github.com/zuyatna/shop-retail-employee-service/internal/util/idgen"
 	"github.com/zuyatna/shop-retail-employee-service/internal/util/jwtutil"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/secretbox"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func NewHandler(pool *pgxpool.Pool, mongoDB *mongo.Database, cfg *config.Config) http.Handler {
+// NewHandler wires the application and returns the HTTP router together with the
+// background jobs and workers that have to run alongside it.
+func NewHandler(pool *pgxpool.Pool, mongoDB *mongo.Database, cfg *config.Config) (http.Handler, []Job, []Worker) {
 	idGenerator := idgen.NewUUIDv7Generator()
 
 	realClock := clock.RealClock{}
@@ -28,43 +34,331 @@ func NewHandler(pool *pgxpool.Pool, mongoDB *mongo.Database, cfg *config.Config)
 		TTL:    time.Duration(cfg.JWTTTL) * time.Second,
 	}
 
-	employeeRepo := repo.NewPostgresEmployeeRepo(pool)
-	attendanceRepo := repo.NewMongoAttendanceRepo(mongoDB)
+	piiCipher, err := newPIICipher(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	employeeRepo := repo.NewPostgresEmployeeRepo(pool, piiCipher)
+	if err := encryptPlaintextPII(context.Background(), employeeRepo); err != nil {
+		panic(err)
+	}
+	roleRepo := repo.NewPostgresRoleRepo(pool)
+	loginThrottleRepo := repo.NewPostgresLoginThrottleRepo(pool)
+
+	totpBox, err := secretbox.NewFromBase64(cfg.TOTPEncryptionKey)
+	if err != nil {
+		panic(err)
+	}
+	twoFactorRepo := repo.NewPostgresTwoFactorRepo(pool, totpBox)
+	attendanceRepo := repo.NewMongoAttendanceRepo(mongoDB, cfg.AppTimezone)
+	erasureAuditRepo := repo.NewPostgresErasureAuditRepo(pool)
+	dataExportRepo := repo.NewPostgresDataExportRepo(pool)
+	documentRepo := repo.NewPostgresDocumentRepo(pool)
+	statusChangeRepo := repo.NewPostgresStatusChangeRepo(pool)
+	emailChangeRepo := repo.NewPostgresEmailChangeRepo(pool)
+	notificationRepo := repo.NewPostgresNotificationRepo(pool)
+	notificationPreferenceRepo := repo.NewPostgresNotificationPreferenceRepo(pool)
 
-	minioStorage, err := storage.NewMinioStorage(cfg)
+	webhookBox, err := secretbox.NewFromBase64(cfg.WebhookEncryptionKey)
 	if err != nil {
 		panic(err)
 	}
+	webhookSubscriptionRepo := repo.NewPostgresWebhookSubscriptionRepo(pool, webhookBox)
+	webhookDeliveryRepo := repo.NewPostgresWebhookDeliveryRepo(pool)
+	processedEventRepo := repo.NewPostgresProcessedEventRepo(pool)
+	kioskRepo := repo.NewPostgresKioskRepo(pool)
+	kioskPINRepo := repo.NewPostgresKioskPINRepo(pool)
+	kioskSyncRepo := repo.NewPostgresProcessedKioskEventRepo(pool)
+
+	fileStorage, fileURLSigner, err := newStorage(cfg, realClock)
+	if err != nil {
+		panic(err)
+	}
+
+	mailSender, err := newMailSender(cfg)
+	if err != nil {
+		panic(err)
+	}
+	webhookSender := notification.NewHTTPWebhookSender(time.Duration(cfg.WebhookTimeoutSeconds)*time.Second, cfg.WebhookAllowPrivate)
 
 	ctxTimeout := 5 * time.Second // Example timeout, can be from config
 
-	employeeUsecase := usecase.NewEmployeeUsecase(employeeRepo, minioStorage, idGenerator, ctxTimeout)
-	authUsecase := usecase.NewAuthUsecase(employeeRepo, jwtSigner, ctxTimeout)
-	attendanceUsecase := usecase.NewAttendanceUsecase(attendanceRepo, employeeRepo, idGenerator, cfg, realClock, ctxTimeout)
+	authorizer := usecase.NewAuthorizer(roleRepo, realClock, 30*time.Second)
+
+	photos := usecase.PhotoConfig{
+		MaxUploadBytes: int64(cfg.PhotoMaxUploadMB) << 20,
+		MaxDimension:   cfg.PhotoMaxDimension,
+		Size:           cfg.PhotoSize,
+		ThumbnailSize:  cfg.PhotoThumbnailSize,
+		URLTTL:         time.Duration(cfg.PhotoURLTTLMinutes) * time.Minute,
+	}
+	employeeUsecase := usecase.NewEmployeeUsecase(employeeRepo, fileStorage, idGenerator, authorizer, photos, realClock, ctxTimeout)
+	// Listing the bucket can take a while, so the cleanup gets more time than a request
+	photoCleanupUsecase := usecase.NewPhotoCleanupUsecase(employeeRepo, fileStorage, time.Duration(cfg.PhotoOrphanGraceHours)*time.Hour, realClock, time.Minute)
+	loginThrottle := usecase.LoginThrottleConfig{
+		Account: domain.LoginThrottlePolicy{
+			MaxAttempts:     cfg.LoginMaxAttempts,
+			BaseDelay:       time.Duration(cfg.LoginBackoffBaseSec) * time.Second,
+			MaxDelay:        time.Duration(cfg.LoginBackoffMaxSec) * time.Second,
+			LockoutDuration: time.Duration(cfg.LoginLockoutDuration) * time.Second,
+		},
+		IP: domain.LoginThrottlePolicy{
+			MaxAttempts:     cfg.LoginIPMaxAttempts,
+			BaseDelay:       time.Duration(cfg.LoginBackoffBaseSec) * time.Second,
+			MaxDelay:        time.Duration(cfg.LoginBackoffMaxSec) * time.Second,
+			LockoutDuration: time.Duration(cfg.LoginLockoutDuration) * time.Second,
+		},
+	}
+
+	kiosk := usecase.KioskConfig{
+		QRSecret: []byte(cfg.KioskQRSecret),
+		QRTTL:    time.Duration(cfg.KioskQRTTLSeconds) * time.Second,
+		PINThrottle: domain.LoginThrottlePolicy{
+			MaxAttempts:     cfg.KioskPINMaxAttempts,
+			BaseDelay:       time.Duration(cfg.LoginBackoffBaseSec) * time.Second,
+			MaxDelay:        time.Duration(cfg.LoginBackoffMaxSec) * time.Second,
+			LockoutDuration: time.Duration(cfg.LoginLockoutDuration) * time.Second,
+		},
+		// A kiosk is shared by the whole store, so it gets as many attempts as an IP address
+		DeviceThrottle: loginThrottle.IP,
+
+		SyncMaxClockSkew: time.Duration(cfg.KioskSyncMaxClockSkewSeconds) * time.Second,
+		SyncMaxEventAge:  time.Duration(cfg.KioskSyncMaxEventAgeHours) * time.Hour,
+		SyncMaxBatch:     cfg.KioskSyncMaxBatch,
+	}
+
+	var twoFactorRoles []domain.Role
+	for _, role := range cfg.TwoFactorRequiredRoles {
+		twoFactorRoles = append(twoFactorRoles, domain.Role(role))
+	}
+
+	authUsecase := usecase.NewAuthUsecase(employeeRepo, loginThrottleRepo, twoFactorRepo, jwtSigner, loginThrottle, twoFactorRoles, realClock, ctxTimeout)
+	twoFactorUsecase := usecase.NewTwoFactorUsecase(twoFactorRepo, employeeRepo, loginThrottleRepo, authorizer, cfg.JWTIssuer, twoFactorRoles, loginThrottle.Account, realClock, ctxTimeout)
+	deliveryRetry := domain.DeliveryRetryPolicy{
+		MaxAttempts: cfg.NotificationMaxAttempts,
+		BaseDelay:   30 * time.Second,
+		MaxDelay:    time.Hour,
+	}
+	notificationUsecase := usecase.NewNotificationUsecase(notificationRepo, notificationPreferenceRepo, employeeRepo, mailSender, webhookSender, authorizer, idGenerator, realClock, deliveryRetry, ctxTimeout)
+	webhookRetry := domain.DeliveryRetryPolicy{
+		MaxAttempts: cfg.WebhookMaxAttempts,
+		BaseDelay:   30 * time.Second,
+		MaxDelay:    time.Hour,
+	}
+	webhookUsecase := usecase.NewWebhookUsecase(webhookSubscriptionRepo, webhookDeliveryRepo, webhookSender, authorizer, idGenerator, realClock, webhookRetry, ctxTimeout)
+	attendanceUsecase := usecase.NewAttendanceUsecase(attendanceRepo, employeeRepo, fileStorage, kioskRepo, idGenerator, notificationUsecase, webhookUsecase, authorizer, photos, kiosk, cfg, realClock, ctxTimeout)
+	kioskUsecase := usecase.NewKioskUsecase(kioskRepo, kioskPINRepo, kioskSyncRepo, employeeRepo, loginThrottleRepo, attendanceUsecase, authorizer, idGenerator, kiosk, realClock, ctxTimeout)
+	roleUsecase := usecase.NewRoleUsecase(roleRepo, authorizer, ctxTimeout)
+	retention := time.Duration(cfg.RetentionPeriodDays) * 24 * time.Hour
+	lifecycleUsecase := usecase.NewLifecycleUsecase(employeeRepo, statusChangeRepo, kioskPINRepo, webhookUsecase, authorizer, idGenerator, realClock, cfg.AppTimezone, ctxTimeout)
+	inboundEventUsecase := usecase.NewInboundEventUsecase(processedEventRepo, employeeRepo, lifecycleUsecase, attendanceUsecase, realClock, ctxTimeout)
+	dataExportTTL := time.Duration(cfg.DataExportTTLHours) * time.Hour
+	dataExportUsecase := usecase.NewDataExportUsecase(dataExportRepo, employeeRepo, attendanceRepo, documentRepo, fileStorage, authorizer, idGenerator, realClock, dataExportTTL, ctxTimeout)
+	deletedEmployeeUsecase := usecase.NewDeletedEmployeeUsecase(employeeRepo, attendanceRepo, fileStorage, loginThrottleRepo, dataExportRepo, documentRepo, authorizer, realClock, ctxTimeout)
+	documentUsecase := usecase.NewDocumentUsecase(documentRepo, employeeRepo, fileStorage, authorizer, idGenerator, int64(cfg.DocumentMaxUploadMB)<<20, realClock, ctxTimeout)
+	complianceUsecase := usecase.NewComplianceUsecase(documentRepo, employeeRepo, notificationUsecase, authorizer, cfg.DocumentExpiryWindowDays, realClock, cfg.AppTimezone, ctxTimeout)
+	emailChangeTTL := time.Duration(cfg.EmailChangeTTLHours) * time.Hour
+	emailChangeUsecase := usecase.NewEmailChangeUsecase(employeeRepo, emailChangeRepo, mailSender, authorizer, idGenerator, realClock, cfg.AppBaseURL, emailChangeTTL, ctxTimeout)
+	erasureUsecase := usecase.NewErasureUsecase(employeeRepo, attendanceRepo, fileStorage, twoFactorRepo, loginThrottleRepo, dataExportRepo, documentRepo, kioskPINRepo, erasureAuditRepo, authorizer, idGenerator, realClock, retention, ctxTimeout)
 
 	employeeHandler := adapterhttp.NewEmployeeHandler(employeeUsecase)
 	authHandler := adapterhttp.NewAuthHandler(authUsecase)
+	twoFactorHandler := adapterhttp.NewTwoFactorHandler(twoFactorUsecase)
 	attendanceHandler := adapterhttp.NewAttendanceHandler(attendanceUsecase)
+	kioskHandler := adapterhttp.NewKioskHandler(kioskUsecase)
+	roleHandler := adapterhttp.NewRoleHandler(roleUsecase)
+	erasureHandler := adapterhttp.NewErasureHandler(erasureUsecase)
+	deletedEmployeeHandler := adapterhttp.NewDeletedEmployeeHandler(deletedEmployeeUsecase)
+	dataExportHandler := adapterhttp.NewDataExportHandler(dataExportUsecase)
+	documentHandler := adapterhttp.NewDocumentHandler(documentUsecase, realClock)
+	complianceHandler := adapterhttp.NewComplianceHandler(complianceUsecase)
+	lifecycleHandler := adapterhttp.NewLifecycleHandler(lifecycleUsecase)
+	emailChangeHandler := adapterhttp.NewEmailChangeHandler(emailChangeUsecase)
+	notificationHandler := adapterhttp.NewNotificationHandler(notificationUsecase)
+	webhookHandler := adapterhttp.NewWebhookHandler(webhookUsecase)
 
-	authMiddleware := adapterhttp.AuthMiddleware(jwtSigner)
+	authMiddleware := adapterhttp.AuthMiddleware(jwtSigner, authUsecase)
+	kioskMiddleware := adapterhttp.KioskMiddleware(kioskUsecase)
+	clientIPMiddleware := adapterhttp.ClientIPMiddleware(cfg.TrustedProxies)
+	enrollmentAuthMiddleware := adapterhttp.AuthMiddleware(jwtSigner, authUsecase, jwtutil.PurposeTwoFactorEnrollment)
 
-	requirePrivileged := adapterhttp.RoleMiddleware(string(domain.RoleAdmin), string(domain.RoleSupervisor))
-	requireAllRoles := adapterhttp.RoleMiddleware(string(domain.RoleAdmin), string(domain.RoleSupervisor), string(domain.RoleStaff))
+	// can guards a route with the permission policy; holding any of perms is enough
+	can := func(perms ...domain.Permission) func(http.Handler) http.Handler {
+		return adapterhttp.PermissionMiddleware(authorizer, perms...)
+	}
 
 	mux := http.NewServeMux()
 
-	mux.HandleFunc("POST /auth/login", authHandler.Login)
+	mux.HandleFunc("POST /auth/login", clientIPMiddleware(http.HandlerFunc(authHandler.Login)).ServeHTTP)
+	mux.HandleFunc("POST /auth/2fa/verify", clientIPMiddleware(http.HandlerFunc(authHandler.VerifyTwoFactor)).ServeHTTP)
+	mux.HandleFunc("GET /email-change/confirm", emailChangeHandler.Confirm)
+	if fileURLSigner != nil {
+		// Signed links stand in for presigned object store URLs, so no auth middleware
+		fileHandler := adapterhttp.NewFileHandler(fileStorage, fileURLSigner)
+		mux.HandleFunc("GET /files/{key...}", fileHandler.Get)
+	}
+	mux.HandleFunc("POST /auth/2fa/enroll", enrollmentAuthMiddleware(http.HandlerFunc(twoFactorHandler.Enroll)).ServeHTTP)
+	mux.HandleFunc("POST /auth/2fa/confirm", enrollmentAuthMiddleware(http.HandlerFunc(twoFactorHandler.Confirm)).ServeHTTP)
+	mux.HandleFunc("POST /auth/2fa/disable", authMiddleware(http.HandlerFunc(twoFactorHandler.Disable)).ServeHTTP)
+
+	mux.HandleFunc("GET /employees/me", authMiddleware(can(domain.PermEmployeeReadSelf)(http.HandlerFunc(employeeHandler.GetMe))).ServeHTTP)
+	mux.HandleFunc("POST /employees", authMiddleware(can(domain.PermEmployeeCreate)(http.HandlerFunc(employeeHandler.Register))).ServeHTTP)
+	mux.HandleFunc("GET /employees", authMiddleware(can(domain.PermEmployeeRead)(http.HandlerFunc(employeeHandler.GetAll))).ServeHTTP)
+	mux.HandleFunc("GET /employees/deleted", authMiddleware(can(domain.PermEmployeeRestore)(http.HandlerFunc(deletedEmployeeHandler.List))).ServeHTTP)
+	mux.HandleFunc("GET /employees/{id}", authMiddleware(can(domain.PermEmployeeRead)(http.HandlerFunc(employeeHandler.GetByID))).ServeHTTP)
+	mux.HandleFunc("PATCH /employees/{id}", authMiddleware(can(domain.PermEmployeeUpdate, domain.PermEmployeeUpdateSelf)(http.HandlerFunc(employeeHandler.Update))).ServeHTTP)
+	mux.HandleFunc("GET /employees/{id}/photo", authMiddleware(can(domain.PermEmployeeRead, domain.PermEmployeeReadSelf)(http.HandlerFunc(employeeHandler.GetPhoto))).ServeHTTP)
+	mux.HandleFunc("POST /employees/{id}/photo", authMiddleware(can(domain.PermEmployeePhotoUpload, domain.PermEmployeePhotoUploadSelf)(http.HandlerFunc(employeeHandler.UploadPhoto))).ServeHTTP)
+	mux.HandleFunc("GET /employees/{id}/documents", authMiddleware(can(domain.PermDocumentManage, domain.PermDocumentReadSelf)(http.HandlerFunc(documentHandler.List))).ServeHTTP)
+	mux.HandleFunc("POST /employees/{id}/documents", authMiddleware(can(domain.PermDocumentManage, domain.PermDocumentUploadSelf)(http.HandlerFunc(documentHandler.Upload))).ServeHTTP)
+	mux.HandleFunc("GET /employees/{id}/documents/{documentID}/download", authMiddleware(can(domain.PermDocumentManage, domain.PermDocumentReadSelf)(http.HandlerFunc(documentHandler.Download))).ServeHTTP)
+	mux.HandleFunc("DELETE /employees/{id}/documents/{documentID}", authMiddleware(can(domain.PermDocumentManage)(http.HandlerFunc(documentHandler.Delete))).ServeHTTP)
+	mux.HandleFunc("DELETE /employees/{id}", authMiddleware(can(domain.PermEmployeeDelete)(http.HandlerFunc(employeeHandler.Delete))).ServeHTTP)
+	mux.HandleFunc("POST /employees/{id}/email-change", authMiddleware(can(domain.PermEmployeeUpdate, domain.PermEmployeeUpdateSelf)(http.HandlerFunc(emailChangeHandler.Request))).ServeHTTP)
+	mux.HandleFunc("POST /employees/{id}/restore", authMiddleware(can(domain.PermEmployeeRestore)(http.HandlerFunc(deletedEmployeeHandler.Restore))).ServeHTTP)
+	mux.HandleFunc("GET /employees/{id}/purge-plan", authMiddleware(can(domain.PermEmployeePurge)(http.HandlerFunc(deletedEmployeeHandler.PurgePlan))).ServeHTTP)
+	mux.HandleFunc("DELETE /employees/{id}/purge", authMiddleware(can(domain.PermEmployeePurge)(http.HandlerFunc(deletedEmployeeHandler.Purge))).ServeHTTP)
+	mux.HandleFunc("POST /employees/{id}/activate", authMiddleware(can(domain.PermEmployeeLifecycle)(http.HandlerFunc(lifecycleHandler.Activate))).ServeHTTP)
+	mux.HandleFunc("POST /employees/{id}/suspend", authMiddleware(can(domain.PermEmployeeLifecycle)(http.HandlerFunc(lifecycleHandler.Suspend))).ServeHTTP)
+	mux.HandleFunc("POST /employees/{id}/reactivate", authMiddleware(can(domain.PermEmployeeLifecycle)(http.HandlerFunc(lifecycleHandler.Reactivate))).ServeHTTP)
+	mux.HandleFunc("POST /employees/{id}/terminate", authMiddleware(can(domain.PermEmployeeLifecycle)(http.HandlerFunc(lifecycleHandler.Terminate))).ServeHTTP)
+	mux.HandleFunc("GET /employees/{id}/status-history", authMiddleware(can(domain.PermEmployeeLifecycle)(http.HandlerFunc(lifecycleHandler.History))).ServeHTTP)
+	mux.HandleFunc("POST /employees/{id}/unlock", authMiddleware(can(domain.PermAccountUnlock)(http.HandlerFunc(authHandler.Unlock))).ServeHTTP)
+	mux.HandleFunc("DELETE /employees/{id}/2fa", authMiddleware(can(domain.PermAccountTwoFactorReset)(http.HandlerFunc(twoFactorHandler.Reset))).ServeHTTP)
+	mux.HandleFunc("POST /employees/{id}/erasure", authMiddleware(can(domain.PermEmployeeErase)(http.HandlerFunc(erasureHandler.Erase))).ServeHTTP)
+	mux.HandleFunc("GET /employees/{id}/data-export", authMiddleware(can(domain.PermEmployeeExport, domain.PermEmployeeExportSelf)(http.HandlerFunc(dataExportHandler.Get))).ServeHTTP)
+	mux.HandleFunc("GET /employees/{id}/data-export/{exportID}/download", authMiddleware(can(domain.PermEmployeeExport, domain.PermEmployeeExportSelf)(http.HandlerFunc(dataExportHandler.Download))).ServeHTTP)
+	mux.HandleFunc("GET /employees/{id}/erasure", authMiddleware(can(domain.PermEmployeeErase)(http.HandlerFunc(erasureHandler.History))).ServeHTTP)
+
+	mux.HandleFunc("POST /attendances/checkin", authMiddleware(can(domain.PermAttendanceRecord)(http.HandlerFunc(attendanceHandler.CheckIn))).ServeHTTP)
+	mux.HandleFunc("POST /attendances/checkout", authMiddleware(can(domain.PermAttendanceRecord)(http.HandlerFunc(attendanceHandler.CheckOut))).ServeHTTP)
+	mux.HandleFunc("POST /attendances/break/start", authMiddleware(can(domain.PermAttendanceRecord)(http.HandlerFunc(attendanceHandler.StartBreak))).ServeHTTP)
+	mux.HandleFunc("POST /attendances/break/end", authMiddleware(can(domain.PermAttendanceRecord)(http.HandlerFunc(attendanceHandler.EndBreak))).ServeHTTP)
+	mux.HandleFunc("PUT /employees/me/kiosk-pin", authMiddleware(can(domain.PermAttendanceRecord)(http.HandlerFunc(kioskHandler.SetPIN))).ServeHTTP)
+	mux.HandleFunc("GET /employees/{id}/attendances", authMiddleware(can(domain.PermAttendanceApprove)(http.HandlerFunc(attendanceHandler.Review))).ServeHTTP)
+
+	mux.HandleFunc("POST /kiosks", authMiddleware(can(domain.PermKioskManage)(http.HandlerFunc(kioskHandler.Register))).ServeHTTP)
+	mux.HandleFunc("GET /kiosks", authMiddleware(can(domain.PermKioskManage)(http.HandlerFunc(kioskHandler.List))).ServeHTTP)
+	mux.HandleFunc("DELETE /kiosks/{id}", authMiddleware(can(domain.PermKioskManage)(http.HandlerFunc(kioskHandler.Revoke))).ServeHTTP)
+	// Kiosks authenticate as their store with a device token instead of an employee session
+	mux.HandleFunc("GET /kiosk/qr", kioskMiddleware(http.HandlerFunc(kioskHandler.QR)).ServeHTTP)
+	mux.HandleFunc("POST /kiosk/checkin", kioskMiddleware(http.HandlerFunc(kioskHandler.CheckIn)).ServeHTTP)
+	mux.HandleFunc("POST /kiosk/checkout", kioskMiddleware(http.HandlerFunc(kioskHandler.CheckOut)).ServeHTTP)
+	mux.HandleFunc("POST /kiosk/break/start", kioskMiddleware(http.HandlerFunc(kioskHandler.StartBreak)).ServeHTTP)
+	mux.HandleFunc("POST /kiosk/break/end", kioskMiddleware(http.HandlerFunc(kioskHandler.EndBreak)).ServeHTTP)
+	mux.HandleFunc("POST /kiosk/sync", kioskMiddleware(http.HandlerFunc(kioskHandler.Sync)).ServeHTTP)
+
+	mux.HandleFunc("GET /compliance/expiring", authMiddleware(can(domain.PermComplianceRead)(http.HandlerFunc(complianceHandler.Expiring))).ServeHTTP)
 
-	mux.HandleFunc("GET /employees/me", authMiddleware(requireAllRoles(http.HandlerFunc(employeeHandler.GetMe))).ServeHTTP)
-	mux.HandleFunc("POST /employees", authMiddleware(requirePrivileged(http.HandlerFunc(employeeHandler.Register))).ServeHTTP)
-	mux.HandleFunc("GET /employees", authMiddleware(requirePrivileged(http.HandlerFunc(employeeHandler.GetAll))).ServeHTTP)
-	mux.HandleFunc("GET /employees/{id}", authMiddleware(requirePrivileged(http.HandlerFunc(employeeHandler.GetByID))).ServeHTTP)
-	mux.HandleFunc("PATCH /employees/{id}", authMiddleware(requirePrivileged(http.HandlerFunc(employeeHandler.Update))).ServeHTTP)
-	mux.HandleFunc("POST /employees/{id}/photo", authMiddleware(requireAllRoles(http.HandlerFunc(employeeHandler.UploadPhoto))).ServeHTTP)
-	mux.HandleFunc("DELETE /employees/{id}", authMiddleware(requirePrivileged(http.HandlerFunc(employeeHandler.Delete))).ServeHTTP)
+	mux.HandleFunc("GET /notifications", authMiddleware(http.HandlerFunc(notificationHandler.Inbox)).ServeHTTP)
+	mux.HandleFunc("POST /notifications/{id}/read", authMiddleware(http.HandlerFunc(notificationHandler.MarkRead)).ServeHTTP)
+	mux.HandleFunc("POST /notifications/read-all", authMiddleware(http.HandlerFunc(notificationHandler.MarkAllRead)).ServeHTTP)
+	mux.HandleFunc("GET /notifications/preferences", authMiddleware(http.HandlerFunc(notificationHandler.GetPreferences)).ServeHTTP)
+	mux.HandleFunc("PUT /notifications/preferences", authMiddleware(http.HandlerFunc(notificationHandler.UpdatePreferences)).ServeHTTP)
 
-	mux.HandleFunc("POST /attendances/checkin", authMiddleware(requireAllRoles(http.HandlerFunc(attendanceHandler.CheckIn))).ServeHTTP)
-	mux.HandleFunc("POST /attendances/checkout", authMiddleware(requireAllRoles(http.HandlerFunc(attendanceHandler.CheckOut))).ServeHTTP)
+	mux.HandleFunc("POST /webhooks", authMiddleware(can(domain.PermWebhookManage)(http.HandlerFunc(webhookHandler.Create))).ServeHTTP)
+	mux.HandleFunc("GET /webhooks", authMiddleware(can(domain.PermWebhookManage)(http.HandlerFunc(webhookHandler.List))).ServeHTTP)
+	mux.HandleFunc("GET /webhooks/{id}", authMiddleware(can(domain.PermWebhookManage)(http.HandlerFunc(webhookHandler.Get))).ServeHTTP)
+	mux.HandleFunc("PUT /webhooks/{id}", authMiddleware(can(domain.PermWebhookManage)(http.HandlerFunc(webhookHandler.Update))).ServeHTTP)
+	mux.HandleFunc("DELETE /webhooks/{id}", authMiddleware(can(domain.PermWebhookManage)(http.HandlerFunc(webhookHandler.Delete))).ServeHTTP)
+	mux.HandleFunc("POST /webhooks/{id}/rotate-secret", authMiddleware(can(domain.PermWebhookManage)(http.HandlerFunc(webhookHandler.RotateSecret))).ServeHTTP)
+	mux.HandleFunc("GET /webhooks/{id}/deliveries", authMiddleware(can(domain.PermWebhookManage)(http.HandlerFunc(webhookHandler.Deliveries))).ServeHTTP)
+	mux.HandleFunc("POST /webhooks/{id}/deliveries/{deliveryID}/redeliver", authMiddleware(can(domain.PermWebhookManage)(http.HandlerFunc(webhookHandler.Redeliver))).ServeHTTP)
+
+	mux.HandleFunc("GET /roles", authMiddleware(can(domain.PermRoleManage)(http.HandlerFunc(roleHandler.GetAll))).ServeHTTP)
+	mux.HandleFunc("GET /permissions", authMiddleware(can(domain.PermRoleManage)(http.HandlerFunc(roleHandler.GetPermissions))).ServeHTTP)
+	mux.HandleFunc("PUT /roles/{name}", authMiddleware(can(domain.PermRoleManage)(http.HandlerFunc(roleHandler.Save))).ServeHTTP)
+	mux.HandleFunc("DELETE /roles/{name}", authMiddleware(can(domain.PermRoleManage)(http.HandlerFunc(roleHandler.Delete))).ServeHTTP)
+
+	jobs := []Job{
+		{
+			Name:     "lifecycle",
+			Interval: time.Minute,
+			Run: func(ctx context.Context) error {
+				_, err := lifecycleUsecase.ApplyDue(ctx)
+				return err
+			},
+		},
+		{
+			Name:     "retention",
+			Interval: time.Duration(cfg.RetentionSweepMinutes) * time.Minute,
+			Run: func(ctx context.Context) error {
+				erased, err := erasureUsecase.ApplyRetention(ctx)
+				if erased > 0 {
+					log.Printf("retention job erased %d employees", erased)
+				}
+				return err
+			},
+		},
+		{
+			Name:     "data-exports",
+			Interval: time.Duration(cfg.DataExportPollSeconds) * time.Second,
+			Run: func(ctx context.Context) error {
+				if _, err := dataExportUsecase.ProcessPending(ctx); err != nil {
+					return err
+				}
+				_, err := dataExportUsecase.PurgeExpired(ctx)
+				return err
+			},
+		},
+		{
+			Name:     "photo-cleanup",
+			Interval: time.Duration(cfg.PhotoCleanupMinutes) * time.Minute,
+			Run: func(ctx context.Context) error {
+				_, err := photoCleanupUsecase.RemoveOrphans(ctx)
+				return err
+			},
+		},
+		{
+			Name:     "document-expiry",
+			Interval: time.Duration(cfg.DocumentExpirySweepHours) * time.Hour,
+			Run: func(ctx context.Context) error {
+				_, err := complianceUsecase.SendExpiryReminders(ctx)
+				return err
+			},
+		},
+		{
+			Name:     "notifications",
+			Interval: time.Duration(cfg.NotificationPollSeconds) * time.Second,
+			Run: func(ctx context.Context) error {
+				_, err := notificationUsecase.DeliverDue(ctx)
+				return err
+			},
+		},
+		{
+			Name:     "missed-check-outs",
+			Interval: time.Duration(cfg.MissedCheckOutSweepMinutes) * time.Minute,
+			Run: func(ctx context.Context) error {
+				_, err := attendanceUsecase.ReportMissedCheckOuts(ctx)
+				return err
+			},
+		},
+		{
+			Name:     "kiosk-sync-events",
+			Interval: time.Hour,
+			Run: func(ctx context.Context) error {
+				_, err := kioskUsecase.PurgeSyncedEvents(ctx)
+				return err
+			},
+		},
+		{
+			Name:     "webhooks",
+			Interval: time.Duration(cfg.WebhookPollSeconds) * time.Second,
+			Run: func(ctx context.Context) error {
+				_, err := webhookUsecase.DeliverDue(ctx)
+				return err
+			},
+		},
+	}
+
+	var workers []Worker
+	if cfg.RabbitMQURL != "" {
+		consumer := messaging.NewAMQPConsumer(cfg, inboundEventUsecase)
+		workers = append(workers, Worker{Name: "inbound-events", Run: consumer.Run})
+	} else {
+		log.Println("RABBITMQ_URL not set, not consuming events from other services")
+	}
 
-	return mux
+	return mux, jobs, workers
 }
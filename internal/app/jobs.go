@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is a background task that runs periodically while the application is up.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Worker is a long running task, e.g. a message consumer, that runs until its context is
+// cancelled and then returns after finishing the work in hand.
+type Worker struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// startJobs runs every job on its own ticker and every worker in its own goroutine until
+// ctx is cancelled. The returned WaitGroup is done once all of them have returned.
+func startJobs(ctx context.Context, jobs []Job, workers []Worker) *sync.WaitGroup {
+	var wg sync.WaitGroup
+
+	for _, worker := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := worker.Run(ctx); err != nil {
+				log.Printf("worker %s failed: %v", worker.Name, err)
+			}
+		}()
+	}
+
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ticker := time.NewTicker(job.Interval)
+			defer ticker.Stop()
+
+			for {
+				if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+					log.Printf("job %s failed: %v", job.Name, err)
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+	}
+
+	return &wg
+}
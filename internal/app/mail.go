@@ -0,0 +1,19 @@
+package app
+
+import (
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/notification"
+	"github.com/zuyatna/shop-retail-employee-service/internal/config"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+// newMailSender picks the mail adapter configured by NOTIFICATION_DRIVER.
+func newMailSender(cfg *config.Config) (usecase.MailSender, error) {
+	switch cfg.NotificationDriver {
+	case "smtp":
+		return notification.NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom), nil
+	case "memory":
+		return notification.NewMemorySender(), nil
+	default:
+		return notification.NewFileSender(cfg.NotificationFileDir, cfg.MailFrom)
+	}
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/repo"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/repo/record"
+	"github.com/zuyatna/shop-retail-employee-service/internal/config"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/envelope"
+)
+
+func newPIICipher(cfg *config.Config) (*record.PIICipher, error) {
+	keyring, err := envelope.ParseKeyring(cfg.PIIKeyID, cfg.PIIKeys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PII_KEYS: %w", err)
+	}
+
+	blindIndex, err := envelope.NewBlindIndexFromBase64(cfg.PIIBlindIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PII_BLIND_INDEX_KEY: %w", err)
+	}
+
+	return &record.PIICipher{Keyring: keyring, BlindIndex: blindIndex}, nil
+}
+
+// encryptPlaintextPII encrypts employee rows left in plaintext by the upgrade to PII
+// encryption, which also fills the blind index phone uniqueness relies on.
+func encryptPlaintextPII(ctx context.Context, employeeRepo *repo.PostgresEmployeeRepo) error {
+	count, err := employeeRepo.EncryptPlaintextPII(ctx, 500)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt plaintext employee PII: %w", err)
+	}
+	if count > 0 {
+		log.Printf("Encrypted PII of %d employees written before encryption", count)
+	}
+	return nil
+}
+
+// ReencryptPII re-encrypts all employee PII with the current key-encryption key.
+// It is used after rotating PII_KEY_ID and to encrypt rows written before encryption existed.
+func ReencryptPII(ctx context.Context, cfg *config.Config, batchSize int) (int, error) {
+	cipher, err := newPIICipher(cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	pool, err := initPostgres(cfg)
+	if err != nil {
+		return 0, err
+	}
+	defer pool.Close()
+
+	return repo.NewPostgresEmployeeRepo(pool, cipher).ReencryptPII(ctx, batchSize)
+}
@@ -0,0 +1,25 @@
+package app
+
+import (
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/storage"
+	"github.com/zuyatna/shop-retail-employee-service/internal/config"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+)
+
+// newStorage returns the storage driver selected in the config. The signer is nil for
+// MinIO, which presigns its own URLs; otherwise the API has to serve the files.
+func newStorage(cfg *config.Config, clk clock.Clock) (usecase.StorageRepository, *storage.URLSigner, error) {
+	signer := storage.NewURLSigner(cfg.AppBaseURL, []byte(cfg.StorageURLSecret), clk)
+
+	switch cfg.StorageDriver {
+	case "filesystem":
+		s, err := storage.NewFilesystemStorage(cfg.StorageDir, signer)
+		return s, signer, err
+	case "memory":
+		return storage.NewMemoryStorage(signer, clk), signer, nil
+	default:
+		s, err := storage.NewMinioStorage(cfg)
+		return s, nil, err
+	}
+}
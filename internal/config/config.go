@@ -2,7 +2,10 @@ package config
 
 import (
 	"fmt"
+	"net/netip"
 	"os"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -22,18 +25,90 @@ type Config struct {
 	MongoUri    string
 	MongoDbName string
 
-	MinioEndpoint  string
-	MinioAccessKey string
-	MinioSecretKey string
-	MinioBucket    string
-	MinioUseSSL    bool
+	StorageDriver    string // minio, filesystem or memory
+	StorageDir       string // where the filesystem driver keeps files
+	StorageURLSecret string // signs the file URLs of the filesystem and memory drivers, defaults to JWT_SECRET
+
+	MinioEndpoint      string // MINIO_* are only required by the minio driver
+	MinioAccessKey     string
+	MinioSecretKey     string
+	MinioBucket        string
+	MinioUseSSL        bool
+	PhotoURLTTLMinutes int // lifetime of presigned employee photo URLs
+	PhotoMaxUploadMB   int // largest accepted photo upload
+	PhotoMaxDimension  int // widest or tallest accepted photo upload, in pixels
+	PhotoSize          int // edge of the stored square avatar, in pixels
+	PhotoThumbnailSize int // edge of the stored square thumbnail, in pixels
+
+	PhotoCleanupMinutes   int // how often unreferenced photo objects are looked for
+	PhotoOrphanGraceHours int // how old an unreferenced photo object must be before it is deleted
+
+	DocumentMaxUploadMB int // largest accepted employee document upload
+
+	DocumentExpiryWindowDays []int // days before a document expires that reminders are sent
+	DocumentExpirySweepHours int   // how often expiring documents are looked for
 
 	OfficeStartHour int
 	OfficeStartMin  int
+	MinBreakMinutes int // breaks shorter than this count as worked time
+
+	LoginMaxAttempts     int
+	LoginIPMaxAttempts   int
+	LoginBackoffBaseSec  int
+	LoginBackoffMaxSec   int
+	LoginLockoutDuration int // in seconds
+
+	// TrustedProxies are the reverse proxies whose X-Forwarded-For entries are believed
+	// when the client IP is throttled. Without any, the peer address is used.
+	TrustedProxies []netip.Prefix
+
+	KioskQRSecret       string // signs the QR codes store kiosks show, defaults to JWT_SECRET
+	KioskQRTTLSeconds   int    // how long a kiosk QR code can be scanned after it was shown
+	KioskPINMaxAttempts int    // wrong kiosk PINs of an employee before the backoff starts
+
+	KioskSyncMaxClockSkewSeconds int // how far a kiosk clock may be off when it syncs offline events
+	KioskSyncMaxEventAgeHours    int // how old an offline kiosk event may be when it is synced
+	KioskSyncMaxBatch            int // most offline events a kiosk syncs at once
+
+	TOTPEncryptionKey      string   // base64 encoded 32 byte key
+	PIIKeyID               string   // ID of the key-encryption key used for new data keys
+	PIIKeys                string   // comma separated id:base64key pairs, old keys kept for rotation
+	PIIBlindIndexKey       string   // base64 encoded key of at least 32 bytes
+	TwoFactorRequiredRoles []string // roles that must enroll before they can log in
+
+	RetentionPeriodDays   int // personal data of deleted employees is erased after this many days
+	RetentionSweepMinutes int // how often the retention job runs
+
+	DataExportTTLHours    int // how long a generated data export can be downloaded
+	DataExportPollSeconds int // how often pending data exports are picked up
+
+	NotificationDriver  string // smtp, file or memory
+	NotificationFileDir string // where the file driver writes .eml files
+	SMTPHost            string
+	SMTPPort            string
+	SMTPUsername        string
+	SMTPPassword        string
+	MailFrom            string
+
+	AppBaseURL          string // public URL used in links sent to employees
+	EmailChangeTTLHours int    // how long an email verification link stays valid
+
+	NotificationPollSeconds    int // how often due email and webhook deliveries are sent
+	NotificationMaxAttempts    int // a delivery fails for good after this many attempts
+	WebhookTimeoutSeconds      int
+	WebhookAllowPrivate        bool // lets webhooks reach loopback and private addresses, e.g. partners on the internal network
+	MissedCheckOutSweepMinutes int  // how often missing check-outs are looked for
+
+	WebhookEncryptionKey string // base64 encoded 32 byte key for subscription secrets, defaults to TOTP_ENCRYPTION_KEY
+	WebhookPollSeconds   int    // how often due partner webhook deliveries are sent
+	WebhookMaxAttempts   int    // a partner delivery goes to the dead letter state after this many attempts
 
 	AppTimezone *time.Location
 
-	RabbitMQURL string
+	RabbitMQURL      string // events from other services are consumed only when set
+	RabbitMQExchange string // topic exchange the shop-retail services publish to
+	RabbitMQQueue    string
+	RabbitMQPrefetch int
 }
 
 func Load() *Config {
@@ -59,16 +134,88 @@ func Load() *Config {
 		MongoUri:    getEnv("MONGO_URI"),
 		MongoDbName: getEnv("MONGO_DB_NAME"),
 
-		MinioEndpoint:  getEnv("MINIO_ENDPOINT"),
-		MinioAccessKey: getEnv("MINIO_ACCESS_KEY"),
-		MinioSecretKey: getEnv("MINIO_SECRET_KEY"),
-		MinioBucket:    getEnv("MINIO_BUCKET"),
-		MinioUseSSL:    getEnv("MINIO_USE_SSL") == "true",
+		StorageDriver:    getEnvOrDefault("STORAGE_DRIVER", "minio"),
+		StorageDir:       getEnvOrDefault("STORAGE_DIR", "./tmp/storage"),
+		StorageURLSecret: getEnvOrDefault("STORAGE_URL_SECRET", getEnv("JWT_SECRET")),
+
+		MinioEndpoint:      getEnvOrDefault("MINIO_ENDPOINT", ""),
+		MinioAccessKey:     getEnvOrDefault("MINIO_ACCESS_KEY", ""),
+		MinioSecretKey:     getEnvOrDefault("MINIO_SECRET_KEY", ""),
+		MinioBucket:        getEnvOrDefault("MINIO_BUCKET", ""),
+		MinioUseSSL:        getEnvOrDefault("MINIO_USE_SSL", "") == "true",
+		PhotoURLTTLMinutes: atoiOrDefault(getEnvOrDefault("PHOTO_URL_TTL_MINUTES", ""), 15),
+		PhotoMaxUploadMB:   atoiOrDefault(getEnvOrDefault("PHOTO_MAX_UPLOAD_MB", ""), 10),
+		PhotoMaxDimension:  atoiOrDefault(getEnvOrDefault("PHOTO_MAX_DIMENSION", ""), 8000),
+		PhotoSize:          atoiOrDefault(getEnvOrDefault("PHOTO_SIZE", ""), 512),
+		PhotoThumbnailSize: atoiOrDefault(getEnvOrDefault("PHOTO_THUMBNAIL_SIZE", ""), 128),
+
+		PhotoCleanupMinutes:   atoiOrDefault(getEnvOrDefault("PHOTO_CLEANUP_MINUTES", ""), 60),
+		PhotoOrphanGraceHours: atoiOrDefault(getEnvOrDefault("PHOTO_ORPHAN_GRACE_HOURS", ""), 24),
+
+		DocumentMaxUploadMB: atoiOrDefault(getEnvOrDefault("DOCUMENT_MAX_UPLOAD_MB", ""), 20),
+
+		DocumentExpiryWindowDays: atoiList(getEnvOrDefault("DOCUMENT_EXPIRY_WINDOWS_DAYS", "30,7,1")),
+		DocumentExpirySweepHours: atoiOrDefault(getEnvOrDefault("DOCUMENT_EXPIRY_SWEEP_HOURS", ""), 24),
 
 		OfficeStartHour: atoiOrDefault(getEnv("OFFICE_START_HOUR"), 9),
 		OfficeStartMin:  atoiOrDefault(getEnv("OFFICE_START_MIN"), 0),
+		MinBreakMinutes: atoiOrDefault(getEnvOrDefault("MIN_BREAK_MINUTES", ""), 15),
+
+		LoginMaxAttempts:     atoiOrDefault(getEnvOrDefault("LOGIN_MAX_ATTEMPTS", ""), 5),
+		LoginIPMaxAttempts:   atoiOrDefault(getEnvOrDefault("LOGIN_IP_MAX_ATTEMPTS", ""), 20),
+		LoginBackoffBaseSec:  atoiOrDefault(getEnvOrDefault("LOGIN_BACKOFF_BASE", ""), 1),
+		LoginBackoffMaxSec:   atoiOrDefault(getEnvOrDefault("LOGIN_BACKOFF_MAX", ""), 60),
+		LoginLockoutDuration: atoiOrDefault(getEnvOrDefault("LOGIN_LOCKOUT_DURATION", ""), 900),
+
+		TrustedProxies: prefixList(getEnvOrDefault("TRUSTED_PROXIES", "")),
+
+		KioskQRSecret:       getEnvOrDefault("KIOSK_QR_SECRET", getEnv("JWT_SECRET")),
+		KioskQRTTLSeconds:   atoiOrDefault(getEnvOrDefault("KIOSK_QR_TTL_SECONDS", ""), 30),
+		KioskPINMaxAttempts: atoiOrDefault(getEnvOrDefault("KIOSK_PIN_MAX_ATTEMPTS", ""), 5),
+
+		KioskSyncMaxClockSkewSeconds: atoiOrDefault(getEnvOrDefault("KIOSK_SYNC_MAX_CLOCK_SKEW_SECONDS", ""), 300),
+		KioskSyncMaxEventAgeHours:    atoiOrDefault(getEnvOrDefault("KIOSK_SYNC_MAX_EVENT_AGE_HOURS", ""), 72),
+		KioskSyncMaxBatch:            atoiOrDefault(getEnvOrDefault("KIOSK_SYNC_MAX_BATCH", ""), 200),
+
+		TOTPEncryptionKey:      getEnv("TOTP_ENCRYPTION_KEY"),
+		PIIKeyID:               getEnv("PII_KEY_ID"),
+		PIIKeys:                getEnv("PII_KEYS"),
+		PIIBlindIndexKey:       getEnv("PII_BLIND_INDEX_KEY"),
+		TwoFactorRequiredRoles: splitList(getEnvOrDefault("TWO_FACTOR_REQUIRED_ROLES", "admin,supervisor")),
+
+		RetentionPeriodDays:   atoiOrDefault(getEnvOrDefault("RETENTION_PERIOD_DAYS", ""), 730),
+		RetentionSweepMinutes: atoiOrDefault(getEnvOrDefault("RETENTION_SWEEP_MINUTES", ""), 1440),
+
+		DataExportTTLHours:    atoiOrDefault(getEnvOrDefault("DATA_EXPORT_TTL_HOURS", ""), 24),
+		DataExportPollSeconds: atoiOrDefault(getEnvOrDefault("DATA_EXPORT_POLL_SECONDS", ""), 30),
+
+		NotificationDriver:  getEnvOrDefault("NOTIFICATION_DRIVER", "file"),
+		NotificationFileDir: getEnvOrDefault("NOTIFICATION_FILE_DIR", "./tmp/mail"),
+		SMTPHost:            getEnvOrDefault("SMTP_HOST", ""),
+		SMTPPort:            getEnvOrDefault("SMTP_PORT", "587"),
+		SMTPUsername:        getEnvOrDefault("SMTP_USERNAME", ""),
+		SMTPPassword:        getEnvOrDefault("SMTP_PASSWORD", ""),
+		MailFrom:            getEnvOrDefault("MAIL_FROM", "no-reply@shop.local"),
+
+		AppBaseURL:          strings.TrimRight(getEnvOrDefault("APP_BASE_URL", "http://localhost:8080"), "/"),
+		EmailChangeTTLHours: atoiOrDefault(getEnvOrDefault("EMAIL_CHANGE_TTL_HOURS", ""), 24),
+
+		NotificationPollSeconds:    atoiOrDefault(getEnvOrDefault("NOTIFICATION_POLL_SECONDS", ""), 15),
+		NotificationMaxAttempts:    atoiOrDefault(getEnvOrDefault("NOTIFICATION_MAX_ATTEMPTS", ""), 5),
+		WebhookTimeoutSeconds:      atoiOrDefault(getEnvOrDefault("WEBHOOK_TIMEOUT_SECONDS", ""), 10),
+		WebhookAllowPrivate:        getEnvOrDefault("WEBHOOK_ALLOW_PRIVATE", "") == "true",
+		MissedCheckOutSweepMinutes: atoiOrDefault(getEnvOrDefault("MISSED_CHECK_OUT_SWEEP_MINUTES", ""), 60),
+
+		WebhookEncryptionKey: getEnvOrDefault("WEBHOOK_ENCRYPTION_KEY", getEnv("TOTP_ENCRYPTION_KEY")),
+		WebhookPollSeconds:   atoiOrDefault(getEnvOrDefault("WEBHOOK_POLL_SECONDS", ""), 10),
+		WebhookMaxAttempts:   atoiOrDefault(getEnvOrDefault("WEBHOOK_MAX_ATTEMPTS", ""), 8),
 
 		AppTimezone: loc,
+
+		RabbitMQURL:      getEnvOrDefault("RABBITMQ_URL", ""),
+		RabbitMQExchange: getEnvOrDefault("RABBITMQ_EXCHANGE", "shop.events"),
+		RabbitMQQueue:    getEnvOrDefault("RABBITMQ_QUEUE", "employee-service.events"),
+		RabbitMQPrefetch: atoiOrDefault(getEnvOrDefault("RABBITMQ_PREFETCH", ""), 10),
 	}
 
 	cfg.validate()
@@ -91,6 +238,69 @@ func (c *Config) validate() {
 	if c.JWTTTL <= 0 {
 		panic("JWT_TTL must be greater than zero")
 	}
+	switch c.StorageDriver {
+	case "minio":
+		if c.MinioEndpoint == "" || c.MinioAccessKey == "" || c.MinioSecretKey == "" || c.MinioBucket == "" {
+			panic("MINIO_ENDPOINT, MINIO_ACCESS_KEY, MINIO_SECRET_KEY and MINIO_BUCKET must be set when STORAGE_DRIVER is minio")
+		}
+	case "filesystem", "memory":
+	default:
+		panic("STORAGE_DRIVER must be one of minio, filesystem or memory")
+	}
+	if c.PhotoURLTTLMinutes <= 0 || c.PhotoMaxUploadMB <= 0 || c.PhotoMaxDimension <= 0 {
+		panic("PHOTO_URL_TTL_MINUTES, PHOTO_MAX_UPLOAD_MB and PHOTO_MAX_DIMENSION must be greater than zero")
+	}
+	if c.PhotoThumbnailSize <= 0 || c.PhotoSize < c.PhotoThumbnailSize {
+		panic("PHOTO_THUMBNAIL_SIZE must be greater than zero and PHOTO_SIZE at least as large")
+	}
+	if c.PhotoCleanupMinutes <= 0 || c.PhotoOrphanGraceHours <= 0 {
+		panic("PHOTO_CLEANUP_MINUTES and PHOTO_ORPHAN_GRACE_HOURS must be greater than zero")
+	}
+	if c.DocumentMaxUploadMB <= 0 {
+		panic("DOCUMENT_MAX_UPLOAD_MB must be greater than zero")
+	}
+	if len(c.DocumentExpiryWindowDays) == 0 || slices.Min(c.DocumentExpiryWindowDays) <= 0 || c.DocumentExpirySweepHours <= 0 {
+		panic("DOCUMENT_EXPIRY_WINDOWS_DAYS must list days greater than zero and DOCUMENT_EXPIRY_SWEEP_HOURS must be greater than zero")
+	}
+	if c.LoginMaxAttempts <= 0 || c.LoginIPMaxAttempts <= 0 {
+		panic("LOGIN_MAX_ATTEMPTS and LOGIN_IP_MAX_ATTEMPTS must be greater than zero")
+	}
+	if c.MinBreakMinutes < 0 {
+		panic("MIN_BREAK_MINUTES must not be negative")
+	}
+	if c.KioskQRTTLSeconds <= 0 || c.KioskPINMaxAttempts <= 0 {
+		panic("KIOSK_QR_TTL_SECONDS and KIOSK_PIN_MAX_ATTEMPTS must be greater than zero")
+	}
+	if c.KioskSyncMaxClockSkewSeconds <= 0 || c.KioskSyncMaxEventAgeHours <= 0 || c.KioskSyncMaxBatch <= 0 {
+		panic("KIOSK_SYNC_MAX_CLOCK_SKEW_SECONDS, KIOSK_SYNC_MAX_EVENT_AGE_HOURS and KIOSK_SYNC_MAX_BATCH must be greater than zero")
+	}
+	if c.RetentionPeriodDays <= 0 || c.RetentionSweepMinutes <= 0 {
+		panic("RETENTION_PERIOD_DAYS and RETENTION_SWEEP_MINUTES must be greater than zero")
+	}
+	if c.DataExportTTLHours <= 0 || c.DataExportPollSeconds <= 0 {
+		panic("DATA_EXPORT_TTL_HOURS and DATA_EXPORT_POLL_SECONDS must be greater than zero")
+	}
+	switch c.NotificationDriver {
+	case "smtp":
+		if c.SMTPHost == "" {
+			panic("SMTP_HOST must be set when NOTIFICATION_DRIVER is smtp")
+		}
+	case "file", "memory":
+	default:
+		panic("NOTIFICATION_DRIVER must be one of smtp, file or memory")
+	}
+	if c.EmailChangeTTLHours <= 0 {
+		panic("EMAIL_CHANGE_TTL_HOURS must be greater than zero")
+	}
+	if c.NotificationPollSeconds <= 0 || c.NotificationMaxAttempts <= 0 || c.WebhookTimeoutSeconds <= 0 || c.MissedCheckOutSweepMinutes <= 0 {
+		panic("NOTIFICATION_POLL_SECONDS, NOTIFICATION_MAX_ATTEMPTS, WEBHOOK_TIMEOUT_SECONDS and MISSED_CHECK_OUT_SWEEP_MINUTES must be greater than zero")
+	}
+	if c.WebhookPollSeconds <= 0 || c.WebhookMaxAttempts <= 0 {
+		panic("WEBHOOK_POLL_SECONDS and WEBHOOK_MAX_ATTEMPTS must be greater than zero")
+	}
+	if c.RabbitMQPrefetch <= 0 {
+		panic("RABBITMQ_PREFETCH must be greater than zero")
+	}
 }
 
 func getEnv(key string) string {
@@ -126,3 +336,38 @@ func atoiOrDefault(s string, defaultValue int) int {
 	}
 	return i
 }
+
+func atoiList(s string) []int {
+	var items []int
+	for _, item := range splitList(s) {
+		items = append(items, atoiMust(item))
+	}
+	return items
+}
+
+// prefixList parses a list of CIDR ranges or single addresses, e.g. "10.0.0.0/8,127.0.0.1".
+func prefixList(s string) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, item := range splitList(s) {
+		if addr, err := netip.ParseAddr(item); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			panic(fmt.Sprintf("invalid address or CIDR range: %s", item))
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
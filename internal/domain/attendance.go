@@ -1,18 +1,89 @@
 package domain
 
 import (
+	"errors"
 	"time"
 )
 
+var (
+	ErrSessionOpen     = errors.New("a work session is already open")
+	ErrNoOpenSession   = errors.New("no work session is open")
+	ErrBreakOpen       = errors.New("a break is already in progress")
+	ErrNoOpenBreak     = errors.New("no break is in progress")
+	ErrPunchOutOfOrder = errors.New("punch is earlier than the previous punch of the day")
+
+	// ErrAttendanceConflict is returned by repositories when the day was updated since it
+	// was read, e.g. by a punch at a kiosk while the employee punched on their phone.
+	ErrAttendanceConflict = errors.New("attendance has been modified concurrently")
+)
+
+// Attendance is the attendance of an employee on one day. It holds the work sessions of
+// the day in the order they were started, e.g. the morning and afternoon of a split
+// shift. CheckIn, CheckOut, Location and IsLate summarise the sessions for reports and
+// integration partners.
 type Attendance struct {
 	ID           string
 	EmployeeID   string
 	EmployeeName string
-	Location     string
-	CheckIn      string
-	CheckOut     *string
-	IsLate       bool
-	Date         time.Time
+	// Location is where the first session of the day started
+	Location string
+	// CheckIn is the first check-in of the day. CheckOut is the check-out of the last
+	// session, nil while a session is open
+	CheckIn  string
+	CheckOut *string
+	IsLate   bool
+	Date     time.Time
+	Sessions []WorkSession
+	// Version counts the updates of the day, so concurrent punches cannot overwrite each
+	// other
+	Version int64
+}
+
+// WorkSession is the time between a check-in and its check-out, with the breaks taken
+// in between.
+type WorkSession struct {
+	CheckIn  time.Time
+	CheckOut *time.Time
+	Location string
+
+	// Object keys of the photos taken at check-in and check-out, empty when none was
+	// attached
+	CheckInPhoto  string
+	CheckOutPhoto string
+
+	// KioskID is the store kiosk the check-in was made at, empty for check-ins made
+	// elsewhere
+	KioskID string
+
+	Breaks []Break
+}
+
+// Break is a pause within a work session. End is nil while the break lasts.
+type Break struct {
+	Start time.Time
+	End   *time.Time
+}
+
+// Duration returns the length of a finished break, zero while it lasts.
+func (b Break) Duration() time.Duration {
+	if b.End == nil {
+		return 0
+	}
+	return b.End.Sub(b.Start)
+}
+
+// BreakPolicy decides which breaks are deducted from the worked time. Breaks shorter
+// than MinBreak do not count as rest and are worked time.
+type BreakPolicy struct {
+	MinBreak time.Duration
+}
+
+// Deducted returns how much of b is taken off the worked time.
+func (p BreakPolicy) Deducted(b Break) time.Duration {
+	if d := b.Duration(); d >= p.MinBreak {
+		return d
+	}
+	return 0
 }
 
 const (
@@ -27,8 +98,12 @@ type CheckInParams struct {
 	CheckInTime     time.Time
 	OfficeStartHour int
 	OfficeStartMin  int
+	Photo           string
+	KioskID         string
 }
 
+// NewAttendance starts the attendance of a day with its first session. Whether the
+// employee is late is only decided here, later sessions of the day never are.
 func NewAttendance(params CheckInParams) *Attendance {
 	isLate := false
 
@@ -57,10 +132,191 @@ func NewAttendance(params CheckInParams) *Attendance {
 		CheckIn:      checkInTime.Format(time.DateTime),
 		IsLate:       isLate,
 		Date:         dateOnly,
+		Sessions:     []WorkSession{newWorkSession(params)},
+	}
+}
+
+func newWorkSession(params CheckInParams) WorkSession {
+	return WorkSession{
+		CheckIn:      params.CheckInTime,
+		Location:     params.Location,
+		CheckInPhoto: params.Photo,
+		KioskID:      params.KioskID,
+	}
+}
+
+// OpenSession returns the session the employee is checked in to, or nil.
+func (a *Attendance) OpenSession() *WorkSession {
+	if len(a.Sessions) == 0 {
+		return nil
+	}
+	if last := &a.Sessions[len(a.Sessions)-1]; last.CheckOut == nil {
+		return last
 	}
+	return nil
 }
 
-func (a *Attendance) SetCheckOut(checkOut time.Time) {
+// OpenBreak returns the break in progress, or nil.
+func (s *WorkSession) OpenBreak() *Break {
+	if len(s.Breaks) == 0 {
+		return nil
+	}
+	if last := &s.Breaks[len(s.Breaks)-1]; last.End == nil {
+		return last
+	}
+	return nil
+}
+
+// StartSession checks the employee in again after an earlier session of the day ended.
+func (a *Attendance) StartSession(params CheckInParams) error {
+	if a.OpenSession() != nil {
+		return ErrSessionOpen
+	}
+	if err := a.checkOrder(params.CheckInTime); err != nil {
+		return err
+	}
+
+	a.Sessions = append(a.Sessions, newWorkSession(params))
+	a.CheckOut = nil
+	return nil
+}
+
+// EndSession checks the employee out of the open session. A break still in progress
+// ends with it.
+func (a *Attendance) EndSession(checkOut time.Time, photo string) error {
+	session := a.OpenSession()
+	if session == nil {
+		return ErrNoOpenSession
+	}
+	if err := a.checkOrder(checkOut); err != nil {
+		return err
+	}
+
+	if b := session.OpenBreak(); b != nil {
+		b.End = &checkOut
+	}
+	session.CheckOut = &checkOut
+	session.CheckOutPhoto = photo
+
 	t := checkOut.Format(time.DateTime)
 	a.CheckOut = &t
+	return nil
+}
+
+// StartBreak starts a break in the open session.
+func (a *Attendance) StartBreak(at time.Time) error {
+	session := a.OpenSession()
+	if session == nil {
+		return ErrNoOpenSession
+	}
+	if session.OpenBreak() != nil {
+		return ErrBreakOpen
+	}
+	if err := a.checkOrder(at); err != nil {
+		return err
+	}
+
+	session.Breaks = append(session.Breaks, Break{Start: at})
+	return nil
+}
+
+// EndBreak ends the break in progress.
+func (a *Attendance) EndBreak(at time.Time) error {
+	session := a.OpenSession()
+	if session == nil {
+		return ErrNoOpenSession
+	}
+	b := session.OpenBreak()
+	if b == nil {
+		return ErrNoOpenBreak
+	}
+	if err := a.checkOrder(at); err != nil {
+		return err
+	}
+
+	b.End = &at
+	return nil
+}
+
+// checkOrder rejects punches before the last punch of the day, which kiosks syncing
+// offline events or a wrong device clock could otherwise record.
+func (a *Attendance) checkOrder(at time.Time) error {
+	var last time.Time
+	for _, s := range a.Sessions {
+		last = s.CheckIn
+		for _, b := range s.Breaks {
+			last = b.Start
+			if b.End != nil {
+				last = *b.End
+			}
+		}
+		if s.CheckOut != nil {
+			last = *s.CheckOut
+		}
+	}
+
+	if at.Before(last) {
+		return ErrPunchOutOfOrder
+	}
+	return nil
+}
+
+// Worked returns the time worked in the session net of the breaks policy deducts, zero
+// while the session is open.
+func (s *WorkSession) Worked(policy BreakPolicy) time.Duration {
+	if s.CheckOut == nil {
+		return 0
+	}
+	return s.CheckOut.Sub(s.CheckIn) - s.BreakTime(policy)
+}
+
+// BreakTime returns the break time policy deducts from the session.
+func (s *WorkSession) BreakTime(policy BreakPolicy) time.Duration {
+	var total time.Duration
+	for _, b := range s.Breaks {
+		total += policy.Deducted(b)
+	}
+	return total
+}
+
+// Worked returns the time worked in the finished sessions of the day net of breaks.
+func (a *Attendance) Worked(policy BreakPolicy) time.Duration {
+	var total time.Duration
+	for i := range a.Sessions {
+		total += a.Sessions[i].Worked(policy)
+	}
+	return total
+}
+
+// BreakTime returns the break time policy deducts from the day.
+func (a *Attendance) BreakTime(policy BreakPolicy) time.Duration {
+	var total time.Duration
+	for i := range a.Sessions {
+		total += a.Sessions[i].BreakTime(policy)
+	}
+	return total
+}
+
+// AttendancePhotoKeyPrefix is shared by all check-in and check-out photos. It is kept
+// apart from PhotoKeyPrefix so the profile photo cleanup never sees these objects.
+const AttendancePhotoKeyPrefix = "attendance/"
+
+// AttendancePhotoKey returns the object key of a newly uploaded check-in or check-out
+// photo.
+func AttendancePhotoKey(uploadID string) string {
+	return AttendancePhotoKeyPrefix + uploadID + ".jpg"
+}
+
+// PhotoFiles returns the object keys of the photos attached to the sessions of the day.
+func (a *Attendance) PhotoFiles() []string {
+	var files []string
+	for _, s := range a.Sessions {
+		if s.CheckInPhoto != "" {
+			files = append(files, s.CheckInPhoto)
+		}
+		if s.CheckOutPhoto != "" {
+			files = append(files, s.CheckOutPhoto)
+		}
+	}
+	return files
 }
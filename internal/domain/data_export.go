@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"time"
+)
+
+type DataExportStatus string
+
+const (
+	DataExportPending    DataExportStatus = "pending"
+	DataExportProcessing DataExportStatus = "processing"
+	DataExportReady      DataExportStatus = "ready"
+	DataExportFailed     DataExportStatus = "failed"
+	DataExportExpired    DataExportStatus = "expired"
+)
+
+// DataExport is a subject access request: an archive with everything stored about an
+// employee. It is generated in the background and can be downloaded until it expires.
+type DataExport struct {
+	ID          string
+	EmployeeID  string
+	RequestedBy string
+	Status      DataExportStatus
+	FileURL     string
+	Size        int64
+	Error       string
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time
+}
+
+func NewDataExport(id, employeeID, requestedBy string, now time.Time) *DataExport {
+	return &DataExport{
+		ID:          id,
+		EmployeeID:  employeeID,
+		RequestedBy: requestedBy,
+		Status:      DataExportPending,
+		CreatedAt:   now,
+	}
+}
+
+// IsInProgress reports whether the archive is still being generated.
+func (e *DataExport) IsInProgress() bool {
+	return e.Status == DataExportPending || e.Status == DataExportProcessing
+}
+
+// IsDownloadable reports whether the archive is ready and its download window is still open.
+func (e *DataExport) IsDownloadable(now time.Time) bool {
+	return e.Status == DataExportReady && e.ExpiresAt != nil && now.Before(*e.ExpiresAt)
+}
+
+func (e *DataExport) MarkReady(fileURL string, size int64, now time.Time, ttl time.Duration) {
+	expiresAt := now.Add(ttl)
+	e.Status = DataExportReady
+	e.FileURL = fileURL
+	e.Size = size
+	e.Error = ""
+	e.CompletedAt = &now
+	e.ExpiresAt = &expiresAt
+}
+
+func (e *DataExport) MarkFailed(reason string, now time.Time) {
+	e.Status = DataExportFailed
+	e.Error = reason
+	e.CompletedAt = &now
+}
+
+// Expire closes the download window once the archive has been removed from storage.
+func (e *DataExport) Expire() {
+	e.Status = DataExportExpired
+	e.FileURL = ""
+}
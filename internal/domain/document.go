@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+type DocumentType string
+
+const (
+	DocumentContract          DocumentType = "contract"
+	DocumentIDCard            DocumentType = "id_card"  // KTP
+	DocumentTaxCard           DocumentType = "tax_card" // NPWP
+	DocumentHealthCertificate DocumentType = "health_certificate"
+)
+
+var DocumentTypes = []DocumentType{DocumentContract, DocumentIDCard, DocumentTaxCard, DocumentHealthCertificate}
+
+func (t DocumentType) IsValid() bool {
+	return slices.Contains(DocumentTypes, t)
+}
+
+// Label names the document type in messages to people.
+func (t DocumentType) Label() string {
+	switch t {
+	case DocumentContract:
+		return "employment contract"
+	case DocumentIDCard:
+		return "ID card"
+	case DocumentTaxCard:
+		return "tax card"
+	case DocumentHealthCertificate:
+		return "health certificate"
+	}
+	return string(t)
+}
+
+// Document is one version of an employee document kept by HR. Uploading a document of a
+// type the employee already has adds a version; the highest version is the current one
+// and older versions are kept until deleted.
+type Document struct {
+	ID          string
+	EmployeeID  string
+	Type        DocumentType
+	Version     int // assigned when the document is saved
+	FileName    string
+	ContentType string
+	Size        int64
+	StorageKey  string
+	ExpiresAt   *time.Time // e.g. the end of a contract or of a certificate's validity
+	UploadedBy  string
+	UploadedAt  time.Time
+
+	// ReminderWindow is the smallest expiry reminder window, in days, that was already
+	// sent for this version; 0 when no reminder was sent
+	ReminderWindow int
+}
+
+// DocumentStorageKey returns the object key of a document file.
+func DocumentStorageKey(employeeID, documentID, ext string) string {
+	return "documents/" + employeeID + "/" + documentID + ext
+}
+
+// IsExpired reports whether the document has an expiry date that has passed.
+func (d *Document) IsExpired(now time.Time) bool {
+	return d.ExpiresAt != nil && !now.Before(*d.ExpiresAt)
+}
+
+// DaysLeft returns the number of days from today, a date, until the document expires.
+// It is zero or negative once the document has expired.
+func (d *Document) DaysLeft(today time.Time) int {
+	if d.ExpiresAt == nil {
+		return 0
+	}
+	expires := time.Date(d.ExpiresAt.Year(), d.ExpiresAt.Month(), d.ExpiresAt.Day(), 0, 0, 0, 0, time.UTC)
+	from := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	return int(expires.Sub(from).Hours() / 24)
+}
+
+// DueReminderWindow returns the expiry reminder window, out of windows in days, that is
+// due for the document today. It is the smallest window still covering the days left,
+// so a document uploaded or last reminded late gets one reminder instead of several.
+// It returns 0 when no reminder is due.
+func (d *Document) DueReminderWindow(today time.Time, windows []int) int {
+	if d.ExpiresAt == nil {
+		return 0
+	}
+	daysLeft := d.DaysLeft(today)
+	if daysLeft <= 0 {
+		return 0
+	}
+
+	due := 0
+	for _, window := range windows {
+		if window >= daysLeft && (due == 0 || window < due) {
+			due = window
+		}
+	}
+	if due == 0 || (d.ReminderWindow != 0 && d.ReminderWindow <= due) {
+		return 0
+	}
+	return due
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+)
+
+// EmailChange is a pending change of an employee's email address. It is only applied once
+// the employee proves control of the new address with the token sent there; only the
+// SHA-256 hash of the token is stored.
+type EmailChange struct {
+	ID          string
+	EmployeeID  string
+	NewEmail    Email
+	TokenHash   string
+	RequestedBy string
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	ConfirmedAt *time.Time
+	CancelledAt *time.Time
+}
+
+func NewEmailChange(id, employeeID string, newEmail Email, tokenHash, requestedBy string, now time.Time, ttl time.Duration) *EmailChange {
+	return &EmailChange{
+		ID:          id,
+		EmployeeID:  employeeID,
+		NewEmail:    newEmail,
+		TokenHash:   tokenHash,
+		RequestedBy: requestedBy,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}
+}
+
+// IsPending reports whether the change can still be confirmed.
+func (c *EmailChange) IsPending(now time.Time) bool {
+	return c.ConfirmedAt == nil && c.CancelledAt == nil && now.Before(c.ExpiresAt)
+}
+
+func (c *EmailChange) Confirm(now time.Time) {
+	c.ConfirmedAt = &now
+}
@@ -2,7 +2,9 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"net/mail"
+	"slices"
 	"strings"
 	"time"
 )
@@ -18,12 +20,45 @@ const (
 	RoleStaff      Role = "staff"
 )
 
+// Employee lifecycle: onboarding → active ↔ suspended → terminated.
 const (
-	StatusActive    Status = "active"
-	StatusInactive  Status = "inactive"
-	StatusSuspended Status = "suspended"
+	StatusOnboarding Status = "onboarding"
+	StatusActive     Status = "active"
+	StatusSuspended  Status = "suspended"
+	StatusTerminated Status = "terminated"
 )
 
+// ErrInvalidStatusTransition is returned when the lifecycle does not allow a status change.
+var ErrInvalidStatusTransition = errors.New("invalid status transition")
+
+// ErrVersionConflict is returned when an employee was changed since the caller read it.
+var ErrVersionConflict = errors.New("employee has been modified concurrently")
+
+// ErrEmployeeNotFound is returned by repositories when no employee matches a lookup.
+var ErrEmployeeNotFound = errors.New("employee not found")
+
+var (
+	ErrEmployeeDeleted    = errors.New("employee is already deleted")
+	ErrEmployeeNotDeleted = errors.New("employee is not deleted")
+	ErrEmployeeErased     = errors.New("employee personal data has been erased")
+)
+
+var allowedTransitions = map[Status][]Status{
+	StatusOnboarding: {StatusActive, StatusTerminated},
+	StatusActive:     {StatusSuspended, StatusTerminated},
+	StatusSuspended:  {StatusActive, StatusTerminated},
+}
+
+// CanTransitionTo reports whether the lifecycle allows moving from s to the given status.
+func (s Status) CanTransitionTo(to Status) bool {
+	return slices.Contains(allowedTransitions[s], to)
+}
+
+// CanSignIn reports whether employees in this status may log in and use their sessions.
+func (s Status) CanSignIn() bool {
+	return s == StatusOnboarding || s == StatusActive
+}
+
 type Employee struct {
 	id           EmployeeID
 	name         string
@@ -39,8 +74,17 @@ type Employee struct {
 	province     string
 	phoneNumber  string
 	photo        string
+	storeID      string
 	createdAt    time.Time
 	updatedAt    time.Time
+	deletedAt    *time.Time
+	anonymizedAt *time.Time
+
+	terminatedAt      *time.Time
+	sessionsRevokedAt *time.Time
+
+	// version is incremented on every write and guards against lost updates
+	version int64
 }
 
 type NewEmployeeParams struct {
@@ -49,6 +93,7 @@ type NewEmployeeParams struct {
 	Email          Email
 	HashedPassword string
 	Role           Role
+	Status         Status // onboarding when empty
 	Position       string
 	Salary         int64
 	BirthDate      *time.Time
@@ -56,6 +101,7 @@ type NewEmployeeParams struct {
 	City           string
 	Province       string
 	PhoneNumber    string
+	StoreID        string
 }
 
 func NewEmployee(params NewEmployeeParams) (*Employee, error) {
@@ -84,13 +130,21 @@ func NewEmployee(params NewEmployeeParams) (*Employee, error) {
 		return nil, errors.New("salary cannot be negative")
 	}
 
+	status := params.Status
+	if status == "" {
+		status = StatusOnboarding
+	}
+	if status != StatusOnboarding && status != StatusActive {
+		return nil, errors.New("new employees start as onboarding or active")
+	}
+
 	employee := &Employee{
 		id:           params.ID,
 		name:         params.Name,
 		email:        params.Email,
 		passwordHash: params.HashedPassword,
 		role:         params.Role,
-		status:       StatusActive,
+		status:       status,
 		position:     params.Position,
 		salary:       params.Salary,
 		birthdate:    params.BirthDate,
@@ -98,28 +152,109 @@ func NewEmployee(params NewEmployeeParams) (*Employee, error) {
 		city:         params.City,
 		province:     params.Province,
 		phoneNumber:  params.PhoneNumber,
+		storeID:      params.StoreID,
+		version:      1,
 	}
 
 	return employee, nil
 }
 
-func (e *Employee) Activate() {
-	e.status = StatusActive
+// TransitionTo moves the employee through the lifecycle. Suspension and termination
+// revoke all sessions issued before now; termination also records its effective time.
+func (e *Employee) TransitionTo(to Status, effectiveAt time.Time, now time.Time) error {
+	if !e.status.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidStatusTransition, e.status, to)
+	}
+
+	switch to {
+	case StatusSuspended:
+		e.RevokeSessions(now)
+	case StatusTerminated:
+		e.terminatedAt = &effectiveAt
+		e.RevokeSessions(now)
+	}
+
+	e.status = to
+	return nil
+}
+
+// RevokeSessions invalidates every token issued before now.
+func (e *Employee) RevokeSessions(now time.Time) {
+	e.sessionsRevokedAt = &now
+}
+
+// IsSessionValid reports whether a token issued at issuedAt may still be used.
+func (e *Employee) IsSessionValid(issuedAt time.Time) bool {
+	return SessionState{Status: e.status, DeletedAt: e.deletedAt, SessionsRevokedAt: e.sessionsRevokedAt}.IsValid(issuedAt)
 }
 
-func (e *Employee) Suspend() {
-	e.status = StatusSuspended
+// SessionState is the part of an employee that decides whether its tokens are still
+// accepted. It is checked on every authenticated request, so it is loaded on its own
+// rather than with the whole employee.
+type SessionState struct {
+	Status            Status
+	DeletedAt         *time.Time
+	SessionsRevokedAt *time.Time
 }
 
-func (e *Employee) Deactivate() {
-	e.status = StatusInactive
+// IsValid reports whether a token issued at issuedAt may still be used. Token times
+// have second precision, so the revocation time is truncated to whole seconds.
+func (s SessionState) IsValid(issuedAt time.Time) bool {
+	if s.DeletedAt != nil || !s.Status.CanSignIn() {
+		return false
+	}
+	return s.SessionsRevokedAt == nil || !issuedAt.Before(s.SessionsRevokedAt.Truncate(time.Second))
 }
 
-func (e *Employee) ChangeRole(newRole Role) error {
+// SoftDelete hides the employee from the workforce and revokes its sessions. The status
+// is kept so Restore can undo an accidental delete; the retention policy erases the
+// personal data of employees that stay deleted.
+func (e *Employee) SoftDelete(now time.Time) error {
+	if e.IsDeleted() {
+		return ErrEmployeeDeleted
+	}
+
+	e.deletedAt = &now
+	e.RevokeSessions(now)
+	return nil
+}
+
+// Restore undoes a soft delete. Anonymised employees cannot be restored. Sessions revoked
+// by the delete stay revoked, the employee has to sign in again.
+func (e *Employee) Restore() error {
+	if !e.IsDeleted() {
+		return ErrEmployeeNotDeleted
+	}
+	if e.IsAnonymized() {
+		return ErrEmployeeErased
+	}
+
+	e.deletedAt = nil
+	return nil
+}
+
+// ChangeRole moves the employee to newRole on behalf of an actor. The actor must outrank
+// both the employee's current role and the new one, and cannot change their own role.
+// Sessions are revoked, as tokens carry the role they were issued with.
+func (e *Employee) ChangeRole(newRole Role, actorID EmployeeID, actorRole Role, policy Policy, now time.Time) error {
 	if !isValidRole(newRole) {
 		return errors.New("invalid role")
 	}
+
+	if actorID == e.id {
+		return errors.New("employees cannot change their own role")
+	}
+
+	if !policy.Outranks(actorRole, e.role) {
+		return ErrRoleNotAssignable
+	}
+
+	if err := policy.CanAssign(actorRole, newRole); err != nil {
+		return err
+	}
+
 	e.role = newRole
+	e.RevokeSessions(now)
 	return nil
 }
 
@@ -207,6 +342,10 @@ func (e *Employee) Photo() string {
 	return e.photo
 }
 
+func (e *Employee) StoreID() string {
+	return e.storeID
+}
+
 func (e *Employee) CreatedAt() time.Time {
 	return e.createdAt
 }
@@ -215,18 +354,64 @@ func (e *Employee) UpdatedAt() time.Time {
 	return e.updatedAt
 }
 
+// DeletedAt is when the employee was removed from the active workforce, nil while employed.
+func (e *Employee) DeletedAt() *time.Time {
+	return e.deletedAt
+}
+
+func (e *Employee) IsDeleted() bool {
+	return e.deletedAt != nil
+}
+
+func (e *Employee) AnonymizedAt() *time.Time {
+	return e.anonymizedAt
+}
+
+func (e *Employee) IsAnonymized() bool {
+	return e.anonymizedAt != nil
+}
+
+// TerminatedAt is the effective time of the termination, nil unless terminated.
+func (e *Employee) TerminatedAt() *time.Time {
+	return e.terminatedAt
+}
+
+func (e *Employee) SessionsRevokedAt() *time.Time {
+	return e.sessionsRevokedAt
+}
+
+func (e *Employee) Version() int64 {
+	return e.version
+}
+
+// CheckVersion fails with ErrVersionConflict unless the employee is still at the version
+// the caller read.
+func (e *Employee) CheckVersion(expected int64) error {
+	if e.version != expected {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
 func isValidEmail(email string) bool {
 	_, err := mail.ParseAddress(email)
 	return err == nil
 }
 
+// isValidRole only checks the shape of the role name. Whether the role exists
+// is decided by the permission policy, which also holds custom roles.
 func isValidRole(role Role) bool {
-	switch role {
-	case RoleAdmin, RoleSupervisor, RoleStaff:
-		return true
-	default:
-		return false
+	return roleNamePattern.MatchString(string(role))
+}
+
+// ChangeEmail replaces the email address. Callers must have verified the new address.
+func (e *Employee) ChangeEmail(email Email) error {
+	if !isValidEmail(string(email)) {
+		return errors.New("invalid email format")
 	}
+
+	e.email = email
+	return nil
 }
 
 func (e *Employee) SetName(name string) {
@@ -261,8 +446,43 @@ func (e *Employee) SetPhoto(photo string) {
 	e.photo = photo
 }
 
-func (e *Employee) Delete() {
-	e.status = StatusInactive
+func (e *Employee) SetStoreID(storeID string) {
+	e.storeID = storeID
+}
+
+// SetVersion records the version assigned by the repository after a write.
+func (e *Employee) SetVersion(version int64) {
+	e.version = version
+}
+
+// Anonymize irreversibly replaces the personal data of the employee, keeping only the
+// ID, role and store so historical records stay consistent. An employee that is not
+// terminated yet is terminated now, and it is soft deleted if it was not already. It
+// returns the names of the fields that were erased.
+func (e *Employee) Anonymize(now time.Time) []string {
+	e.name = "Erased Employee"
+	e.email = Email("erased-" + string(e.id) + "@erased.invalid")
+	e.passwordHash = ""
+	e.position = ""
+	e.salary = 0
+	e.birthdate = nil
+	e.address = ""
+	e.city = ""
+	e.province = ""
+	e.phoneNumber = ""
+	e.photo = ""
+	e.anonymizedAt = &now
+
+	if e.status != StatusTerminated {
+		e.status = StatusTerminated
+		e.terminatedAt = &now
+	}
+	if e.deletedAt == nil {
+		e.deletedAt = &now
+	}
+	e.RevokeSessions(now)
+
+	return []string{"name", "email", "password", "position", "salary", "birthdate", "address", "city", "province", "phone_number", "photo"}
 }
 
 type ReconstituteEmployeeParams struct {
@@ -280,8 +500,16 @@ type ReconstituteEmployeeParams struct {
 	Province     string
 	PhoneNumber  string
 	Photo        string
+	StoreID      string
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
+	DeletedAt    *time.Time
+	AnonymizedAt *time.Time
+
+	TerminatedAt      *time.Time
+	SessionsRevokedAt *time.Time
+
+	Version int64
 }
 
 func ReconstituteEmployee(p ReconstituteEmployeeParams) (*Employee, error) {
@@ -300,7 +528,15 @@ func ReconstituteEmployee(p ReconstituteEmployeeParams) (*Employee, error) {
 		province:     p.Province,
 		phoneNumber:  p.PhoneNumber,
 		photo:        p.Photo,
+		storeID:      p.StoreID,
 		createdAt:    p.CreatedAt,
 		updatedAt:    p.UpdatedAt,
+		deletedAt:    p.DeletedAt,
+		anonymizedAt: p.AnonymizedAt,
+
+		terminatedAt:      p.TerminatedAt,
+		sessionsRevokedAt: p.SessionsRevokedAt,
+
+		version: p.Version,
 	}, nil
 }
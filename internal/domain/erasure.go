@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+)
+
+type ErasureTrigger string
+
+const (
+	// ErasureTriggerRetention marks erasures done by the retention policy after termination.
+	ErasureTriggerRetention ErasureTrigger = "retention"
+	// ErasureTriggerRequest marks erasures requested by an administrator.
+	ErasureTriggerRequest ErasureTrigger = "request"
+)
+
+// ErasureRecord is the audit trail of an erasure. It describes what was removed
+// without keeping any of the erased personal data.
+type ErasureRecord struct {
+	ID                string
+	EmployeeID        string
+	Trigger           ErasureTrigger
+	RequestedBy       string
+	Reason            string
+	ErasedFields      []string
+	AttendanceRecords int64
+	PhotoDeleted      bool
+	ErasedAt          time.Time
+}
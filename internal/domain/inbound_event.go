@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// InboundEventType names an event other shop-retail services publish and the employee
+// service reacts to. It is also the routing key of the message.
+type InboundEventType string
+
+const (
+	InboundStoreClosed          InboundEventType = "store.closed"
+	InboundCashierSessionOpened InboundEventType = "pos.cashier_session_opened"
+)
+
+// InboundEventTypes lists every event the employee service consumes.
+var InboundEventTypes = []InboundEventType{
+	InboundStoreClosed,
+	InboundCashierSessionOpened,
+}
+
+// InboundEvent is a message from another service. ID is chosen by the publisher and
+// stays the same on redelivery, so it is what duplicates are recognised by.
+type InboundEvent struct {
+	ID         string
+	Type       InboundEventType
+	Source     string
+	OccurredAt time.Time
+	Data       []byte // JSON, decoded by the handler of Type
+}
+
+// StoreClosureAction is what happens to the employees of a closed store.
+type StoreClosureAction string
+
+const (
+	StoreClosureSuspend  StoreClosureAction = "suspend"
+	StoreClosureTransfer StoreClosureAction = "transfer"
+)
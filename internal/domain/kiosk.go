@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidKioskName = errors.New("kiosk name must be between 1 and 100 characters")
+	ErrInvalidKioskPIN  = errors.New("kiosk PIN must be 4 to 8 digits")
+)
+
+// Kiosk is a store device employees check in at, either by scanning the rotating QR code
+// it shows or by entering their PIN on it. The device authenticates with a token of which
+// only the hash is kept, and signs the events it records offline with a private key of
+// which only the public half, SigningKey, is kept.
+type Kiosk struct {
+	ID         string
+	StoreID    string
+	Name       string
+	TokenHash  string
+	SigningKey ed25519.PublicKey
+	CreatedBy  string
+	CreatedAt  time.Time
+	RevokedAt  *time.Time
+}
+
+// NewKiosk validates the name and registers a device for storeID.
+func NewKiosk(id, storeID, name, tokenHash string, signingKey ed25519.PublicKey, createdBy string, now time.Time) (*Kiosk, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 100 {
+		return nil, ErrInvalidKioskName
+	}
+
+	return &Kiosk{
+		ID:         id,
+		StoreID:    storeID,
+		Name:       name,
+		TokenHash:  tokenHash,
+		SigningKey: signingKey,
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+	}, nil
+}
+
+func (k *Kiosk) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// AttendanceLocation is the location recorded for check-ins made at the kiosk.
+func (k *Kiosk) AttendanceLocation() string {
+	return "kiosk: " + k.Name
+}
+
+// KioskPIN is the hashed PIN an employee enters on a store kiosk to check in without a
+// phone.
+type KioskPIN struct {
+	EmployeeID string
+	PINHash    string
+	UpdatedAt  time.Time
+}
+
+// ValidateKioskPIN accepts PINs of 4 to 8 digits.
+func ValidateKioskPIN(pin string) error {
+	if len(pin) < 4 || len(pin) > 8 {
+		return ErrInvalidKioskPIN
+	}
+	for _, r := range pin {
+		if r < '0' || r > '9' {
+			return ErrInvalidKioskPIN
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInvalidKioskEvent = errors.New("kiosk event needs an id of at most 100 characters, a known type and an employee")
+
+type KioskEventType string
+
+const (
+	KioskEventCheckIn    KioskEventType = "check_in"
+	KioskEventCheckOut   KioskEventType = "check_out"
+	KioskEventBreakStart KioskEventType = "break_start"
+	KioskEventBreakEnd   KioskEventType = "break_end"
+)
+
+// KioskEvent is a check-in, check-out or break punch a kiosk recorded while it was offline. ID is
+// generated by the kiosk and identifies the event across retries of the sync, OccurredAt
+// is the kiosk's clock when the employee entered their PIN.
+type KioskEvent struct {
+	ID         string
+	Type       KioskEventType
+	EmployeeID string
+	PIN        string
+	OccurredAt time.Time
+	Signature  string
+}
+
+func (e *KioskEvent) Validate() error {
+	if e.ID == "" || len(e.ID) > 100 || e.EmployeeID == "" {
+		return ErrInvalidKioskEvent
+	}
+	switch e.Type {
+	case KioskEventCheckIn, KioskEventCheckOut, KioskEventBreakStart, KioskEventBreakEnd:
+	default:
+		return ErrInvalidKioskEvent
+	}
+	return nil
+}
+
+type KioskEventStatus string
+
+const (
+	// KioskEventApplied events were recorded as attendance.
+	KioskEventApplied KioskEventStatus = "applied"
+	// KioskEventDuplicate events were synced before; the result of that sync stands.
+	KioskEventDuplicate KioskEventStatus = "duplicate"
+	// KioskEventRejected events can never be applied, e.g. a check-out without check-in.
+	KioskEventRejected KioskEventStatus = "rejected"
+	// KioskEventFailed events hit a temporary problem and should be synced again.
+	KioskEventFailed KioskEventStatus = "failed"
+)
+
+// ProcessedKioskEvent remembers the outcome of an applied or rejected kiosk event, so a
+// kiosk syncing it again does not record the attendance twice.
+type ProcessedKioskEvent struct {
+	KioskID      string
+	EventID      string
+	Type         KioskEventType
+	OccurredAt   time.Time
+	Status       KioskEventStatus
+	AttendanceID string
+	Error        string
+	ProcessedAt  time.Time
+}
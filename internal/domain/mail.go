@@ -0,0 +1,8 @@
+package domain
+
+// MailMessage is an email for a person. Body is plain text.
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
@@ -0,0 +1,246 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"time"
+)
+
+var (
+	ErrUnknownNotificationEvent   = errors.New("unknown notification event")
+	ErrUnknownNotificationChannel = errors.New("unknown notification channel")
+	ErrInvalidWebhookURL          = errors.New("webhook URL must be an absolute http or https URL")
+	ErrPrivateWebhookTarget       = errors.New("webhook URL must point to a public address")
+)
+
+type NotificationEvent string
+
+const (
+	EventLateCheckIn        NotificationEvent = "attendance.late_check_in"
+	EventMissedCheckOut     NotificationEvent = "attendance.missed_check_out"
+	EventLeaveSubmitted     NotificationEvent = "leave.submitted"
+	EventLeaveApproved      NotificationEvent = "leave.approved"
+	EventCorrectionApproved NotificationEvent = "correction.approved"
+	EventDocumentExpiring   NotificationEvent = "document.expiring"
+)
+
+// NotificationEvents lists every event employees can be notified about.
+var NotificationEvents = []NotificationEvent{
+	EventLateCheckIn,
+	EventMissedCheckOut,
+	EventLeaveSubmitted,
+	EventLeaveApproved,
+	EventCorrectionApproved,
+	EventDocumentExpiring,
+}
+
+func IsKnownNotificationEvent(event NotificationEvent) bool {
+	return slices.Contains(NotificationEvents, event)
+}
+
+// NotificationAudience says who hears about an event concerning an employee: the
+// employee, the supervisors of the employee's store, or both.
+type NotificationAudience struct {
+	Employee    bool
+	Supervisors bool
+}
+
+func (e NotificationEvent) Audience() NotificationAudience {
+	switch e {
+	case EventLateCheckIn, EventLeaveSubmitted:
+		return NotificationAudience{Supervisors: true}
+	case EventMissedCheckOut, EventDocumentExpiring:
+		return NotificationAudience{Employee: true, Supervisors: true}
+	default:
+		return NotificationAudience{Employee: true}
+	}
+}
+
+type NotificationChannel string
+
+const (
+	ChannelInApp   NotificationChannel = "in_app"
+	ChannelEmail   NotificationChannel = "email"
+	ChannelWebhook NotificationChannel = "webhook"
+)
+
+var NotificationChannels = []NotificationChannel{ChannelInApp, ChannelEmail, ChannelWebhook}
+
+// DefaultNotificationChannels are used for events the employee has not configured.
+var DefaultNotificationChannels = []NotificationChannel{ChannelInApp, ChannelEmail}
+
+// Notification is a rendered message for one employee. It shows up in the employee's
+// inbox when the in-app channel was chosen; other channels are sent as deliveries.
+type Notification struct {
+	ID          string
+	RecipientID string
+	Event       NotificationEvent
+	Subject     string
+	Body        string
+	InInbox     bool
+	CreatedAt   time.Time
+	ReadAt      *time.Time
+}
+
+func NewNotification(id, recipientID string, event NotificationEvent, subject, body string, inInbox bool, now time.Time) *Notification {
+	return &Notification{
+		ID:          id,
+		RecipientID: recipientID,
+		Event:       event,
+		Subject:     subject,
+		Body:        body,
+		InInbox:     inInbox,
+		CreatedAt:   now,
+	}
+}
+
+func (n *Notification) IsRead() bool {
+	return n.ReadAt != nil
+}
+
+// MarkRead keeps the time the notification was first read.
+func (n *Notification) MarkRead(now time.Time) {
+	if n.ReadAt == nil {
+		n.ReadAt = &now
+	}
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending DeliveryStatus = "pending"
+	DeliverySent    DeliveryStatus = "sent"
+	DeliveryFailed  DeliveryStatus = "failed"
+)
+
+// DeliveryRetryPolicy spaces out retries of a failed delivery with exponential backoff
+// and gives up after MaxAttempts.
+type DeliveryRetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// Backoff returns how long to wait after the given number of failed attempts.
+func (p DeliveryRetryPolicy) Backoff(attempts int) time.Duration {
+	delay := p.BaseDelay << (attempts - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// NotificationDelivery sends a notification over one external channel to Target, an
+// email address or a webhook URL. Every attempt is recorded.
+type NotificationDelivery struct {
+	ID             string
+	NotificationID string
+	Channel        NotificationChannel
+	Target         string
+	Status         DeliveryStatus
+	Attempts       int
+	LastError      string
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+func NewNotificationDelivery(id, notificationID string, channel NotificationChannel, target string, now time.Time) *NotificationDelivery {
+	return &NotificationDelivery{
+		ID:             id,
+		NotificationID: notificationID,
+		Channel:        channel,
+		Target:         target,
+		Status:         DeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+}
+
+func (d *NotificationDelivery) MarkSent(now time.Time) {
+	d.Attempts++
+	d.Status = DeliverySent
+	d.LastError = ""
+	d.DeliveredAt = &now
+}
+
+// MarkAttemptFailed schedules the next attempt, or fails the delivery for good once the
+// policy's attempts are used up.
+func (d *NotificationDelivery) MarkAttemptFailed(reason string, now time.Time, policy DeliveryRetryPolicy) {
+	d.Attempts++
+	d.LastError = reason
+
+	if d.Attempts >= policy.MaxAttempts {
+		d.Status = DeliveryFailed
+		return
+	}
+	d.NextAttemptAt = now.Add(policy.Backoff(d.Attempts))
+}
+
+// NotificationPreferences are the channels an employee wants per event, and the URL
+// their webhook channel posts to.
+type NotificationPreferences struct {
+	EmployeeID string
+	WebhookURL string
+	Channels   map[NotificationEvent][]NotificationChannel
+	UpdatedAt  time.Time
+}
+
+func NewNotificationPreferences(employeeID string) *NotificationPreferences {
+	return &NotificationPreferences{
+		EmployeeID: employeeID,
+		Channels:   map[NotificationEvent][]NotificationChannel{},
+	}
+}
+
+// ChannelsFor returns the channels to use for event. The webhook channel is left out
+// while no webhook URL is set.
+func (p *NotificationPreferences) ChannelsFor(event NotificationEvent) []NotificationChannel {
+	channels, ok := p.Channels[event]
+	if !ok {
+		channels = DefaultNotificationChannels
+	}
+
+	result := make([]NotificationChannel, 0, len(channels))
+	for _, channel := range channels {
+		if channel == ChannelWebhook && p.WebhookURL == "" {
+			continue
+		}
+		result = append(result, channel)
+	}
+	return result
+}
+
+// SetChannels replaces the channels of event; no channels mutes the event.
+func (p *NotificationPreferences) SetChannels(event NotificationEvent, channels []NotificationChannel) error {
+	if !IsKnownNotificationEvent(event) {
+		return ErrUnknownNotificationEvent
+	}
+
+	unique := make([]NotificationChannel, 0, len(channels))
+	for _, channel := range channels {
+		if !slices.Contains(NotificationChannels, channel) {
+			return ErrUnknownNotificationChannel
+		}
+		if !slices.Contains(unique, channel) {
+			unique = append(unique, channel)
+		}
+	}
+
+	p.Channels[event] = unique
+	return nil
+}
+
+// SetWebhookURL sets where the webhook channel posts to; an empty URL turns it off.
+func (p *NotificationPreferences) SetWebhookURL(rawURL string) error {
+	if rawURL != "" {
+		u, err := url.Parse(rawURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidWebhookURL
+		}
+	}
+
+	p.WebhookURL = rawURL
+	return nil
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"strings"
+	"text/template"
+)
+
+// notificationTemplates holds the subject and body of every event. Templates are filled
+// from a map, so fields the caller does not know are rendered empty.
+var notificationTemplates = map[NotificationEvent][2]*template.Template{
+	EventLateCheckIn: parseNotificationTemplate(
+		"Late check-in: {{.EmployeeName}}",
+		"{{.EmployeeName}} checked in late at {{.CheckIn}} on {{.Date}} ({{.Location}}).",
+	),
+	EventMissedCheckOut: parseNotificationTemplate(
+		"Missed check-out: {{.EmployeeName}} on {{.Date}}",
+		"{{.EmployeeName}} checked in at {{.CheckIn}} on {{.Date}} but never checked out. Please request a correction if the record is wrong.",
+	),
+	EventLeaveSubmitted: parseNotificationTemplate(
+		"Leave request from {{.EmployeeName}}",
+		"{{.EmployeeName}} requested leave from {{.StartDate}} to {{.EndDate}}.{{if .Reason}} Reason: {{.Reason}}{{end}}",
+	),
+	EventLeaveApproved: parseNotificationTemplate(
+		"Your leave request was approved",
+		"Your leave from {{.StartDate}} to {{.EndDate}} was approved{{if .ApprovedBy}} by {{.ApprovedBy}}{{end}}.",
+	),
+	EventCorrectionApproved: parseNotificationTemplate(
+		"Your attendance correction was approved",
+		"Your attendance correction for {{.Date}} was approved{{if .ApprovedBy}} by {{.ApprovedBy}}{{end}}.",
+	),
+	EventDocumentExpiring: parseNotificationTemplate(
+		"The {{.DocumentType}} of {{.EmployeeName}} expires on {{.ExpiresAt}}",
+		"The {{.DocumentType}} of {{.EmployeeName}} expires on {{.ExpiresAt}}, in {{.DaysLeft}} day(s). Please upload a renewed document before then.",
+	),
+}
+
+func parseNotificationTemplate(subject, body string) [2]*template.Template {
+	return [2]*template.Template{
+		template.Must(template.New("subject").Option("missingkey=zero").Parse(subject)),
+		template.Must(template.New("body").Option("missingkey=zero").Parse(body)),
+	}
+}
+
+// RenderNotification fills the templates of event with data.
+func RenderNotification(event NotificationEvent, data map[string]string) (string, string, error) {
+	templates, ok := notificationTemplates[event]
+	if !ok {
+		return "", "", ErrUnknownNotificationEvent
+	}
+
+	var subject, body strings.Builder
+	if err := templates[0].Execute(&subject, data); err != nil {
+		return "", "", err
+	}
+	if err := templates[1].Execute(&body, data); err != nil {
+		return "", "", err
+	}
+
+	return subject.String(), body.String(), nil
+}
@@ -0,0 +1,200 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type Permission string
+
+const (
+	PermEmployeeReadSelf        Permission = "employee.read.self"
+	PermEmployeeRead            Permission = "employee.read"
+	PermEmployeeSalaryRead      Permission = "employee.salary.read"
+	PermEmployeePersonalRead    Permission = "employee.personal.read"
+	PermEmployeeAllStores       Permission = "employee.scope.all_stores"
+	PermEmployeeCreate          Permission = "employee.create"
+	PermEmployeeUpdateSelf      Permission = "employee.update.self"
+	PermEmployeeUpdate          Permission = "employee.update"
+	PermEmployeeDelete          Permission = "employee.delete"
+	PermEmployeeRestore         Permission = "employee.restore"
+	PermEmployeePurge           Permission = "employee.purge"
+	PermEmployeeErase           Permission = "employee.erase"
+	PermEmployeeExportSelf      Permission = "employee.export.self"
+	PermEmployeeExport          Permission = "employee.export"
+	PermEmployeeLifecycle       Permission = "employee.lifecycle"
+	PermEmployeePhotoUploadSelf Permission = "employee.photo.upload.self"
+	PermEmployeePhotoUpload     Permission = "employee.photo.upload"
+	PermDocumentReadSelf        Permission = "employee.document.read.self"
+	PermDocumentUploadSelf      Permission = "employee.document.upload.self"
+	PermDocumentManage          Permission = "employee.document.manage"
+	PermAttendanceRecord        Permission = "attendance.record"
+	PermAttendanceApprove       Permission = "attendance.approve"
+	PermKioskManage             Permission = "kiosk.manage"
+	PermAccountUnlock           Permission = "account.unlock"
+	PermAccountTwoFactorReset   Permission = "account.2fa.reset"
+	PermRoleManage              Permission = "role.manage"
+	PermWebhookManage           Permission = "webhook.manage"
+	PermComplianceRead          Permission = "compliance.read"
+)
+
+// AllPermissions lists every permission the service checks. Roles may only be granted these.
+var AllPermissions = []Permission{
+	PermEmployeeReadSelf,
+	PermEmployeeRead,
+	PermEmployeeSalaryRead,
+	PermEmployeePersonalRead,
+	PermEmployeeAllStores,
+	PermEmployeeCreate,
+	PermEmployeeUpdateSelf,
+	PermEmployeeUpdate,
+	PermEmployeeDelete,
+	PermEmployeeRestore,
+	PermEmployeePurge,
+	PermEmployeeErase,
+	PermEmployeeExportSelf,
+	PermEmployeeExport,
+	PermEmployeeLifecycle,
+	PermEmployeePhotoUploadSelf,
+	PermEmployeePhotoUpload,
+	PermDocumentReadSelf,
+	PermDocumentUploadSelf,
+	PermDocumentManage,
+	PermAttendanceRecord,
+	PermAttendanceApprove,
+	PermKioskManage,
+	PermAccountUnlock,
+	PermAccountTwoFactorReset,
+	PermRoleManage,
+	PermWebhookManage,
+	PermComplianceRead,
+}
+
+func IsKnownPermission(p Permission) bool {
+	return slices.Contains(AllPermissions, p)
+}
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+
+// ErrRoleNotAssignable is returned when an actor tries to hand out or change a role
+// that is not strictly below their own rank.
+var ErrRoleNotAssignable = errors.New("role is not below the actor's role")
+
+// RoleDefinition is a named set of permissions. Built-in roles are seeded by
+// migrations and cannot be deleted; custom roles (e.g. "store_manager", "hr") can be
+// created at runtime. Rank orders roles in the hierarchy, higher ranks manage lower ones.
+type RoleDefinition struct {
+	Name        Role
+	Description string
+	Rank        int
+	BuiltIn     bool
+	Permissions []Permission
+}
+
+func NewRoleDefinition(name Role, description string, rank int, permissions []Permission) (*RoleDefinition, error) {
+	if !isValidRole(name) {
+		return nil, errors.New("role name must be lowercase letters, digits or underscores")
+	}
+
+	if rank < 0 {
+		return nil, errors.New("role rank cannot be negative")
+	}
+
+	if err := validatePermissions(permissions); err != nil {
+		return nil, err
+	}
+
+	return &RoleDefinition{
+		Name:        name,
+		Description: strings.TrimSpace(description),
+		Rank:        rank,
+		Permissions: dedupePermissions(permissions),
+	}, nil
+}
+
+func (r *RoleDefinition) SetPermissions(permissions []Permission) error {
+	if err := validatePermissions(permissions); err != nil {
+		return err
+	}
+	r.Permissions = dedupePermissions(permissions)
+	return nil
+}
+
+func (r *RoleDefinition) SetDescription(description string) {
+	r.Description = strings.TrimSpace(description)
+}
+
+func (r *RoleDefinition) SetRank(rank int) error {
+	if rank < 0 {
+		return errors.New("role rank cannot be negative")
+	}
+	r.Rank = rank
+	return nil
+}
+
+func (r *RoleDefinition) Has(p Permission) bool {
+	return slices.Contains(r.Permissions, p)
+}
+
+// Policy maps role names to their permissions.
+type Policy map[Role]*RoleDefinition
+
+func NewPolicy(roles []*RoleDefinition) Policy {
+	policy := make(Policy, len(roles))
+	for _, role := range roles {
+		policy[role.Name] = role
+	}
+	return policy
+}
+
+func (p Policy) Allows(role Role, perm Permission) bool {
+	def, ok := p[role]
+	return ok && def.Has(perm)
+}
+
+func (p Policy) HasRole(role Role) bool {
+	_, ok := p[role]
+	return ok
+}
+
+// Outranks reports whether role a sits strictly above role b. Unknown roles outrank nothing.
+func (p Policy) Outranks(a, b Role) bool {
+	defA, okA := p[a]
+	if !okA {
+		return false
+	}
+	defB, okB := p[b]
+	if !okB {
+		// a role that no longer exists in the policy is treated as the lowest rank
+		return true
+	}
+	return defA.Rank > defB.Rank
+}
+
+// CanAssign reports whether an actor with actorRole may give target to someone.
+func (p Policy) CanAssign(actorRole, target Role) error {
+	if !p.HasRole(target) {
+		return errors.New("unknown role: " + string(target))
+	}
+	if !p.Outranks(actorRole, target) {
+		return ErrRoleNotAssignable
+	}
+	return nil
+}
+
+func validatePermissions(permissions []Permission) error {
+	for _, perm := range permissions {
+		if !IsKnownPermission(perm) {
+			return errors.New("unknown permission: " + string(perm))
+		}
+	}
+	return nil
+}
+
+func dedupePermissions(permissions []Permission) []Permission {
+	out := slices.Clone(permissions)
+	slices.Sort(out)
+	return slices.Compact(out)
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "strings"
+
+// Photos are stored as a square avatar with a thumbnail next to it. Photos uploaded
+// before thumbnails were generated have keys outside the photos/ prefix and no
+// thumbnail.
+const (
+	// PhotoKeyPrefix is shared by all photo objects, nothing else is stored under it
+	PhotoKeyPrefix       = "photos/"
+	photoKeySuffix       = ".jpg"
+	photoThumbnailSuffix = "_thumb.jpg"
+)
+
+// PhotoKeys returns the object keys of a newly uploaded photo and its thumbnail.
+func PhotoKeys(uploadID string) (photo, thumbnail string) {
+	return PhotoKeyPrefix + uploadID + photoKeySuffix, PhotoKeyPrefix + uploadID + photoThumbnailSuffix
+}
+
+// PhotoThumbnailKey returns the key of the thumbnail stored next to photo, or "" if
+// the photo has none.
+func PhotoThumbnailKey(photo string) string {
+	if !strings.HasPrefix(photo, PhotoKeyPrefix) || !strings.HasSuffix(photo, photoKeySuffix) {
+		return ""
+	}
+	return strings.TrimSuffix(photo, photoKeySuffix) + photoThumbnailSuffix
+}
+
+// PhotoThumbnail returns the object key of the employee's photo thumbnail, or "" if
+// there is none.
+func (e *Employee) PhotoThumbnail() string {
+	return PhotoThumbnailKey(e.photo)
+}
+
+// PhotoFiles returns the object keys of everything stored for the employee's photo.
+func (e *Employee) PhotoFiles() []string {
+	var files []string
+	if e.photo != "" {
+		files = append(files, e.photo)
+	}
+	if thumbnail := e.PhotoThumbnail(); thumbnail != "" {
+		files = append(files, thumbnail)
+	}
+	return files
+}
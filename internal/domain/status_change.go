@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type StatusChangeState string
+
+const (
+	StatusChangeScheduled StatusChangeState = "scheduled"
+	StatusChangeApplied   StatusChangeState = "applied"
+	StatusChangeFailed    StatusChangeState = "failed"
+)
+
+// StatusChange records a lifecycle transition of an employee with its reason. Changes
+// with a future effective date stay scheduled until they are due.
+type StatusChange struct {
+	ID         string
+	EmployeeID string
+	// From is the status the change moves away from. Changes that only apply to one
+	// status, e.g. a reactivation, carry it from the start; others get it once applied
+	From        Status
+	To          Status
+	Reason      string
+	EffectiveAt time.Time
+	ActorID     string // empty for changes made by the system
+	State       StatusChangeState
+	Error       string
+	CreatedAt   time.Time
+	AppliedAt   *time.Time
+}
+
+func NewStatusChange(id, employeeID string, to Status, reason string, effectiveAt time.Time, actorID string, now time.Time) *StatusChange {
+	return &StatusChange{
+		ID:          id,
+		EmployeeID:  employeeID,
+		To:          to,
+		Reason:      reason,
+		EffectiveAt: effectiveAt,
+		ActorID:     actorID,
+		State:       StatusChangeScheduled,
+		CreatedAt:   now,
+	}
+}
+
+func (c *StatusChange) IsDue(now time.Time) bool {
+	return c.State == StatusChangeScheduled && !c.EffectiveAt.After(now)
+}
+
+// CheckFrom fails with ErrInvalidStatusTransition when the change only applies to
+// another status than current.
+func (c *StatusChange) CheckFrom(current Status) error {
+	if c.From != "" && c.From != current {
+		return fmt.Errorf("%w: %s to %s is only allowed from %s", ErrInvalidStatusTransition, current, c.To, c.From)
+	}
+	return nil
+}
+
+func (c *StatusChange) MarkApplied(from Status, now time.Time) {
+	c.From = from
+	c.State = StatusChangeApplied
+	c.AppliedAt = &now
+}
+
+func (c *StatusChange) MarkFailed(reason string, now time.Time) {
+	c.State = StatusChangeFailed
+	c.Error = reason
+	c.AppliedAt = &now
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+type TwoFactor struct {
+	EmployeeID         string
+	Secret             string
+	RecoveryCodeHashes []string
+	LastUsedStep       int64
+	ConfirmedAt        *time.Time
+}
+
+func NewTwoFactor(employeeID, secret string) *TwoFactor {
+	return &TwoFactor{
+		EmployeeID: employeeID,
+		Secret:     secret,
+	}
+}
+
+// IsEnabled reports whether enrollment was completed with a valid code.
+func (t *TwoFactor) IsEnabled() bool {
+	return t.ConfirmedAt != nil
+}
+
+func (t *TwoFactor) Confirm(now time.Time, recoveryCodeHashes []string) {
+	t.ConfirmedAt = &now
+	t.RecoveryCodeHashes = recoveryCodeHashes
+}
+
+// UseStep records a successfully verified time step and rejects codes that were already used.
+func (t *TwoFactor) UseStep(step int64) error {
+	if step <= t.LastUsedStep {
+		return errors.New("verification code already used")
+	}
+	t.LastUsedStep = step
+	return nil
+}
+
+// UseRecoveryCode consumes a recovery code so it cannot be used twice.
+func (t *TwoFactor) UseRecoveryCode(hash string) bool {
+	idx := slices.Index(t.RecoveryCodeHashes, hash)
+	if idx < 0 {
+		return false
+	}
+	t.RecoveryCodeHashes = slices.Delete(t.RecoveryCodeHashes, idx, idx+1)
+	return true
+}
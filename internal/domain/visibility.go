@@ -0,0 +1,55 @@
+package domain
+
+// Relationship describes how the viewer relates to the employee being viewed.
+type Relationship string
+
+const (
+	RelationshipSelf      Relationship = "self"
+	RelationshipSameStore Relationship = "same_store"
+	RelationshipOther     Relationship = "other"
+)
+
+// FieldVisibility lists which sensitive employee fields a viewer may see.
+type FieldVisibility struct {
+	Salary      bool
+	BirthDate   bool
+	Address     bool
+	PhoneNumber bool
+}
+
+func FullVisibility() FieldVisibility {
+	return FieldVisibility{Salary: true, BirthDate: true, Address: true, PhoneNumber: true}
+}
+
+// RelationshipBetween derives the relationship of viewer to target.
+func RelationshipBetween(viewer, target *Employee) Relationship {
+	switch {
+	case viewer.ID() == target.ID():
+		return RelationshipSelf
+	case viewer.StoreID() != "" && viewer.StoreID() == target.StoreID():
+		return RelationshipSameStore
+	default:
+		return RelationshipOther
+	}
+}
+
+// VisibleFields decides field visibility from the viewer's role and relationship:
+//   - employees always see their own data;
+//   - salary needs employee.salary.read (admins by default);
+//   - birth date, address and phone number need employee.personal.read, and only
+//     within the same store unless the role also has employee.scope.all_stores.
+func VisibleFields(policy Policy, viewerRole Role, rel Relationship) FieldVisibility {
+	if rel == RelationshipSelf {
+		return FullVisibility()
+	}
+
+	personal := policy.Allows(viewerRole, PermEmployeePersonalRead) &&
+		(rel == RelationshipSameStore || policy.Allows(viewerRole, PermEmployeeAllStores))
+
+	return FieldVisibility{
+		Salary:      policy.Allows(viewerRole, PermEmployeeSalaryRead),
+		BirthDate:   personal,
+		Address:     personal,
+		PhoneNumber: personal,
+	}
+}
@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"time"
+)
+
+var (
+	ErrUnknownWebhookEvent     = errors.New("unknown webhook event")
+	ErrNoWebhookEvents         = errors.New("a webhook subscription needs at least one event")
+	ErrWebhookNotRedeliverable = errors.New("only dead webhook deliveries can be redelivered")
+)
+
+type WebhookEvent string
+
+const (
+	WebhookEmployeeActivated    WebhookEvent = "employee.activated"
+	WebhookEmployeeSuspended    WebhookEvent = "employee.suspended"
+	WebhookEmployeeTerminated   WebhookEvent = "employee.terminated"
+	WebhookAttendanceCheckedIn  WebhookEvent = "attendance.checked_in"
+	WebhookAttendanceCheckedOut WebhookEvent = "attendance.checked_out"
+)
+
+// WebhookEvents lists every event integration partners can subscribe to.
+var WebhookEvents = []WebhookEvent{
+	WebhookEmployeeActivated,
+	WebhookEmployeeSuspended,
+	WebhookEmployeeTerminated,
+	WebhookAttendanceCheckedIn,
+	WebhookAttendanceCheckedOut,
+}
+
+// WebhookEventForStatus returns the event published when an employee enters status.
+func WebhookEventForStatus(status Status) (WebhookEvent, bool) {
+	switch status {
+	case StatusActive:
+		return WebhookEmployeeActivated, true
+	case StatusSuspended:
+		return WebhookEmployeeSuspended, true
+	case StatusTerminated:
+		return WebhookEmployeeTerminated, true
+	default:
+		return "", false
+	}
+}
+
+// WebhookSubscription sends the events it lists to URL, signed with Secret.
+type WebhookSubscription struct {
+	ID        string
+	URL       string
+	Events    []WebhookEvent
+	Secret    string
+	Active    bool
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewWebhookSubscription(id, rawURL string, events []WebhookEvent, secret, createdBy string, now time.Time) (*WebhookSubscription, error) {
+	s := &WebhookSubscription{
+		ID:        id,
+		Secret:    secret,
+		Active:    true,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.Update(rawURL, events, true, now); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update replaces the URL, events and active flag of the subscription.
+func (s *WebhookSubscription) Update(rawURL string, events []WebhookEvent, active bool, now time.Time) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+
+	if len(events) == 0 {
+		return ErrNoWebhookEvents
+	}
+	unique := make([]WebhookEvent, 0, len(events))
+	for _, event := range events {
+		if !slices.Contains(WebhookEvents, event) {
+			return ErrUnknownWebhookEvent
+		}
+		if !slices.Contains(unique, event) {
+			unique = append(unique, event)
+		}
+	}
+
+	s.URL = rawURL
+	s.Events = unique
+	s.Active = active
+	s.UpdatedAt = now
+	return nil
+}
+
+func (s *WebhookSubscription) RotateSecret(secret string, now time.Time) {
+	s.Secret = secret
+	s.UpdatedAt = now
+}
+
+func (s *WebhookSubscription) Subscribes(event WebhookEvent) bool {
+	return s.Active && slices.Contains(s.Events, event)
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryDead is the dead-letter state: retries are used up and the delivery
+	// waits for an admin to redeliver it.
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery is one event sent to one subscription. Payload is kept byte for byte,
+// so a retry carries exactly the body the first attempt did.
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID string
+	EventID        string
+	Event          WebhookEvent
+	Payload        []byte
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	LastError      string
+	ResponseStatus int
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+func NewWebhookDelivery(id, subscriptionID, eventID string, event WebhookEvent, payload []byte, now time.Time) *WebhookDelivery {
+	return &WebhookDelivery{
+		ID:             id,
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		Event:          event,
+		Payload:        payload,
+		Status:         WebhookDeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+}
+
+func (d *WebhookDelivery) MarkDelivered(responseStatus int, now time.Time) {
+	d.Attempts++
+	d.Status = WebhookDeliveryDelivered
+	d.ResponseStatus = responseStatus
+	d.LastError = ""
+	d.DeliveredAt = &now
+}
+
+// MarkAttemptFailed schedules a retry with backoff, or moves the delivery to the dead
+// letter state once the policy's attempts are used up.
+func (d *WebhookDelivery) MarkAttemptFailed(responseStatus int, reason string, now time.Time, policy DeliveryRetryPolicy) {
+	d.Attempts++
+	d.ResponseStatus = responseStatus
+	d.LastError = reason
+
+	if d.Attempts >= policy.MaxAttempts {
+		d.Status = WebhookDeliveryDead
+		return
+	}
+	d.NextAttemptAt = now.Add(policy.Backoff(d.Attempts))
+}
+
+// Redeliver takes a dead delivery out of the dead letter state for another round of attempts.
+func (d *WebhookDelivery) Redeliver(now time.Time) error {
+	if d.Status != WebhookDeliveryDead {
+		return ErrWebhookNotRedeliverable
+	}
+
+	d.Status = WebhookDeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+	return nil
+}
@@ -2,4 +2,6 @@ package attendance
 
 type CheckInRequest struct {
 	Location string `json:"location" binding:"required"`
+	// QR is the code shown by a store kiosk, scanned to prove the employee is in the store
+	QR string `json:"qr,omitempty"`
 }
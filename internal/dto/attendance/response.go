@@ -0,0 +1,35 @@
+package attendance
+
+type AttendanceRecordResponse struct {
+	ID            string            `json:"id"`
+	Date          string            `json:"date"`
+	Location      string            `json:"location"`
+	CheckIn       string            `json:"check_in"`
+	CheckOut      *string           `json:"check_out,omitempty"`
+	IsLate        bool              `json:"is_late"`
+	WorkedMinutes int               `json:"worked_minutes"`
+	BreakMinutes  int               `json:"break_minutes"`
+	Sessions      []SessionResponse `json:"sessions"`
+}
+
+type SessionResponse struct {
+	CheckIn          string          `json:"check_in"`
+	CheckOut         *string         `json:"check_out,omitempty"`
+	Location         string          `json:"location"`
+	CheckInPhotoURL  string          `json:"check_in_photo_url,omitempty"`
+	CheckOutPhotoURL string          `json:"check_out_photo_url,omitempty"`
+	Breaks           []BreakResponse `json:"breaks,omitempty"`
+}
+
+type BreakResponse struct {
+	Start string  `json:"start"`
+	End   *string `json:"end,omitempty"`
+}
+
+type AttendanceReviewResponse struct {
+	EmployeeID   string                     `json:"employee_id"`
+	EmployeeName string                     `json:"employee_name"`
+	StoreID      string                     `json:"store_id,omitempty"`
+	PhotoURL     string                     `json:"photo_url,omitempty"`
+	Records      []AttendanceRecordResponse `json:"records"`
+}
@@ -1,5 +1,7 @@
 package auth
 
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token              string `json:"token"`
+	TwoFactorRequired  bool   `json:"two_factor_required,omitempty"`
+	EnrollmentRequired bool   `json:"enrollment_required,omitempty"`
 }
@@ -0,0 +1,19 @@
+package compliance
+
+import "time"
+
+type ExpiringDocumentResponse struct {
+	DocumentID   string    `json:"document_id"`
+	EmployeeID   string    `json:"employee_id"`
+	EmployeeName string    `json:"employee_name"`
+	Type         string    `json:"type"`
+	Version      int       `json:"version"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	DaysLeft     int       `json:"days_left"`
+	Expired      bool      `json:"expired"`
+}
+
+type StoreExpirationsResponse struct {
+	StoreID   string                     `json:"store_id"`
+	Documents []ExpiringDocumentResponse `json:"documents"`
+}
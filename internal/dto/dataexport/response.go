@@ -0,0 +1,15 @@
+package dataexport
+
+import "time"
+
+type DataExportResponse struct {
+	ID          string     `json:"id"`
+	EmployeeID  string     `json:"employee_id"`
+	Status      string     `json:"status"`
+	Size        int64      `json:"size,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
@@ -0,0 +1,19 @@
+package document
+
+import "time"
+
+type DocumentResponse struct {
+	ID          string     `json:"id"`
+	EmployeeID  string     `json:"employee_id"`
+	Type        string     `json:"type"`
+	Version     int        `json:"version"`
+	Current     bool       `json:"current"` // the latest version of its type
+	FileName    string     `json:"file_name"`
+	ContentType string     `json:"content_type"`
+	Size        int64      `json:"size"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Expired     bool       `json:"expired"`
+	UploadedBy  string     `json:"uploaded_by"`
+	UploadedAt  time.Time  `json:"uploaded_at"`
+	DownloadURL string     `json:"download_url"`
+}
@@ -0,0 +1,21 @@
+package employee
+
+import "time"
+
+type DeletedEmployeeResponse struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	Role       string    `json:"role"`
+	Status     string    `json:"status"`
+	StoreID    string    `json:"store_id,omitempty"`
+	DeletedAt  time.Time `json:"deleted_at"`
+	Anonymized bool      `json:"anonymized"`
+}
+
+type PurgePlanResponse struct {
+	EmployeeID        string   `json:"employee_id"`
+	AttendanceRecords int64    `json:"attendance_records"`
+	Files             []string `json:"files"`
+	Cascade           []string `json:"cascade"`
+}
@@ -0,0 +1,13 @@
+package employee
+
+import "time"
+
+type EmailChangeRequest struct {
+	Email string `json:"email" validate:"required,email,max=150"`
+}
+
+type EmailChangeResponse struct {
+	ID        string    `json:"id"`
+	NewEmail  string    `json:"new_email"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,5 @@
+package erasure
+
+type EraseEmployeeRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
@@ -0,0 +1,15 @@
+package erasure
+
+import "time"
+
+type ErasureResponse struct {
+	ID                string    `json:"id"`
+	EmployeeID        string    `json:"employee_id"`
+	Trigger           string    `json:"trigger"`
+	RequestedBy       string    `json:"requested_by,omitempty"`
+	Reason            string    `json:"reason,omitempty"`
+	ErasedFields      []string  `json:"erased_fields"`
+	AttendanceRecords int64     `json:"attendance_records"`
+	PhotoDeleted      bool      `json:"photo_deleted"`
+	ErasedAt          time.Time `json:"erased_at"`
+}
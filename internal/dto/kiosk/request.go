@@ -0,0 +1,38 @@
+package kiosk
+
+import "time"
+
+// RegisterRequest carries the public key the kiosk generated to sign offline events,
+// base64 encoded. The private key stays on the kiosk.
+type RegisterRequest struct {
+	StoreID    string `json:"store_id" validate:"max=50"`
+	Name       string `json:"name" validate:"required,max=100"`
+	SigningKey string `json:"signing_key" validate:"required"`
+}
+
+// PINRequest identifies an employee at a kiosk.
+type PINRequest struct {
+	EmployeeID string `json:"employee_id" validate:"required"`
+	PIN        string `json:"pin" validate:"required"`
+}
+
+type SetPINRequest struct {
+	PIN string `json:"pin" validate:"required"`
+}
+
+// SyncRequest carries the events a kiosk collected while offline. SentAt is the kiosk's
+// clock when it sent the batch.
+type SyncRequest struct {
+	SentAt time.Time   `json:"sent_at" validate:"required"`
+	Events []SyncEvent `json:"events" validate:"required,min=1"`
+}
+
+// SyncEvent is signed by the kiosk, see package kiosksig.
+type SyncEvent struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	EmployeeID string    `json:"employee_id"`
+	PIN        string    `json:"pin"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Signature  string    `json:"signature"`
+}
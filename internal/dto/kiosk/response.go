@@ -0,0 +1,28 @@
+package kiosk
+
+import "time"
+
+// KioskResponse carries the device token only in the response of register.
+type KioskResponse struct {
+	ID        string     `json:"id"`
+	StoreID   string     `json:"store_id"`
+	Name      string     `json:"name"`
+	Token     string     `json:"token,omitempty"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+type QRResponse struct {
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SyncEventResult reports one event of a sync, in the order the events were sent.
+// Failed events should be sent again, all others can be dropped by the kiosk.
+type SyncEventResult struct {
+	ID           string `json:"id"`
+	Status       string `json:"status"`
+	AttendanceID string `json:"attendance_id,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
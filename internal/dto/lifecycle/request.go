@@ -0,0 +1,6 @@
+package lifecycle
+
+type StatusChangeRequest struct {
+	Reason        string `json:"reason" validate:"required,max=500"`
+	EffectiveDate string `json:"effective_date" validate:"required,datetime=2006-01-02"`
+}
@@ -0,0 +1,17 @@
+package lifecycle
+
+import "time"
+
+type StatusChangeResponse struct {
+	ID          string     `json:"id"`
+	EmployeeID  string     `json:"employee_id"`
+	From        string     `json:"from,omitempty"`
+	To          string     `json:"to"`
+	Reason      string     `json:"reason"`
+	EffectiveAt time.Time  `json:"effective_at"`
+	ActorID     string     `json:"actor_id,omitempty"`
+	State       string     `json:"state"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty"`
+}
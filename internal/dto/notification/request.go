@@ -0,0 +1,8 @@
+package notification
+
+// PreferencesRequest replaces the webhook URL and the channels of the listed events.
+// Events left out keep their channels; an empty list mutes an event.
+type PreferencesRequest struct {
+	WebhookURL string              `json:"webhook_url" validate:"omitempty,url,max=500"`
+	Channels   map[string][]string `json:"channels"`
+}
@@ -0,0 +1,25 @@
+package notification
+
+import "time"
+
+type NotificationResponse struct {
+	ID        string     `json:"id"`
+	Event     string     `json:"event"`
+	Subject   string     `json:"subject"`
+	Body      string     `json:"body"`
+	Read      bool       `json:"read"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+}
+
+type InboxResponse struct {
+	UnreadCount   int                    `json:"unread_count"`
+	Notifications []NotificationResponse `json:"notifications"`
+}
+
+// PreferencesResponse lists the channels in effect for every event, defaults included.
+type PreferencesResponse struct {
+	WebhookURL string              `json:"webhook_url,omitempty"`
+	Channels   map[string][]string `json:"channels"`
+	UpdatedAt  *time.Time          `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,7 @@
+package role
+
+type SaveRoleRequest struct {
+	Description string   `json:"description"`
+	Rank        int      `json:"rank" validate:"gte=0"`
+	Permissions []string `json:"permissions" validate:"required,dive,required"`
+}
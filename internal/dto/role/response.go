@@ -0,0 +1,9 @@
+package role
+
+type RoleResponse struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Rank        int      `json:"rank"`
+	BuiltIn     bool     `json:"built_in"`
+	Permissions []string `json:"permissions"`
+}
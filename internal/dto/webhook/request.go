@@ -0,0 +1,13 @@
+package webhook
+
+type CreateSubscriptionRequest struct {
+	URL    string   `json:"url" validate:"required,url,max=500"`
+	Events []string `json:"events" validate:"required,min=1"`
+}
+
+// UpdateSubscriptionRequest replaces the URL, events and active flag of a subscription.
+type UpdateSubscriptionRequest struct {
+	URL    string   `json:"url" validate:"required,url,max=500"`
+	Events []string `json:"events" validate:"required,min=1"`
+	Active *bool    `json:"active" validate:"required"`
+}
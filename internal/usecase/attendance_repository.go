@@ -11,4 +11,17 @@ type AttendanceRepository interface {
 	Save(ctx context.Context, attendance *domain.Attendance) error
 	Update(ctx context.Context, attendance *domain.Attendance) error
 	FindByEmployeeIDAndDate(ctx context.Context, employeeID string, date time.Time) (*domain.Attendance, error)
+	FindByEmployeeID(ctx context.Context, employeeID string) ([]*domain.Attendance, error)
+	// FindByEmployeeIDBetween returns the records of an employee dated in [from, to),
+	// oldest first.
+	FindByEmployeeIDBetween(ctx context.Context, employeeID string, from time.Time, to time.Time) ([]*domain.Attendance, error)
+	PseudonymizeEmployee(ctx context.Context, employeeID string, pseudonym string) (int64, error)
+	CountByEmployeeID(ctx context.Context, employeeID string) (int64, error)
+	DeleteByEmployeeID(ctx context.Context, employeeID string) (int64, error)
+	// FindMissingCheckOut returns records dated in [from, to) without a check-out whose
+	// employees have not been notified about the session left open yet.
+	FindMissingCheckOut(ctx context.Context, from time.Time, to time.Time) ([]*domain.Attendance, error)
+	// MarkMissedCheckOutNotified records that the employee was notified about the
+	// session'th session of the day, so a later session left open is reported again.
+	MarkMissedCheckOutNotified(ctx context.Context, id string, session int) error
 }
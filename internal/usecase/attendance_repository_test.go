@@ -37,3 +37,47 @@ func (m *MockAttendanceRepo) FindByEmployeeIDAndDate(ctx context.Context, employ
 func (m MockClock) Now() time.Time {
 	return m.currentTime
 }
+
+func (m *MockAttendanceRepo) PseudonymizeEmployee(ctx context.Context, employeeID string, pseudonym string) (int64, error) {
+	args := m.Called(ctx, employeeID, pseudonym)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAttendanceRepo) CountByEmployeeID(ctx context.Context, employeeID string) (int64, error) {
+	args := m.Called(ctx, employeeID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAttendanceRepo) DeleteByEmployeeID(ctx context.Context, employeeID string) (int64, error) {
+	args := m.Called(ctx, employeeID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAttendanceRepo) FindByEmployeeID(ctx context.Context, employeeID string) ([]*domain.Attendance, error) {
+	args := m.Called(ctx, employeeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Attendance), args.Error(1)
+}
+
+func (m *MockAttendanceRepo) FindMissingCheckOut(ctx context.Context, from time.Time, to time.Time) ([]*domain.Attendance, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Attendance), args.Error(1)
+}
+
+func (m *MockAttendanceRepo) MarkMissedCheckOutNotified(ctx context.Context, id string, session int) error {
+	args := m.Called(ctx, id, session)
+	return args.Error(0)
+}
+
+func (m *MockAttendanceRepo) FindByEmployeeIDBetween(ctx context.Context, employeeID string, from time.Time, to time.Time) ([]*domain.Attendance, error) {
+	args := m.Called(ctx, employeeID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Attendance), args.Error(1)
+}
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -11,29 +12,58 @@ import (
 	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
 	"github.com/zuyatna/shop-retail-employee-service/internal/dto/attendance"
 	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/imageproc"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/kioskqr"
+)
+
+const (
+	// missedCheckOutLookback is how many past days are searched for missing check-outs.
+	missedCheckOutLookback = 7
+	// defaultReviewDays and maxReviewDays bound the days shown by Review.
+	defaultReviewDays = 7
+	maxReviewDays     = 31
 )
 
 type AttendanceUsecase struct {
 	attendanceRepo AttendanceRepository
 	employeeRepo   EmployeeRepository
+	storageRepo    StorageRepository
+	kioskRepo      KioskRepository
 	idGen          IDGenerator
+	notifier       Notifier
+	publisher      EventPublisher
+	authorizer     *Authorizer
+	photos         PhotoConfig
+	kiosk          KioskConfig
 	cfg            *config.Config
 	clock          clock.Clock
 	ctxTimeout     time.Duration
 }
 
-func NewAttendanceUsecase(attendanceRepo AttendanceRepository, employeeRepo EmployeeRepository, idGen IDGenerator, cfg *config.Config, clk clock.Clock, timeout time.Duration) *AttendanceUsecase {
+func NewAttendanceUsecase(attendanceRepo AttendanceRepository, employeeRepo EmployeeRepository, storageRepo StorageRepository, kioskRepo KioskRepository, idGen IDGenerator, notifier Notifier, publisher EventPublisher, authorizer *Authorizer, photos PhotoConfig, kiosk KioskConfig, cfg *config.Config, clk clock.Clock, timeout time.Duration) *AttendanceUsecase {
 	return &AttendanceUsecase{
 		attendanceRepo: attendanceRepo,
 		employeeRepo:   employeeRepo,
+		storageRepo:    storageRepo,
+		kioskRepo:      kioskRepo,
 		idGen:          idGen,
+		notifier:       notifier,
+		publisher:      publisher,
+		authorizer:     authorizer,
+		photos:         photos,
+		kiosk:          kiosk,
 		cfg:            cfg,
 		clock:          clk,
 		ctxTimeout:     timeout,
 	}
 }
 
-func (uc *AttendanceUsecase) CheckIn(ctx context.Context, employeeID string, req attendance.CheckInRequest) (string, error) {
+// CheckIn checks the employee in, starting the first session of the day or a new one
+// after an earlier session was checked out. photo is optional, when given it is
+// stored next to the record. A QR code scanned at a store kiosk proves the employee is
+// in their store; it must be signed by this service, fresh, and shown by an active kiosk
+// of the employee's store.
+func (uc *AttendanceUsecase) CheckIn(ctx context.Context, employeeID string, req attendance.CheckInRequest, photo *PhotoUpload) (string, error) {
 	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
 	defer cancel()
 
@@ -45,45 +75,277 @@ func (uc *AttendanceUsecase) CheckIn(ctx context.Context, employeeID string, req
 		return "", EmployeeNotFoundError
 	}
 
-	now := uc.clock.Now().In(uc.cfg.AppTimezone)
+	var kiosk *domain.Kiosk
+	if req.QR != "" {
+		kiosk, err = uc.verifyKioskQR(ctx, req.QR, employee)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return uc.checkInOn(ctx, employeeID, employee, req.Location, kiosk, photo, uc.clock.Now())
+}
+
+// CheckInAtKiosk records the check-in of an employee who identified at kiosk, e.g. with
+// their PIN. at is when the employee did so, which lies in the past for events a kiosk
+// collected while offline. Kiosks only check in employees of their own store.
+func (uc *AttendanceUsecase) CheckInAtKiosk(ctx context.Context, kiosk *domain.Kiosk, employeeID string, at time.Time) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	employee, err := uc.kioskEmployee(ctx, kiosk, employeeID)
+	if err != nil {
+		return "", err
+	}
+
+	return uc.checkInOn(ctx, employeeID, employee, "", kiosk, nil, at)
+}
+
+// kioskEmployee loads an employee who identified at kiosk. Kiosks only serve active
+// employees of their own store.
+func (uc *AttendanceUsecase) kioskEmployee(ctx context.Context, kiosk *domain.Kiosk, employeeID string) (*domain.Employee, error) {
+	employee, err := uc.employeeRepo.FindByID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find employee by id: %w", err)
+	}
+	if employee == nil {
+		return nil, EmployeeNotFoundError
+	}
+	if employee.Status() != domain.StatusActive {
+		return nil, EmployeeNotActiveError
+	}
+	if err := checkKioskStore(kiosk, employee); err != nil {
+		return nil, err
+	}
+	return employee, nil
+}
+
+// checkInOn records a check-in at the given time unless the employee is still checked
+// in. kiosk is the kiosk the check-in was made at, if any. The ID of the day's
+// attendance is returned.
+func (uc *AttendanceUsecase) checkInOn(ctx context.Context, employeeID string, employee *domain.Employee, location string, kiosk *domain.Kiosk, photo *PhotoUpload, at time.Time) (string, error) {
+	now := at.In(uc.cfg.AppTimezone)
 	dateOnly := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
 	existingAttendance, err := uc.attendanceRepo.FindByEmployeeIDAndDate(ctx, employeeID, dateOnly)
 	if err != nil {
 		return "", err
 	}
+	if existingAttendance != nil && existingAttendance.OpenSession() != nil {
+		return "", AlreadyCheckedInError
+	}
+
+	photoKey, err := uc.storePhoto(ctx, photo)
+	if err != nil {
+		return "", err
+	}
+
+	params := domain.CheckInParams{
+		EmployeeID:  employeeID,
+		Location:    location,
+		CheckInTime: now,
+		Photo:       photoKey,
+	}
+	if kiosk != nil {
+		params.KioskID = kiosk.ID
+		if params.Location == "" {
+			params.Location = kiosk.AttendanceLocation()
+		}
+	}
+
 	if existingAttendance != nil {
-		return "", errors.New("you've already checked in today")
+		if err := uc.startSession(ctx, employee, existingAttendance, params); err != nil {
+			uc.deleteFile(ctx, photoKey)
+			return "", err
+		}
+		return existingAttendance.ID, nil
 	}
 
-	attendanceID, err := uc.idGen.NewID()
+	newAttendance, err := uc.checkIn(ctx, employee, params)
 	if err != nil {
+		uc.deleteFile(ctx, photoKey)
 		return "", err
 	}
 
-	newAttendance := domain.NewAttendance(domain.CheckInParams{
-		ID:              attendanceID,
-		EmployeeID:      employeeID,
-		EmployeeName:    employee.Name(),
-		Location:        req.Location,
-		CheckInTime:     now,
-		OfficeStartHour: uc.cfg.OfficeStartHour,
-		OfficeStartMin:  uc.cfg.OfficeStartMin,
-	})
+	return newAttendance.ID, nil
+}
 
-	if err := uc.attendanceRepo.Save(ctx, newAttendance); err != nil {
-		return "", fmt.Errorf("failed to save attendance: %w", err)
+// verifyKioskQR checks that a scanned QR code was issued by this service within the QR
+// lifetime, by a kiosk that is still active and stands in the employee's store.
+func (uc *AttendanceUsecase) verifyKioskQR(ctx context.Context, code string, employee *domain.Employee) (*domain.Kiosk, error) {
+	payload, err := kioskqr.Verify(uc.kiosk.QRSecret, code, uc.clock.Now(), uc.kiosk.QRTTL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", InvalidKioskQRError, err)
 	}
-	slog.Log(ctx, slog.LevelInfo, "Employee checked in", "employeeID", employeeID, "time", now)
 
-	return attendanceID, nil
+	kiosk, err := uc.kioskRepo.FindByID(ctx, payload.KioskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find kiosk: %w", err)
+	}
+	if kiosk == nil || kiosk.IsRevoked() || kiosk.StoreID != payload.StoreID {
+		return nil, fmt.Errorf("%w: kiosk is not active", InvalidKioskQRError)
+	}
+
+	if err := checkKioskStore(kiosk, employee); err != nil {
+		return nil, err
+	}
+	return kiosk, nil
+}
+
+// checkKioskStore rejects employees checking in at a kiosk of another store.
+func checkKioskStore(kiosk *domain.Kiosk, employee *domain.Employee) error {
+	if employee.StoreID() == "" || employee.StoreID() != kiosk.StoreID {
+		return KioskStoreMismatchError
+	}
+	return nil
 }
 
-func (uc *AttendanceUsecase) CheckOut(ctx context.Context, employeeID string) error {
+// RecordImplicitCheckIn checks an employee in on behalf of another system, e.g. when the
+// employee opens a cashier session at a POS register. It is idempotent: when the employee
+// is already checked in, the open session is kept and the ID of the day returned.
+func (uc *AttendanceUsecase) RecordImplicitCheckIn(ctx context.Context, employeeID string, location string, at time.Time) (string, error) {
 	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
 	defer cancel()
 
-	now := uc.clock.Now().In(uc.cfg.AppTimezone)
+	employee, err := uc.employeeRepo.FindByID(ctx, employeeID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find employee by id: %w", err)
+	}
+	if employee == nil {
+		return "", EmployeeNotFoundError
+	}
+	if employee.Status() != domain.StatusActive {
+		return "", EmployeeNotActiveError
+	}
+
+	at = at.In(uc.cfg.AppTimezone)
+	dateOnly := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+
+	existingAttendance, err := uc.attendanceRepo.FindByEmployeeIDAndDate(ctx, employeeID, dateOnly)
+	if err != nil {
+		return "", err
+	}
+	params := domain.CheckInParams{
+		EmployeeID:  employeeID,
+		Location:    location,
+		CheckInTime: at,
+	}
+	if existingAttendance != nil {
+		if existingAttendance.OpenSession() != nil {
+			return existingAttendance.ID, nil
+		}
+		if err := uc.startSession(ctx, employee, existingAttendance, params); err != nil {
+			return "", err
+		}
+		return existingAttendance.ID, nil
+	}
+
+	newAttendance, err := uc.checkIn(ctx, employee, params)
+	if err != nil {
+		return "", err
+	}
+
+	return newAttendance.ID, nil
+}
+
+// CheckOut checks the employee out of their open session of today. photo is optional, when given it
+// is stored next to the record.
+func (uc *AttendanceUsecase) CheckOut(ctx context.Context, employeeID string, photo *PhotoUpload) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	return uc.checkOutOn(ctx, employeeID, photo, uc.clock.Now())
+}
+
+// CheckOutAtKiosk records the check-out of an employee who identified at kiosk at the
+// given time. Kiosks only check out employees of their own store.
+func (uc *AttendanceUsecase) CheckOutAtKiosk(ctx context.Context, kiosk *domain.Kiosk, employeeID string, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if _, err := uc.kioskEmployee(ctx, kiosk, employeeID); err != nil {
+		return err
+	}
+
+	return uc.checkOutOn(ctx, employeeID, nil, at)
+}
+
+// checkOutOn records the check-out at the given time on the employee's open session of
+// that day.
+func (uc *AttendanceUsecase) checkOutOn(ctx context.Context, employeeID string, photo *PhotoUpload, at time.Time) error {
+	now := at.In(uc.cfg.AppTimezone)
+	dateOnly := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	attendanceRecord, err := uc.attendanceRepo.FindByEmployeeIDAndDate(ctx, employeeID, dateOnly)
+	if err != nil {
+		return fmt.Errorf("failed to find attendance record: %w", err)
+	}
+	if attendanceRecord == nil || attendanceRecord.OpenSession() == nil {
+		return NotCheckedInError
+	}
+
+	photoKey, err := uc.storePhoto(ctx, photo)
+	if err != nil {
+		return err
+	}
+
+	endSession := func(a *domain.Attendance) error {
+		return a.EndSession(now, photoKey)
+	}
+	if err := uc.punchDay(ctx, attendanceRecord, endSession); err != nil {
+		uc.deleteFile(ctx, photoKey)
+		return err
+	}
+	slog.Log(ctx, slog.LevelInfo, "Employee checked out", "employeeID", employeeID, "time", now)
+	uc.publish(ctx, domain.WebhookAttendanceCheckedOut, nil, attendanceRecord)
+
+	return nil
+}
+
+// StartBreak starts a break in the employee's open session of today.
+func (uc *AttendanceUsecase) StartBreak(ctx context.Context, employeeID string) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	return uc.breakOn(ctx, employeeID, uc.clock.Now(), true)
+}
+
+// EndBreak ends the employee's break in progress.
+func (uc *AttendanceUsecase) EndBreak(ctx context.Context, employeeID string) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	return uc.breakOn(ctx, employeeID, uc.clock.Now(), false)
+}
+
+// StartBreakAtKiosk starts a break of an employee who identified at kiosk at the given
+// time.
+func (uc *AttendanceUsecase) StartBreakAtKiosk(ctx context.Context, kiosk *domain.Kiosk, employeeID string, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if _, err := uc.kioskEmployee(ctx, kiosk, employeeID); err != nil {
+		return err
+	}
+	return uc.breakOn(ctx, employeeID, at, true)
+}
+
+// EndBreakAtKiosk ends the break of an employee who identified at kiosk at the given
+// time.
+func (uc *AttendanceUsecase) EndBreakAtKiosk(ctx context.Context, kiosk *domain.Kiosk, employeeID string, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if _, err := uc.kioskEmployee(ctx, kiosk, employeeID); err != nil {
+		return err
+	}
+	return uc.breakOn(ctx, employeeID, at, false)
+}
+
+// breakOn starts or ends a break at the given time in the employee's open session of
+// that day.
+func (uc *AttendanceUsecase) breakOn(ctx context.Context, employeeID string, at time.Time, start bool) error {
+	now := at.In(uc.cfg.AppTimezone)
 	dateOnly := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
 	attendanceRecord, err := uc.attendanceRepo.FindByEmployeeIDAndDate(ctx, employeeID, dateOnly)
@@ -91,18 +353,381 @@ func (uc *AttendanceUsecase) CheckOut(ctx context.Context, employeeID string) er
 		return fmt.Errorf("failed to find attendance record: %w", err)
 	}
 	if attendanceRecord == nil {
-		return errors.New("no check-in record found for today")
+		return NotCheckedInError
+	}
+
+	punch, message := (*domain.Attendance).EndBreak, "Employee ended a break"
+	if start {
+		punch, message = (*domain.Attendance).StartBreak, "Employee started a break"
 	}
-	if attendanceRecord.CheckOut != nil {
-		return errors.New("you have already checked out today")
+	if err := uc.punchDay(ctx, attendanceRecord, func(a *domain.Attendance) error { return punch(a, now) }); err != nil {
+		return err
 	}
+	slog.Log(ctx, slog.LevelInfo, message, "employeeID", employeeID, "time", now)
+
+	return nil
+}
+
+// attendancePunchAttempts is how often a punch is applied to a freshly read day when
+// other punches of the day keep being stored in between.
+const attendancePunchAttempts = 3
 
-	attendanceRecord.SetCheckOut(now)
+// punchDay applies punch to the day a and stores it. When another punch of the day was
+// stored since a was read, the day is read again and punch applied to it, so neither
+// punch is lost. a holds the stored day afterwards.
+func (uc *AttendanceUsecase) punchDay(ctx context.Context, a *domain.Attendance, punch func(*domain.Attendance) error) error {
+	for attempt := 1; ; attempt++ {
+		if err := punch(a); err != nil {
+			return punchError(err)
+		}
 
-	if err := uc.attendanceRepo.Update(ctx, attendanceRecord); err != nil {
-		return fmt.Errorf("failed to update attendance record: %w", err)
+		err := uc.attendanceRepo.Update(ctx, a)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, domain.ErrAttendanceConflict) {
+			return fmt.Errorf("failed to update attendance record: %w", err)
+		}
+		if attempt == attendancePunchAttempts {
+			return AttendanceConflictError
+		}
+
+		fresh, err := uc.attendanceRepo.FindByEmployeeIDAndDate(ctx, a.EmployeeID, a.Date)
+		if err != nil {
+			return fmt.Errorf("failed to find attendance record: %w", err)
+		}
+		if fresh == nil {
+			return NotCheckedInError
+		}
+		*a = *fresh
 	}
-	slog.Log(ctx, slog.LevelInfo, "Employee checked out", "employeeID", employeeID, "time", now)
+}
+
+// punchError turns the reason the day refused a punch into the error shown to the
+// employee.
+func punchError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrSessionOpen):
+		return AlreadyCheckedInError
+	case errors.Is(err, domain.ErrNoOpenSession):
+		return NotCheckedInError
+	case errors.Is(err, domain.ErrBreakOpen):
+		return AlreadyOnBreakError
+	case errors.Is(err, domain.ErrNoOpenBreak):
+		return NotOnBreakError
+	case errors.Is(err, domain.ErrPunchOutOfOrder):
+		return PunchOutOfOrderError
+	}
+	return err
+}
+
+// breakPolicy returns the policy deciding which breaks are deducted from worked time.
+func (uc *AttendanceUsecase) breakPolicy() domain.BreakPolicy {
+	return domain.BreakPolicy{MinBreak: time.Duration(uc.cfg.MinBreakMinutes) * time.Minute}
+}
+
+// AttendanceReview is an employee's attendance as a supervisor reviews it, with the
+// profile photo to compare the check-in and check-out photos against.
+type AttendanceReview struct {
+	Employee *domain.Employee
+	PhotoURL string
+	Records  []ReviewedAttendance
+}
+
+// ReviewedAttendance is the attendance of a day with presigned URLs of the photos of
+// each session, and the time worked and spent on breaks under the break policy.
+type ReviewedAttendance struct {
+	*domain.Attendance
+	PhotoURLs []SessionPhotoURLs // one per session, in the same order
+	Worked    time.Duration
+	BreakTime time.Duration
+}
+
+type SessionPhotoURLs struct {
+	CheckIn  string
+	CheckOut string
+}
+
+// Review returns the attendance of an employee between two dates (YYYY-MM-DD, both
+// inclusive). An empty to means today, an empty from the defaultReviewDays up to to.
+// Actors without access to all stores only review employees of their own store.
+func (uc *AttendanceUsecase) Review(ctx context.Context, actor Actor, employeeID string, from string, to string) (*AttendanceReview, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermAttendanceApprove); err != nil {
+		return nil, err
+	}
+
+	first, last, err := uc.reviewRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	employee, err := uc.employeeRepo.FindByID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find employee by id: %w", err)
+	}
+	if employee == nil {
+		return nil, EmployeeNotFoundError
+	}
+	if err := uc.authorizer.AuthorizeHierarchy(ctx, actor, employee); err != nil {
+		return nil, err
+	}
+
+	allStores, err := uc.authorizer.HasAny(ctx, actor.Role, domain.PermEmployeeAllStores)
+	if err != nil {
+		return nil, err
+	}
+	if !allStores {
+		viewer, err := uc.employeeRepo.FindByID(ctx, actor.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find viewer: %w", err)
+		}
+		if viewer == nil || viewer.StoreID() == "" || viewer.StoreID() != employee.StoreID() {
+			return nil, ForbiddenError
+		}
+	}
+
+	records, err := uc.attendanceRepo.FindByEmployeeIDBetween(ctx, employeeID, first, last.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find attendance records: %w", err)
+	}
+
+	review := &AttendanceReview{
+		Employee: employee,
+		PhotoURL: presignPhoto(ctx, uc.storageRepo, employee.Photo(), uc.photos.URLTTL),
+		Records:  make([]ReviewedAttendance, 0, len(records)),
+	}
+	policy := uc.breakPolicy()
+	for _, record := range records {
+		reviewed := ReviewedAttendance{
+			Attendance: record,
+			PhotoURLs:  make([]SessionPhotoURLs, 0, len(record.Sessions)),
+			Worked:     record.Worked(policy),
+			BreakTime:  record.BreakTime(policy),
+		}
+		for _, session := range record.Sessions {
+			reviewed.PhotoURLs = append(reviewed.PhotoURLs, SessionPhotoURLs{
+				CheckIn:  presignPhoto(ctx, uc.storageRepo, session.CheckInPhoto, uc.photos.URLTTL),
+				CheckOut: presignPhoto(ctx, uc.storageRepo, session.CheckOutPhoto, uc.photos.URLTTL),
+			})
+		}
+		review.Records = append(review.Records, reviewed)
+	}
+
+	return review, nil
+}
+
+// reviewRange parses the dates of a review, filling in the defaults, and returns the
+// first and last day reviewed.
+func (uc *AttendanceUsecase) reviewRange(from string, to string) (time.Time, time.Time, error) {
+	now := uc.clock.Now().In(uc.cfg.AppTimezone)
+	last := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if to != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, to, uc.cfg.AppTimezone)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: invalid to date: %v", InvalidDateRangeError, err)
+		}
+		last = parsed
+	}
+
+	first := last.AddDate(0, 0, -(defaultReviewDays - 1))
+	if from != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, from, uc.cfg.AppTimezone)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: invalid from date: %v", InvalidDateRangeError, err)
+		}
+		first = parsed
+	}
+
+	if first.After(last) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: from is after to", InvalidDateRangeError)
+	}
+	if first.AddDate(0, 0, maxReviewDays).Before(last.AddDate(0, 0, 1)) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: at most %d days can be reviewed at once", InvalidDateRangeError, maxReviewDays)
+	}
+
+	return first, last, nil
+}
+
+// ReportMissedCheckOuts notifies about check-ins of the last missedCheckOutLookback days,
+// today excluded, that were never followed by a check-out. Each session left open is
+// reported once.
+func (uc *AttendanceUsecase) ReportMissedCheckOuts(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	now := uc.clock.Now().In(uc.cfg.AppTimezone)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	missing, err := uc.attendanceRepo.FindMissingCheckOut(ctx, today.AddDate(0, 0, -missedCheckOutLookback), today)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find missing check-outs: %w", err)
+	}
+
+	reported := 0
+	for _, record := range missing {
+		employee, err := uc.employeeRepo.FindByID(ctx, record.EmployeeID)
+		if err != nil {
+			// Left unmarked, so the next run tries again while the record is in the window
+			slog.Log(ctx, slog.LevelWarn, "Failed to find employee of missed check-out", "ID", record.ID, "error", err)
+			continue
+		}
+		if employee != nil {
+			if err := uc.notifier.NotifyAboutEmployee(ctx, domain.EventMissedCheckOut, employee, attendanceNotificationData(record)); err != nil {
+				slog.Log(ctx, slog.LevelWarn, "Failed to notify about missed check-out", "ID", record.ID, "error", err)
+				continue
+			}
+		}
+
+		if err := uc.attendanceRepo.MarkMissedCheckOutNotified(ctx, record.ID, len(record.Sessions)); err != nil {
+			return reported, fmt.Errorf("failed to mark missed check-out: %w", err)
+		}
+		reported++
+	}
+
+	return reported, nil
+}
+
+// checkIn records the check-in of employee described by params and tells whoever has to
+// know about it. The ID, the employee name and the office hours are filled in here.
+func (uc *AttendanceUsecase) checkIn(ctx context.Context, employee *domain.Employee, params domain.CheckInParams) (*domain.Attendance, error) {
+	attendanceID, err := uc.idGen.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	params.ID = attendanceID
+	params.EmployeeName = employee.Name()
+	params.OfficeStartHour = uc.cfg.OfficeStartHour
+	params.OfficeStartMin = uc.cfg.OfficeStartMin
+	newAttendance := domain.NewAttendance(params)
+	employeeID := params.EmployeeID
+
+	if err := uc.attendanceRepo.Save(ctx, newAttendance); err != nil {
+		return nil, fmt.Errorf("failed to save attendance: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Employee checked in", "employeeID", employeeID, "time", params.CheckInTime, "kioskID", params.KioskID)
+	uc.publish(ctx, domain.WebhookAttendanceCheckedIn, employee, newAttendance)
+
+	if newAttendance.IsLate {
+		// The check-in is recorded either way, a failed notification must not undo it
+		if err := uc.notifier.NotifyAboutEmployee(ctx, domain.EventLateCheckIn, employee, attendanceNotificationData(newAttendance)); err != nil {
+			slog.Log(ctx, slog.LevelWarn, "Failed to notify about late check-in", "employeeID", employeeID, "error", err)
+		}
+	}
+
+	return newAttendance, nil
+}
+
+// startSession checks employee in again on a day whose earlier sessions were checked
+// out. Only the first check-in of a day can be late.
+func (uc *AttendanceUsecase) startSession(ctx context.Context, employee *domain.Employee, a *domain.Attendance, params domain.CheckInParams) error {
+	if err := uc.punchDay(ctx, a, func(a *domain.Attendance) error { return a.StartSession(params) }); err != nil {
+		return err
+	}
+	slog.Log(ctx, slog.LevelInfo, "Employee checked in again", "employeeID", params.EmployeeID, "time", params.CheckInTime, "session", len(a.Sessions), "kioskID", params.KioskID)
+	uc.publish(ctx, domain.WebhookAttendanceCheckedIn, employee, a)
 
 	return nil
 }
+
+// storePhoto normalises a check-in or check-out photo like a profile photo and stores
+// it, returning its object key. Without a photo it stores nothing and returns "".
+func (uc *AttendanceUsecase) storePhoto(ctx context.Context, upload *PhotoUpload) (string, error) {
+	if upload == nil {
+		return "", nil
+	}
+
+	if err := uc.photos.check(*upload); err != nil {
+		return "", err
+	}
+	photo, err := uc.photos.decode(*upload)
+	if err != nil {
+		return "", err
+	}
+
+	// Re-encoding drops the EXIF data of the original, location included
+	encoded, err := imageproc.EncodeJPEG(photo.Square(uc.photos.Size))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode attendance photo: %w", err)
+	}
+
+	uploadID, err := uc.idGen.NewID()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate photo ID: %w", err)
+	}
+	key := domain.AttendancePhotoKey(uploadID)
+
+	if _, err := uc.storageRepo.UploadFile(ctx, key, "image/jpeg", bytes.NewReader(encoded), int64(len(encoded))); err != nil {
+		return "", fmt.Errorf("failed to upload attendance photo to storage: %w", err)
+	}
+
+	return key, nil
+}
+
+// deleteFile removes a photo whose record could not be stored, on a best effort basis.
+func (uc *AttendanceUsecase) deleteFile(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	if err := uc.storageRepo.DeleteFile(ctx, key); err != nil {
+		slog.Log(ctx, slog.LevelWarn, "Failed to delete orphaned file", "key", key, "error", err)
+	}
+}
+
+// attendanceEvent is the data of the attendance.* webhook events.
+type attendanceEvent struct {
+	AttendanceID string  `json:"attendance_id"`
+	EmployeeID   string  `json:"employee_id"`
+	StoreID      string  `json:"store_id,omitempty"`
+	Location     string  `json:"location"`
+	Date         string  `json:"date"`
+	CheckIn      string  `json:"check_in"`
+	CheckOut     *string `json:"check_out,omitempty"`
+	IsLate       bool    `json:"is_late"`
+	// SessionCount is how many sessions the day has so far, WorkedMinutes the time
+	// worked in those checked out, net of breaks
+	SessionCount  int `json:"session_count"`
+	WorkedMinutes int `json:"worked_minutes"`
+}
+
+// publish tells integration partners about an attendance record. The record is already
+// stored, so a failure is logged instead of returned. employee may be nil when it was
+// not loaded.
+func (uc *AttendanceUsecase) publish(ctx context.Context, event domain.WebhookEvent, employee *domain.Employee, a *domain.Attendance) {
+	data := attendanceEvent{
+		AttendanceID: a.ID,
+		EmployeeID:   a.EmployeeID,
+		Location:     a.Location,
+		Date:         a.Date.Format(time.DateOnly),
+		CheckIn:      a.CheckIn,
+		CheckOut:     a.CheckOut,
+		IsLate:       a.IsLate,
+
+		SessionCount:  len(a.Sessions),
+		WorkedMinutes: int(a.Worked(uc.breakPolicy()).Minutes()),
+	}
+	if employee != nil {
+		data.StoreID = employee.StoreID()
+	}
+
+	if err := uc.publisher.Publish(ctx, event, data); err != nil {
+		slog.Log(ctx, slog.LevelError, "Failed to publish attendance event", "ID", a.ID, "event", event, "error", err)
+	}
+}
+
+func attendanceNotificationData(a *domain.Attendance) map[string]string {
+	checkIn := a.CheckIn
+	if t, err := time.Parse(time.DateTime, a.CheckIn); err == nil {
+		checkIn = t.Format("15:04")
+	}
+
+	return map[string]string{
+		"EmployeeName": a.EmployeeName,
+		"Date":         a.Date.Format(time.DateOnly),
+		"CheckIn":      checkIn,
+		"Location":     a.Location,
+	}
+}
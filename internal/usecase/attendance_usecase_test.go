@@ -0,0 +1,491 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/config"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/dto/attendance"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+var testKioskConfig = usecase.KioskConfig{
+	QRSecret:       []byte("kiosk-secret"),
+	QRTTL:          30 * time.Second,
+	PINThrottle:    domain.LoginThrottlePolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute, LockoutDuration: 15 * time.Minute},
+	DeviceThrottle: domain.LoginThrottlePolicy{MaxAttempts: 20, BaseDelay: time.Second, MaxDelay: time.Minute, LockoutDuration: 15 * time.Minute},
+
+	SyncMaxClockSkew: 5 * time.Minute,
+	SyncMaxEventAge:  72 * time.Hour,
+	SyncMaxBatch:     50,
+}
+
+func photoUpload(content []byte, fileName string) *usecase.PhotoUpload {
+	return &usecase.PhotoUpload{Content: bytes.NewReader(content), Size: int64(len(content)), ContentType: "image/jpeg", FileName: fileName}
+}
+
+func TestAttendanceUsecase_CheckInPhoto(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	now := time.Date(2026, 10, 10, 8, 55, 0, 0, loc)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	cfg := &config.Config{AppTimezone: now.Location(), OfficeStartHour: 9, MinBreakMinutes: 15}
+
+	today := time.Date(2026, 10, 10, 0, 0, 0, 0, loc)
+	req := attendance.CheckInRequest{Location: "Store 1"}
+
+	mockAttendanceRepo := new(MockAttendanceRepo)
+	mockRepo := new(MockEmployeeRepo)
+	mockStorageRepo := new(MockStorageRepo)
+	mockIDGen := new(MockIDGenerator)
+	uc := usecase.NewAttendanceUsecase(mockAttendanceRepo, mockRepo, mockStorageRepo, new(MockKioskRepo), mockIDGen, new(MockNotifier), publisher, authorizer, testPhotoConfig, testKioskConfig, cfg, clk, time.Second)
+
+	var stored []byte
+
+	// Storage and Save are only mocked where a photo is stored, so recording a
+	// refused photo fails the mock.
+	tests := []struct {
+		name    string
+		photo   *usecase.PhotoUpload
+		setup   func()
+		wantErr error
+		check   func(t *testing.T)
+	}{
+		{
+			name:  "Success - Stores Square Photo Without EXIF",
+			photo: photoUpload(twoToneJPEG(t, 600, 300, 6), "selfie.jpg"),
+			setup: func() {
+				mockIDGen.On("NewID").Return("upload-1", nil).Once()
+				mockIDGen.On("NewID").Return("att-1", nil).Once()
+				mockStorageRepo.On("UploadFile", mock.Anything, "attendance/upload-1.jpg", "image/jpeg", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					stored, _ = io.ReadAll(args.Get(3).(io.Reader))
+				}).Return("attendance/upload-1.jpg", nil).Once()
+				mockAttendanceRepo.On("Save", mock.Anything, mock.MatchedBy(func(a *domain.Attendance) bool {
+					return a.ID == "att-1" && a.Sessions[0].CheckInPhoto == "attendance/upload-1.jpg"
+				})).Return(nil).Once()
+			},
+			check: func(t *testing.T) {
+				assert.NotContains(t, string(stored), "Exif")
+				img, err := jpeg.Decode(bytes.NewReader(stored))
+				assert.NoError(t, err)
+				assert.Equal(t, image.Rect(0, 0, 256, 256), img.Bounds())
+			},
+		},
+		{
+			name:    "Fail - Invalid Photo Records Nothing",
+			photo:   photoUpload([]byte("not an image"), "selfie.jpg"),
+			setup:   func() {},
+			wantErr: usecase.InvalidPhotoError,
+		},
+		{
+			name:  "Fail - Save Error Deletes Stored Photo",
+			photo: photoUpload(twoToneJPEG(t, 100, 100, 1), "selfie.jpg"),
+			setup: func() {
+				mockIDGen.On("NewID").Return("upload-1", nil).Once()
+				mockIDGen.On("NewID").Return("att-1", nil).Once()
+				mockStorageRepo.On("UploadFile", mock.Anything, "attendance/upload-1.jpg", "image/jpeg", mock.Anything, mock.Anything).Return("attendance/upload-1.jpg", nil).Once()
+				mockAttendanceRepo.On("Save", mock.Anything, mock.Anything).Return(assert.AnError).Once()
+				mockStorageRepo.On("DeleteFile", mock.Anything, "attendance/upload-1.jpg").Return(nil).Once()
+			},
+			wantErr: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo.On("FindByID", mock.Anything, "emp-1").Return(employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive), nil).Once()
+			mockAttendanceRepo.On("FindByEmployeeIDAndDate", mock.Anything, "emp-1", today).Return(nil, nil).Once()
+			tt.setup()
+
+			id, err := uc.CheckIn(context.Background(), "emp-1", req, tt.photo)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "att-1", id)
+				tt.check(t)
+			}
+			mockAttendanceRepo.AssertExpectations(t)
+			mockStorageRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAttendanceUsecase_CheckOutPhoto(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	now := time.Date(2026, 10, 10, 17, 5, 0, 0, loc)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	cfg := &config.Config{AppTimezone: now.Location(), OfficeStartHour: 9, MinBreakMinutes: 15}
+
+	today := time.Date(2026, 10, 10, 0, 0, 0, 0, loc)
+
+	mockAttendanceRepo := new(MockAttendanceRepo)
+	mockStorageRepo := new(MockStorageRepo)
+	mockIDGen := new(MockIDGenerator)
+	uc := usecase.NewAttendanceUsecase(mockAttendanceRepo, new(MockEmployeeRepo), mockStorageRepo, new(MockKioskRepo), mockIDGen, new(MockNotifier), publisher, authorizer, testPhotoConfig, testKioskConfig, cfg, clk, time.Second)
+
+	record := domain.NewAttendance(domain.CheckInParams{ID: "att-1", EmployeeID: "emp-1", CheckInTime: time.Date(2026, 10, 10, 8, 55, 0, 0, loc)})
+	mockAttendanceRepo.On("FindByEmployeeIDAndDate", mock.Anything, "emp-1", today).Return(record, nil).Once()
+	mockIDGen.On("NewID").Return("upload-2", nil).Once()
+	mockStorageRepo.On("UploadFile", mock.Anything, "attendance/upload-2.jpg", "image/jpeg", mock.Anything, mock.Anything).Return("attendance/upload-2.jpg", nil).Once()
+	mockAttendanceRepo.On("Update", mock.Anything, record).Return(nil).Once()
+
+	err := uc.CheckOut(context.Background(), "emp-1", photoUpload(twoToneJPEG(t, 100, 100, 1), "selfie.jpg"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-10-10 17:05:00", *record.CheckOut)
+	assert.Equal(t, "attendance/upload-2.jpg", record.Sessions[0].CheckOutPhoto)
+	mockAttendanceRepo.AssertExpectations(t)
+}
+
+func TestAttendanceUsecase_Review(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	now := time.Date(2026, 10, 10, 12, 0, 0, 0, loc)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	cfg := &config.Config{AppTimezone: now.Location(), OfficeStartHour: 9, MinBreakMinutes: 15}
+
+	mockAttendanceRepo := new(MockAttendanceRepo)
+	mockRepo := new(MockEmployeeRepo)
+	mockStorageRepo := new(MockStorageRepo)
+	uc := usecase.NewAttendanceUsecase(mockAttendanceRepo, mockRepo, mockStorageRepo, new(MockKioskRepo), new(MockIDGenerator), new(MockNotifier), publisher, authorizer, testPhotoConfig, testKioskConfig, cfg, clk, time.Second)
+
+	supervisor := usecase.Actor{ID: "sup-1", Role: domain.RoleSupervisor}
+	found := func(emp *domain.Employee) {
+		mockRepo.On("FindByID", mock.Anything, string(emp.ID())).Return(emp, nil).Once()
+		mockRepo.On("FindByID", mock.Anything, "sup-1").Return(employeeWithStatus("sup-1", domain.RoleSupervisor, domain.StatusActive), nil).Once()
+	}
+
+	withPhoto := employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive)
+	withPhoto.SetPhoto("photos/profile.jpg")
+
+	from := time.Date(2026, 10, 4, 0, 0, 0, 0, loc)
+	photographed := &domain.Attendance{ID: "att-1", EmployeeID: "emp-1", Date: from, Sessions: []domain.WorkSession{{CheckInPhoto: "attendance/upload-1.jpg"}}}
+
+	at := func(hour, min int) time.Time { return time.Date(2026, 10, 9, hour, min, 0, 0, loc) }
+	split := domain.NewAttendance(domain.CheckInParams{ID: "att-1", EmployeeID: "emp-1", CheckInTime: at(8, 0)})
+	assert.NoError(t, split.StartBreak(at(10, 0)))
+	assert.NoError(t, split.EndBreak(at(10, 10))) // shorter than the minimum, worked time
+	assert.NoError(t, split.EndSession(at(12, 0), ""))
+	assert.NoError(t, split.StartSession(domain.CheckInParams{CheckInTime: at(16, 0)}))
+	assert.NoError(t, split.StartBreak(at(18, 0)))
+	assert.NoError(t, split.EndBreak(at(18, 30)))
+	assert.NoError(t, split.EndSession(at(21, 0), ""))
+
+	// Attendance is only mocked where the review is allowed, so listing it for
+	// anyone else fails the mock.
+	tests := []struct {
+		name       string
+		actor      usecase.Actor
+		employeeID string
+		from, to   string
+		setup      func()
+		wantErr    error
+		check      func(t *testing.T, review *usecase.AttendanceReview)
+	}{
+		{
+			name:       "Success - Presigns Attendance And Profile Photos",
+			actor:      supervisor,
+			employeeID: "emp-1",
+			setup: func() {
+				found(withPhoto)
+				mockAttendanceRepo.On("FindByEmployeeIDBetween", mock.Anything, "emp-1", from, time.Date(2026, 10, 11, 0, 0, 0, 0, loc)).Return([]*domain.Attendance{photographed}, nil).Once()
+				mockStorageRepo.On("PresignGetURL", mock.Anything, "photos/profile.jpg", 15*time.Minute).Return("https://minio/profile", nil).Once()
+				mockStorageRepo.On("PresignGetURL", mock.Anything, "attendance/upload-1.jpg", 15*time.Minute).Return("https://minio/check-in", nil).Once()
+			},
+			check: func(t *testing.T, review *usecase.AttendanceReview) {
+				assert.Equal(t, "https://minio/profile", review.PhotoURL)
+				if assert.Len(t, review.Records, 1) {
+					assert.Equal(t, "https://minio/check-in", review.Records[0].PhotoURLs[0].CheckIn)
+					assert.Empty(t, review.Records[0].PhotoURLs[0].CheckOut)
+				}
+			},
+		},
+		{
+			name:       "Success - Worked Time Net Of Breaks",
+			actor:      supervisor,
+			employeeID: "emp-1",
+			setup: func() {
+				found(employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive))
+				mockAttendanceRepo.On("FindByEmployeeIDBetween", mock.Anything, "emp-1", mock.Anything, mock.Anything).Return([]*domain.Attendance{split}, nil).Once()
+			},
+			check: func(t *testing.T, review *usecase.AttendanceReview) {
+				if assert.Len(t, review.Records, 1) {
+					assert.Equal(t, 8*time.Hour+30*time.Minute, review.Records[0].Worked)
+					assert.Equal(t, 30*time.Minute, review.Records[0].BreakTime)
+					assert.Len(t, review.Records[0].PhotoURLs, 2)
+				}
+			},
+		},
+		{
+			name:       "Fail - Employee Of Another Store",
+			actor:      supervisor,
+			employeeID: "emp-2",
+			setup: func() {
+				found(storeEmployee("emp-2", domain.RoleStaff, "store-2"))
+			},
+			wantErr: usecase.ForbiddenError,
+		},
+		{
+			name:       "Fail - Range Too Long",
+			actor:      supervisor,
+			employeeID: "emp-1",
+			from:       "2026-08-01",
+			to:         "2026-10-01",
+			setup:      func() {},
+			wantErr:    usecase.InvalidDateRangeError,
+		},
+		{
+			name:       "Fail - Staff Cannot Review",
+			actor:      usecase.Actor{ID: "emp-1", Role: domain.RoleStaff},
+			employeeID: "emp-1",
+			setup:      func() {},
+			wantErr:    usecase.ForbiddenError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			review, err := uc.Review(context.Background(), tt.actor, tt.employeeID, tt.from, tt.to)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				tt.check(t, review)
+			}
+			mockRepo.AssertExpectations(t)
+			mockAttendanceRepo.AssertExpectations(t)
+			mockStorageRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAttendanceUsecase_Sessions(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	today := time.Date(2026, 10, 10, 0, 0, 0, 0, loc)
+	at := func(hour, min int) time.Time { return time.Date(2026, 10, 10, hour, min, 0, 0, loc) }
+	req := attendance.CheckInRequest{Location: "Store 1"}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, MockClock{currentTime: today}, time.Minute)
+
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	cfg := &config.Config{AppTimezone: loc, OfficeStartHour: 9, MinBreakMinutes: 15}
+
+	morning := func() *domain.Attendance {
+		a := domain.NewAttendance(domain.CheckInParams{ID: "att-1", EmployeeID: "emp-1", Location: "Store 1", CheckInTime: at(8, 0), OfficeStartHour: 9})
+		_ = a.EndSession(at(12, 0), "")
+		return a
+	}
+
+	clk := &MockClock{}
+	mockAttendanceRepo := new(MockAttendanceRepo)
+	mockRepo := new(MockEmployeeRepo)
+	uc := usecase.NewAttendanceUsecase(mockAttendanceRepo, mockRepo, new(MockStorageRepo), new(MockKioskRepo), new(MockIDGenerator), new(MockNotifier), publisher, authorizer, testPhotoConfig, testKioskConfig, cfg, clk, time.Second)
+
+	working := func() *domain.Attendance {
+		return domain.NewAttendance(domain.CheckInParams{ID: "att-1", EmployeeID: "emp-1", CheckInTime: at(8, 0)})
+	}
+	found := func(record *domain.Attendance) {
+		mockAttendanceRepo.On("FindByEmployeeIDAndDate", mock.Anything, "emp-1", today).Return(record, nil).Once()
+	}
+	activeEmployee := func() {
+		mockRepo.On("FindByID", mock.Anything, "emp-1").Return(employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive), nil).Once()
+	}
+	var id string
+	checkIn := func() (err error) {
+		id, err = uc.CheckIn(context.Background(), "emp-1", req, nil)
+		return err
+	}
+	checkOut := func() error { return uc.CheckOut(context.Background(), "emp-1", nil) }
+	startBreak := func() error { return uc.StartBreak(context.Background(), "emp-1") }
+	endBreak := func() error { return uc.EndBreak(context.Background(), "emp-1") }
+
+	afternoon := morning()
+	onBreak := working()
+	_ = onBreak.StartBreak(at(11, 30))
+	breakless := working()
+	backFromBreak := working()
+	_ = backFromBreak.StartBreak(at(10, 0))
+	stale := working()
+	fresh := working()
+	_ = fresh.StartBreak(at(11, 30))
+	fresh.Version = 1
+
+	// Save and Update are only mocked where a punch is stored, so storing a
+	// refused punch fails the mock.
+	tests := []struct {
+		name    string
+		now     time.Time
+		setup   func()
+		punch   func() error
+		wantErr error
+		check   func(t *testing.T)
+	}{
+		{
+			name: "Success - Check-In After Check-Out Starts New Session",
+			now:  at(13, 0),
+			setup: func() {
+				activeEmployee()
+				found(afternoon)
+				mockAttendanceRepo.On("Update", mock.Anything, afternoon).Return(nil).Once()
+			},
+			punch: checkIn,
+			check: func(t *testing.T) {
+				assert.Equal(t, "att-1", id)
+				assert.Len(t, afternoon.Sessions, 2)
+				assert.Nil(t, afternoon.CheckOut)
+				assert.Equal(t, "2026-10-10 08:00:00", afternoon.CheckIn)
+				assert.False(t, afternoon.IsLate)
+			},
+		},
+		{
+			name: "Success - Check-Out Ends Break In Progress",
+			now:  at(12, 0),
+			setup: func() {
+				found(onBreak)
+				mockAttendanceRepo.On("Update", mock.Anything, onBreak).Return(nil).Once()
+			},
+			punch: checkOut,
+			check: func(t *testing.T) {
+				assert.Equal(t, at(12, 0), *onBreak.Sessions[0].Breaks[0].End)
+				assert.Equal(t, "2026-10-10 12:00:00", *onBreak.CheckOut)
+			},
+		},
+		{
+			name: "Success - Starts Break",
+			now:  at(10, 0),
+			setup: func() {
+				found(breakless)
+				mockAttendanceRepo.On("Update", mock.Anything, breakless).Return(nil).Once()
+			},
+			punch: startBreak,
+			check: func(t *testing.T) {
+				assert.ErrorIs(t, breakless.StartBreak(at(10, 5)), domain.ErrBreakOpen)
+			},
+		},
+		{
+			name: "Success - Ends Break",
+			now:  at(10, 30),
+			setup: func() {
+				found(backFromBreak)
+				mockAttendanceRepo.On("Update", mock.Anything, backFromBreak).Return(nil).Once()
+			},
+			punch: endBreak,
+			check: func(t *testing.T) {
+				assert.Equal(t, at(10, 30), *backFromBreak.Sessions[0].Breaks[0].End)
+			},
+		},
+		{
+			name:    "Fail - Break Outside Session",
+			now:     at(13, 0),
+			setup:   func() { found(morning()) },
+			punch:   startBreak,
+			wantErr: usecase.NotCheckedInError,
+		},
+		{
+			name:    "Fail - End Break Without Break",
+			now:     at(10, 0),
+			setup:   func() { found(working()) },
+			punch:   endBreak,
+			wantErr: usecase.NotOnBreakError,
+		},
+		{
+			name:    "Fail - Check-Out Without Open Session",
+			now:     at(13, 0),
+			setup:   func() { found(morning()) },
+			punch:   checkOut,
+			wantErr: usecase.NotCheckedInError,
+		},
+		{
+			name: "Success - Check-Out Keeps Break Stored Concurrently",
+			now:  at(12, 0),
+			setup: func() {
+				found(stale)
+				mockAttendanceRepo.On("Update", mock.Anything, mock.Anything).Return(domain.ErrAttendanceConflict).Once()
+				found(fresh)
+				mockAttendanceRepo.On("Update", mock.Anything, mock.MatchedBy(func(a *domain.Attendance) bool {
+					return a.Version == 1 && len(a.Sessions[0].Breaks) == 1
+				})).Return(nil).Once()
+			},
+			punch: checkOut,
+			check: func(t *testing.T) {
+				assert.Equal(t, at(12, 0), *stale.Sessions[0].Breaks[0].End)
+				assert.Equal(t, "2026-10-10 12:00:00", *stale.CheckOut)
+			},
+		},
+		{
+			name: "Fail - Punch Keeps Conflicting",
+			now:  at(10, 0),
+			setup: func() {
+				for range 3 {
+					found(working())
+				}
+				mockAttendanceRepo.On("Update", mock.Anything, mock.Anything).Return(domain.ErrAttendanceConflict).Times(3)
+			},
+			punch:   startBreak,
+			wantErr: usecase.AttendanceConflictError,
+		},
+		{
+			name: "Fail - Session Before Previous Check-Out",
+			now:  at(11, 0),
+			setup: func() {
+				activeEmployee()
+				found(morning())
+			},
+			punch:   checkIn,
+			wantErr: usecase.PunchOutOfOrderError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk.currentTime = tt.now
+			tt.setup()
+
+			err := tt.punch()
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				tt.check(t)
+			}
+			mockRepo.AssertExpectations(t)
+			mockAttendanceRepo.AssertExpectations(t)
+		})
+	}
+}
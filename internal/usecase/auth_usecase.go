@@ -2,48 +2,188 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
 	"github.com/zuyatna/shop-retail-employee-service/internal/util/jwtutil"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/totp"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	twoFactorChallengeTTL  = 5 * time.Minute
+	twoFactorEnrollmentTTL = 15 * time.Minute
+)
+
+// dummyPasswordHash is compared against when the account does not exist,
+// so unknown emails take as long to reject as wrong passwords.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+type LoginThrottleConfig struct {
+	Account domain.LoginThrottlePolicy
+	IP      domain.LoginThrottlePolicy
+}
+
+// LoginResult is the outcome of the password step. When TwoFactorRequired or
+// EnrollmentRequired is set, Token is a restricted token for the 2FA endpoints only.
+type LoginResult struct {
+	Token              string
+	TwoFactorRequired  bool
+	EnrollmentRequired bool
+}
+
 type AuthUsecase struct {
-	repo       EmployeeRepository
-	jwtSigner  *jwtutil.Signer
-	ctxTimeout time.Duration
+	repo           EmployeeRepository
+	throttleRepo   LoginThrottleRepository
+	twoFactorRepo  TwoFactorRepository
+	jwtSigner      *jwtutil.Signer
+	throttle       LoginThrottleConfig
+	twoFactorRoles []domain.Role
+	clock          clock.Clock
+	ctxTimeout     time.Duration
 }
 
-func NewAuthUsecase(repo EmployeeRepository, jwtSigner *jwtutil.Signer, timeout time.Duration) *AuthUsecase {
+func NewAuthUsecase(repo EmployeeRepository, throttleRepo LoginThrottleRepository, twoFactorRepo TwoFactorRepository, jwtSigner *jwtutil.Signer, throttle LoginThrottleConfig, twoFactorRoles []domain.Role, clk clock.Clock, timeout time.Duration) *AuthUsecase {
 	return &AuthUsecase{
-		repo:       repo,
-		jwtSigner:  jwtSigner,
-		ctxTimeout: timeout,
+		repo:           repo,
+		throttleRepo:   throttleRepo,
+		twoFactorRepo:  twoFactorRepo,
+		jwtSigner:      jwtSigner,
+		throttle:       throttle,
+		twoFactorRoles: twoFactorRoles,
+		clock:          clk,
+		ctxTimeout:     timeout,
 	}
 }
 
-func (uc *AuthUsecase) Login(ctx context.Context, email, password string) (string, error) {
+func (uc *AuthUsecase) Login(ctx context.Context, email, password, clientIP string) (*LoginResult, error) {
 	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
 	defer cancel()
 
-	user, err := uc.repo.FindByEmail(ctx, email)
+	now := uc.clock.Now()
+
+	accountThrottle, ipThrottle, err := uc.checkThrottles(ctx, now, email, clientIP)
 	if err != nil {
-		return "", fmt.Errorf("login failed: %w", err)
+		return nil, err
+	}
+
+	user, err := uc.repo.FindByEmail(ctx, email)
+	if err != nil && !errors.Is(err, domain.ErrEmployeeNotFound) {
+		// Not a wrong password, so nothing is counted against the account or IP
+		return nil, fmt.Errorf("failed to find employee by email: %w", err)
 	}
 	if user == nil {
-		return "", fmt.Errorf("login failed: user not found")
+		// Unknown emails cost as much and count as much as wrong passwords
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return nil, uc.registerFailure(ctx, now, accountThrottle, ipThrottle, InvalidCredentialsError)
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash()), []byte(password))
 	if err != nil {
-		log.Printf("password mismatch for email=%s: %v", email, err)
-		return "", fmt.Errorf("invalid email or password")
+		slog.Log(ctx, slog.LevelWarn, "Password mismatch", "employeeID", user.ID(), "ip", clientIP)
+		return nil, uc.registerFailure(ctx, now, accountThrottle, ipThrottle, InvalidCredentialsError)
+	}
+
+	if !user.Status().CanSignIn() {
+		return nil, fmt.Errorf("user account is not active")
+	}
+
+	if err := uc.resetThrottle(ctx, accountThrottle); err != nil {
+		return nil, err
+	}
+
+	twoFactor, err := uc.twoFactorRepo.FindByEmployeeID(ctx, string(user.ID()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load two factor: %w", err)
+	}
+
+	switch {
+	case twoFactor != nil && twoFactor.IsEnabled():
+		token, err := uc.jwtSigner.GenerateWithPurpose(string(user.ID()), string(user.Email()), string(user.Role()), jwtutil.PurposeTwoFactorChallenge, twoFactorChallengeTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate challenge token: %w", err)
+		}
+		return &LoginResult{Token: token, TwoFactorRequired: true}, nil
+
+	case requiresTwoFactor(uc.twoFactorRoles, user.Role()):
+		token, err := uc.jwtSigner.GenerateWithPurpose(string(user.ID()), string(user.Email()), string(user.Role()), jwtutil.PurposeTwoFactorEnrollment, twoFactorEnrollmentTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate enrollment token: %w", err)
+		}
+		slog.Log(ctx, slog.LevelInfo, "Two factor enrollment required before login", "employeeID", user.ID())
+		return &LoginResult{Token: token, EnrollmentRequired: true}, nil
+	}
+
+	token, err := uc.jwtSigner.Generate(string(user.ID()), string(user.Email()), string(user.Role()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
+	slog.Log(ctx, slog.LevelInfo, "Employee logged in", "employeeID", user.ID())
+
+	return &LoginResult{Token: token}, nil
+}
 
-	if user.Status() != domain.StatusActive {
+// VerifyTwoFactor completes a login started with Login by checking a TOTP code
+// or a one-time recovery code against the challenge token.
+func (uc *AuthUsecase) VerifyTwoFactor(ctx context.Context, challengeToken, code, recoveryCode, clientIP string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	claims, err := uc.jwtSigner.Parse(challengeToken)
+	if err != nil || claims.Purpose != jwtutil.PurposeTwoFactorChallenge {
+		return "", InvalidChallengeTokenError
+	}
+
+	now := uc.clock.Now()
+
+	accountThrottle, ipThrottle, err := uc.checkThrottles(ctx, now, claims.Email, clientIP)
+	if err != nil {
+		return "", err
+	}
+
+	twoFactor, err := uc.twoFactorRepo.FindByEmployeeID(ctx, claims.UserID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load two factor: %w", err)
+	}
+	if twoFactor == nil || !twoFactor.IsEnabled() {
+		return "", TwoFactorNotEnrolledError
+	}
+
+	verified := false
+	switch {
+	case code != "":
+		if step, ok := totp.Validate(twoFactor.Secret, code, now, totpSkew); ok {
+			verified = twoFactor.UseStep(step) == nil
+		}
+	case recoveryCode != "":
+		verified = twoFactor.UseRecoveryCode(hashRecoveryCode(recoveryCode))
+		if verified {
+			slog.Log(ctx, slog.LevelWarn, "Recovery code used", "employeeID", claims.UserID, "remaining", len(twoFactor.RecoveryCodeHashes))
+		}
+	}
+
+	if !verified {
+		slog.Log(ctx, slog.LevelWarn, "Two factor verification failed", "employeeID", claims.UserID, "ip", clientIP)
+		return "", uc.registerFailure(ctx, now, accountThrottle, ipThrottle, InvalidTwoFactorCodeError)
+	}
+
+	if err := uc.twoFactorRepo.Save(ctx, twoFactor); err != nil {
+		return "", fmt.Errorf("failed to save two factor: %w", err)
+	}
+	if err := uc.resetThrottle(ctx, accountThrottle); err != nil {
+		return "", err
+	}
+
+	// Reload the employee so a status or role change since the password step is respected
+	user, err := uc.repo.FindByID(ctx, claims.UserID)
+	if err != nil || user == nil {
+		return "", InvalidChallengeTokenError
+	}
+	if !user.Status().CanSignIn() {
 		return "", fmt.Errorf("user account is not active")
 	}
 
@@ -51,7 +191,112 @@ func (uc *AuthUsecase) Login(ctx context.Context, email, password string) (strin
 	if err != nil {
 		return "", fmt.Errorf("failed to generate token: %w", err)
 	}
-	log.Printf("Employee %s logged in successfully", email)
+	slog.Log(ctx, slog.LevelInfo, "Employee logged in with two factor", "employeeID", user.ID())
 
 	return token, nil
 }
+
+// Unlock clears the failed attempts of an employee account so they can log in again immediately.
+func (uc *AuthUsecase) Unlock(ctx context.Context, employeeID string) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	user, err := uc.repo.FindByID(ctx, employeeID)
+	if err != nil {
+		return fmt.Errorf("failed to find employee: %w", err)
+	}
+	if user == nil {
+		return EmployeeNotFoundError
+	}
+
+	key := domain.LoginThrottleKey(domain.ThrottleScopeAccount, string(user.Email()))
+	if err := uc.throttleRepo.DeleteByKey(ctx, key); err != nil {
+		return fmt.Errorf("failed to unlock account: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Unlocked employee account", "employeeID", employeeID)
+
+	return nil
+}
+
+// ValidateSession checks that a token issued at issuedAt still belongs to a valid session:
+// the employee must exist, be allowed to sign in and not have had their sessions revoked
+// since. Lookup failures are returned as they are so they are not mistaken for a
+// revoked session.
+func (uc *AuthUsecase) ValidateSession(ctx context.Context, employeeID string, issuedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	state, err := uc.repo.FindSessionState(ctx, employeeID)
+	if err != nil {
+		return fmt.Errorf("failed to load session state: %w", err)
+	}
+
+	if state == nil || !state.IsValid(issuedAt) {
+		return SessionRevokedError
+	}
+
+	return nil
+}
+
+// checkThrottles loads the account and IP throttles and rejects the attempt while either
+// is locked, before any password or code is checked.
+func (uc *AuthUsecase) checkThrottles(ctx context.Context, now time.Time, email, clientIP string) (*domain.LoginThrottle, *domain.LoginThrottle, error) {
+	accountThrottle, err := uc.loadThrottle(ctx, domain.ThrottleScopeAccount, email)
+	if err != nil {
+		return nil, nil, err
+	}
+	ipThrottle, err := uc.loadThrottle(ctx, domain.ThrottleScopeIP, clientIP)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if wait := max(accountThrottle.RetryAfter(now), ipThrottle.RetryAfter(now)); wait > 0 {
+		return nil, nil, &LoginLockedError{RetryAfter: wait}
+	}
+
+	return accountThrottle, ipThrottle, nil
+}
+
+func (uc *AuthUsecase) loadThrottle(ctx context.Context, scope domain.ThrottleScope, value string) (*domain.LoginThrottle, error) {
+	key := domain.LoginThrottleKey(scope, value)
+
+	throttle, err := uc.throttleRepo.FindByKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load login throttle: %w", err)
+	}
+	if throttle == nil {
+		throttle = domain.NewLoginThrottle(scope, value)
+	}
+
+	return throttle, nil
+}
+
+func (uc *AuthUsecase) resetThrottle(ctx context.Context, throttle *domain.LoginThrottle) error {
+	if throttle.FailedCount == 0 {
+		return nil
+	}
+	if err := uc.throttleRepo.DeleteByKey(ctx, throttle.Key); err != nil {
+		return fmt.Errorf("failed to reset login throttle: %w", err)
+	}
+	return nil
+}
+
+// registerFailure records a failed attempt on both throttles and returns cause,
+// unless the failure itself could not be stored.
+func (uc *AuthUsecase) registerFailure(ctx context.Context, now time.Time, accountThrottle, ipThrottle *domain.LoginThrottle, cause error) error {
+	if err := uc.throttleRepo.RegisterFailure(ctx, accountThrottle, now, uc.throttle.Account); err != nil {
+		return fmt.Errorf("failed to record login failure: %w", err)
+	}
+	if err := uc.throttleRepo.RegisterFailure(ctx, ipThrottle, now, uc.throttle.IP); err != nil {
+		return fmt.Errorf("failed to record login failure: %w", err)
+	}
+
+	if accountThrottle.IsLockedOut(now, uc.throttle.Account) {
+		slog.Log(ctx, slog.LevelWarn, "Account locked after repeated login failures", "attempts", accountThrottle.FailedCount)
+	}
+	if ipThrottle.IsLockedOut(now, uc.throttle.IP) {
+		slog.Log(ctx, slog.LevelWarn, "Client IP locked after repeated login failures", "key", ipThrottle.Key, "attempts", ipThrottle.FailedCount)
+	}
+
+	return cause
+}
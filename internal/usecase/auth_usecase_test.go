@@ -0,0 +1,307 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/jwtutil"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/totp"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuthUsecase_Login(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	now := time.Date(2026, 10, 10, 8, 0, 0, 0, loc)
+	mockClock := MockClock{currentTime: now}
+
+	signer := &jwtutil.Signer{Secret: []byte("secret"), Issuer: "test", TTL: time.Hour}
+	policy := domain.LoginThrottlePolicy{
+		MaxAttempts:     3,
+		BaseDelay:       time.Second,
+		MaxDelay:        time.Minute,
+		LockoutDuration: 15 * time.Minute,
+	}
+	throttleCfg := usecase.LoginThrottleConfig{Account: policy, IP: policy}
+	twoFactorRoles := []domain.Role{domain.RoleAdmin, domain.RoleSupervisor}
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	emp, _ := domain.ReconstituteEmployee(domain.ReconstituteEmployeeParams{
+		ID:           "emp-123",
+		Name:         "Test User",
+		Email:        "test@example.com",
+		PasswordHash: string(hash),
+		Role:         string(domain.RoleStaff),
+		Status:       string(domain.StatusActive),
+	})
+
+	accountKey := domain.LoginThrottleKey(domain.ThrottleScopeAccount, "test@example.com")
+	ipKey := domain.LoginThrottleKey(domain.ThrottleScopeIP, "10.0.0.1")
+
+	t.Run("Success - Resets Account Throttle", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		mockThrottleRepo := new(MockLoginThrottleRepo)
+		mockTwoFactorRepo := new(MockTwoFactorRepo)
+		uc := usecase.NewAuthUsecase(mockRepo, mockThrottleRepo, mockTwoFactorRepo, signer, throttleCfg, twoFactorRoles, mockClock, time.Second)
+
+		previous := &domain.LoginThrottle{Key: accountKey, Scope: domain.ThrottleScopeAccount, FailedCount: 1, LastFailedAt: now.Add(-time.Minute)}
+		mockThrottleRepo.On("FindByKey", mock.Anything, accountKey).Return(previous, nil).Once()
+		mockThrottleRepo.On("FindByKey", mock.Anything, ipKey).Return(nil, nil).Once()
+		mockRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(emp, nil).Once()
+		mockThrottleRepo.On("DeleteByKey", mock.Anything, accountKey).Return(nil).Once()
+		mockTwoFactorRepo.On("FindByEmployeeID", mock.Anything, "emp-123").Return(nil, nil).Once()
+
+		result, err := uc.Login(context.Background(), "test@example.com", "password123", "10.0.0.1")
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result.Token)
+		assert.False(t, result.TwoFactorRequired)
+		mockRepo.AssertExpectations(t)
+		mockThrottleRepo.AssertExpectations(t)
+	})
+
+	t.Run("Fail - Wrong Password Records Failure", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		mockThrottleRepo := new(MockLoginThrottleRepo)
+		mockTwoFactorRepo := new(MockTwoFactorRepo)
+		uc := usecase.NewAuthUsecase(mockRepo, mockThrottleRepo, mockTwoFactorRepo, signer, throttleCfg, twoFactorRoles, mockClock, time.Second)
+
+		mockThrottleRepo.On("FindByKey", mock.Anything, accountKey).Return(nil, nil).Once()
+		mockThrottleRepo.On("FindByKey", mock.Anything, ipKey).Return(nil, nil).Once()
+		mockRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(emp, nil).Once()
+		mockThrottleRepo.On("RegisterFailure", mock.Anything, mock.MatchedBy(func(th *domain.LoginThrottle) bool {
+			return th.FailedCount == 1 && th.LockedUntil == nil
+		}), mock.Anything, mock.Anything).Return(nil).Twice()
+
+		result, err := uc.Login(context.Background(), "test@example.com", "wrong-password", "10.0.0.1")
+
+		assert.ErrorIs(t, err, usecase.InvalidCredentialsError)
+		assert.Nil(t, result)
+		mockThrottleRepo.AssertExpectations(t)
+	})
+
+	t.Run("Fail - Unknown Email Records Failure", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		mockThrottleRepo := new(MockLoginThrottleRepo)
+		uc := usecase.NewAuthUsecase(mockRepo, mockThrottleRepo, new(MockTwoFactorRepo), signer, throttleCfg, twoFactorRoles, mockClock, time.Second)
+
+		unknownKey := domain.LoginThrottleKey(domain.ThrottleScopeAccount, "nobody@example.com")
+		mockThrottleRepo.On("FindByKey", mock.Anything, unknownKey).Return(nil, nil).Once()
+		mockThrottleRepo.On("FindByKey", mock.Anything, ipKey).Return(nil, nil).Once()
+		mockRepo.On("FindByEmail", mock.Anything, " Nobody@Example.com").Return(nil, domain.ErrEmployeeNotFound).Once()
+		mockThrottleRepo.On("RegisterFailure", mock.Anything, mock.MatchedBy(func(th *domain.LoginThrottle) bool {
+			return th.Key == unknownKey || th.Key == ipKey
+		}), mock.Anything, mock.Anything).Return(nil).Twice()
+
+		result, err := uc.Login(context.Background(), " Nobody@Example.com", "password123", "10.0.0.1")
+
+		assert.ErrorIs(t, err, usecase.InvalidCredentialsError)
+		assert.Nil(t, result)
+		assert.Equal(t, "account:nobody@example.com", unknownKey)
+		mockThrottleRepo.AssertExpectations(t)
+	})
+
+	t.Run("Fail - Lookup Error Is Not Counted As Failure", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		mockThrottleRepo := new(MockLoginThrottleRepo)
+		uc := usecase.NewAuthUsecase(mockRepo, mockThrottleRepo, new(MockTwoFactorRepo), signer, throttleCfg, twoFactorRoles, mockClock, time.Second)
+
+		mockThrottleRepo.On("FindByKey", mock.Anything, accountKey).Return(nil, nil).Once()
+		mockThrottleRepo.On("FindByKey", mock.Anything, ipKey).Return(nil, nil).Once()
+		mockRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(nil, errors.New("db down")).Once()
+
+		_, err := uc.Login(context.Background(), "test@example.com", "password123", "10.0.0.1")
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, usecase.InvalidCredentialsError)
+		mockThrottleRepo.AssertNotCalled(t, "RegisterFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Fail - Max Attempts Locks Account", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		mockThrottleRepo := new(MockLoginThrottleRepo)
+		mockTwoFactorRepo := new(MockTwoFactorRepo)
+		uc := usecase.NewAuthUsecase(mockRepo, mockThrottleRepo, mockTwoFactorRepo, signer, throttleCfg, twoFactorRoles, mockClock, time.Second)
+
+		previous := &domain.LoginThrottle{Key: accountKey, Scope: domain.ThrottleScopeAccount, FailedCount: 2, LastFailedAt: now.Add(-time.Minute)}
+		mockThrottleRepo.On("FindByKey", mock.Anything, accountKey).Return(previous, nil).Once()
+		mockThrottleRepo.On("FindByKey", mock.Anything, ipKey).Return(nil, nil).Once()
+		mockRepo.On("FindByEmail", mock.Anything, "test@example.com").Return(emp, nil).Once()
+		mockThrottleRepo.On("RegisterFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+
+		_, err := uc.Login(context.Background(), "test@example.com", "wrong-password", "10.0.0.1")
+
+		assert.ErrorIs(t, err, usecase.InvalidCredentialsError)
+		assert.Equal(t, 3, previous.FailedCount)
+		assert.True(t, previous.IsLockedOut(now, policy))
+		assert.Equal(t, 15*time.Minute, previous.RetryAfter(now))
+	})
+
+	t.Run("Fail - Locked Account Skips Password Check", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		mockThrottleRepo := new(MockLoginThrottleRepo)
+		mockTwoFactorRepo := new(MockTwoFactorRepo)
+		uc := usecase.NewAuthUsecase(mockRepo, mockThrottleRepo, mockTwoFactorRepo, signer, throttleCfg, twoFactorRoles, mockClock, time.Second)
+
+		lockedUntil := now.Add(10 * time.Minute)
+		locked := &domain.LoginThrottle{Key: accountKey, Scope: domain.ThrottleScopeAccount, FailedCount: 3, LastFailedAt: now.Add(-5 * time.Minute), LockedUntil: &lockedUntil}
+		mockThrottleRepo.On("FindByKey", mock.Anything, accountKey).Return(locked, nil).Once()
+		mockThrottleRepo.On("FindByKey", mock.Anything, ipKey).Return(nil, nil).Once()
+
+		_, err := uc.Login(context.Background(), "test@example.com", "password123", "10.0.0.1")
+
+		var lockedErr *usecase.LoginLockedError
+		assert.True(t, errors.As(err, &lockedErr))
+		assert.Equal(t, 10*time.Minute, lockedErr.RetryAfter)
+		mockRepo.AssertNotCalled(t, "FindByEmail", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthUsecase_TwoFactor(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	now := time.Date(2026, 10, 10, 8, 0, 0, 0, loc)
+	mockClock := MockClock{currentTime: now}
+
+	signer := &jwtutil.Signer{Secret: []byte("secret"), Issuer: "test", TTL: time.Hour}
+	policy := domain.LoginThrottlePolicy{MaxAttempts: 5, BaseDelay: time.Second, LockoutDuration: 15 * time.Minute}
+	throttleCfg := usecase.LoginThrottleConfig{Account: policy, IP: policy}
+	twoFactorRoles := []domain.Role{domain.RoleAdmin, domain.RoleSupervisor}
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	supervisor, _ := domain.ReconstituteEmployee(domain.ReconstituteEmployeeParams{
+		ID:           "emp-456",
+		Name:         "Supervisor",
+		Email:        "spv@example.com",
+		PasswordHash: string(hash),
+		Role:         string(domain.RoleSupervisor),
+		Status:       string(domain.StatusActive),
+	})
+
+	secret, _ := totp.GenerateSecret()
+	confirmedAt := now.Add(-24 * time.Hour)
+
+	t.Run("Not Enrolled Supervisor Must Enroll", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		mockThrottleRepo := new(MockLoginThrottleRepo)
+		mockTwoFactorRepo := new(MockTwoFactorRepo)
+		uc := usecase.NewAuthUsecase(mockRepo, mockThrottleRepo, mockTwoFactorRepo, signer, throttleCfg, twoFactorRoles, mockClock, time.Second)
+
+		mockThrottleRepo.On("FindByKey", mock.Anything, mock.Anything).Return(nil, nil).Twice()
+		mockRepo.On("FindByEmail", mock.Anything, "spv@example.com").Return(supervisor, nil).Once()
+		mockTwoFactorRepo.On("FindByEmployeeID", mock.Anything, "emp-456").Return(nil, nil).Once()
+
+		result, err := uc.Login(context.Background(), "spv@example.com", "password123", "10.0.0.1")
+
+		assert.NoError(t, err)
+		assert.True(t, result.EnrollmentRequired)
+		claims, err := signer.Parse(result.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, jwtutil.PurposeTwoFactorEnrollment, claims.Purpose)
+	})
+
+	t.Run("Enrolled Login Returns Challenge Then Verifies Code", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		mockThrottleRepo := new(MockLoginThrottleRepo)
+		mockTwoFactorRepo := new(MockTwoFactorRepo)
+		uc := usecase.NewAuthUsecase(mockRepo, mockThrottleRepo, mockTwoFactorRepo, signer, throttleCfg, twoFactorRoles, mockClock, time.Second)
+
+		twoFactor := &domain.TwoFactor{EmployeeID: "emp-456", Secret: secret, ConfirmedAt: &confirmedAt}
+
+		mockThrottleRepo.On("FindByKey", mock.Anything, mock.Anything).Return(nil, nil).Times(4)
+		mockRepo.On("FindByEmail", mock.Anything, "spv@example.com").Return(supervisor, nil).Once()
+		mockTwoFactorRepo.On("FindByEmployeeID", mock.Anything, "emp-456").Return(twoFactor, nil).Twice()
+
+		result, err := uc.Login(context.Background(), "spv@example.com", "password123", "10.0.0.1")
+		assert.NoError(t, err)
+		assert.True(t, result.TwoFactorRequired)
+
+		code, _ := totp.CodeAt(secret, totp.Step(now))
+		mockTwoFactorRepo.On("Save", mock.Anything, twoFactor).Return(nil).Once()
+		mockRepo.On("FindByID", mock.Anything, "emp-456").Return(supervisor, nil).Once()
+
+		token, err := uc.VerifyTwoFactor(context.Background(), result.Token, code, "", "10.0.0.1")
+
+		assert.NoError(t, err)
+		claims, err := signer.Parse(token)
+		assert.NoError(t, err)
+		assert.Equal(t, "", claims.Purpose)
+		assert.Equal(t, totp.Step(now), twoFactor.LastUsedStep)
+		mockTwoFactorRepo.AssertExpectations(t)
+	})
+
+	t.Run("Fail - Replayed Code", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		mockThrottleRepo := new(MockLoginThrottleRepo)
+		mockTwoFactorRepo := new(MockTwoFactorRepo)
+		uc := usecase.NewAuthUsecase(mockRepo, mockThrottleRepo, mockTwoFactorRepo, signer, throttleCfg, twoFactorRoles, mockClock, time.Second)
+
+		twoFactor := &domain.TwoFactor{EmployeeID: "emp-456", Secret: secret, ConfirmedAt: &confirmedAt, LastUsedStep: totp.Step(now)}
+		challenge, _ := signer.GenerateWithPurpose("emp-456", "spv@example.com", "supervisor", jwtutil.PurposeTwoFactorChallenge, time.Minute)
+
+		mockThrottleRepo.On("FindByKey", mock.Anything, mock.Anything).Return(nil, nil).Twice()
+		mockTwoFactorRepo.On("FindByEmployeeID", mock.Anything, "emp-456").Return(twoFactor, nil).Once()
+		mockThrottleRepo.On("RegisterFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+
+		code, _ := totp.CodeAt(secret, totp.Step(now))
+		_, err := uc.VerifyTwoFactor(context.Background(), challenge, code, "", "10.0.0.1")
+
+		assert.ErrorIs(t, err, usecase.InvalidTwoFactorCodeError)
+		mockTwoFactorRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Fail - Access Token Is Not A Challenge", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(new(MockEmployeeRepo), new(MockLoginThrottleRepo), new(MockTwoFactorRepo), signer, throttleCfg, twoFactorRoles, mockClock, time.Second)
+
+		accessToken, _ := signer.Generate("emp-456", "spv@example.com", "supervisor")
+		_, err := uc.VerifyTwoFactor(context.Background(), accessToken, "123456", "", "10.0.0.1")
+
+		assert.ErrorIs(t, err, usecase.InvalidChallengeTokenError)
+	})
+}
+
+func TestAuthUsecase_ValidateSession(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	signer := &jwtutil.Signer{Secret: []byte("secret"), Issuer: "test", TTL: time.Hour}
+	policy := domain.LoginThrottlePolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute, LockoutDuration: time.Minute}
+	throttleCfg := usecase.LoginThrottleConfig{Account: policy, IP: policy}
+
+	revokedAt := now.Add(-time.Hour)
+	state := &domain.SessionState{Status: domain.StatusActive, SessionsRevokedAt: &revokedAt}
+
+	mockRepo := new(MockEmployeeRepo)
+	mockRepo.On("FindSessionState", mock.Anything, "emp-1").Return(state, nil)
+	mockRepo.On("FindSessionState", mock.Anything, "missing").Return(nil, nil)
+	mockRepo.On("FindSessionState", mock.Anything, "emp-db-down").Return(nil, errors.New("connection refused"))
+	uc := usecase.NewAuthUsecase(mockRepo, new(MockLoginThrottleRepo), new(MockTwoFactorRepo), signer, throttleCfg, nil, MockClock{currentTime: now}, time.Second)
+
+	t.Run("Success - Token Issued After Revocation", func(t *testing.T) {
+		err := uc.ValidateSession(context.Background(), "emp-1", now.Add(-time.Minute))
+
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Fail - Token Issued Before Revocation", func(t *testing.T) {
+		err := uc.ValidateSession(context.Background(), "emp-1", revokedAt.Add(-time.Minute))
+
+		assert.ErrorIs(t, err, usecase.SessionRevokedError)
+	})
+
+	t.Run("Fail - Unknown Employee", func(t *testing.T) {
+		err := uc.ValidateSession(context.Background(), "missing", now)
+
+		assert.ErrorIs(t, err, usecase.SessionRevokedError)
+	})
+
+	t.Run("Fail - Lookup Error Is Not A Revoked Session", func(t *testing.T) {
+		err := uc.ValidateSession(context.Background(), "emp-db-down", now)
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, usecase.SessionRevokedError)
+	})
+}
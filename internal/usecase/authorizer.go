@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+)
+
+// Actor is the authenticated employee performing an action.
+type Actor struct {
+	ID   string
+	Role domain.Role
+}
+
+func (a Actor) IsSelf(employeeID string) bool {
+	return a.ID != "" && a.ID == employeeID
+}
+
+// Authorizer answers permission checks from the role policy stored in the database.
+// The policy is cached for cacheTTL so changes made on another instance show up shortly after.
+type Authorizer struct {
+	repo     RoleRepository
+	clock    clock.Clock
+	cacheTTL time.Duration
+
+	mu       sync.RWMutex
+	policy   domain.Policy
+	loadedAt time.Time
+}
+
+func NewAuthorizer(repo RoleRepository, clk clock.Clock, cacheTTL time.Duration) *Authorizer {
+	return &Authorizer{
+		repo:     repo,
+		clock:    clk,
+		cacheTTL: cacheTTL,
+	}
+}
+
+func (a *Authorizer) Policy(ctx context.Context) (domain.Policy, error) {
+	now := a.clock.Now()
+
+	a.mu.RLock()
+	policy, loadedAt := a.policy, a.loadedAt
+	a.mu.RUnlock()
+
+	if policy != nil && now.Sub(loadedAt) < a.cacheTTL {
+		return policy, nil
+	}
+
+	roles, err := a.repo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load role policy: %w", err)
+	}
+	policy = domain.NewPolicy(roles)
+
+	a.mu.Lock()
+	a.policy, a.loadedAt = policy, now
+	a.mu.Unlock()
+
+	return policy, nil
+}
+
+// Invalidate drops the cached policy, e.g. after a role was changed.
+func (a *Authorizer) Invalidate() {
+	a.mu.Lock()
+	a.policy = nil
+	a.mu.Unlock()
+}
+
+// HasAny reports whether role holds at least one of perms.
+func (a *Authorizer) HasAny(ctx context.Context, role domain.Role, perms ...domain.Permission) (bool, error) {
+	policy, err := a.Policy(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, perm := range perms {
+		if policy.Allows(role, perm) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Authorize returns ForbiddenError unless the actor holds perm.
+func (a *Authorizer) Authorize(ctx context.Context, actor Actor, perm domain.Permission) error {
+	ok, err := a.HasAny(ctx, actor.Role, perm)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: missing permission %s", ForbiddenError, perm)
+	}
+	return nil
+}
+
+// AuthorizeOnEmployee checks perm against a target employee. When the actor is the
+// target, selfPerm is sufficient as well.
+func (a *Authorizer) AuthorizeOnEmployee(ctx context.Context, actor Actor, targetID string, perm, selfPerm domain.Permission) error {
+	if actor.IsSelf(targetID) {
+		ok, err := a.HasAny(ctx, actor.Role, perm, selfPerm)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		return fmt.Errorf("%w: missing permission %s", ForbiddenError, selfPerm)
+	}
+
+	return a.Authorize(ctx, actor, perm)
+}
+
+// RoleExists reports whether role is defined in the policy.
+func (a *Authorizer) RoleExists(ctx context.Context, role domain.Role) (bool, error) {
+	policy, err := a.Policy(ctx)
+	if err != nil {
+		return false, err
+	}
+	return policy.HasRole(role), nil
+}
+
+// AuthorizeHierarchy checks that the actor outranks the target employee. Acting on
+// yourself is always within the hierarchy; permissions are checked separately.
+func (a *Authorizer) AuthorizeHierarchy(ctx context.Context, actor Actor, target *domain.Employee) error {
+	if actor.IsSelf(string(target.ID())) {
+		return nil
+	}
+
+	policy, err := a.Policy(ctx)
+	if err != nil {
+		return err
+	}
+	if !policy.Outranks(actor.Role, target.Role()) {
+		return fmt.Errorf("%w: target employee is not below your role", ForbiddenError)
+	}
+	return nil
+}
+
+// AuthorizeRoleAssignment checks that the actor may give role to an employee.
+func (a *Authorizer) AuthorizeRoleAssignment(ctx context.Context, actor Actor, role domain.Role) error {
+	policy, err := a.Policy(ctx)
+	if err != nil {
+		return err
+	}
+	return roleAssignmentError(policy.CanAssign(actor.Role, role))
+}
+
+// roleAssignmentError maps hierarchy violations from the domain to ForbiddenError.
+func roleAssignmentError(err error) error {
+	if errors.Is(err, domain.ErrRoleNotAssignable) {
+		return fmt.Errorf("%w: %v", ForbiddenError, err)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", InvalidRoleError, err)
+	}
+	return nil
+}
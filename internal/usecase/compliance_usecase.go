@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+)
+
+// ExpiringDocument is a current document version that expires soon or has expired.
+type ExpiringDocument struct {
+	Document *domain.Document
+	Employee *domain.Employee
+	DaysLeft int // zero or negative once expired
+}
+
+// StoreExpirations groups the expiring documents of one store.
+type StoreExpirations struct {
+	StoreID   string
+	Documents []ExpiringDocument
+}
+
+// ComplianceUsecase keeps track of expiring employee documents, such as health
+// certificates and employment contracts, whose expiry date is the end of the contract.
+// Only the latest version of a document type counts; uploading a renewed document
+// replaces an expiring one.
+type ComplianceUsecase struct {
+	documentRepo DocumentRepository
+	employeeRepo EmployeeRepository
+	notifier     Notifier
+	authorizer   *Authorizer
+	windows      []int // expiry reminder windows, in days before the expiry date
+	clock        clock.Clock
+	location     *time.Location
+	ctxTimeout   time.Duration
+}
+
+func NewComplianceUsecase(documentRepo DocumentRepository, employeeRepo EmployeeRepository, notifier Notifier, authorizer *Authorizer, windows []int, clk clock.Clock, location *time.Location, timeout time.Duration) *ComplianceUsecase {
+	return &ComplianceUsecase{
+		documentRepo: documentRepo,
+		employeeRepo: employeeRepo,
+		notifier:     notifier,
+		authorizer:   authorizer,
+		windows:      windows,
+		clock:        clk,
+		location:     location,
+		ctxTimeout:   timeout,
+	}
+}
+
+// MaxWindow returns the widest expiry reminder window, the default horizon of the report.
+func (uc *ComplianceUsecase) MaxWindow() int {
+	return slices.Max(uc.windows)
+}
+
+// SendExpiryReminders notifies employees and the supervisors of their store about
+// documents entering an expiry reminder window and returns how many reminders were sent.
+// Every window is reminded once per document version.
+func (uc *ComplianceUsecase) SendExpiryReminders(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	today := uc.today()
+	expiring, err := uc.expiring(ctx, today, uc.MaxWindow())
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, item := range expiring {
+		window := item.Document.DueReminderWindow(today, uc.windows)
+		if window == 0 {
+			continue
+		}
+
+		data := map[string]string{
+			"EmployeeName": item.Employee.Name(),
+			"DocumentType": item.Document.Type.Label(),
+			"ExpiresAt":    item.Document.ExpiresAt.Format(time.DateOnly),
+			"DaysLeft":     strconv.Itoa(item.DaysLeft),
+		}
+		if err := uc.notifier.NotifyAboutEmployee(ctx, domain.EventDocumentExpiring, item.Employee, data); err != nil {
+			// Left unmarked, so the next run tries again
+			slog.Log(ctx, slog.LevelWarn, "Failed to notify about expiring document", "ID", item.Document.ID, "error", err)
+			continue
+		}
+
+		if err := uc.documentRepo.MarkReminded(ctx, item.Document.ID, window); err != nil {
+			return sent, fmt.Errorf("failed to mark document reminded: %w", err)
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// ExpiringReport lists, per store, the documents expiring within the given number of
+// days and those already expired. Actors without access to all stores only see their
+// own store; storeID narrows the report down to one store.
+func (uc *ComplianceUsecase) ExpiringReport(ctx context.Context, actor Actor, days int, storeID string) ([]StoreExpirations, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermComplianceRead); err != nil {
+		return nil, err
+	}
+
+	allStores, err := uc.authorizer.HasAny(ctx, actor.Role, domain.PermEmployeeAllStores)
+	if err != nil {
+		return nil, err
+	}
+	if !allStores {
+		viewer, err := uc.employeeRepo.FindByID(ctx, actor.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find viewer: %w", err)
+		}
+		if viewer == nil || viewer.StoreID() == "" || (storeID != "" && storeID != viewer.StoreID()) {
+			return nil, fmt.Errorf("%w: the report is limited to your own store", ForbiddenError)
+		}
+		storeID = viewer.StoreID()
+	}
+
+	expiring, err := uc.expiring(ctx, uc.today(), days)
+	if err != nil {
+		return nil, err
+	}
+
+	var stores []StoreExpirations
+	index := make(map[string]int)
+	for _, item := range expiring {
+		store := item.Employee.StoreID()
+		if storeID != "" && store != storeID {
+			continue
+		}
+		i, ok := index[store]
+		if !ok {
+			i = len(stores)
+			index[store] = i
+			stores = append(stores, StoreExpirations{StoreID: store})
+		}
+		stores[i].Documents = append(stores[i].Documents, item)
+	}
+
+	slices.SortFunc(stores, func(a, b StoreExpirations) int {
+		return cmp.Compare(a.StoreID, b.StoreID)
+	})
+
+	return stores, nil
+}
+
+// expiring returns the current documents of employees still working here that expire
+// within days after today or have expired, soonest first.
+func (uc *ComplianceUsecase) expiring(ctx context.Context, today time.Time, days int) ([]ExpiringDocument, error) {
+	before := time.Date(today.Year(), today.Month(), today.Day()+days+1, 0, 0, 0, 0, time.UTC)
+
+	documents, err := uc.documentRepo.FindCurrentExpiringBefore(ctx, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expiring documents: %w", err)
+	}
+
+	employees := make(map[string]*domain.Employee)
+	var expiring []ExpiringDocument
+	for _, document := range documents {
+		employee, ok := employees[document.EmployeeID]
+		if !ok {
+			employee, err = uc.employeeRepo.FindByID(ctx, document.EmployeeID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find employee: %w", err)
+			}
+			employees[document.EmployeeID] = employee
+		}
+		if employee == nil || employee.Status() == domain.StatusTerminated {
+			continue
+		}
+
+		expiring = append(expiring, ExpiringDocument{
+			Document: document,
+			Employee: employee,
+			DaysLeft: document.DaysLeft(today),
+		})
+	}
+
+	return expiring, nil
+}
+
+// today returns the current date in the application timezone.
+func (uc *ComplianceUsecase) today() time.Time {
+	now := uc.clock.Now().In(uc.location)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, uc.location)
+}
@@ -0,0 +1,202 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+func expiringDocument(id, employeeID string, docType domain.DocumentType, expiresAt time.Time, reminderWindow int) *domain.Document {
+	return &domain.Document{ID: id, EmployeeID: employeeID, Type: docType, Version: 1, ExpiresAt: &expiresAt, ReminderWindow: reminderWindow}
+}
+
+func TestComplianceUsecase_SendExpiryReminders(t *testing.T) {
+	// 2026-03-10 in the application timezone, still 2026-03-09 in UTC
+	now := time.Date(2026, 3, 9, 20, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	loc := time.FixedZone("WIB", 7*60*60)
+
+	date := func(day int) time.Time { return time.Date(2026, 3, day, 0, 0, 0, 0, time.UTC) }
+	before := date(10).AddDate(0, 0, 31)
+
+	mockDocumentRepo := new(MockDocumentRepo)
+	mockRepo := new(MockEmployeeRepo)
+	mockNotifier := new(MockNotifier)
+	uc := usecase.NewComplianceUsecase(mockDocumentRepo, mockRepo, mockNotifier, authorizer, []int{30, 7, 1}, clk, loc, 2*time.Second)
+
+	jane := storeEmployee("emp-1", domain.RoleStaff, "store-1")
+
+	// Notifications and reminder marks are only mocked where they are expected,
+	// so reminding a terminated employee or marking a failed one fails the mock.
+	tests := []struct {
+		name      string
+		documents []*domain.Document
+		setup     func()
+		wantSent  int
+	}{
+		{
+			name: "Success - Sends The Smallest Due Window Once",
+			documents: []*domain.Document{
+				expiringDocument("expired", "emp-1", domain.DocumentContract, date(10), 0),
+				expiringDocument("tomorrow", "emp-1", domain.DocumentHealthCertificate, date(11), 7),
+				expiringDocument("in-five-days", "emp-1", domain.DocumentIDCard, date(15), 0),
+				expiringDocument("reminded", "emp-1", domain.DocumentTaxCard, date(15), 7),
+				expiringDocument("in-twenty-days", "emp-2", domain.DocumentContract, date(30), 30),
+			},
+			setup: func() {
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(jane, nil).Once()
+				mockRepo.On("FindByID", mock.Anything, "emp-2").Return(storeEmployee("emp-2", domain.RoleStaff, "store-1"), nil).Once()
+				mockNotifier.On("NotifyAboutEmployee", mock.Anything, domain.EventDocumentExpiring, jane, map[string]string{
+					"EmployeeName": "Employee emp-1", "DocumentType": "health certificate", "ExpiresAt": "2026-03-11", "DaysLeft": "1",
+				}).Return(nil).Once()
+				mockNotifier.On("NotifyAboutEmployee", mock.Anything, domain.EventDocumentExpiring, jane, map[string]string{
+					"EmployeeName": "Employee emp-1", "DocumentType": "ID card", "ExpiresAt": "2026-03-15", "DaysLeft": "5",
+				}).Return(nil).Once()
+				mockDocumentRepo.On("MarkReminded", mock.Anything, "tomorrow", 1).Return(nil).Once()
+				mockDocumentRepo.On("MarkReminded", mock.Anything, "in-five-days", 7).Return(nil).Once()
+			},
+			wantSent: 2,
+		},
+		{
+			name:      "Success - Skips Terminated Employees",
+			documents: []*domain.Document{expiringDocument("doc-1", "emp-1", domain.DocumentContract, date(12), 0)},
+			setup: func() {
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusTerminated), nil).Once()
+			},
+		},
+		{
+			name:      "Success - Failed Notification Is Retried Next Run",
+			documents: []*domain.Document{expiringDocument("doc-1", "emp-1", domain.DocumentContract, date(12), 0)},
+			setup: func() {
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(storeEmployee("emp-1", domain.RoleStaff, "store-1"), nil).Once()
+				mockNotifier.On("NotifyAboutEmployee", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("smtp down")).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDocumentRepo.On("FindCurrentExpiringBefore", mock.Anything, before).Return(tt.documents, nil).Once()
+			tt.setup()
+
+			sent, err := uc.SendExpiryReminders(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSent, sent)
+			mockRepo.AssertExpectations(t)
+			mockNotifier.AssertExpectations(t)
+			mockDocumentRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestComplianceUsecase_ExpiringReport(t *testing.T) {
+	now := time.Date(2026, 3, 10, 3, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	loc := time.FixedZone("WIB", 7*60*60)
+
+	date := func(day int) time.Time { return time.Date(2026, 3, day, 0, 0, 0, 0, time.UTC) }
+	admin := usecase.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+	supervisor := usecase.Actor{ID: "spv-1", Role: domain.RoleSupervisor}
+
+	mockDocumentRepo := new(MockDocumentRepo)
+	mockRepo := new(MockEmployeeRepo)
+	uc := usecase.NewComplianceUsecase(mockDocumentRepo, mockRepo, new(MockNotifier), authorizer, []int{30, 7, 1}, clk, loc, 2*time.Second)
+
+	expectDocuments := func() {
+		mockDocumentRepo.On("FindCurrentExpiringBefore", mock.Anything, date(18)).Return([]*domain.Document{
+			expiringDocument("doc-1", "emp-1", domain.DocumentContract, date(9), 0),
+			expiringDocument("doc-2", "emp-2", domain.DocumentHealthCertificate, date(12), 0),
+			expiringDocument("doc-3", "emp-1", domain.DocumentIDCard, date(17), 0),
+		}, nil).Once()
+		mockRepo.On("FindByID", mock.Anything, "emp-1").Return(storeEmployee("emp-1", domain.RoleStaff, "store-2"), nil).Once()
+		mockRepo.On("FindByID", mock.Anything, "emp-2").Return(storeEmployee("emp-2", domain.RoleStaff, "store-1"), nil).Once()
+	}
+	supervisorFound := func() {
+		mockRepo.On("FindByID", mock.Anything, "spv-1").Return(storeEmployee("spv-1", domain.RoleSupervisor, "store-1"), nil).Once()
+	}
+
+	tests := []struct {
+		name    string
+		actor   usecase.Actor
+		storeID string
+		setup   func()
+		wantErr error
+		check   func(t *testing.T, stores []usecase.StoreExpirations)
+	}{
+		{
+			name:  "Success - Admin Sees Every Store",
+			actor: admin,
+			setup: expectDocuments,
+			check: func(t *testing.T, stores []usecase.StoreExpirations) {
+				if assert.Len(t, stores, 2) {
+					assert.Equal(t, "store-1", stores[0].StoreID)
+					assert.Equal(t, "store-2", stores[1].StoreID)
+					assert.Len(t, stores[1].Documents, 2)
+					assert.Equal(t, -1, stores[1].Documents[0].DaysLeft)
+					assert.Equal(t, 7, stores[1].Documents[1].DaysLeft)
+				}
+			},
+		},
+		{
+			name:  "Success - Supervisor Sees Own Store",
+			actor: supervisor,
+			setup: func() {
+				supervisorFound()
+				expectDocuments()
+			},
+			check: func(t *testing.T, stores []usecase.StoreExpirations) {
+				if assert.Len(t, stores, 1) {
+					assert.Equal(t, "store-1", stores[0].StoreID)
+					assert.Equal(t, "doc-2", stores[0].Documents[0].Document.ID)
+				}
+			},
+		},
+		{
+			name:    "Fail - Supervisor Asking For Another Store",
+			actor:   supervisor,
+			storeID: "store-2",
+			setup:   supervisorFound,
+			wantErr: usecase.ForbiddenError,
+		},
+		{
+			name:    "Fail - Staff Without Permission",
+			actor:   usecase.Actor{ID: "emp-1", Role: domain.RoleStaff},
+			setup:   func() {},
+			wantErr: usecase.ForbiddenError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			stores, err := uc.ExpiringReport(context.Background(), tt.actor, 7, tt.storeID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				tt.check(t, stores)
+			}
+			mockRepo.AssertExpectations(t)
+			mockDocumentRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type DataExportRepository interface {
+	Save(ctx context.Context, export *domain.DataExport) error
+	FindByID(ctx context.Context, id string) (*domain.DataExport, error)
+	FindByEmployeeID(ctx context.Context, employeeID string) ([]*domain.DataExport, error)
+	// ClaimNextPending marks the oldest pending export as processing and returns it,
+	// or nil when nothing is pending. Concurrent callers never claim the same export.
+	ClaimNextPending(ctx context.Context) (*domain.DataExport, error)
+	FindExpired(ctx context.Context, now time.Time) ([]*domain.DataExport, error)
+}
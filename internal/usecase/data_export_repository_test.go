@@ -0,0 +1,50 @@
+package usecase_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type MockDataExportRepo struct {
+	mock.Mock
+}
+
+func (m *MockDataExportRepo) Save(ctx context.Context, export *domain.DataExport) error {
+	args := m.Called(ctx, export)
+	return args.Error(0)
+}
+
+func (m *MockDataExportRepo) FindByID(ctx context.Context, id string) (*domain.DataExport, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DataExport), args.Error(1)
+}
+
+func (m *MockDataExportRepo) FindByEmployeeID(ctx context.Context, employeeID string) ([]*domain.DataExport, error) {
+	args := m.Called(ctx, employeeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.DataExport), args.Error(1)
+}
+
+func (m *MockDataExportRepo) ClaimNextPending(ctx context.Context) (*domain.DataExport, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DataExport), args.Error(1)
+}
+
+func (m *MockDataExportRepo) FindExpired(ctx context.Context, now time.Time) ([]*domain.DataExport, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.DataExport), args.Error(1)
+}
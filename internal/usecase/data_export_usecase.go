@@ -0,0 +1,421 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+)
+
+// dataExportGenerateTimeout bounds the generation of a single archive, which downloads
+// photos and reads every attendance record of the employee.
+const dataExportGenerateTimeout = 2 * time.Minute
+
+const dataExportReadme = `Personal data export
+
+This archive contains all personal data the employee service holds about you:
+
+  profile.json       your employee profile
+  compensation.json  your current position and salary (no history is recorded)
+  attendance.json    your attendance records
+  audit.json         data export requests made about you
+  photos/            your stored profile photo
+  attendance/        the photos taken at your check-ins and check-outs, named after
+                     the day and the number of the work session
+  documents/         your stored documents, every version that was not deleted
+
+No leave records are kept by this service.
+`
+
+// DataExportUsecase handles subject access requests. Archives are generated in the
+// background by ProcessPending and can be downloaded until they expire.
+type DataExportUsecase struct {
+	exportRepo     DataExportRepository
+	employeeRepo   EmployeeRepository
+	attendanceRepo AttendanceRepository
+	documentRepo   DocumentRepository
+	storageRepo    StorageRepository
+	authorizer     *Authorizer
+	idGen          IDGenerator
+	clock          clock.Clock
+	ttl            time.Duration
+	ctxTimeout     time.Duration
+}
+
+func NewDataExportUsecase(exportRepo DataExportRepository, employeeRepo EmployeeRepository, attendanceRepo AttendanceRepository, documentRepo DocumentRepository, storageRepo StorageRepository, authorizer *Authorizer, idGen IDGenerator, clk clock.Clock, ttl time.Duration, timeout time.Duration) *DataExportUsecase {
+	return &DataExportUsecase{
+		exportRepo:     exportRepo,
+		employeeRepo:   employeeRepo,
+		attendanceRepo: attendanceRepo,
+		documentRepo:   documentRepo,
+		storageRepo:    storageRepo,
+		authorizer:     authorizer,
+		idGen:          idGen,
+		clock:          clk,
+		ttl:            ttl,
+		ctxTimeout:     timeout,
+	}
+}
+
+// Request returns the current export of the employee. A new export is queued when there
+// is none in progress and no archive that can still be downloaded.
+func (uc *DataExportUsecase) Request(ctx context.Context, actor Actor, employeeID string) (*domain.DataExport, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorize(ctx, actor, employeeID); err != nil {
+		return nil, err
+	}
+
+	exports, err := uc.exportRepo.FindByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find data exports: %w", err)
+	}
+
+	now := uc.clock.Now()
+	if len(exports) > 0 {
+		latest := exports[0]
+		if latest.IsInProgress() || latest.IsDownloadable(now) {
+			return latest, nil
+		}
+	}
+
+	id, err := uc.idGen.NewID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ID: %w", err)
+	}
+
+	export := domain.NewDataExport(id, employeeID, actor.ID, now)
+	if err := uc.exportRepo.Save(ctx, export); err != nil {
+		return nil, fmt.Errorf("failed to save data export: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Requested personal data export", "ID", id, "employeeID", employeeID, "actorID", actor.ID)
+
+	return export, nil
+}
+
+// Download opens the archive of a ready export. The caller must close the returned reader.
+func (uc *DataExportUsecase) Download(ctx context.Context, actor Actor, employeeID string, exportID string) (io.ReadCloser, *domain.DataExport, error) {
+	// The archive is streamed after this method returns, so the timeout only covers the lookup
+	lookupCtx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorize(lookupCtx, actor, employeeID); err != nil {
+		return nil, nil, err
+	}
+
+	export, err := uc.exportRepo.FindByID(lookupCtx, exportID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find data export: %w", err)
+	}
+	if export == nil || export.EmployeeID != employeeID {
+		return nil, nil, DataExportNotFoundError
+	}
+
+	if export.Status == domain.DataExportExpired || (export.Status == domain.DataExportReady && !export.IsDownloadable(uc.clock.Now())) {
+		return nil, nil, DataExportExpiredError
+	}
+	if export.Status != domain.DataExportReady {
+		return nil, nil, DataExportNotReadyError
+	}
+
+	file, err := uc.storageRepo.DownloadFile(ctx, export.FileURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open data export archive: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Downloaded personal data export", "ID", exportID, "employeeID", employeeID, "actorID", actor.ID)
+
+	return file, export, nil
+}
+
+// ProcessPending generates the archives of all pending exports and returns how many were processed.
+func (uc *DataExportUsecase) ProcessPending(ctx context.Context) (int, error) {
+	processed := 0
+	for {
+		claimCtx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+		export, err := uc.exportRepo.ClaimNextPending(claimCtx)
+		cancel()
+		if err != nil {
+			return processed, fmt.Errorf("failed to claim data export: %w", err)
+		}
+		if export == nil {
+			return processed, nil
+		}
+
+		genCtx, cancel := context.WithTimeout(ctx, dataExportGenerateTimeout)
+		fileURL, size, err := uc.generate(genCtx, export)
+		if err != nil {
+			slog.Log(ctx, slog.LevelError, "Failed to generate data export", "ID", export.ID, "error", err)
+			export.MarkFailed("failed to generate the archive", uc.clock.Now())
+		} else {
+			export.MarkReady(fileURL, size, uc.clock.Now(), uc.ttl)
+		}
+
+		if err := uc.exportRepo.Save(genCtx, export); err != nil {
+			cancel()
+			return processed, fmt.Errorf("failed to save data export: %w", err)
+		}
+		cancel()
+		processed++
+	}
+}
+
+// PurgeExpired removes the archives whose download window has closed.
+func (uc *DataExportUsecase) PurgeExpired(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	expired, err := uc.exportRepo.FindExpired(ctx, uc.clock.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to find expired data exports: %w", err)
+	}
+
+	purged := 0
+	for _, export := range expired {
+		if err := uc.storageRepo.DeleteFile(ctx, export.FileURL); err != nil {
+			slog.Log(ctx, slog.LevelError, "Failed to delete expired data export", "ID", export.ID, "error", err)
+			continue
+		}
+
+		export.Expire()
+		if err := uc.exportRepo.Save(ctx, export); err != nil {
+			return purged, fmt.Errorf("failed to save data export: %w", err)
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// authorize allows employees to export their own data, and actors holding the export
+// permission to export the data of employees below them.
+func (uc *DataExportUsecase) authorize(ctx context.Context, actor Actor, employeeID string) error {
+	if err := uc.authorizer.AuthorizeOnEmployee(ctx, actor, employeeID, domain.PermEmployeeExport, domain.PermEmployeeExportSelf); err != nil {
+		return err
+	}
+
+	employee, err := uc.employeeRepo.FindByIDIncludingDeleted(ctx, employeeID)
+	if err != nil {
+		return fmt.Errorf("failed to find employee: %w", err)
+	}
+	if employee == nil {
+		return EmployeeNotFoundError
+	}
+	if employee.IsAnonymized() {
+		return EmployeeAlreadyErasedError
+	}
+
+	if actor.IsSelf(employeeID) {
+		return nil
+	}
+	return uc.authorizer.AuthorizeHierarchy(ctx, actor, employee)
+}
+
+// generate builds the archive of an export and stores it, returning its location and size.
+func (uc *DataExportUsecase) generate(ctx context.Context, export *domain.DataExport) (string, int64, error) {
+	employee, err := uc.employeeRepo.FindByIDIncludingDeleted(ctx, export.EmployeeID)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to find employee: %w", err)
+	}
+	if employee == nil {
+		return "", 0, EmployeeNotFoundError
+	}
+
+	attendances, err := uc.attendanceRepo.FindByEmployeeID(ctx, export.EmployeeID)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to find attendance records: %w", err)
+	}
+
+	exports, err := uc.exportRepo.FindByEmployeeID(ctx, export.EmployeeID)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to find data exports: %w", err)
+	}
+
+	documents, err := uc.documentRepo.FindByEmployeeID(ctx, export.EmployeeID)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to find documents: %w", err)
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	files := []struct {
+		name    string
+		content any
+	}{
+		{"profile.json", FromDomain(employee)},
+		{"compensation.json", compensationExport{Position: employee.Position(), Salary: employee.Salary()}},
+		{"attendance.json", attendanceExports(attendances)},
+		{"audit.json", auditExport{DataExports: dataExportEntries(exports)}},
+	}
+
+	if err := writeZipEntry(archive, "README.txt", []byte(dataExportReadme)); err != nil {
+		return "", 0, err
+	}
+	for _, f := range files {
+		content, err := json.MarshalIndent(f.content, "", "  ")
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to encode %s: %w", f.name, err)
+		}
+		if err := writeZipEntry(archive, f.name, content); err != nil {
+			return "", 0, err
+		}
+	}
+
+	if employee.Photo() != "" {
+		if err := uc.addStoredFile(ctx, archive, "photos/"+path.Base(employee.Photo()), employee.Photo()); err != nil {
+			return "", 0, err
+		}
+	}
+
+	for _, a := range attendances {
+		for i, session := range a.Sessions {
+			prefix := fmt.Sprintf("attendance/%s-%d", a.Date.Format(time.DateOnly), i+1)
+			if session.CheckInPhoto != "" {
+				if err := uc.addStoredFile(ctx, archive, prefix+"-check-in.jpg", session.CheckInPhoto); err != nil {
+					return "", 0, err
+				}
+			}
+			if session.CheckOutPhoto != "" {
+				if err := uc.addStoredFile(ctx, archive, prefix+"-check-out.jpg", session.CheckOutPhoto); err != nil {
+					return "", 0, err
+				}
+			}
+		}
+	}
+
+	for _, document := range documents {
+		name := fmt.Sprintf("documents/%s-v%d%s", document.Type, document.Version, path.Ext(document.StorageKey))
+		if err := uc.addStoredFile(ctx, archive, name, document.StorageKey); err != nil {
+			return "", 0, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return "", 0, fmt.Errorf("failed to finish archive: %w", err)
+	}
+
+	size := int64(buf.Len())
+	fileURL, err := uc.storageRepo.UploadFile(ctx, "exports/"+export.ID+".zip", "application/zip", &buf, size)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to store archive: %w", err)
+	}
+
+	return fileURL, size, nil
+}
+
+func (uc *DataExportUsecase) addStoredFile(ctx context.Context, archive *zip.Writer, name string, fileURL string) error {
+	file, err := uc.storageRepo.DownloadFile(ctx, fileURL)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	defer file.Close()
+
+	w, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+
+	if _, err := io.Copy(w, file); err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+
+	return nil
+}
+
+func writeZipEntry(archive *zip.Writer, name string, content []byte) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	if _, err := w.Write(content); err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	return nil
+}
+
+type compensationExport struct {
+	Position string `json:"position"`
+	Salary   int64  `json:"salary"`
+}
+
+type attendanceExport struct {
+	ID       string    `json:"id"`
+	Date     time.Time `json:"date"`
+	Location string    `json:"location"`
+	CheckIn  string    `json:"check_in"`
+	CheckOut *string   `json:"check_out,omitempty"`
+	IsLate   bool      `json:"is_late"`
+
+	Sessions []attendanceSessionExport `json:"sessions"`
+}
+
+type attendanceSessionExport struct {
+	CheckIn  time.Time     `json:"check_in"`
+	CheckOut *time.Time    `json:"check_out,omitempty"`
+	Location string        `json:"location"`
+	Breaks   []breakExport `json:"breaks,omitempty"`
+}
+
+type breakExport struct {
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end,omitempty"`
+}
+
+type dataExportEntry struct {
+	ID          string     `json:"id"`
+	RequestedBy string     `json:"requested_by"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+type auditExport struct {
+	DataExports []dataExportEntry `json:"data_exports"`
+}
+
+func attendanceExports(attendances []*domain.Attendance) []attendanceExport {
+	entries := make([]attendanceExport, 0, len(attendances))
+	for _, a := range attendances {
+		entry := attendanceExport{
+			ID:       a.ID,
+			Date:     a.Date,
+			Location: a.Location,
+			CheckIn:  a.CheckIn,
+			CheckOut: a.CheckOut,
+			IsLate:   a.IsLate,
+			Sessions: make([]attendanceSessionExport, 0, len(a.Sessions)),
+		}
+		for _, s := range a.Sessions {
+			session := attendanceSessionExport{CheckIn: s.CheckIn, CheckOut: s.CheckOut, Location: s.Location}
+			for _, b := range s.Breaks {
+				session.Breaks = append(session.Breaks, breakExport{Start: b.Start, End: b.End})
+			}
+			entry.Sessions = append(entry.Sessions, session)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func dataExportEntries(exports []*domain.DataExport) []dataExportEntry {
+	entries := make([]dataExportEntry, 0, len(exports))
+	for _, e := range exports {
+		entries = append(entries, dataExportEntry{
+			ID:          e.ID,
+			RequestedBy: e.RequestedBy,
+			Status:      string(e.Status),
+			CreatedAt:   e.CreatedAt,
+			CompletedAt: e.CompletedAt,
+		})
+	}
+	return entries
+}
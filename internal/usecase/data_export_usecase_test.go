@@ -0,0 +1,157 @@
+package usecase_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+func TestDataExportUsecase_Request(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	exportRepo, employeeRepo, idGen := new(MockDataExportRepo), new(MockEmployeeRepo), new(MockIDGenerator)
+	uc := usecase.NewDataExportUsecase(exportRepo, employeeRepo, new(MockAttendanceRepo), new(MockDocumentRepo), new(MockStorageRepo), authorizer, idGen, clk, 24*time.Hour, 2*time.Second)
+
+	staff := usecase.Actor{ID: "emp-1", Role: domain.RoleStaff}
+
+	ready := domain.NewDataExport("export-1", "emp-1", "emp-1", now.Add(-time.Hour))
+	ready.MarkReady("exports/export-1.zip", 100, now.Add(-time.Hour), 24*time.Hour)
+
+	tests := []struct {
+		name       string
+		employeeID string
+		setup      func()
+		wantStatus domain.DataExportStatus
+		wantErr    error
+	}{
+		{
+			name:       "Success - Queues Export For Self",
+			employeeID: "emp-1",
+			setup: func() {
+				employeeRepo.On("FindByIDIncludingDeleted", mock.Anything, "emp-1").Return(erasableEmployee("emp-1", domain.RoleStaff, nil), nil).Once()
+				exportRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return([]*domain.DataExport{}, nil).Once()
+				idGen.On("NewID").Return("export-1", nil).Once()
+				exportRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.DataExport")).Return(nil).Once()
+			},
+			wantStatus: domain.DataExportPending,
+		},
+		{
+			// No Save expectation: queuing a second export would fail the mock.
+			name:       "Success - Reuses Downloadable Export",
+			employeeID: "emp-1",
+			setup: func() {
+				employeeRepo.On("FindByIDIncludingDeleted", mock.Anything, "emp-1").Return(erasableEmployee("emp-1", domain.RoleStaff, nil), nil).Once()
+				exportRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return([]*domain.DataExport{ready}, nil).Once()
+			},
+			wantStatus: domain.DataExportReady,
+		},
+		{
+			name:       "Fail - Staff Exporting Someone Else",
+			employeeID: "emp-2",
+			setup:      func() {},
+			wantErr:    usecase.ForbiddenError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			export, err := uc.Request(context.Background(), staff, tt.employeeID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "export-1", export.ID)
+			assert.Equal(t, tt.wantStatus, export.Status)
+			exportRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDataExportUsecase_ProcessPending(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	exportRepo, employeeRepo, attendanceRepo, documentRepo, storageRepo := new(MockDataExportRepo), new(MockEmployeeRepo), new(MockAttendanceRepo), new(MockDocumentRepo), new(MockStorageRepo)
+	uc := usecase.NewDataExportUsecase(exportRepo, employeeRepo, attendanceRepo, documentRepo, storageRepo, authorizer, new(MockIDGenerator), clk, 24*time.Hour, 2*time.Second)
+
+	pending := domain.NewDataExport("export-1", "emp-1", "emp-1", now)
+	emp := erasableEmployee("emp-1", domain.RoleStaff, nil)
+
+	exportRepo.On("ClaimNextPending", mock.Anything).Return(pending, nil).Once()
+	exportRepo.On("ClaimNextPending", mock.Anything).Return(nil, nil).Once()
+	employeeRepo.On("FindByIDIncludingDeleted", mock.Anything, "emp-1").Return(emp, nil).Once()
+	attendanceRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return([]*domain.Attendance{{ID: "att-1", EmployeeID: "emp-1", CheckIn: "2026-02-27 08:55:00"}}, nil).Once()
+	exportRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return([]*domain.DataExport{pending}, nil).Once()
+	documentRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return([]*domain.Document{
+		{ID: "doc-1", EmployeeID: "emp-1", Type: domain.DocumentContract, Version: 2, StorageKey: "documents/emp-1/doc-1.pdf"},
+	}, nil).Once()
+	storageRepo.On("DownloadFile", mock.Anything, emp.Photo()).Return(io.NopCloser(bytes.NewReader([]byte("jpeg"))), nil).Once()
+	storageRepo.On("DownloadFile", mock.Anything, "documents/emp-1/doc-1.pdf").Return(io.NopCloser(bytes.NewReader([]byte("%PDF"))), nil).Once()
+
+	var archive []byte
+	storageRepo.On("UploadFile", mock.Anything, "exports/export-1.zip", "application/zip", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			archive, _ = io.ReadAll(args.Get(3).(io.Reader))
+		}).
+		Return("http://minio/employees/exports/export-1.zip", nil).Once()
+	exportRepo.On("Save", mock.Anything, pending).Return(nil).Once()
+
+	processed, err := uc.ProcessPending(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.Equal(t, domain.DataExportReady, pending.Status)
+	assert.True(t, pending.IsDownloadable(now))
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	assert.NoError(t, err)
+	var names []string
+	for _, f := range reader.File {
+		names = append(names, f.Name)
+	}
+	assert.ElementsMatch(t, []string{"README.txt", "profile.json", "compensation.json", "attendance.json", "audit.json", "photos/photo.jpg", "documents/contract-v2.pdf"}, names)
+}
+
+func TestDataExportUsecase_Download(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+	staff := usecase.Actor{ID: "emp-1", Role: domain.RoleStaff}
+
+	exportRepo, employeeRepo := new(MockDataExportRepo), new(MockEmployeeRepo)
+	uc := usecase.NewDataExportUsecase(exportRepo, employeeRepo, new(MockAttendanceRepo), new(MockDocumentRepo), new(MockStorageRepo), authorizer, new(MockIDGenerator), clk, 24*time.Hour, 2*time.Second)
+
+	stale := domain.NewDataExport("export-1", "emp-1", "emp-1", now.AddDate(0, 0, -3))
+	stale.MarkReady("exports/export-1.zip", 100, now.AddDate(0, 0, -3), 24*time.Hour)
+
+	employeeRepo.On("FindByIDIncludingDeleted", mock.Anything, "emp-1").Return(erasableEmployee("emp-1", domain.RoleStaff, nil), nil)
+	exportRepo.On("FindByID", mock.Anything, "export-1").Return(stale, nil).Once()
+
+	_, _, err := uc.Download(context.Background(), staff, "emp-1", "export-1")
+
+	assert.ErrorIs(t, err, usecase.DataExportExpiredError)
+}
@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+)
+
+// PurgePlan describes everything a hard delete removes for an employee.
+type PurgePlan struct {
+	EmployeeID        string
+	AttendanceRecords int64
+	Files             []string // photos, documents and data export archives in object storage
+	Cascade           []string // tables whose rows are removed together with the employee
+}
+
+// purgeCascade lists the tables that reference employees with ON DELETE CASCADE.
+var purgeCascade = []string{"employee_two_factors", "data_exports", "employee_status_changes", "email_changes", "notifications", "notification_preferences", "employee_documents", "employee_kiosk_pins"}
+
+// DeletedEmployeeUsecase manages soft deleted employees: listing them, restoring an
+// accidental delete and purging an employee permanently.
+type DeletedEmployeeUsecase struct {
+	employeeRepo   EmployeeRepository
+	attendanceRepo AttendanceRepository
+	storageRepo    StorageRepository
+	throttleRepo   LoginThrottleRepository
+	exportRepo     DataExportRepository
+	documentRepo   DocumentRepository
+	authorizer     *Authorizer
+	clock          clock.Clock
+	ctxTimeout     time.Duration
+}
+
+func NewDeletedEmployeeUsecase(employeeRepo EmployeeRepository, attendanceRepo AttendanceRepository, storageRepo StorageRepository, throttleRepo LoginThrottleRepository, exportRepo DataExportRepository, documentRepo DocumentRepository, authorizer *Authorizer, clk clock.Clock, timeout time.Duration) *DeletedEmployeeUsecase {
+	return &DeletedEmployeeUsecase{
+		employeeRepo:   employeeRepo,
+		attendanceRepo: attendanceRepo,
+		storageRepo:    storageRepo,
+		throttleRepo:   throttleRepo,
+		exportRepo:     exportRepo,
+		documentRepo:   documentRepo,
+		authorizer:     authorizer,
+		clock:          clk,
+		ctxTimeout:     timeout,
+	}
+}
+
+// List returns the soft deleted employees, most recently deleted first.
+func (uc *DeletedEmployeeUsecase) List(ctx context.Context, actor Actor) ([]*domain.Employee, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermEmployeeRestore); err != nil {
+		return nil, err
+	}
+
+	employees, err := uc.employeeRepo.FindDeleted(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find deleted employees: %w", err)
+	}
+
+	return employees, nil
+}
+
+// Restore undoes the soft delete of an employee. It fails when the email or phone number
+// has been taken by another employee in the meantime.
+func (uc *DeletedEmployeeUsecase) Restore(ctx context.Context, actor Actor, employeeID string) (*domain.Employee, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermEmployeeRestore); err != nil {
+		return nil, err
+	}
+
+	employee, err := uc.findDeleted(ctx, actor, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := employee.Restore(); err != nil {
+		return nil, deletedEmployeeError(err)
+	}
+
+	conflicts, err := uc.employeeRepo.FindContactConflicts(ctx, string(employee.Email()), employee.PhoneNumber(), employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check contact conflicts: %w", err)
+	}
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("%w: %s", EmployeeContactConflictError, strings.Join(conflicts, ", "))
+	}
+
+	if err := uc.employeeRepo.Restore(ctx, employee); err != nil {
+		return nil, fmt.Errorf("failed to restore employee: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Restored employee", "ID", employeeID, "actorID", actor.ID)
+
+	return employee, nil
+}
+
+// PurgePlan returns what Purge would remove for a soft deleted employee, without removing it.
+func (uc *DeletedEmployeeUsecase) PurgePlan(ctx context.Context, actor Actor, employeeID string) (*PurgePlan, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermEmployeePurge); err != nil {
+		return nil, err
+	}
+
+	employee, err := uc.findDeleted(ctx, actor, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.plan(ctx, employee)
+}
+
+// Purge permanently removes a soft deleted employee: attendance records and files first,
+// then the employee row with its cascade. A failed purge can be retried, every step
+// tolerates data that is already gone.
+func (uc *DeletedEmployeeUsecase) Purge(ctx context.Context, actor Actor, employeeID string) (*PurgePlan, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermEmployeePurge); err != nil {
+		return nil, err
+	}
+
+	employee, err := uc.findDeleted(ctx, actor, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := uc.plan(ctx, employee)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := uc.attendanceRepo.DeleteByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete attendance records: %w", err)
+	}
+	plan.AttendanceRecords = deleted
+
+	for _, file := range plan.Files {
+		if err := uc.storageRepo.DeleteFile(ctx, file); err != nil {
+			return nil, fmt.Errorf("failed to delete file: %w", err)
+		}
+	}
+
+	if err := uc.throttleRepo.DeleteByKey(ctx, domain.LoginThrottleKey(domain.ThrottleScopeAccount, string(employee.Email()))); err != nil {
+		return nil, fmt.Errorf("failed to delete login throttle: %w", err)
+	}
+
+	if err := uc.employeeRepo.HardDelete(ctx, employeeID); err != nil {
+		return nil, fmt.Errorf("failed to hard delete employee: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Purged employee", "ID", employeeID, "actorID", actor.ID, "attendanceRecords", deleted, "files", len(plan.Files))
+
+	return plan, nil
+}
+
+func (uc *DeletedEmployeeUsecase) findDeleted(ctx context.Context, actor Actor, employeeID string) (*domain.Employee, error) {
+	employee, err := uc.employeeRepo.FindByIDIncludingDeleted(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find employee: %w", err)
+	}
+	if employee == nil {
+		return nil, EmployeeNotFoundError
+	}
+
+	if !employee.IsDeleted() {
+		return nil, EmployeeNotDeletedError
+	}
+
+	if err := uc.authorizer.AuthorizeHierarchy(ctx, actor, employee); err != nil {
+		return nil, err
+	}
+
+	return employee, nil
+}
+
+func (uc *DeletedEmployeeUsecase) plan(ctx context.Context, employee *domain.Employee) (*PurgePlan, error) {
+	employeeID := string(employee.ID())
+
+	attendanceCount, err := uc.attendanceRepo.CountByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count attendance records: %w", err)
+	}
+
+	files := employee.PhotoFiles()
+
+	attendances, err := uc.attendanceRepo.FindByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find attendance records: %w", err)
+	}
+	for _, attendance := range attendances {
+		files = append(files, attendance.PhotoFiles()...)
+	}
+
+	exports, err := uc.exportRepo.FindByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find data exports: %w", err)
+	}
+	for _, export := range exports {
+		if export.FileURL != "" {
+			files = append(files, export.FileURL)
+		}
+	}
+
+	documents, err := uc.documentRepo.FindByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find documents: %w", err)
+	}
+	for _, document := range documents {
+		files = append(files, document.StorageKey)
+	}
+
+	return &PurgePlan{
+		EmployeeID:        employeeID,
+		AttendanceRecords: attendanceCount,
+		Files:             files,
+		Cascade:           purgeCascade,
+	}, nil
+}
+
+func deletedEmployeeError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrEmployeeNotDeleted):
+		return EmployeeNotDeletedError
+	case errors.Is(err, domain.ErrEmployeeErased):
+		return EmployeeAlreadyErasedError
+	}
+	return err
+}
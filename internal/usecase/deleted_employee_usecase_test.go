@@ -0,0 +1,208 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+func deletedEmployee(id string, deletedAt time.Time) *domain.Employee {
+	emp, _ := domain.ReconstituteEmployee(domain.ReconstituteEmployeeParams{
+		ID:          id,
+		Name:        "Jane Doe",
+		Email:       "jane@shop.local",
+		Role:        string(domain.RoleStaff),
+		Status:      string(domain.StatusActive),
+		PhoneNumber: "08123456789",
+		Photo:       "http://minio/employees/photo.jpg",
+		StoreID:     "store-1",
+		DeletedAt:   &deletedAt,
+	})
+	return emp
+}
+
+func TestDeletedEmployeeUsecase_Restore(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	mockRepo := new(MockEmployeeRepo)
+	uc := usecase.NewDeletedEmployeeUsecase(mockRepo, new(MockAttendanceRepo), new(MockStorageRepo), new(MockLoginThrottleRepo), new(MockDataExportRepo), new(MockDocumentRepo), authorizer, clk, 2*time.Second)
+
+	admin := usecase.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+	supervisor := usecase.Actor{ID: "spv-1", Role: domain.RoleSupervisor}
+
+	anonymized := deletedEmployee("emp-1", now.Add(-time.Hour))
+	anonymized.Anonymize(now)
+
+	// Restore is only mocked for the success case, so restoring after a refusal
+	// fails the mock.
+	tests := []struct {
+		name      string
+		actor     usecase.Actor
+		emp       *domain.Employee
+		conflicts []string
+		wantErr   error
+		wantInErr string
+	}{
+		{
+			name:  "Success - Restores With Previous Status",
+			actor: admin,
+			emp:   deletedEmployee("emp-1", now.Add(-time.Hour)),
+		},
+		{
+			name:      "Fail - Email Taken Since Delete",
+			actor:     admin,
+			emp:       deletedEmployee("emp-1", now.Add(-time.Hour)),
+			conflicts: []string{"email"},
+			wantErr:   usecase.EmployeeContactConflictError,
+			wantInErr: "email",
+		},
+		{
+			name:    "Fail - Not Deleted",
+			actor:   admin,
+			emp:     employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive),
+			wantErr: usecase.EmployeeNotDeletedError,
+		},
+		{
+			name:    "Fail - Anonymized",
+			actor:   admin,
+			emp:     anonymized,
+			wantErr: usecase.EmployeeAlreadyErasedError,
+		},
+		{
+			name:    "Fail - Supervisor Not Allowed",
+			actor:   supervisor,
+			wantErr: usecase.ForbiddenError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.emp != nil {
+				mockRepo.On("FindByIDIncludingDeleted", mock.Anything, "emp-1").Return(tt.emp, nil).Once()
+				if tt.emp.IsDeleted() && !tt.emp.IsAnonymized() {
+					mockRepo.On("FindContactConflicts", mock.Anything, "jane@shop.local", "08123456789", "emp-1").Return(append([]string{}, tt.conflicts...), nil).Once()
+				}
+				if tt.wantErr == nil {
+					mockRepo.On("Restore", mock.Anything, tt.emp).Return(nil).Once()
+				}
+			}
+
+			restored, err := uc.Restore(context.Background(), tt.actor, "emp-1")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Contains(t, err.Error(), tt.wantInErr)
+			} else {
+				assert.NoError(t, err)
+				assert.False(t, restored.IsDeleted())
+				assert.Equal(t, domain.StatusActive, restored.Status())
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeletedEmployeeUsecase_Purge(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	mockRepo := new(MockEmployeeRepo)
+	mockAttendanceRepo := new(MockAttendanceRepo)
+	mockStorageRepo := new(MockStorageRepo)
+	mockThrottleRepo := new(MockLoginThrottleRepo)
+	mockExportRepo := new(MockDataExportRepo)
+	mockDocumentRepo := new(MockDocumentRepo)
+	uc := usecase.NewDeletedEmployeeUsecase(mockRepo, mockAttendanceRepo, mockStorageRepo, mockThrottleRepo, mockExportRepo, mockDocumentRepo, authorizer, clk, 2*time.Second)
+
+	admin := usecase.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+	exports := []*domain.DataExport{
+		{ID: "export-1", EmployeeID: "emp-1", FileURL: "http://minio/exports/export-1.zip"},
+		{ID: "export-2", EmployeeID: "emp-1"},
+	}
+	documents := []*domain.Document{{ID: "doc-1", EmployeeID: "emp-1", StorageKey: "documents/emp-1/doc-1.pdf"}}
+	attendances := []*domain.Attendance{
+		{ID: "att-1", EmployeeID: "emp-1", Sessions: []domain.WorkSession{{CheckInPhoto: "attendance/photo-1.jpg", CheckOutPhoto: "attendance/photo-2.jpg"}}},
+		{ID: "att-2", EmployeeID: "emp-1"},
+	}
+	files := []string{"http://minio/employees/photo.jpg", "attendance/photo-1.jpg", "attendance/photo-2.jpg", "http://minio/exports/export-1.zip", "documents/emp-1/doc-1.pdf"}
+
+	planned := func() {
+		mockRepo.On("FindByIDIncludingDeleted", mock.Anything, "emp-1").Return(deletedEmployee("emp-1", now.Add(-time.Hour)), nil).Once()
+		mockAttendanceRepo.On("CountByEmployeeID", mock.Anything, "emp-1").Return(int64(12), nil).Once()
+		mockAttendanceRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return(attendances, nil).Once()
+		mockExportRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return(exports, nil).Once()
+		mockDocumentRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return(documents, nil).Once()
+	}
+
+	// Deletes are only mocked for the purge that makes them, so deleting while
+	// planning or after a refusal fails the mock.
+	tests := []struct {
+		name    string
+		run     func(ctx context.Context, actor usecase.Actor, employeeID string) (*usecase.PurgePlan, error)
+		setup   func()
+		wantErr error
+	}{
+		{
+			name:  "Success - Plan Lists Everything That Goes",
+			run:   uc.PurgePlan,
+			setup: planned,
+		},
+		{
+			name: "Success - Removes Attendance Files And Row",
+			run:  uc.Purge,
+			setup: func() {
+				planned()
+				mockAttendanceRepo.On("DeleteByEmployeeID", mock.Anything, "emp-1").Return(int64(12), nil).Once()
+				for _, file := range files {
+					mockStorageRepo.On("DeleteFile", mock.Anything, file).Return(nil).Once()
+				}
+				mockThrottleRepo.On("DeleteByKey", mock.Anything, "account:jane@shop.local").Return(nil).Once()
+				mockRepo.On("HardDelete", mock.Anything, "emp-1").Return(nil).Once()
+			},
+		},
+		{
+			name: "Fail - Employee Not Soft Deleted",
+			run:  uc.Purge,
+			setup: func() {
+				mockRepo.On("FindByIDIncludingDeleted", mock.Anything, "emp-1").Return(employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive), nil).Once()
+			},
+			wantErr: usecase.EmployeeNotDeletedError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			plan, err := tt.run(context.Background(), admin, "emp-1")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(12), plan.AttendanceRecords)
+				assert.Equal(t, files, plan.Files)
+				assert.Contains(t, plan.Cascade, "employee_two_factors")
+				assert.Contains(t, plan.Cascade, "employee_documents")
+			}
+			mockRepo.AssertExpectations(t)
+			mockAttendanceRepo.AssertExpectations(t)
+			mockStorageRepo.AssertExpectations(t)
+			mockThrottleRepo.AssertExpectations(t)
+		})
+	}
+}
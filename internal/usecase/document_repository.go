@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type DocumentRepository interface {
+	// Create saves a new document as the next version of the employee's documents of
+	// its type and sets document.Version.
+	Create(ctx context.Context, document *domain.Document) error
+	// FindByID returns nil for unknown and deleted documents.
+	FindByID(ctx context.Context, id string) (*domain.Document, error)
+	// FindByEmployeeID returns the documents of an employee that are not deleted, by
+	// type and newest version first.
+	FindByEmployeeID(ctx context.Context, employeeID string) ([]*domain.Document, error)
+	// FindCurrentExpiringBefore returns the latest version of every document type that
+	// expires before the given date, expired ones included, skipping deleted employees.
+	FindCurrentExpiringBefore(ctx context.Context, before time.Time) ([]*domain.Document, error)
+	// MarkReminded records window as the smallest expiry reminder sent for the document.
+	MarkReminded(ctx context.Context, id string, window int) error
+	MarkDeleted(ctx context.Context, id string, deletedAt time.Time) error
+	// DeleteByEmployeeID removes every document row of an employee, deleted ones included.
+	DeleteByEmployeeID(ctx context.Context, employeeID string) error
+}
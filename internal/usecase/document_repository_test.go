@@ -0,0 +1,57 @@
+package usecase_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type MockDocumentRepo struct {
+	mock.Mock
+}
+
+func (m *MockDocumentRepo) Create(ctx context.Context, document *domain.Document) error {
+	args := m.Called(ctx, document)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepo) FindByID(ctx context.Context, id string) (*domain.Document, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Document), args.Error(1)
+}
+
+func (m *MockDocumentRepo) FindByEmployeeID(ctx context.Context, employeeID string) ([]*domain.Document, error) {
+	args := m.Called(ctx, employeeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Document), args.Error(1)
+}
+
+func (m *MockDocumentRepo) FindCurrentExpiringBefore(ctx context.Context, before time.Time) ([]*domain.Document, error) {
+	args := m.Called(ctx, before)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Document), args.Error(1)
+}
+
+func (m *MockDocumentRepo) MarkReminded(ctx context.Context, id string, window int) error {
+	args := m.Called(ctx, id, window)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepo) MarkDeleted(ctx context.Context, id string, deletedAt time.Time) error {
+	args := m.Called(ctx, id, deletedAt)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepo) DeleteByEmployeeID(ctx context.Context, employeeID string) error {
+	args := m.Called(ctx, employeeID)
+	return args.Error(0)
+}
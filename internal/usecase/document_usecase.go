@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+)
+
+// documentFormats maps the content types accepted for documents to the extension their
+// files are stored with.
+var documentFormats = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// maxDocumentFileNameLength bounds the file name kept for the download, in runes.
+const maxDocumentFileNameLength = 200
+
+// UploadDocumentParams is a document upload. Content is read up to the configured limit.
+type UploadDocumentParams struct {
+	Type      domain.DocumentType
+	FileName  string
+	ExpiresAt *time.Time
+	Content   io.Reader
+}
+
+// DocumentUsecase keeps the documents HR holds per employee. Employees see and upload
+// their own documents; actors holding the manage permission handle the documents of
+// employees below them and are the only ones deleting documents.
+type DocumentUsecase struct {
+	documentRepo   DocumentRepository
+	employeeRepo   EmployeeRepository
+	storageRepo    StorageRepository
+	authorizer     *Authorizer
+	idGen          IDGenerator
+	maxUploadBytes int64
+	clock          clock.Clock
+	ctxTimeout     time.Duration
+}
+
+func NewDocumentUsecase(documentRepo DocumentRepository, employeeRepo EmployeeRepository, storageRepo StorageRepository, authorizer *Authorizer, idGen IDGenerator, maxUploadBytes int64, clk clock.Clock, timeout time.Duration) *DocumentUsecase {
+	return &DocumentUsecase{
+		documentRepo:   documentRepo,
+		employeeRepo:   employeeRepo,
+		storageRepo:    storageRepo,
+		authorizer:     authorizer,
+		idGen:          idGen,
+		maxUploadBytes: maxUploadBytes,
+		clock:          clk,
+		ctxTimeout:     timeout,
+	}
+}
+
+// List returns every version of the employee's documents, by type and newest version first.
+func (uc *DocumentUsecase) List(ctx context.Context, actor Actor, employeeID string) ([]*domain.Document, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorize(ctx, actor, employeeID, domain.PermDocumentReadSelf); err != nil {
+		return nil, err
+	}
+
+	documents, err := uc.documentRepo.FindByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find documents: %w", err)
+	}
+
+	return documents, nil
+}
+
+// Upload validates and stores a document as the next version of the employee's document
+// of its type. Only PDF files and JPEG or PNG scans are accepted; the format is taken
+// from the content, not from the name or the declared content type.
+func (uc *DocumentUsecase) Upload(ctx context.Context, actor Actor, employeeID string, params UploadDocumentParams) (*domain.Document, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorize(ctx, actor, employeeID, domain.PermDocumentUploadSelf); err != nil {
+		return nil, err
+	}
+
+	if !params.Type.IsValid() {
+		return nil, fmt.Errorf("%w: unknown document type %q", InvalidDocumentError, params.Type)
+	}
+
+	// Read one byte past the limit so an oversized body is caught whatever its header claims
+	content, err := io.ReadAll(io.LimitReader(params.Content, uc.maxUploadBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read document: %w", err)
+	}
+	if int64(len(content)) > uc.maxUploadBytes {
+		return nil, fmt.Errorf("%w: size exceeds the maximum of %d MB", InvalidDocumentError, uc.maxUploadBytes>>20)
+	}
+
+	contentType, err := documentContentType(content)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uc.idGen.NewID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate document ID: %w", err)
+	}
+
+	document := &domain.Document{
+		ID:          id,
+		EmployeeID:  employeeID,
+		Type:        params.Type,
+		FileName:    documentFileName(params.FileName, string(params.Type)+documentFormats[contentType]),
+		ContentType: contentType,
+		Size:        int64(len(content)),
+		StorageKey:  domain.DocumentStorageKey(employeeID, id, documentFormats[contentType]),
+		ExpiresAt:   params.ExpiresAt,
+		UploadedBy:  actor.ID,
+		UploadedAt:  uc.clock.Now(),
+	}
+
+	if _, err := uc.storageRepo.UploadFile(ctx, document.StorageKey, contentType, bytes.NewReader(content), document.Size); err != nil {
+		return nil, fmt.Errorf("failed to upload document to storage: %w", err)
+	}
+
+	if err := uc.documentRepo.Create(ctx, document); err != nil {
+		if err := uc.storageRepo.DeleteFile(ctx, document.StorageKey); err != nil {
+			slog.Log(ctx, slog.LevelWarn, "Failed to delete orphaned file", "key", document.StorageKey, "error", err)
+		}
+		return nil, fmt.Errorf("failed to save document: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Uploaded employee document", "ID", id, "employeeID", employeeID, "type", document.Type, "version", document.Version, "actorID", actor.ID)
+
+	return document, nil
+}
+
+// Download opens the file of a document. The caller must close the returned reader.
+func (uc *DocumentUsecase) Download(ctx context.Context, actor Actor, employeeID string, documentID string) (io.ReadCloser, *domain.Document, error) {
+	// The file is streamed after this method returns, so the timeout only covers the lookup
+	lookupCtx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorize(lookupCtx, actor, employeeID, domain.PermDocumentReadSelf); err != nil {
+		return nil, nil, err
+	}
+
+	document, err := uc.find(lookupCtx, employeeID, documentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := uc.storageRepo.DownloadFile(ctx, document.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open document: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Downloaded employee document", "ID", documentID, "employeeID", employeeID, "actorID", actor.ID)
+
+	return file, document, nil
+}
+
+// Delete removes a document version and its file. The version number is not reused.
+func (uc *DocumentUsecase) Delete(ctx context.Context, actor Actor, employeeID string, documentID string) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	// Passing the manage permission as the self permission too keeps owners from deleting
+	if err := uc.authorize(ctx, actor, employeeID, domain.PermDocumentManage); err != nil {
+		return err
+	}
+
+	document, err := uc.find(ctx, employeeID, documentID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.storageRepo.DeleteFile(ctx, document.StorageKey); err != nil {
+		return fmt.Errorf("failed to delete document from storage: %w", err)
+	}
+	if err := uc.documentRepo.MarkDeleted(ctx, documentID, uc.clock.Now()); err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Deleted employee document", "ID", documentID, "employeeID", employeeID, "actorID", actor.ID)
+
+	return nil
+}
+
+// authorize lets employees act on their own documents with selfPerm, and actors holding
+// the manage permission on the documents of employees below them.
+func (uc *DocumentUsecase) authorize(ctx context.Context, actor Actor, employeeID string, selfPerm domain.Permission) error {
+	if err := uc.authorizer.AuthorizeOnEmployee(ctx, actor, employeeID, domain.PermDocumentManage, selfPerm); err != nil {
+		return err
+	}
+
+	employee, err := uc.employeeRepo.FindByID(ctx, employeeID)
+	if err != nil {
+		return fmt.Errorf("failed to find employee: %w", err)
+	}
+	if employee == nil {
+		return EmployeeNotFoundError
+	}
+
+	return uc.authorizer.AuthorizeHierarchy(ctx, actor, employee)
+}
+
+func (uc *DocumentUsecase) find(ctx context.Context, employeeID string, documentID string) (*domain.Document, error) {
+	document, err := uc.documentRepo.FindByID(ctx, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find document: %w", err)
+	}
+	if document == nil || document.EmployeeID != employeeID {
+		return nil, DocumentNotFoundError
+	}
+	return document, nil
+}
+
+// documentContentType sniffs the format of a document and checks the file is complete:
+// images must have a readable header and PDF files their end-of-file marker.
+func documentContentType(content []byte) (string, error) {
+	contentType := http.DetectContentType(content)
+	if _, ok := documentFormats[contentType]; !ok {
+		return "", fmt.Errorf("%w: only PDF, JPEG and PNG files are accepted", InvalidDocumentError)
+	}
+
+	if contentType == "application/pdf" {
+		// The marker may be followed by a line break or some trailing garbage
+		tail := content[max(0, len(content)-1024):]
+		if !bytes.Contains(tail, []byte("%%EOF")) {
+			return "", fmt.Errorf("%w: PDF file is truncated or corrupted", InvalidDocumentError)
+		}
+		return contentType, nil
+	}
+
+	if _, _, err := image.DecodeConfig(bytes.NewReader(content)); err != nil {
+		return "", fmt.Errorf("%w: corrupted image: %v", InvalidDocumentError, err)
+	}
+	return contentType, nil
+}
+
+// documentFileName keeps the base name of an uploaded file for the download, without
+// control characters. fallback is used when nothing is left.
+func documentFileName(name string, fallback string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if runes := []rune(name); len(runes) > maxDocumentFileNameLength {
+		name = string(runes[:maxDocumentFileNameLength])
+	}
+	if name == "" || name == "." || name == "/" {
+		return fallback
+	}
+	return name
+}
@@ -0,0 +1,310 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+const testPDF = "%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\ntrailer\n<< /Root 1 0 R >>\n%%EOF\n"
+
+func TestDocumentUsecase_Upload(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	mockDocumentRepo := new(MockDocumentRepo)
+	mockRepo := new(MockEmployeeRepo)
+	mockStorageRepo := new(MockStorageRepo)
+	mockIDGen := new(MockIDGenerator)
+	mockIDGen.On("NewID").Return("doc-1", nil)
+	uc := usecase.NewDocumentUsecase(mockDocumentRepo, mockRepo, mockStorageRepo, authorizer, mockIDGen, 1<<10, clk, 2*time.Second)
+
+	staff := usecase.Actor{ID: "emp-1", Role: domain.RoleStaff}
+	admin := usecase.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+	expiresAt := time.Date(2027, 2, 28, 0, 0, 0, 0, time.UTC)
+
+	var scan bytes.Buffer
+	assert.NoError(t, png.Encode(&scan, image.NewGray(image.Rect(0, 0, 4, 4))))
+	scanSize := int64(scan.Len())
+
+	found := func() {
+		mockRepo.On("FindByID", mock.Anything, "emp-1").Return(employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive), nil).Once()
+	}
+
+	// Storage is only mocked for the cases that store a file, so uploading
+	// refused content fails the mock.
+	tests := []struct {
+		name       string
+		actor      usecase.Actor
+		employeeID string
+		params     usecase.UploadDocumentParams
+		setup      func()
+		wantErr    error
+		check      func(t *testing.T, document *domain.Document)
+	}{
+		{
+			name:       "Success - Owner Uploads Next Version",
+			actor:      staff,
+			employeeID: "emp-1",
+			params: usecase.UploadDocumentParams{
+				Type:      domain.DocumentHealthCertificate,
+				FileName:  `C:\scans\health.pdf`,
+				ExpiresAt: &expiresAt,
+				Content:   strings.NewReader(testPDF),
+			},
+			setup: func() {
+				found()
+				mockStorageRepo.On("UploadFile", mock.Anything, "documents/emp-1/doc-1.pdf", "application/pdf", mock.Anything, int64(len(testPDF))).Return("documents/emp-1/doc-1.pdf", nil).Once()
+				mockDocumentRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Document")).
+					Run(func(args mock.Arguments) { args.Get(1).(*domain.Document).Version = 2 }).
+					Return(nil).Once()
+			},
+			check: func(t *testing.T, document *domain.Document) {
+				assert.Equal(t, 2, document.Version)
+				assert.Equal(t, "health.pdf", document.FileName)
+				assert.Equal(t, "application/pdf", document.ContentType)
+				assert.Equal(t, "emp-1", document.UploadedBy)
+				assert.Equal(t, &expiresAt, document.ExpiresAt)
+			},
+		},
+		{
+			name:       "Success - Admin Uploads Scan For Staff",
+			actor:      admin,
+			employeeID: "emp-1",
+			params:     usecase.UploadDocumentParams{Type: domain.DocumentIDCard, Content: &scan},
+			setup: func() {
+				found()
+				mockStorageRepo.On("UploadFile", mock.Anything, "documents/emp-1/doc-1.png", "image/png", mock.Anything, scanSize).Return("documents/emp-1/doc-1.png", nil).Once()
+				mockDocumentRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Document")).Return(nil).Once()
+			},
+			check: func(t *testing.T, document *domain.Document) {
+				assert.Equal(t, "id_card.png", document.FileName)
+				assert.Equal(t, "admin-1", document.UploadedBy)
+			},
+		},
+		{
+			name:       "Fail - Unknown Type",
+			actor:      staff,
+			employeeID: "emp-1",
+			params:     usecase.UploadDocumentParams{Type: "passport", Content: strings.NewReader(testPDF)},
+			setup:      found,
+			wantErr:    usecase.InvalidDocumentError,
+		},
+		{
+			name:       "Fail - Not A PDF Or Image",
+			actor:      staff,
+			employeeID: "emp-1",
+			params:     usecase.UploadDocumentParams{Type: domain.DocumentContract, Content: strings.NewReader("PK\x03\x04 a zip archive")},
+			setup:      found,
+			wantErr:    usecase.InvalidDocumentError,
+		},
+		{
+			name:       "Fail - Truncated PDF",
+			actor:      staff,
+			employeeID: "emp-1",
+			params:     usecase.UploadDocumentParams{Type: domain.DocumentContract, Content: strings.NewReader(testPDF[:40])},
+			setup:      found,
+			wantErr:    usecase.InvalidDocumentError,
+		},
+		{
+			name:       "Fail - Corrupted PNG",
+			actor:      staff,
+			employeeID: "emp-1",
+			params:     usecase.UploadDocumentParams{Type: domain.DocumentIDCard, Content: strings.NewReader("\x89PNG\r\n\x1a\n garbage")},
+			setup:      found,
+			wantErr:    usecase.InvalidDocumentError,
+		},
+		{
+			name:       "Fail - Too Large",
+			actor:      staff,
+			employeeID: "emp-1",
+			params:     usecase.UploadDocumentParams{Type: domain.DocumentContract, Content: strings.NewReader("%PDF-1.4\n" + strings.Repeat("x", 1<<10) + "\n%%EOF")},
+			setup:      found,
+			wantErr:    usecase.InvalidDocumentError,
+		},
+		{
+			name:       "Fail - Save Error Removes Stored File",
+			actor:      staff,
+			employeeID: "emp-1",
+			params:     usecase.UploadDocumentParams{Type: domain.DocumentContract, Content: strings.NewReader(testPDF)},
+			setup: func() {
+				found()
+				mockStorageRepo.On("UploadFile", mock.Anything, "documents/emp-1/doc-1.pdf", "application/pdf", mock.Anything, mock.Anything).Return("documents/emp-1/doc-1.pdf", nil).Once()
+				mockDocumentRepo.On("Create", mock.Anything, mock.Anything).Return(assert.AnError).Once()
+				mockStorageRepo.On("DeleteFile", mock.Anything, "documents/emp-1/doc-1.pdf").Return(nil).Once()
+			},
+			wantErr: assert.AnError,
+		},
+		{
+			name:       "Fail - Staff Uploading For Someone Else",
+			actor:      staff,
+			employeeID: "emp-2",
+			params:     usecase.UploadDocumentParams{Type: domain.DocumentContract, Content: strings.NewReader(testPDF)},
+			setup:      func() {},
+			wantErr:    usecase.ForbiddenError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			document, err := uc.Upload(context.Background(), tt.actor, tt.employeeID, tt.params)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				tt.check(t, document)
+			}
+			mockRepo.AssertExpectations(t)
+			mockStorageRepo.AssertExpectations(t)
+			mockDocumentRepo.AssertExpectations(t)
+		})
+	}
+
+}
+
+func TestDocumentUsecase_Download(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	mockDocumentRepo := new(MockDocumentRepo)
+	mockRepo := new(MockEmployeeRepo)
+	mockStorageRepo := new(MockStorageRepo)
+	uc := usecase.NewDocumentUsecase(mockDocumentRepo, mockRepo, mockStorageRepo, authorizer, new(MockIDGenerator), 1<<10, clk, 2*time.Second)
+
+	document := &domain.Document{ID: "doc-1", EmployeeID: "emp-1", Type: domain.DocumentContract, Version: 1, StorageKey: "documents/emp-1/doc-1.pdf"}
+
+	// Downloads are only mocked for the owner, so serving the file to anyone
+	// else fails the mock.
+	tests := []struct {
+		name       string
+		actor      usecase.Actor
+		employeeID string
+		setup      func()
+		wantErr    error
+	}{
+		{
+			name:       "Success - Owner Downloads",
+			actor:      usecase.Actor{ID: "emp-1", Role: domain.RoleStaff},
+			employeeID: "emp-1",
+			setup: func() {
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive), nil).Once()
+				mockDocumentRepo.On("FindByID", mock.Anything, "doc-1").Return(document, nil).Once()
+				mockStorageRepo.On("DownloadFile", mock.Anything, "documents/emp-1/doc-1.pdf").Return(io.NopCloser(strings.NewReader(testPDF)), nil).Once()
+			},
+		},
+		{
+			name:       "Fail - Document Of Another Employee",
+			actor:      usecase.Actor{ID: "emp-2", Role: domain.RoleStaff},
+			employeeID: "emp-2",
+			setup: func() {
+				mockRepo.On("FindByID", mock.Anything, "emp-2").Return(employeeWithStatus("emp-2", domain.RoleStaff, domain.StatusActive), nil).Once()
+				mockDocumentRepo.On("FindByID", mock.Anything, "doc-1").Return(document, nil).Once()
+			},
+			wantErr: usecase.DocumentNotFoundError,
+		},
+		{
+			name:       "Fail - Supervisor Without Document Permission",
+			actor:      usecase.Actor{ID: "spv-1", Role: domain.RoleSupervisor},
+			employeeID: "emp-1",
+			setup:      func() {},
+			wantErr:    usecase.ForbiddenError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			file, found, err := uc.Download(context.Background(), tt.actor, tt.employeeID, "doc-1")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Same(t, document, found)
+				content, _ := io.ReadAll(file)
+				assert.Equal(t, testPDF, string(content))
+			}
+			mockRepo.AssertExpectations(t)
+			mockDocumentRepo.AssertExpectations(t)
+			mockStorageRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDocumentUsecase_Delete(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	mockDocumentRepo := new(MockDocumentRepo)
+	mockRepo := new(MockEmployeeRepo)
+	mockStorageRepo := new(MockStorageRepo)
+	uc := usecase.NewDocumentUsecase(mockDocumentRepo, mockRepo, mockStorageRepo, authorizer, new(MockIDGenerator), 1<<10, clk, 2*time.Second)
+
+	document := &domain.Document{ID: "doc-1", EmployeeID: "emp-1", StorageKey: "documents/emp-1/doc-1.pdf"}
+
+	tests := []struct {
+		name    string
+		actor   usecase.Actor
+		setup   func()
+		wantErr error
+	}{
+		{
+			name:  "Success - Admin Deletes File And Version",
+			actor: usecase.Actor{ID: "admin-1", Role: domain.RoleAdmin},
+			setup: func() {
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive), nil).Once()
+				mockDocumentRepo.On("FindByID", mock.Anything, "doc-1").Return(document, nil).Once()
+				mockStorageRepo.On("DeleteFile", mock.Anything, "documents/emp-1/doc-1.pdf").Return(nil).Once()
+				mockDocumentRepo.On("MarkDeleted", mock.Anything, "doc-1", now).Return(nil).Once()
+			},
+		},
+		{
+			name:    "Fail - Owner Cannot Delete",
+			actor:   usecase.Actor{ID: "emp-1", Role: domain.RoleStaff},
+			setup:   func() {},
+			wantErr: usecase.ForbiddenError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			err := uc.Delete(context.Background(), tt.actor, "emp-1", "doc-1")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockStorageRepo.AssertExpectations(t)
+			mockDocumentRepo.AssertExpectations(t)
+		})
+	}
+}
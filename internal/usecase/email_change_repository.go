@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type EmailChangeRepository interface {
+	Save(ctx context.Context, change *domain.EmailChange) error
+	FindByTokenHash(ctx context.Context, tokenHash string) (*domain.EmailChange, error)
+	CancelPending(ctx context.Context, employeeID string, now time.Time) error
+}
@@ -0,0 +1,31 @@
+package usecase_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type MockEmailChangeRepo struct {
+	mock.Mock
+}
+
+func (m *MockEmailChangeRepo) Save(ctx context.Context, change *domain.EmailChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+func (m *MockEmailChangeRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*domain.EmailChange, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.EmailChange), args.Error(1)
+}
+
+func (m *MockEmailChangeRepo) CancelPending(ctx context.Context, employeeID string, now time.Time) error {
+	args := m.Called(ctx, employeeID, now)
+	return args.Error(0)
+}
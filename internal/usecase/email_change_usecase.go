@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+)
+
+// EmailChangeUsecase changes email addresses in two steps: a token is sent to the new
+// address, and the change is applied when the link with the token is opened.
+type EmailChangeUsecase struct {
+	employeeRepo EmployeeRepository
+	changeRepo   EmailChangeRepository
+	sender       MailSender
+	authorizer   *Authorizer
+	idGen        IDGenerator
+	clock        clock.Clock
+	confirmURL   string
+	ttl          time.Duration
+	ctxTimeout   time.Duration
+}
+
+// NewEmailChangeUsecase builds the confirmation links from baseURL, the public URL of the service.
+func NewEmailChangeUsecase(employeeRepo EmployeeRepository, changeRepo EmailChangeRepository, sender MailSender, authorizer *Authorizer, idGen IDGenerator, clk clock.Clock, baseURL string, ttl time.Duration, timeout time.Duration) *EmailChangeUsecase {
+	return &EmailChangeUsecase{
+		employeeRepo: employeeRepo,
+		changeRepo:   changeRepo,
+		sender:       sender,
+		authorizer:   authorizer,
+		idGen:        idGen,
+		clock:        clk,
+		confirmURL:   strings.TrimRight(baseURL, "/") + "/email-change/confirm",
+		ttl:          ttl,
+		ctxTimeout:   timeout,
+	}
+}
+
+// Request starts an email change. Earlier unconfirmed requests of the employee are
+// cancelled; the current address is told about the request.
+func (uc *EmailChangeUsecase) Request(ctx context.Context, actor Actor, employeeID string, newEmail string) (*domain.EmailChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.AuthorizeOnEmployee(ctx, actor, employeeID, domain.PermEmployeeUpdate, domain.PermEmployeeUpdateSelf); err != nil {
+		return nil, err
+	}
+
+	employee, err := uc.employeeRepo.FindByID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find employee: %w", err)
+	}
+	if employee == nil {
+		return nil, EmployeeNotFoundError
+	}
+
+	if err := uc.authorizer.AuthorizeHierarchy(ctx, actor, employee); err != nil {
+		return nil, err
+	}
+
+	newEmail = strings.TrimSpace(newEmail)
+	if strings.EqualFold(newEmail, string(employee.Email())) {
+		return nil, EmailUnchangedError
+	}
+
+	if err := uc.checkEmailAvailable(ctx, newEmail, employeeID); err != nil {
+		return nil, err
+	}
+
+	token, tokenHash, err := generateEmailChangeToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	id, err := uc.idGen.NewID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ID: %w", err)
+	}
+
+	now := uc.clock.Now()
+	if err := uc.changeRepo.CancelPending(ctx, employeeID, now); err != nil {
+		return nil, fmt.Errorf("failed to cancel pending email changes: %w", err)
+	}
+
+	change := domain.NewEmailChange(id, employeeID, domain.Email(newEmail), tokenHash, actor.ID, now, uc.ttl)
+	if err := uc.changeRepo.Save(ctx, change); err != nil {
+		return nil, fmt.Errorf("failed to save email change: %w", err)
+	}
+
+	link := uc.confirmURL + "?token=" + url.QueryEscape(token)
+	verification := domain.MailMessage{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf("Hello %s,\n\nPlease confirm that this is your new email address by opening the link below:\n\n%s\n\nThe link is valid until %s. If you did not request this change, ignore this email.\n",
+			employee.Name(), link, change.ExpiresAt.Format(time.RFC1123)),
+	}
+	if err := uc.sender.Send(ctx, verification); err != nil {
+		return nil, fmt.Errorf("failed to send verification email: %w", err)
+	}
+
+	// The current address only learns that a change was requested, never the token
+	notice := domain.MailMessage{
+		To:      string(employee.Email()),
+		Subject: "Your email address is about to change",
+		Body: fmt.Sprintf("Hello %s,\n\nA change of your email address was requested. It takes effect once the new address is confirmed. If this was not you, contact your administrator.\n",
+			employee.Name()),
+	}
+	if err := uc.sender.Send(ctx, notice); err != nil {
+		slog.Log(ctx, slog.LevelWarn, "Failed to notify current email address", "ID", employeeID, "error", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Requested email change", "ID", employeeID, "actorID", actor.ID)
+
+	return change, nil
+}
+
+// Confirm applies the email change the token was issued for. Tokens can be used once and
+// only until they expire or a newer change is requested.
+func (uc *EmailChangeUsecase) Confirm(ctx context.Context, token string) (*domain.Employee, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	change, err := uc.changeRepo.FindByTokenHash(ctx, hashEmailChangeToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find email change: %w", err)
+	}
+
+	now := uc.clock.Now()
+	if change == nil || !change.IsPending(now) {
+		return nil, InvalidEmailChangeTokenError
+	}
+
+	employee, err := uc.employeeRepo.FindByID(ctx, change.EmployeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find employee: %w", err)
+	}
+	if employee == nil {
+		return nil, EmployeeNotFoundError
+	}
+
+	// The address may have been taken while the link was waiting in the inbox
+	if err := uc.checkEmailAvailable(ctx, string(change.NewEmail), change.EmployeeID); err != nil {
+		return nil, err
+	}
+
+	if err := employee.ChangeEmail(change.NewEmail); err != nil {
+		return nil, fmt.Errorf("failed to change email: %w", err)
+	}
+
+	if err := uc.employeeRepo.Update(ctx, employee); err != nil {
+		return nil, versionError(fmt.Errorf("failed to update employee: %w", err))
+	}
+
+	change.Confirm(now)
+	if err := uc.changeRepo.Save(ctx, change); err != nil {
+		return nil, fmt.Errorf("failed to save email change: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Changed employee email", "ID", change.EmployeeID)
+
+	return employee, nil
+}
+
+func (uc *EmailChangeUsecase) checkEmailAvailable(ctx context.Context, email string, employeeID string) error {
+	conflicts, err := uc.employeeRepo.FindContactConflicts(ctx, email, "", employeeID)
+	if err != nil {
+		return fmt.Errorf("failed to check email: %w", err)
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%w: email", EmployeeContactConflictError)
+	}
+	return nil
+}
+
+func generateEmailChangeToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashEmailChangeToken(token), nil
+}
+
+func hashEmailChangeToken(token string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	return hex.EncodeToString(sum[:])
+}
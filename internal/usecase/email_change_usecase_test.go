@@ -0,0 +1,223 @@
+package usecase_test
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/notification"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+var confirmLinkPattern = regexp.MustCompile(`https://hr\.shop\.local/email-change/confirm\?token=(\S+)`)
+
+func TestEmailChangeUsecase_Request(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	idGen := new(MockIDGenerator)
+	idGen.On("NewID").Return("change-1", nil)
+
+	mockRepo := new(MockEmployeeRepo)
+	mockChangeRepo := new(MockEmailChangeRepo)
+	sender := notification.NewMemorySender()
+	uc := usecase.NewEmailChangeUsecase(mockRepo, mockChangeRepo, sender, authorizer, idGen, clk, "https://hr.shop.local/", 24*time.Hour, 2*time.Second)
+
+	supervisor := usecase.Actor{ID: "spv-1", Role: domain.RoleSupervisor}
+	staff := usecase.Actor{ID: "emp-2", Role: domain.RoleStaff}
+	emp := employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive)
+
+	tests := []struct {
+		name     string
+		actor    usecase.Actor
+		newEmail string
+		setup    func()
+		wantErr  error
+		check    func(t *testing.T, change *domain.EmailChange, sent []domain.MailMessage)
+	}{
+		{
+			name:     "Success - Sends Token To New Address Only",
+			actor:    supervisor,
+			newEmail: "jane.new@shop.local",
+			setup: func() {
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(emp, nil).Once()
+				mockRepo.On("FindContactConflicts", mock.Anything, "jane.new@shop.local", "", "emp-1").Return([]string{}, nil).Once()
+				mockChangeRepo.On("CancelPending", mock.Anything, "emp-1", now).Return(nil).Once()
+				mockChangeRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.EmailChange")).Return(nil).Once()
+			},
+			check: func(t *testing.T, change *domain.EmailChange, sent []domain.MailMessage) {
+				assert.Equal(t, now.Add(24*time.Hour), change.ExpiresAt)
+				assert.Equal(t, domain.Email("jane@shop.local"), emp.Email())
+
+				if assert.Len(t, sent, 2) {
+					assert.Equal(t, "jane.new@shop.local", sent[0].To)
+					match := confirmLinkPattern.FindStringSubmatch(sent[0].Body)
+					if assert.NotNil(t, match) {
+						token, _ := url.QueryUnescape(match[1])
+						assert.NotEqual(t, token, change.TokenHash)
+					}
+
+					assert.Equal(t, "jane@shop.local", sent[1].To)
+					assert.NotRegexp(t, confirmLinkPattern, sent[1].Body)
+				}
+			},
+		},
+		{
+			name:     "Fail - Address Already In Use",
+			actor:    supervisor,
+			newEmail: "taken@shop.local",
+			setup: func() {
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(emp, nil).Once()
+				mockRepo.On("FindContactConflicts", mock.Anything, "taken@shop.local", "", "emp-1").Return([]string{"email"}, nil).Once()
+			},
+			wantErr: usecase.EmployeeContactConflictError,
+		},
+		{
+			name:     "Fail - Staff Changing Another Employee",
+			actor:    staff,
+			newEmail: "other@shop.local",
+			setup:    func() {},
+			wantErr:  usecase.ForbiddenError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			before := len(sender.Sent())
+
+			change, err := uc.Request(context.Background(), tt.actor, "emp-1", tt.newEmail)
+
+			sent := sender.Sent()[before:]
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, sent)
+			} else {
+				assert.NoError(t, err)
+				tt.check(t, change, sent)
+			}
+			mockRepo.AssertExpectations(t)
+			mockChangeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEmailChangeUsecase_Confirm(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	idGen := new(MockIDGenerator)
+	idGen.On("NewID").Return("change-1", nil)
+
+	mockRepo := new(MockEmployeeRepo)
+	mockChangeRepo := new(MockEmailChangeRepo)
+	sender := notification.NewMemorySender()
+	uc := usecase.NewEmailChangeUsecase(mockRepo, mockChangeRepo, sender, authorizer, idGen, clk, "https://hr.shop.local/", 24*time.Hour, 2*time.Second)
+
+	supervisor := usecase.Actor{ID: "spv-1", Role: domain.RoleSupervisor}
+
+	// requestToken runs a real request for emp and returns the mailed token with
+	// the change that was stored for it.
+	requestToken := func(t *testing.T, emp *domain.Employee) (string, *domain.EmailChange) {
+		var saved *domain.EmailChange
+		mockRepo.On("FindByID", mock.Anything, "emp-1").Return(emp, nil).Once()
+		mockRepo.On("FindContactConflicts", mock.Anything, "jane.new@shop.local", "", "emp-1").Return([]string{}, nil).Once()
+		mockChangeRepo.On("CancelPending", mock.Anything, "emp-1", now).Return(nil).Once()
+		mockChangeRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.EmailChange")).Run(func(args mock.Arguments) {
+			saved = args.Get(1).(*domain.EmailChange)
+		}).Return(nil).Once()
+
+		_, err := uc.Request(context.Background(), supervisor, "emp-1", "jane.new@shop.local")
+		assert.NoError(t, err)
+
+		sent := sender.Sent()
+		match := confirmLinkPattern.FindStringSubmatch(sent[len(sent)-2].Body)
+		token, _ := url.QueryUnescape(match[1])
+		return token, saved
+	}
+
+	// Update is only mocked for the success case, so applying a refused token
+	// fails the mock.
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T) string
+		wantErr error
+		check   func(t *testing.T, confirmed *domain.Employee)
+	}{
+		{
+			name: "Success - Applies New Email",
+			setup: func(t *testing.T) string {
+				emp := employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive)
+				token, saved := requestToken(t, emp)
+				mockChangeRepo.On("FindByTokenHash", mock.Anything, saved.TokenHash).Return(saved, nil).Once()
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(emp, nil).Once()
+				mockRepo.On("FindContactConflicts", mock.Anything, "jane.new@shop.local", "", "emp-1").Return([]string{}, nil).Once()
+				mockRepo.On("Update", mock.Anything, emp).Return(nil).Once()
+				mockChangeRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *domain.EmailChange) bool {
+					return c == saved && c.ConfirmedAt != nil
+				})).Return(nil).Once()
+				return token
+			},
+			check: func(t *testing.T, confirmed *domain.Employee) {
+				assert.Equal(t, domain.Email("jane.new@shop.local"), confirmed.Email())
+			},
+		},
+		{
+			name: "Fail - Token Used Twice",
+			setup: func(t *testing.T) string {
+				token, saved := requestToken(t, employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive))
+				saved.Confirm(now)
+				mockChangeRepo.On("FindByTokenHash", mock.Anything, saved.TokenHash).Return(saved, nil).Once()
+				return token
+			},
+			wantErr: usecase.InvalidEmailChangeTokenError,
+		},
+		{
+			name: "Fail - Expired Token",
+			setup: func(*testing.T) string {
+				expired := domain.NewEmailChange("change-0", "emp-1", "jane.new@shop.local", "hash", "emp-1", now.Add(-48*time.Hour), 24*time.Hour)
+				mockChangeRepo.On("FindByTokenHash", mock.Anything, mock.Anything).Return(expired, nil).Once()
+				return "some-token"
+			},
+			wantErr: usecase.InvalidEmailChangeTokenError,
+		},
+		{
+			name: "Fail - Unknown Token",
+			setup: func(*testing.T) string {
+				mockChangeRepo.On("FindByTokenHash", mock.Anything, mock.Anything).Return(nil, nil).Once()
+				return "unknown"
+			},
+			wantErr: usecase.InvalidEmailChangeTokenError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := tt.setup(t)
+
+			confirmed, err := uc.Confirm(context.Background(), token)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				tt.check(t, confirmed)
+			}
+			mockRepo.AssertExpectations(t)
+			mockChangeRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
 )
@@ -12,5 +13,19 @@ type EmployeeRepository interface {
 	FindByEmail(ctx context.Context, email string) (*domain.Employee, error)
 	FindAll(ctx context.Context) ([]*domain.Employee, error)
 	Update(ctx context.Context, employee *domain.Employee) error
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, employee *domain.Employee) error
+	FindByIDIncludingDeleted(ctx context.Context, id string) (*domain.Employee, error)
+	// FindSessionState returns what decides whether the employee's tokens are still
+	// accepted, without loading or decrypting the rest of the employee. It returns nil
+	// when no employee has the id.
+	FindSessionState(ctx context.Context, id string) (*domain.SessionState, error)
+	FindDeleted(ctx context.Context) ([]*domain.Employee, error)
+	FindContactConflicts(ctx context.Context, email string, phoneNumber string, excludeID string) ([]string, error)
+	Restore(ctx context.Context, employee *domain.Employee) error
+	HardDelete(ctx context.Context, id string) error
+	FindRetentionExpired(ctx context.Context, terminatedBefore time.Time) ([]*domain.Employee, error)
+	Anonymize(ctx context.Context, employee *domain.Employee) error
+	// FindPhotoKeys returns the photo of every employee that has one, soft deleted
+	// employees included.
+	FindPhotoKeys(ctx context.Context) ([]string, error)
 }
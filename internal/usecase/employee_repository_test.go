@@ -3,9 +3,11 @@ package usecase_test
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
 )
 
 type MockEmployeeRepo struct {
@@ -50,7 +52,33 @@ func (m *MockEmployeeRepo) Update(ctx context.Context, employee *domain.Employee
 	return args.Error(0)
 }
 
-func (m *MockEmployeeRepo) Delete(ctx context.Context, id string) error {
+func (m *MockEmployeeRepo) Delete(ctx context.Context, employee *domain.Employee) error {
+	args := m.Called(ctx, employee)
+	return args.Error(0)
+}
+
+func (m *MockEmployeeRepo) FindDeleted(ctx context.Context) ([]*domain.Employee, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Employee), args.Error(1)
+}
+
+func (m *MockEmployeeRepo) FindContactConflicts(ctx context.Context, email string, phoneNumber string, excludeID string) ([]string, error) {
+	args := m.Called(ctx, email, phoneNumber, excludeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockEmployeeRepo) Restore(ctx context.Context, employee *domain.Employee) error {
+	args := m.Called(ctx, employee)
+	return args.Error(0)
+}
+
+func (m *MockEmployeeRepo) HardDelete(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
@@ -59,3 +87,66 @@ func (m *MockStorageRepo) UploadFile(ctx context.Context, fileName string, conte
 	args := m.Called(ctx, fileName, contentType, content, size)
 	return args.String(0), args.Error(1)
 }
+
+func (m *MockEmployeeRepo) FindByIDIncludingDeleted(ctx context.Context, id string) (*domain.Employee, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Employee), args.Error(1)
+}
+
+func (m *MockEmployeeRepo) FindSessionState(ctx context.Context, id string) (*domain.SessionState, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SessionState), args.Error(1)
+}
+
+func (m *MockEmployeeRepo) FindRetentionExpired(ctx context.Context, terminatedBefore time.Time) ([]*domain.Employee, error) {
+	args := m.Called(ctx, terminatedBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Employee), args.Error(1)
+}
+
+func (m *MockEmployeeRepo) Anonymize(ctx context.Context, employee *domain.Employee) error {
+	args := m.Called(ctx, employee)
+	return args.Error(0)
+}
+
+func (m *MockStorageRepo) DeleteFile(ctx context.Context, fileURL string) error {
+	args := m.Called(ctx, fileURL)
+	return args.Error(0)
+}
+
+func (m *MockStorageRepo) PresignGetURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	args := m.Called(ctx, key, expiry)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockStorageRepo) ListFiles(ctx context.Context, prefix string) ([]usecase.StoredFile, error) {
+	args := m.Called(ctx, prefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]usecase.StoredFile), args.Error(1)
+}
+
+func (m *MockEmployeeRepo) FindPhotoKeys(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockStorageRepo) DownloadFile(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	args := m.Called(ctx, fileURL)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
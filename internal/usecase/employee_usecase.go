@@ -1,20 +1,18 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"image"
-	_ "image/jpeg"
-	_ "image/png"
 	"io"
 	"log/slog"
-	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
 	"github.com/zuyatna/shop-retail-employee-service/internal/dto/employee"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/imageproc"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -26,22 +24,33 @@ type EmployeeUsecase struct {
 	repo        EmployeeRepository
 	storageRepo StorageRepository
 	idGen       IDGenerator
+	authorizer  *Authorizer
+	photos      PhotoConfig
+	clock       clock.Clock
 	ctxTimeout  time.Duration
 }
 
-func NewEmployeeUsecase(repo EmployeeRepository, storageRepo StorageRepository, idGen IDGenerator, timeout time.Duration) *EmployeeUsecase {
+func NewEmployeeUsecase(repo EmployeeRepository, storageRepo StorageRepository, idGen IDGenerator, authorizer *Authorizer, photos PhotoConfig, clk clock.Clock, timeout time.Duration) *EmployeeUsecase {
 	return &EmployeeUsecase{
 		repo:        repo,
 		storageRepo: storageRepo,
 		idGen:       idGen,
+		authorizer:  authorizer,
+		photos:      photos,
+		clock:       clk,
 		ctxTimeout:  timeout,
 	}
 }
 
-func (uc *EmployeeUsecase) Register(ctx context.Context, req employee.CreateEmployeeRequest) (string, error) {
+func (uc *EmployeeUsecase) Register(ctx context.Context, actor Actor, req employee.CreateEmployeeRequest) (string, error) {
 	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
 	defer cancel()
 
+	// Employees can only be created with a role below the actor's own
+	if err := uc.authorizer.AuthorizeRoleAssignment(ctx, actor, domain.Role(req.Role)); err != nil {
+		return "", err
+	}
+
 	existing, err := uc.repo.FindByEmail(ctx, req.Email)
 	if err != nil {
 		if errors.Is(err, EmployeeNotFoundError) {
@@ -74,6 +83,7 @@ func (uc *EmployeeUsecase) Register(ctx context.Context, req employee.CreateEmpl
 		Email:          domain.Email(req.Email),
 		HashedPassword: string(hashedBytes),
 		Role:           domain.Role(req.Role),
+		Status:         domain.Status(req.Status),
 		Position:       req.Position,
 		Salary:         req.Salary,
 		BirthDate:      birthDate,
@@ -81,6 +91,7 @@ func (uc *EmployeeUsecase) Register(ctx context.Context, req employee.CreateEmpl
 		City:           req.City,
 		Province:       req.Province,
 		PhoneNumber:    req.PhoneNumber,
+		StoreID:        req.StoreID,
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to create newEmployee domain: %w", err)
@@ -142,13 +153,38 @@ func (uc *EmployeeUsecase) GetAll(ctx context.Context) ([]*domain.Employee, erro
 	return employees, nil
 }
 
-func (uc *EmployeeUsecase) UpdateProfile(ctx context.Context, id string, req employee.UpdateEmployeeRequest) error {
+// UpdateProfile applies the changes only if the employee is still at expectedVersion,
+// the version the caller read. It returns the new version.
+func (uc *EmployeeUsecase) UpdateProfile(ctx context.Context, actor Actor, id string, req employee.UpdateEmployeeRequest, expectedVersion int64) (int64, error) {
 	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
 	defer cancel()
 
+	if err := uc.authorizer.AuthorizeOnEmployee(ctx, actor, id, domain.PermEmployeeUpdate, domain.PermEmployeeUpdateSelf); err != nil {
+		return 0, err
+	}
+
 	findByID, err := uc.repo.FindByID(ctx, id)
 	if err != nil {
-		return fmt.Errorf("failed to find findByID: %w", err)
+		return 0, fmt.Errorf("failed to find findByID: %w", err)
+	}
+
+	if err := uc.authorizer.AuthorizeHierarchy(ctx, actor, findByID); err != nil {
+		return 0, err
+	}
+
+	if err := findByID.CheckVersion(expectedVersion); err != nil {
+		return 0, versionError(err)
+	}
+
+	if req.Role != nil && domain.Role(*req.Role) != findByID.Role() {
+		policy, err := uc.authorizer.Policy(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if err := findByID.ChangeRole(domain.Role(*req.Role), domain.EmployeeID(actor.ID), actor.Role, policy, uc.clock.Now()); err != nil {
+			return 0, roleAssignmentError(err)
+		}
+		slog.Log(ctx, slog.LevelInfo, "Changed employee role", "ID", id, "role", *req.Role, "actorID", actor.ID)
 	}
 
 	updateIfPresent(req.Name, findByID.SetName)
@@ -158,84 +194,167 @@ func (uc *EmployeeUsecase) UpdateProfile(ctx context.Context, id string, req emp
 	updateIfPresent(req.City, findByID.SetCity)
 	updateIfPresent(req.Province, findByID.SetProvince)
 	updateIfPresent(req.PhoneNumber, findByID.SetPhoneNumber)
-	updateIfPresent(req.Photo, findByID.SetPhoto)
+	updateIfPresent(req.StoreID, findByID.SetStoreID)
 
+	// The repository repeats the version check in the UPDATE, so a write that raced
+	// with this one since FindByID is rejected as well
 	if err := uc.repo.Update(ctx, findByID); err != nil {
-		return fmt.Errorf("failed to update findByID in repo: %w", err)
-	}
-	emailLog := ""
-	if req.Email != nil {
-		emailLog = *req.Email
+		return 0, versionError(fmt.Errorf("failed to update findByID in repo: %w", err))
 	}
-	slog.Log(ctx, slog.LevelInfo, "Updated employee profile", "ID", id, "Email", emailLog)
+	slog.Log(ctx, slog.LevelInfo, "Updated employee profile", "ID", id, "version", findByID.Version())
 
-	return nil
+	return findByID.Version(), nil
 }
 
-func (uc *EmployeeUsecase) UploadPhoto(ctx context.Context, employeeID string, file io.ReadSeeker, headerSize int64, contentType string, fileName string) error {
-	{
-		ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
-		defer cancel()
+// UploadPhoto normalises an uploaded photo and stores it as a square avatar and a
+// thumbnail. Re-encoding drops the EXIF data of the original, the orientation is applied
+// to the pixels first.
+func (uc *EmployeeUsecase) UploadPhoto(ctx context.Context, actor Actor, employeeID string, file io.Reader, headerSize int64, contentType string, fileName string) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
 
-		const MaxSize = 5 * 1024 * 1024 // 5 MB
-		if headerSize > MaxSize {
-			return fmt.Errorf("photo size exceeds the maximum limit of 5 MB")
-		}
+	if err := uc.authorizer.AuthorizeOnEmployee(ctx, actor, employeeID, domain.PermEmployeePhotoUpload, domain.PermEmployeePhotoUploadSelf); err != nil {
+		return err
+	}
 
-		ext := strings.ToLower(filepath.Ext(fileName))
-		if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
-			return fmt.Errorf("unsupported file format: %s", ext)
-		}
+	upload := PhotoUpload{Content: file, Size: headerSize, ContentType: contentType, FileName: fileName}
+	if err := uc.photos.check(upload); err != nil {
+		return err
+	}
 
-		if contentType != "image/jpeg" && contentType != "image/png" {
-			return fmt.Errorf("unsupported content type: %s", contentType)
-		}
+	existingEmployee, err := uc.repo.FindByID(ctx, employeeID)
+	if err != nil {
+		return fmt.Errorf("failed to find employee by ID: %w", err)
+	}
+	if existingEmployee == nil {
+		return EmployeeNotFoundError
+	}
 
-		cfg, _, err := image.DecodeConfig(file)
-		if err != nil {
-			return fmt.Errorf("invalid image or corrupted file: %v", err)
-		}
+	if err := uc.authorizer.AuthorizeHierarchy(ctx, actor, existingEmployee); err != nil {
+		return err
+	}
 
-		if cfg.Width > 800 || cfg.Height > 800 {
-			return fmt.Errorf("image dimensions exceed the maximum limit of 800x800 pixels")
-		}
+	photo, err := uc.photos.decode(upload)
+	if err != nil {
+		return err
+	}
 
-		// Reset file pointer after reading
-		_, err = file.Seek(0, 0)
-		if err != nil {
-			return err
-		}
+	avatar, err := imageproc.EncodeJPEG(photo.Square(uc.photos.Size))
+	if err != nil {
+		return fmt.Errorf("failed to encode photo: %w", err)
+	}
+	thumbnail, err := imageproc.EncodeJPEG(photo.Square(uc.photos.ThumbnailSize))
+	if err != nil {
+		return fmt.Errorf("failed to encode photo thumbnail: %w", err)
+	}
 
-		uniqueID, _ := uc.idGen.NewID()
-		storageFileName := fmt.Sprintf("%s%s", uniqueID, ext)
+	uploadID, err := uc.idGen.NewID()
+	if err != nil {
+		return fmt.Errorf("failed to generate photo ID: %w", err)
+	}
+	photoKey, thumbnailKey := domain.PhotoKeys(uploadID)
 
-		fileURL, err := uc.storageRepo.UploadFile(ctx, storageFileName, contentType, file, headerSize)
-		if err != nil {
-			return fmt.Errorf("failed to upload photo to storage: %w", err)
-		}
+	// The thumbnail goes first, a photo key is only stored once both objects exist
+	if _, err := uc.storageRepo.UploadFile(ctx, thumbnailKey, "image/jpeg", bytes.NewReader(thumbnail), int64(len(thumbnail))); err != nil {
+		return fmt.Errorf("failed to upload photo thumbnail to storage: %w", err)
+	}
+	if _, err := uc.storageRepo.UploadFile(ctx, photoKey, "image/jpeg", bytes.NewReader(avatar), int64(len(avatar))); err != nil {
+		uc.deleteFile(ctx, thumbnailKey)
+		return fmt.Errorf("failed to upload photo to storage: %w", err)
+	}
 
-		existingEmployee, err := uc.repo.FindByID(ctx, employeeID)
-		if err != nil {
-			return fmt.Errorf("failed to find employee by ID: %w", err)
-		}
-		if existingEmployee == nil {
-			return EmployeeNotFoundError
-		}
+	previous := existingEmployee.PhotoFiles()
+	existingEmployee.SetPhoto(photoKey)
 
-		existingEmployee.SetPhoto(fileURL)
+	if err := uc.repo.Update(ctx, existingEmployee); err != nil {
+		uc.deleteFile(ctx, photoKey)
+		uc.deleteFile(ctx, thumbnailKey)
+		return fmt.Errorf("failed to update employee photo: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Uploaded employee photo", "ID", employeeID, "key", photoKey, "actorID", actor.ID)
 
-		if err := uc.repo.Update(ctx, existingEmployee); err != nil {
-			return fmt.Errorf("failed to update employee photo URL: %w", err)
-		}
+	// The replaced photo is no longer referenced; whatever fails to go here is picked up
+	// by the photo cleanup job
+	for _, key := range previous {
+		uc.deleteFile(ctx, key)
+	}
+
+	return nil
+}
 
-		return nil
+// deleteFile removes an object nothing references any more, on a best effort basis.
+func (uc *EmployeeUsecase) deleteFile(ctx context.Context, key string) {
+	if err := uc.storageRepo.DeleteFile(ctx, key); err != nil {
+		slog.Log(ctx, slog.LevelWarn, "Failed to delete orphaned file", "key", key, "error", err)
 	}
 }
 
-func (uc *EmployeeUsecase) Delete(ctx context.Context, id string) error {
+// PhotoURL returns a presigned URL for the employee's photo, or its thumbnail, valid
+// for the configured photo URL lifetime. Photos without a thumbnail serve the photo.
+func (uc *EmployeeUsecase) PhotoURL(ctx context.Context, actor Actor, id string, thumbnail bool) (string, error) {
 	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
 	defer cancel()
 
+	if err := uc.authorizer.AuthorizeOnEmployee(ctx, actor, id, domain.PermEmployeeRead, domain.PermEmployeeReadSelf); err != nil {
+		return "", err
+	}
+
+	findByID, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("failed to find employee by ID: %w", err)
+	}
+	if findByID == nil {
+		return "", EmployeeNotFoundError
+	}
+	if findByID.Photo() == "" {
+		return "", EmployeePhotoNotFoundError
+	}
+
+	key := findByID.Photo()
+	if thumbnail && findByID.PhotoThumbnail() != "" {
+		key = findByID.PhotoThumbnail()
+	}
+
+	url, err := uc.storageRepo.PresignGetURL(ctx, key, uc.photos.URLTTL)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign photo URL: %w", err)
+	}
+
+	return url, nil
+}
+
+// Response maps the employee to a response with every field visible, for callers
+// looking at their own record.
+func (uc *EmployeeUsecase) Response(ctx context.Context, e *domain.Employee) *EmployeeResponse {
+	resp := FromDomain(e)
+	if resp != nil {
+		resp.Photo = uc.photoURL(ctx, e.Photo())
+		resp.PhotoThumb = uc.photoURL(ctx, e.PhotoThumbnail())
+	}
+	return resp
+}
+
+// photoURL presigns a stored photo key for a response, see presignPhoto.
+func (uc *EmployeeUsecase) photoURL(ctx context.Context, key string) string {
+	if key == "" {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	return presignPhoto(ctx, uc.storageRepo, key, uc.photos.URLTTL)
+}
+
+// Delete soft deletes the employee if it is still at expectedVersion.
+func (uc *EmployeeUsecase) Delete(ctx context.Context, actor Actor, id string, expectedVersion int64) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermEmployeeDelete); err != nil {
+		return err
+	}
+
 	findByID, err := uc.repo.FindByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to find findByID: %w", err)
@@ -245,16 +364,36 @@ func (uc *EmployeeUsecase) Delete(ctx context.Context, id string) error {
 		return EmployeeNotFoundError
 	}
 
-	findByID.Delete()
+	if err := uc.authorizer.AuthorizeHierarchy(ctx, actor, findByID); err != nil {
+		return err
+	}
+
+	if err := findByID.CheckVersion(expectedVersion); err != nil {
+		return versionError(err)
+	}
 
-	if err := uc.repo.Update(ctx, findByID); err != nil {
+	// The employee is soft deleted and can be restored; the retention policy erases the
+	// personal data of employees that stay deleted
+	if err := findByID.SoftDelete(uc.clock.Now()); err != nil {
 		return fmt.Errorf("failed to soft delete findByID: %w", err)
 	}
+
+	if err := uc.repo.Delete(ctx, findByID); err != nil {
+		return versionError(fmt.Errorf("failed to soft delete findByID: %w", err))
+	}
 	slog.Log(ctx, slog.LevelInfo, "Deleted employee", "ID", id)
 
 	return nil
 }
 
+// versionError turns a domain version conflict into EmployeeVersionConflictError.
+func versionError(err error) error {
+	if errors.Is(err, domain.ErrVersionConflict) {
+		return fmt.Errorf("%w: %v", EmployeeVersionConflictError, err)
+	}
+	return err
+}
+
 func parseBirthDate(dateStr string) (*time.Time, error) {
 	layout := "2006-01-02"
 	t, err := time.Parse(layout, dateStr)
@@ -1,8 +1,16 @@
 package usecase_test
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
 	"testing"
 	"time"
 
@@ -15,13 +23,27 @@ import (
 	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
 )
 
+var testPhotoConfig = usecase.PhotoConfig{
+	MaxUploadBytes: 5 << 20,
+	MaxDimension:   4000,
+	Size:           256,
+	ThumbnailSize:  64,
+	URLTTL:         15 * time.Minute,
+}
+
 func TestEmployeeUsecase_Register(t *testing.T) {
 	mockRepo := new(MockEmployeeRepo)
 	mockStorageRepo := new(MockStorageRepo)
 	mockIDGen := new(MockIDGenerator)
 
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, MockClock{currentTime: time.Now()}, time.Minute)
+
 	ctxTimeout := 2 * time.Second
-	uc := usecase.NewEmployeeUsecase(mockRepo, mockStorageRepo, mockIDGen, ctxTimeout)
+	uc := usecase.NewEmployeeUsecase(mockRepo, mockStorageRepo, mockIDGen, authorizer, testPhotoConfig, MockClock{currentTime: time.Now()}, ctxTimeout)
+
+	supervisor := usecase.Actor{ID: "spv-1", Role: domain.RoleSupervisor}
 
 	req := employee.CreateEmployeeRequest{
 		Name:        "Test User",
@@ -48,7 +70,7 @@ func TestEmployeeUsecase_Register(t *testing.T) {
 		// 3. Mock Save
 		mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Employee")).Return(nil).Once()
 
-		id, err := uc.Register(context.Background(), req)
+		id, err := uc.Register(context.Background(), supervisor, req)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "uuid-123", id)
@@ -60,7 +82,7 @@ func TestEmployeeUsecase_Register(t *testing.T) {
 		existingEmp := &domain.Employee{}
 		mockRepo.On("FindByEmail", mock.Anything, req.Email).Return(existingEmp, nil).Once()
 
-		id, err := uc.Register(context.Background(), req)
+		id, err := uc.Register(context.Background(), supervisor, req)
 
 		assert.Error(t, err)
 		assert.Equal(t, "", id)
@@ -69,12 +91,453 @@ func TestEmployeeUsecase_Register(t *testing.T) {
 		// Ensure Save was not called
 		mockRepo.AssertNotCalled(t, "Save")
 	})
+
+	t.Run("Fail - Role Not Below Actor", func(t *testing.T) {
+		adminReq := req
+		adminReq.Role = string(domain.RoleAdmin)
+
+		id, err := uc.Register(context.Background(), supervisor, adminReq)
+
+		assert.ErrorIs(t, err, usecase.ForbiddenError)
+		assert.Equal(t, "", id)
+	})
+
+	t.Run("Fail - Unknown Role", func(t *testing.T) {
+		unknownReq := req
+		unknownReq.Role = "manager"
+
+		_, err := uc.Register(context.Background(), supervisor, unknownReq)
+
+		assert.ErrorIs(t, err, usecase.InvalidRoleError)
+	})
+}
+
+func TestEmployeeUsecase_UpdateProfile_RoleHierarchy(t *testing.T) {
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, MockClock{currentTime: time.Now()}, time.Minute)
+
+	supervisor := usecase.Actor{ID: "spv-1", Role: domain.RoleSupervisor}
+
+	newStaff := func(id string, role domain.Role) *domain.Employee {
+		emp, _ := domain.ReconstituteEmployee(domain.ReconstituteEmployeeParams{ID: id, Name: "Employee", Role: string(role)})
+		return emp
+	}
+
+	t.Run("Fail - Supervisor Promotes Staff To Admin", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		uc := usecase.NewEmployeeUsecase(mockRepo, new(MockStorageRepo), new(MockIDGenerator), authorizer, testPhotoConfig, MockClock{currentTime: time.Now()}, time.Second)
+
+		target := newStaff("emp-2", domain.RoleStaff)
+		mockRepo.On("FindByID", mock.Anything, "emp-2").Return(target, nil).Once()
+
+		role := string(domain.RoleAdmin)
+		_, err := uc.UpdateProfile(context.Background(), supervisor, "emp-2", employee.UpdateEmployeeRequest{Role: &role}, 0)
+
+		assert.ErrorIs(t, err, usecase.ForbiddenError)
+		assert.Equal(t, domain.RoleStaff, target.Role())
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Fail - Supervisor Edits Another Supervisor", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		uc := usecase.NewEmployeeUsecase(mockRepo, new(MockStorageRepo), new(MockIDGenerator), authorizer, testPhotoConfig, MockClock{currentTime: time.Now()}, time.Second)
+
+		mockRepo.On("FindByID", mock.Anything, "spv-2").Return(newStaff("spv-2", domain.RoleSupervisor), nil).Once()
+
+		name := "Renamed"
+		_, err := uc.UpdateProfile(context.Background(), supervisor, "spv-2", employee.UpdateEmployeeRequest{Name: &name}, 0)
+
+		assert.ErrorIs(t, err, usecase.ForbiddenError)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Admin Promotes Staff To Supervisor", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		uc := usecase.NewEmployeeUsecase(mockRepo, new(MockStorageRepo), new(MockIDGenerator), authorizer, testPhotoConfig, MockClock{currentTime: time.Now()}, time.Second)
+
+		target := newStaff("emp-2", domain.RoleStaff)
+		mockRepo.On("FindByID", mock.Anything, "emp-2").Return(target, nil).Once()
+		mockRepo.On("Update", mock.Anything, target).Return(nil).Once()
+
+		role := string(domain.RoleSupervisor)
+		admin := usecase.Actor{ID: "adm-1", Role: domain.RoleAdmin}
+		_, err := uc.UpdateProfile(context.Background(), admin, "emp-2", employee.UpdateEmployeeRequest{Role: &role}, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RoleSupervisor, target.Role())
+		assert.NotNil(t, target.SessionsRevokedAt())
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestEmployeeUsecase_UpdateProfile_Authorization(t *testing.T) {
+	roles := append(defaultRoles(), &domain.RoleDefinition{
+		Name:        "store_manager",
+		Permissions: []domain.Permission{domain.PermEmployeeRead, domain.PermEmployeeUpdate},
+	})
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(roles, nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, MockClock{currentTime: time.Now()}, time.Minute)
+
+	newName := "Updated Name"
+	req := employee.UpdateEmployeeRequest{Name: &newName}
+
+	t.Run("Fail - Staff Updating Another Employee", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		uc := usecase.NewEmployeeUsecase(mockRepo, new(MockStorageRepo), new(MockIDGenerator), authorizer, testPhotoConfig, MockClock{currentTime: time.Now()}, time.Second)
+
+		actor := usecase.Actor{ID: "emp-1", Role: domain.RoleStaff}
+		_, err := uc.UpdateProfile(context.Background(), actor, "emp-2", req, 0)
+
+		assert.ErrorIs(t, err, usecase.ForbiddenError)
+		mockRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Custom Role With Permission", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		uc := usecase.NewEmployeeUsecase(mockRepo, new(MockStorageRepo), new(MockIDGenerator), authorizer, testPhotoConfig, MockClock{currentTime: time.Now()}, time.Second)
+
+		emp := &domain.Employee{}
+		mockRepo.On("FindByID", mock.Anything, "emp-2").Return(emp, nil).Once()
+		mockRepo.On("Update", mock.Anything, emp).Return(nil).Once()
+
+		actor := usecase.Actor{ID: "emp-1", Role: "store_manager"}
+		_, err := uc.UpdateProfile(context.Background(), actor, "emp-2", req, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, newName, emp.Name())
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestEmployeeViewer_Visibility(t *testing.T) {
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, MockClock{currentTime: time.Now()}, time.Minute)
+
+	newEmployee := func(id, role, store string) *domain.Employee {
+		emp, _ := domain.ReconstituteEmployee(domain.ReconstituteEmployeeParams{
+			ID: id, Name: "Employee", Role: role, StoreID: store,
+			Salary: 5000000, Address: "Jl Test", PhoneNumber: "+628123456789",
+		})
+		return emp
+	}
+
+	cashier := newEmployee("emp-1", "staff", "store-a")
+	otherStoreCashier := newEmployee("emp-2", "staff", "store-b")
+
+	viewerFor := func(t *testing.T, viewer *domain.Employee) *usecase.EmployeeViewer {
+		mockRepo := new(MockEmployeeRepo)
+		mockRepo.On("FindByID", mock.Anything, string(viewer.ID())).Return(viewer, nil).Once()
+		uc := usecase.NewEmployeeUsecase(mockRepo, new(MockStorageRepo), new(MockIDGenerator), authorizer, testPhotoConfig, MockClock{currentTime: time.Now()}, time.Second)
+
+		v, err := uc.Viewer(context.Background(), usecase.Actor{ID: string(viewer.ID()), Role: viewer.Role()})
+		assert.NoError(t, err)
+		return v
+	}
+
+	t.Run("Self Sees Everything", func(t *testing.T) {
+		resp := viewerFor(t, cashier).Response(context.Background(), cashier)
+
+		assert.NotNil(t, resp.Salary)
+		assert.Equal(t, "Jl Test", resp.Address)
+	})
+
+	t.Run("Same Store Supervisor Sees Contact But Not Salary", func(t *testing.T) {
+		resp := viewerFor(t, newEmployee("spv-1", "supervisor", "store-a")).Response(context.Background(), cashier)
+
+		assert.Nil(t, resp.Salary)
+		assert.Equal(t, "Jl Test", resp.Address)
+		assert.Equal(t, "+628123456789", resp.PhoneNumber)
+	})
+
+	t.Run("Other Store Supervisor Sees Neither", func(t *testing.T) {
+		resp := viewerFor(t, newEmployee("spv-1", "supervisor", "store-a")).Response(context.Background(), otherStoreCashier)
+
+		assert.Nil(t, resp.Salary)
+		assert.Empty(t, resp.Address)
+		assert.Empty(t, resp.PhoneNumber)
+	})
+
+	t.Run("Admin Sees Everything In Any Store", func(t *testing.T) {
+		resp := viewerFor(t, newEmployee("adm-1", "admin", "")).Response(context.Background(), otherStoreCashier)
+
+		assert.Equal(t, int64(5000000), *resp.Salary)
+		assert.Equal(t, "Jl Test", resp.Address)
+	})
+}
+
+func TestEmployeeUsecase_PhotoURL(t *testing.T) {
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, MockClock{currentTime: time.Now()}, time.Minute)
+
+	staff := usecase.Actor{ID: "emp-1", Role: domain.RoleStaff}
+
+	withPhoto := func(id, photo string) *domain.Employee {
+		emp, _ := domain.ReconstituteEmployee(domain.ReconstituteEmployeeParams{ID: id, Name: "Employee", Role: "staff", Photo: photo})
+		return emp
+	}
+
+	t.Run("Success - Presigns Own Photo", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		mockStorage := new(MockStorageRepo)
+		uc := usecase.NewEmployeeUsecase(mockRepo, mockStorage, new(MockIDGenerator), authorizer, testPhotoConfig, MockClock{currentTime: time.Now()}, time.Second)
+
+		mockRepo.On("FindByID", mock.Anything, "emp-1").Return(withPhoto("emp-1", "photo-1.jpg"), nil).Once()
+		mockStorage.On("PresignGetURL", mock.Anything, "photo-1.jpg", 15*time.Minute).Return("https://minio/photo-1.jpg?X-Amz-Signature=abc", nil).Once()
+
+		url, err := uc.PhotoURL(context.Background(), staff, "emp-1", false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://minio/photo-1.jpg?X-Amz-Signature=abc", url)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Success - Presigns Thumbnail", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		mockStorage := new(MockStorageRepo)
+		uc := usecase.NewEmployeeUsecase(mockRepo, mockStorage, new(MockIDGenerator), authorizer, testPhotoConfig, MockClock{currentTime: time.Now()}, time.Second)
+
+		mockRepo.On("FindByID", mock.Anything, "emp-1").Return(withPhoto("emp-1", "photos/upload-1.jpg"), nil).Once()
+		mockStorage.On("PresignGetURL", mock.Anything, "photos/upload-1_thumb.jpg", 15*time.Minute).Return("https://minio/photos/upload-1_thumb.jpg?X-Amz-Signature=abc", nil).Once()
+
+		url, err := uc.PhotoURL(context.Background(), staff, "emp-1", true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://minio/photos/upload-1_thumb.jpg?X-Amz-Signature=abc", url)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Fail - No Photo", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		mockStorage := new(MockStorageRepo)
+		uc := usecase.NewEmployeeUsecase(mockRepo, mockStorage, new(MockIDGenerator), authorizer, testPhotoConfig, MockClock{currentTime: time.Now()}, time.Second)
+
+		mockRepo.On("FindByID", mock.Anything, "emp-1").Return(withPhoto("emp-1", ""), nil).Once()
+
+		_, err := uc.PhotoURL(context.Background(), staff, "emp-1", false)
+
+		assert.ErrorIs(t, err, usecase.EmployeePhotoNotFoundError)
+		mockStorage.AssertNotCalled(t, "PresignGetURL", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Fail - Staff Reads Another Employee's Photo", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		mockStorage := new(MockStorageRepo)
+		uc := usecase.NewEmployeeUsecase(mockRepo, mockStorage, new(MockIDGenerator), authorizer, testPhotoConfig, MockClock{currentTime: time.Now()}, time.Second)
+
+		_, err := uc.PhotoURL(context.Background(), staff, "emp-2", false)
+
+		assert.ErrorIs(t, err, usecase.ForbiddenError)
+		mockRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+		mockStorage.AssertNotCalled(t, "PresignGetURL", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Responses Carry Presigned URLs, Not Keys", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		mockStorage := new(MockStorageRepo)
+		uc := usecase.NewEmployeeUsecase(mockRepo, mockStorage, new(MockIDGenerator), authorizer, testPhotoConfig, MockClock{currentTime: time.Now()}, time.Second)
+
+		admin := withPhoto("adm-1", "")
+		mockRepo.On("FindByID", mock.Anything, "adm-1").Return(admin, nil).Once()
+		mockStorage.On("PresignGetURL", mock.Anything, "photo-1.jpg", 15*time.Minute).Return("https://minio/photo-1.jpg?X-Amz-Signature=abc", nil).Once()
+		mockStorage.On("PresignGetURL", mock.Anything, "photo-2.jpg", 15*time.Minute).Return("", errors.New("minio down")).Once()
+
+		viewer, err := uc.Viewer(context.Background(), usecase.Actor{ID: "adm-1", Role: domain.RoleAdmin})
+		assert.NoError(t, err)
+
+		resp := viewer.Responses(context.Background(), []*domain.Employee{withPhoto("emp-1", "photo-1.jpg"), withPhoto("emp-2", "photo-2.jpg"), withPhoto("emp-3", "")})
+
+		assert.Equal(t, "https://minio/photo-1.jpg?X-Amz-Signature=abc", resp[0].Photo)
+		assert.Empty(t, resp[1].Photo, "a failed presign leaves the photo out")
+		assert.Empty(t, resp[2].Photo)
+		mockStorage.AssertExpectations(t)
+	})
+}
+
+// twoToneJPEG encodes a width x height JPEG with a red left half and a blue right half,
+// carrying an EXIF segment with the given orientation and a GPS tag.
+func twoToneJPEG(t *testing.T, width, height int, orientation uint16) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= width/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&buf, img, nil))
+
+	// TIFF header, one IFD with the orientation and a GPS IFD pointer, then junk GPS data
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 2)
+	tiff = append(tiff, 0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0x00, 0x00)
+	tiff = append(tiff, 0x88, 0x25, 0x00, 0x04, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x26)
+	tiff = append(tiff, 0x00, 0x00, 0x00, 0x00)
+	tiff = append(tiff, []byte("GPSLatitude -6.2088 GPSLongitude 106.8456")...)
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+
+	exif := []byte{0xFF, 0xE1}
+	exif = binary.BigEndian.AppendUint16(exif, uint16(len(segment)+2))
+	exif = append(exif, segment...)
+
+	data := buf.Bytes()
+	return append(append(append([]byte{}, data[:2]...), exif...), data[2:]...)
+}
+
+func TestEmployeeUsecase_UploadPhoto(t *testing.T) {
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, MockClock{currentTime: time.Now()}, time.Minute)
+
+	staff := usecase.Actor{ID: "emp-1", Role: domain.RoleStaff}
+
+	setup := func() (*usecase.EmployeeUsecase, *MockEmployeeRepo, *MockStorageRepo, *domain.Employee) {
+		mockRepo := new(MockEmployeeRepo)
+		mockStorage := new(MockStorageRepo)
+		mockIDGen := new(MockIDGenerator)
+		uc := usecase.NewEmployeeUsecase(mockRepo, mockStorage, mockIDGen, authorizer, testPhotoConfig, MockClock{currentTime: time.Now()}, time.Second)
+
+		emp, _ := domain.ReconstituteEmployee(domain.ReconstituteEmployeeParams{ID: "emp-1", Name: "Employee", Role: "staff"})
+		mockRepo.On("FindByID", mock.Anything, "emp-1").Return(emp, nil).Maybe()
+		mockIDGen.On("NewID").Return("upload-1", nil).Maybe()
+		return uc, mockRepo, mockStorage, emp
+	}
+
+	t.Run("Success - Stores Upright Avatar And Thumbnail Without EXIF", func(t *testing.T) {
+		uc, mockRepo, mockStorage, emp := setup()
+
+		stored := map[string][]byte{}
+		capture := func(args mock.Arguments) {
+			data, _ := io.ReadAll(args.Get(3).(io.Reader))
+			stored[args.String(1)] = data
+		}
+		mockStorage.On("UploadFile", mock.Anything, "photos/upload-1_thumb.jpg", "image/jpeg", mock.Anything, mock.Anything).Run(capture).Return("photos/upload-1_thumb.jpg", nil).Once()
+		mockStorage.On("UploadFile", mock.Anything, "photos/upload-1.jpg", "image/jpeg", mock.Anything, mock.Anything).Run(capture).Return("photos/upload-1.jpg", nil).Once()
+		mockRepo.On("Update", mock.Anything, emp).Return(nil).Once()
+
+		// Wider than tall and turned: the phone was held upright, so red ends up on top
+		upload := twoToneJPEG(t, 600, 300, 6)
+		err := uc.UploadPhoto(context.Background(), staff, "emp-1", bytes.NewReader(upload), int64(len(upload)), "image/jpeg", "me.jpg")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "photos/upload-1.jpg", emp.Photo())
+		assert.Equal(t, "photos/upload-1_thumb.jpg", emp.PhotoThumbnail())
+
+		for key, size := range map[string]int{"photos/upload-1.jpg": 256, "photos/upload-1_thumb.jpg": 64} {
+			assert.NotContains(t, string(stored[key]), "Exif", key)
+			assert.NotContains(t, string(stored[key]), "GPS", key)
+
+			img, err := jpeg.Decode(bytes.NewReader(stored[key]))
+			assert.NoError(t, err, key)
+			assert.Equal(t, image.Rect(0, 0, size, size), img.Bounds(), key)
+
+			top, _, _, _ := img.At(size/2, size/8).RGBA()
+			_, _, bottom, _ := img.At(size/2, size-size/8).RGBA()
+			assert.Greater(t, top, uint32(0xC000), "%s should be red on top", key)
+			assert.Greater(t, bottom, uint32(0xC000), "%s should be blue at the bottom", key)
+		}
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Success - Deletes Replaced Photo", func(t *testing.T) {
+		uc, mockRepo, mockStorage, emp := setup()
+		emp.SetPhoto("photos/old.jpg")
+
+		mockStorage.On("UploadFile", mock.Anything, mock.Anything, "image/jpeg", mock.Anything, mock.Anything).Return("", nil).Twice()
+		mockRepo.On("Update", mock.Anything, emp).Return(nil).Once()
+		mockStorage.On("DeleteFile", mock.Anything, "photos/old.jpg").Return(nil).Once()
+		mockStorage.On("DeleteFile", mock.Anything, "photos/old_thumb.jpg").Return(errors.New("minio down")).Once()
+
+		upload := twoToneJPEG(t, 100, 100, 1)
+		err := uc.UploadPhoto(context.Background(), staff, "emp-1", bytes.NewReader(upload), int64(len(upload)), "image/jpeg", "me.jpg")
+
+		assert.NoError(t, err, "a failed delete is left to the cleanup job")
+		assert.Equal(t, "photos/upload-1.jpg", emp.Photo())
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Fail - Update Removes New Objects And Keeps Old Photo", func(t *testing.T) {
+		uc, mockRepo, mockStorage, emp := setup()
+		emp.SetPhoto("photos/old.jpg")
+
+		mockStorage.On("UploadFile", mock.Anything, mock.Anything, "image/jpeg", mock.Anything, mock.Anything).Return("", nil).Twice()
+		mockRepo.On("Update", mock.Anything, emp).Return(errors.New("db down")).Once()
+		mockStorage.On("DeleteFile", mock.Anything, "photos/upload-1.jpg").Return(nil).Once()
+		mockStorage.On("DeleteFile", mock.Anything, "photos/upload-1_thumb.jpg").Return(nil).Once()
+
+		upload := twoToneJPEG(t, 100, 100, 1)
+		err := uc.UploadPhoto(context.Background(), staff, "emp-1", bytes.NewReader(upload), int64(len(upload)), "image/jpeg", "me.jpg")
+
+		assert.Error(t, err)
+		mockStorage.AssertExpectations(t)
+		mockStorage.AssertNotCalled(t, "DeleteFile", mock.Anything, "photos/old.jpg")
+	})
+
+	t.Run("Fail - Dimensions Above Limit", func(t *testing.T) {
+		uc, mockRepo, mockStorage, _ := setup()
+
+		var buf bytes.Buffer
+		assert.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, testPhotoConfig.MaxDimension+1, 1))))
+
+		err := uc.UploadPhoto(context.Background(), staff, "emp-1", &buf, int64(buf.Len()), "image/png", "wide.png")
+
+		assert.ErrorIs(t, err, usecase.InvalidPhotoError)
+		mockStorage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Fail - Upload Above Size Limit", func(t *testing.T) {
+		uc, _, mockStorage, _ := setup()
+
+		err := uc.UploadPhoto(context.Background(), staff, "emp-1", bytes.NewReader(nil), testPhotoConfig.MaxUploadBytes+1, "image/jpeg", "big.jpg")
+
+		assert.ErrorIs(t, err, usecase.InvalidPhotoError)
+		mockStorage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Fail - Not An Image", func(t *testing.T) {
+		uc, _, mockStorage, _ := setup()
+
+		body := []byte("definitely not a jpeg")
+		err := uc.UploadPhoto(context.Background(), staff, "emp-1", bytes.NewReader(body), int64(len(body)), "image/jpeg", "fake.jpg")
+
+		assert.ErrorIs(t, err, usecase.InvalidPhotoError)
+		mockStorage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Fail - Photo Upload Cleans Up Thumbnail", func(t *testing.T) {
+		uc, mockRepo, mockStorage, emp := setup()
+
+		mockStorage.On("UploadFile", mock.Anything, "photos/upload-1_thumb.jpg", "image/jpeg", mock.Anything, mock.Anything).Return("photos/upload-1_thumb.jpg", nil).Once()
+		mockStorage.On("UploadFile", mock.Anything, "photos/upload-1.jpg", "image/jpeg", mock.Anything, mock.Anything).Return("", errors.New("minio down")).Once()
+		mockStorage.On("DeleteFile", mock.Anything, "photos/upload-1_thumb.jpg").Return(nil).Once()
+
+		upload := twoToneJPEG(t, 100, 100, 1)
+		err := uc.UploadPhoto(context.Background(), staff, "emp-1", bytes.NewReader(upload), int64(len(upload)), "image/jpeg", "me.jpg")
+
+		assert.Error(t, err)
+		assert.Empty(t, emp.Photo())
+		mockStorage.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
 }
 
 func TestAttendanceUsecase_CheckIn(t *testing.T) {
 	mockAttRepo := new(MockAttendanceRepo)
 	mockEmpRepo := new(MockEmployeeRepo)
 	mockIDGen := new(MockIDGenerator)
+	mockNotifier := new(MockNotifier)
+	mockPublisher := new(MockEventPublisher)
+	mockPublisher.On("Publish", mock.Anything, domain.WebhookAttendanceCheckedIn, mock.Anything).Return(nil).Maybe()
 
 	// Setup Config & Timezone
 	loc, _ := time.LoadLocation("Asia/Jakarta")
@@ -95,7 +558,7 @@ func TestAttendanceUsecase_CheckIn(t *testing.T) {
 		mockTime := time.Date(2026, 10, 10, 8, 55, 0, 0, loc) // June 10, 2026 08:55:00
 		mockClock := MockClock{currentTime: mockTime}
 
-		uc := usecase.NewAttendanceUsecase(mockAttRepo, mockEmpRepo, mockIDGen, cfg, mockClock, time.Second)
+		uc := usecase.NewAttendanceUsecase(mockAttRepo, mockEmpRepo, new(MockStorageRepo), new(MockKioskRepo), mockIDGen, mockNotifier, mockPublisher, nil, testPhotoConfig, usecase.KioskConfig{}, cfg, mockClock, time.Second)
 
 		emp := &domain.Employee{}
 		mockEmpRepo.On("FindByID", mock.Anything, employeeID).Return(emp, nil).Once()
@@ -108,7 +571,7 @@ func TestAttendanceUsecase_CheckIn(t *testing.T) {
 			return a.IsLate == false && a.EmployeeID == employeeID
 		})).Return(nil).Once()
 
-		id, err := uc.CheckIn(context.Background(), employeeID, req)
+		id, err := uc.CheckIn(context.Background(), employeeID, req, nil)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "att-123", id)
@@ -121,7 +584,7 @@ func TestAttendanceUsecase_CheckIn(t *testing.T) {
 		mockTime := time.Date(2026, 10, 10, 9, 15, 0, 0, loc) // June 10, 2026 09:15:00
 		mockClock := MockClock{currentTime: mockTime}
 
-		uc := usecase.NewAttendanceUsecase(mockAttRepo, mockEmpRepo, mockIDGen, cfg, mockClock, time.Second)
+		uc := usecase.NewAttendanceUsecase(mockAttRepo, mockEmpRepo, new(MockStorageRepo), new(MockKioskRepo), mockIDGen, mockNotifier, mockPublisher, nil, testPhotoConfig, usecase.KioskConfig{}, cfg, mockClock, time.Second)
 
 		emp := &domain.Employee{}
 		mockEmpRepo.On("FindByID", mock.Anything, employeeID).Return(emp, nil).Once()
@@ -134,33 +597,188 @@ func TestAttendanceUsecase_CheckIn(t *testing.T) {
 			return a.IsLate == true && a.EmployeeID == employeeID
 		})).Return(nil).Once()
 
-		id, err := uc.CheckIn(context.Background(), employeeID, req)
+		mockNotifier.On("NotifyAboutEmployee", mock.Anything, domain.EventLateCheckIn, emp, mock.MatchedBy(func(data map[string]string) bool {
+			return data["CheckIn"] == "09:15" && data["Date"] == "2026-10-10" && data["Location"] == "Office HQ"
+		})).Return(nil).Once()
+
+		id, err := uc.CheckIn(context.Background(), employeeID, req, nil)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "att-124", id)
 		mockEmpRepo.AssertExpectations(t)
 		mockAttRepo.AssertExpectations(t)
 		mockIDGen.AssertExpectations(t)
+		mockNotifier.AssertExpectations(t)
 	})
 
 	t.Run("Fail - Already Checked In", func(t *testing.T) {
 		mockTime := time.Date(2026, 10, 10, 8, 55, 0, 0, loc) // June 10, 2026 08:55:00
 		mockClock := MockClock{currentTime: mockTime}
 
-		uc := usecase.NewAttendanceUsecase(mockAttRepo, mockEmpRepo, mockIDGen, cfg, mockClock, time.Second)
+		uc := usecase.NewAttendanceUsecase(mockAttRepo, mockEmpRepo, new(MockStorageRepo), new(MockKioskRepo), mockIDGen, mockNotifier, mockPublisher, nil, testPhotoConfig, usecase.KioskConfig{}, cfg, mockClock, time.Second)
 
 		emp := &domain.Employee{}
 		mockEmpRepo.On("FindByID", mock.Anything, employeeID).Return(emp, nil).Once()
 
-		existingAttendance := &domain.Attendance{}
+		existingAttendance := &domain.Attendance{Sessions: []domain.WorkSession{{CheckIn: mockTime.Add(-time.Hour)}}}
 		mockAttRepo.On("FindByEmployeeIDAndDate", mock.Anything, employeeID, simulateDate).Return(existingAttendance, nil).Once()
 
-		id, err := uc.CheckIn(context.Background(), employeeID, req)
+		id, err := uc.CheckIn(context.Background(), employeeID, req, nil)
 
 		assert.Error(t, err)
 		assert.Equal(t, "", id)
-		assert.Contains(t, err.Error(), "you've already checked in today")
+		assert.Contains(t, err.Error(), "you are already checked in")
 		mockEmpRepo.AssertExpectations(t)
 		mockAttRepo.AssertExpectations(t)
 	})
 }
+
+func TestAttendanceUsecase_ReportMissedCheckOuts(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	cfg := &config.Config{AppTimezone: loc, OfficeStartHour: 9}
+	now := time.Date(2026, 10, 11, 6, 0, 0, 0, loc)
+	today := time.Date(2026, 10, 11, 0, 0, 0, 0, loc)
+
+	t.Run("Success - Notifies About The Session Left Open", func(t *testing.T) {
+		mockAttRepo := new(MockAttendanceRepo)
+		mockEmpRepo := new(MockEmployeeRepo)
+		mockNotifier := new(MockNotifier)
+		uc := usecase.NewAttendanceUsecase(mockAttRepo, mockEmpRepo, new(MockStorageRepo), new(MockKioskRepo), new(MockIDGenerator), mockNotifier, new(MockEventPublisher), nil, testPhotoConfig, usecase.KioskConfig{}, cfg, MockClock{currentTime: now}, time.Second)
+
+		morningOut := time.Date(2026, 10, 10, 12, 0, 0, 0, loc)
+		missing := &domain.Attendance{ID: "att-1", EmployeeID: "emp-1", EmployeeName: "Jane Doe", CheckIn: "2026-10-10 08:50:00", Date: today.AddDate(0, 0, -1),
+			Sessions: []domain.WorkSession{
+				{CheckIn: time.Date(2026, 10, 10, 8, 50, 0, 0, loc), CheckOut: &morningOut},
+				{CheckIn: time.Date(2026, 10, 10, 13, 0, 0, 0, loc)},
+			}}
+		emp := employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive)
+		mockAttRepo.On("FindMissingCheckOut", mock.Anything, today.AddDate(0, 0, -7), today).Return([]*domain.Attendance{missing}, nil).Once()
+		mockEmpRepo.On("FindByID", mock.Anything, "emp-1").Return(emp, nil).Once()
+		mockNotifier.On("NotifyAboutEmployee", mock.Anything, domain.EventMissedCheckOut, emp, mock.MatchedBy(func(data map[string]string) bool {
+			return data["Date"] == "2026-10-10" && data["CheckIn"] == "08:50"
+		})).Return(nil).Once()
+		mockAttRepo.On("MarkMissedCheckOutNotified", mock.Anything, "att-1", 2).Return(nil).Once()
+
+		reported, err := uc.ReportMissedCheckOuts(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, reported)
+		mockAttRepo.AssertExpectations(t)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Fail - Notification Error Leaves Record For Next Run", func(t *testing.T) {
+		mockAttRepo := new(MockAttendanceRepo)
+		mockEmpRepo := new(MockEmployeeRepo)
+		mockNotifier := new(MockNotifier)
+		uc := usecase.NewAttendanceUsecase(mockAttRepo, mockEmpRepo, new(MockStorageRepo), new(MockKioskRepo), new(MockIDGenerator), mockNotifier, new(MockEventPublisher), nil, testPhotoConfig, usecase.KioskConfig{}, cfg, MockClock{currentTime: now}, time.Second)
+
+		missing := &domain.Attendance{ID: "att-1", EmployeeID: "emp-1", CheckIn: "2026-10-10 08:50:00", Date: today.AddDate(0, 0, -1)}
+		mockAttRepo.On("FindMissingCheckOut", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Attendance{missing}, nil).Once()
+		mockEmpRepo.On("FindByID", mock.Anything, "emp-1").Return(employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive), nil).Once()
+		mockNotifier.On("NotifyAboutEmployee", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db down")).Once()
+
+		reported, err := uc.ReportMissedCheckOuts(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, reported)
+		mockAttRepo.AssertNotCalled(t, "MarkMissedCheckOutNotified", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestEmployeeUsecase_Delete(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, MockClock{currentTime: now}, time.Minute)
+	admin := usecase.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+	t.Run("Success - Soft Deletes And Revokes Sessions", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		uc := usecase.NewEmployeeUsecase(mockRepo, new(MockStorageRepo), new(MockIDGenerator), authorizer, testPhotoConfig, MockClock{currentTime: now}, time.Second)
+
+		emp := employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive)
+		mockRepo.On("FindByID", mock.Anything, "emp-1").Return(emp, nil).Once()
+		mockRepo.On("Delete", mock.Anything, emp).Return(nil).Once()
+
+		err := uc.Delete(context.Background(), admin, "emp-1", 0)
+
+		assert.NoError(t, err)
+		assert.True(t, emp.IsDeleted())
+		assert.Equal(t, domain.StatusActive, emp.Status())
+		assert.False(t, emp.IsSessionValid(now.Add(-time.Minute)))
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestEmployeeUsecase_OptimisticConcurrency(t *testing.T) {
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, MockClock{currentTime: time.Now()}, time.Minute)
+	supervisor := usecase.Actor{ID: "spv-1", Role: domain.RoleSupervisor}
+
+	newCashier := func(version int64) *domain.Employee {
+		emp, _ := domain.ReconstituteEmployee(domain.ReconstituteEmployeeParams{
+			ID: "emp-2", Name: "Cashier", Role: string(domain.RoleStaff), Status: string(domain.StatusActive), Version: version,
+		})
+		return emp
+	}
+
+	name := "Renamed"
+	req := employee.UpdateEmployeeRequest{Name: &name}
+
+	t.Run("Success - Returns New Version", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		uc := usecase.NewEmployeeUsecase(mockRepo, new(MockStorageRepo), new(MockIDGenerator), authorizer, testPhotoConfig, MockClock{currentTime: time.Now()}, time.Second)
+
+		target := newCashier(3)
+		mockRepo.On("FindByID", mock.Anything, "emp-2").Return(target, nil).Once()
+		mockRepo.On("Update", mock.Anything, target).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Employee).SetVersion(4)
+		}).Return(nil).Once()
+
+		version, err := uc.UpdateProfile(context.Background(), supervisor, "emp-2", req, 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), version)
+	})
+
+	t.Run("Fail - Stale Version", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		uc := usecase.NewEmployeeUsecase(mockRepo, new(MockStorageRepo), new(MockIDGenerator), authorizer, testPhotoConfig, MockClock{currentTime: time.Now()}, time.Second)
+
+		mockRepo.On("FindByID", mock.Anything, "emp-2").Return(newCashier(4), nil).Once()
+
+		_, err := uc.UpdateProfile(context.Background(), supervisor, "emp-2", req, 3)
+
+		assert.ErrorIs(t, err, usecase.EmployeeVersionConflictError)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Fail - Concurrent Write Rejected By Repository", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		uc := usecase.NewEmployeeUsecase(mockRepo, new(MockStorageRepo), new(MockIDGenerator), authorizer, testPhotoConfig, MockClock{currentTime: time.Now()}, time.Second)
+
+		target := newCashier(3)
+		mockRepo.On("FindByID", mock.Anything, "emp-2").Return(target, nil).Once()
+		mockRepo.On("Update", mock.Anything, target).Return(domain.ErrVersionConflict).Once()
+
+		_, err := uc.UpdateProfile(context.Background(), supervisor, "emp-2", req, 3)
+
+		assert.ErrorIs(t, err, usecase.EmployeeVersionConflictError)
+	})
+
+	t.Run("Fail - Delete With Stale Version", func(t *testing.T) {
+		mockRepo := new(MockEmployeeRepo)
+		uc := usecase.NewEmployeeUsecase(mockRepo, new(MockStorageRepo), new(MockIDGenerator), authorizer, testPhotoConfig, MockClock{currentTime: time.Now()}, time.Second)
+
+		target := newCashier(4)
+		mockRepo.On("FindByID", mock.Anything, "emp-2").Return(target, nil).Once()
+
+		err := uc.Delete(context.Background(), supervisor, "emp-2", 3)
+
+		assert.ErrorIs(t, err, usecase.EmployeeVersionConflictError)
+		assert.False(t, target.IsDeleted())
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
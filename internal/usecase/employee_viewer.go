@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+// EmployeeViewer shapes employee responses for one actor, so get, list and export
+// all hide the same fields and hand out photos as presigned URLs.
+type EmployeeViewer struct {
+	viewer   *domain.Employee
+	role     domain.Role
+	policy   domain.Policy
+	photoURL func(ctx context.Context, key string) string
+}
+
+// Viewer loads the actor and the current policy once per request.
+func (uc *EmployeeUsecase) Viewer(ctx context.Context, actor Actor) (*EmployeeViewer, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	viewer, err := uc.repo.FindByID(ctx, actor.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find viewer: %w", err)
+	}
+	if viewer == nil {
+		return nil, EmployeeNotFoundError
+	}
+
+	policy, err := uc.authorizer.Policy(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmployeeViewer{
+		viewer:   viewer,
+		role:     actor.Role,
+		policy:   policy,
+		photoURL: uc.photoURL,
+	}, nil
+}
+
+func (v *EmployeeViewer) Visibility(target *domain.Employee) domain.FieldVisibility {
+	return domain.VisibleFields(v.policy, v.role, domain.RelationshipBetween(v.viewer, target))
+}
+
+func (v *EmployeeViewer) Response(ctx context.Context, target *domain.Employee) *EmployeeResponse {
+	resp := FromDomainWithVisibility(target, v.Visibility(target))
+	if resp != nil {
+		resp.Photo = v.photoURL(ctx, target.Photo())
+		resp.PhotoThumb = v.photoURL(ctx, target.PhotoThumbnail())
+	}
+	return resp
+}
+
+func (v *EmployeeViewer) Responses(ctx context.Context, targets []*domain.Employee) []*EmployeeResponse {
+	resp := make([]*EmployeeResponse, 0, len(targets))
+	for _, target := range targets {
+		resp = append(resp, v.Response(ctx, target))
+	}
+	return resp
+}
@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type ErasureAuditRepository interface {
+	Save(ctx context.Context, record *domain.ErasureRecord) error
+	FindByEmployeeID(ctx context.Context, employeeID string) ([]*domain.ErasureRecord, error)
+}
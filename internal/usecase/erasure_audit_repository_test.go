@@ -0,0 +1,25 @@
+package usecase_test
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type MockErasureAuditRepo struct {
+	mock.Mock
+}
+
+func (m *MockErasureAuditRepo) Save(ctx context.Context, record *domain.ErasureRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockErasureAuditRepo) FindByEmployeeID(ctx context.Context, employeeID string) ([]*domain.ErasureRecord, error) {
+	args := m.Called(ctx, employeeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ErasureRecord), args.Error(1)
+}
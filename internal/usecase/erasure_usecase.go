@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+)
+
+// ErasureUsecase removes the personal data of former employees, either when the retention
+// period after their termination has passed or when an administrator requests it.
+type ErasureUsecase struct {
+	employeeRepo   EmployeeRepository
+	attendanceRepo AttendanceRepository
+	storageRepo    StorageRepository
+	twoFactorRepo  TwoFactorRepository
+	throttleRepo   LoginThrottleRepository
+	exportRepo     DataExportRepository
+	documentRepo   DocumentRepository
+	kioskPINRepo   KioskPINRepository
+	auditRepo      ErasureAuditRepository
+	authorizer     *Authorizer
+	idGen          IDGenerator
+	clock          clock.Clock
+	retention      time.Duration
+	ctxTimeout     time.Duration
+}
+
+func NewErasureUsecase(employeeRepo EmployeeRepository, attendanceRepo AttendanceRepository, storageRepo StorageRepository, twoFactorRepo TwoFactorRepository, throttleRepo LoginThrottleRepository, exportRepo DataExportRepository, documentRepo DocumentRepository, kioskPINRepo KioskPINRepository, auditRepo ErasureAuditRepository, authorizer *Authorizer, idGen IDGenerator, clk clock.Clock, retention time.Duration, timeout time.Duration) *ErasureUsecase {
+	return &ErasureUsecase{
+		employeeRepo:   employeeRepo,
+		attendanceRepo: attendanceRepo,
+		storageRepo:    storageRepo,
+		twoFactorRepo:  twoFactorRepo,
+		throttleRepo:   throttleRepo,
+		exportRepo:     exportRepo,
+		documentRepo:   documentRepo,
+		kioskPINRepo:   kioskPINRepo,
+		auditRepo:      auditRepo,
+		authorizer:     authorizer,
+		idGen:          idGen,
+		clock:          clk,
+		retention:      retention,
+		ctxTimeout:     timeout,
+	}
+}
+
+// Erase erases the personal data of an employee on request of an administrator.
+func (uc *ErasureUsecase) Erase(ctx context.Context, actor Actor, employeeID string, reason string) (*domain.ErasureRecord, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermEmployeeErase); err != nil {
+		return nil, err
+	}
+
+	if actor.IsSelf(employeeID) {
+		return nil, ForbiddenError
+	}
+
+	employee, err := uc.employeeRepo.FindByIDIncludingDeleted(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find employee: %w", err)
+	}
+	if employee == nil {
+		return nil, EmployeeNotFoundError
+	}
+
+	if employee.IsAnonymized() {
+		return nil, EmployeeAlreadyErasedError
+	}
+
+	if err := uc.authorizer.AuthorizeHierarchy(ctx, actor, employee); err != nil {
+		return nil, err
+	}
+
+	return uc.erase(ctx, employee, domain.ErasureTriggerRequest, actor.ID, reason)
+}
+
+// History returns the erasure audit records of an employee.
+func (uc *ErasureUsecase) History(ctx context.Context, actor Actor, employeeID string) ([]*domain.ErasureRecord, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermEmployeeErase); err != nil {
+		return nil, err
+	}
+
+	records, err := uc.auditRepo.FindByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find erasure records: %w", err)
+	}
+
+	return records, nil
+}
+
+// ApplyRetention erases every employee that was deleted longer ago than the retention
+// period. A failure on one employee is logged and does not stop the others; they are
+// retried on the next run. It returns the number of erased employees.
+func (uc *ErasureUsecase) ApplyRetention(ctx context.Context) (int, error) {
+	cutoff := uc.clock.Now().Add(-uc.retention)
+
+	listCtx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	expired, err := uc.employeeRepo.FindRetentionExpired(listCtx, cutoff)
+	cancel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to find employees past retention: %w", err)
+	}
+
+	erased := 0
+	for _, employee := range expired {
+		eraseCtx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+		_, err := uc.erase(eraseCtx, employee, domain.ErasureTriggerRetention, "", "retention period expired")
+		cancel()
+		if err != nil {
+			slog.Log(ctx, slog.LevelError, "Failed to erase employee past retention", "ID", employee.ID(), "error", err)
+			continue
+		}
+		erased++
+	}
+
+	return erased, nil
+}
+
+// erase removes the personal data of the employee everywhere it is kept. The employee row
+// is anonymised last so a failed erasure is picked up again by the next retention run.
+func (uc *ErasureUsecase) erase(ctx context.Context, employee *domain.Employee, trigger domain.ErasureTrigger, requestedBy string, reason string) (*domain.ErasureRecord, error) {
+	employeeID := string(employee.ID())
+
+	pseudonym, err := uc.idGen.NewID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate pseudonym: %w", err)
+	}
+
+	// Check-in and check-out photos show the employee, the records only keep the times.
+	// They go first, pseudonymized records no longer reference them.
+	attendances, err := uc.attendanceRepo.FindByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find attendance records: %w", err)
+	}
+	for _, attendance := range attendances {
+		for _, file := range attendance.PhotoFiles() {
+			if err := uc.storageRepo.DeleteFile(ctx, file); err != nil {
+				return nil, fmt.Errorf("failed to delete attendance photo: %w", err)
+			}
+		}
+	}
+
+	attendanceCount, err := uc.attendanceRepo.PseudonymizeEmployee(ctx, employeeID, "erased-"+pseudonym)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pseudonymize attendance records: %w", err)
+	}
+
+	photoDeleted := false
+	for _, file := range employee.PhotoFiles() {
+		if err := uc.storageRepo.DeleteFile(ctx, file); err != nil {
+			return nil, fmt.Errorf("failed to delete photo: %w", err)
+		}
+		photoDeleted = true
+	}
+
+	// Data export archives hold a copy of everything that is erased here
+	exports, err := uc.exportRepo.FindByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find data exports: %w", err)
+	}
+	for _, export := range exports {
+		if export.FileURL == "" {
+			continue
+		}
+		if err := uc.storageRepo.DeleteFile(ctx, export.FileURL); err != nil {
+			return nil, fmt.Errorf("failed to delete data export archive: %w", err)
+		}
+		export.Expire()
+		if err := uc.exportRepo.Save(ctx, export); err != nil {
+			return nil, fmt.Errorf("failed to save data export: %w", err)
+		}
+	}
+
+	// Contracts and ID scans are personal data as well, they go with every version
+	documents, err := uc.documentRepo.FindByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find documents: %w", err)
+	}
+	for _, document := range documents {
+		if err := uc.storageRepo.DeleteFile(ctx, document.StorageKey); err != nil {
+			return nil, fmt.Errorf("failed to delete document: %w", err)
+		}
+	}
+	if err := uc.documentRepo.DeleteByEmployeeID(ctx, employeeID); err != nil {
+		return nil, fmt.Errorf("failed to delete documents: %w", err)
+	}
+
+	if err := uc.twoFactorRepo.Delete(ctx, employeeID); err != nil {
+		return nil, fmt.Errorf("failed to delete two factor enrollment: %w", err)
+	}
+
+	if err := uc.kioskPINRepo.DeleteByEmployeeID(ctx, employeeID); err != nil {
+		return nil, fmt.Errorf("failed to delete kiosk PIN: %w", err)
+	}
+
+	if err := uc.throttleRepo.DeleteByKey(ctx, domain.LoginThrottleKey(domain.ThrottleScopeAccount, string(employee.Email()))); err != nil {
+		return nil, fmt.Errorf("failed to delete login throttle: %w", err)
+	}
+
+	now := uc.clock.Now().UTC()
+	erasedFields := employee.Anonymize(now)
+
+	if err := uc.employeeRepo.Anonymize(ctx, employee); err != nil {
+		return nil, fmt.Errorf("failed to anonymize employee: %w", err)
+	}
+
+	auditID, err := uc.idGen.NewID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate audit ID: %w", err)
+	}
+
+	record := &domain.ErasureRecord{
+		ID:                auditID,
+		EmployeeID:        employeeID,
+		Trigger:           trigger,
+		RequestedBy:       requestedBy,
+		Reason:            reason,
+		ErasedFields:      erasedFields,
+		AttendanceRecords: attendanceCount,
+		PhotoDeleted:      photoDeleted,
+		ErasedAt:          now,
+	}
+
+	if err := uc.auditRepo.Save(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to save erasure audit record: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Erased employee personal data", "ID", employeeID, "trigger", trigger)
+
+	return record, nil
+}
@@ -0,0 +1,208 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+func expectErasure(emp *domain.Employee, mockRepo *MockEmployeeRepo, mockAttendanceRepo *MockAttendanceRepo, mockStorageRepo *MockStorageRepo, mockTwoFactorRepo *MockTwoFactorRepo, mockThrottleRepo *MockLoginThrottleRepo, mockExportRepo *MockDataExportRepo, mockDocumentRepo *MockDocumentRepo, mockKioskPINRepo *MockKioskPINRepo, mockAuditRepo *MockErasureAuditRepo, mockIDGen *MockIDGenerator) {
+	id := string(emp.ID())
+	mockIDGen.On("NewID").Return("generated-id", nil)
+	mockAttendanceRepo.On("FindByEmployeeID", mock.Anything, id).Return([]*domain.Attendance{{ID: "att-1", EmployeeID: id, Sessions: []domain.WorkSession{{CheckInPhoto: "attendance/photo-1.jpg"}}}}, nil).Once()
+	mockStorageRepo.On("DeleteFile", mock.Anything, "attendance/photo-1.jpg").Return(nil).Once()
+	mockAttendanceRepo.On("PseudonymizeEmployee", mock.Anything, id, "erased-generated-id").Return(int64(3), nil).Once()
+	if emp.Photo() != "" {
+		mockStorageRepo.On("DeleteFile", mock.Anything, emp.Photo()).Return(nil).Once()
+	}
+	mockExportRepo.On("FindByEmployeeID", mock.Anything, id).Return([]*domain.DataExport{}, nil).Once()
+	documentKey := "documents/" + id + "/doc-1.pdf"
+	mockDocumentRepo.On("FindByEmployeeID", mock.Anything, id).Return([]*domain.Document{{ID: "doc-1", EmployeeID: id, StorageKey: documentKey}}, nil).Once()
+	mockStorageRepo.On("DeleteFile", mock.Anything, documentKey).Return(nil).Once()
+	mockDocumentRepo.On("DeleteByEmployeeID", mock.Anything, id).Return(nil).Once()
+	mockTwoFactorRepo.On("Delete", mock.Anything, id).Return(nil).Once()
+	mockKioskPINRepo.On("DeleteByEmployeeID", mock.Anything, id).Return(nil).Once()
+	mockThrottleRepo.On("DeleteByKey", mock.Anything, "account:"+string(emp.Email())).Return(nil).Once()
+	mockRepo.On("Anonymize", mock.Anything, emp).Return(nil).Once()
+	mockAuditRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.ErasureRecord")).Return(nil).Once()
+}
+
+func erasableEmployee(id string, role domain.Role, terminatedAt *time.Time) *domain.Employee {
+	birthDate := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	status := domain.StatusActive
+	if terminatedAt != nil {
+		status = domain.StatusTerminated
+	}
+	emp, _ := domain.ReconstituteEmployee(domain.ReconstituteEmployeeParams{
+		ID:           id,
+		Name:         "Jane Doe",
+		Email:        "jane@shop.local",
+		Role:         string(role),
+		Status:       string(status),
+		Salary:       5000,
+		BirthDate:    &birthDate,
+		Address:      "Jl. Sudirman 1",
+		PhoneNumber:  "08123456789",
+		Photo:        "http://minio/employees/photo.jpg",
+		StoreID:      "store-1",
+		TerminatedAt: terminatedAt,
+	})
+	return emp
+}
+
+func TestErasureUsecase_Erase(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	mockRepo := new(MockEmployeeRepo)
+	mockAttendanceRepo := new(MockAttendanceRepo)
+	mockStorageRepo := new(MockStorageRepo)
+	mockTwoFactorRepo := new(MockTwoFactorRepo)
+	mockThrottleRepo := new(MockLoginThrottleRepo)
+	mockExportRepo := new(MockDataExportRepo)
+	mockDocumentRepo := new(MockDocumentRepo)
+	mockKioskPINRepo := new(MockKioskPINRepo)
+	mockAuditRepo := new(MockErasureAuditRepo)
+	mockIDGen := new(MockIDGenerator)
+	uc := usecase.NewErasureUsecase(mockRepo, mockAttendanceRepo, mockStorageRepo, mockTwoFactorRepo, mockThrottleRepo, mockExportRepo, mockDocumentRepo, mockKioskPINRepo, mockAuditRepo, authorizer, mockIDGen, clk, 365*24*time.Hour, 2*time.Second)
+
+	admin := usecase.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+	supervisor := usecase.Actor{ID: "spv-1", Role: domain.RoleSupervisor}
+
+	erased := erasableEmployee("emp-1", domain.RoleStaff, nil)
+	erased.Anonymize(now)
+
+	// Only the calls a case expects are mocked; anything else, such as
+	// anonymizing after a failed photo deletion, fails the mock.
+	tests := []struct {
+		name    string
+		actor   usecase.Actor
+		emp     *domain.Employee
+		setup   func(emp *domain.Employee)
+		wantErr error
+		check   func(t *testing.T, record *domain.ErasureRecord, emp *domain.Employee)
+	}{
+		{
+			name:  "Success - Erases Everywhere And Audits",
+			actor: admin,
+			emp:   erasableEmployee("emp-1", domain.RoleStaff, nil),
+			setup: func(emp *domain.Employee) {
+				mockRepo.On("FindByIDIncludingDeleted", mock.Anything, "emp-1").Return(emp, nil).Once()
+				expectErasure(emp, mockRepo, mockAttendanceRepo, mockStorageRepo, mockTwoFactorRepo, mockThrottleRepo, mockExportRepo, mockDocumentRepo, mockKioskPINRepo, mockAuditRepo, mockIDGen)
+			},
+			check: func(t *testing.T, record *domain.ErasureRecord, emp *domain.Employee) {
+				assert.Equal(t, domain.ErasureTriggerRequest, record.Trigger)
+				assert.Equal(t, "admin-1", record.RequestedBy)
+				assert.Equal(t, int64(3), record.AttendanceRecords)
+				assert.True(t, record.PhotoDeleted)
+				assert.Contains(t, record.ErasedFields, "phone_number")
+
+				assert.True(t, emp.IsAnonymized())
+				assert.Empty(t, emp.Address())
+				assert.Empty(t, emp.PhoneNumber())
+				assert.Nil(t, emp.BirthDate())
+				assert.NotContains(t, string(emp.Email()), "jane")
+				assert.Equal(t, "store-1", emp.StoreID())
+			},
+		},
+		{
+			name:    "Fail - Not Allowed For Supervisor",
+			actor:   supervisor,
+			emp:     erasableEmployee("emp-1", domain.RoleStaff, nil),
+			setup:   func(*domain.Employee) {},
+			wantErr: usecase.ForbiddenError,
+		},
+		{
+			name:  "Fail - Already Erased",
+			actor: admin,
+			emp:   erased,
+			setup: func(emp *domain.Employee) {
+				mockRepo.On("FindByIDIncludingDeleted", mock.Anything, "emp-1").Return(emp, nil).Once()
+			},
+			wantErr: usecase.EmployeeAlreadyErasedError,
+		},
+		{
+			name:  "Fail - Photo Deletion Keeps Employee Intact",
+			actor: admin,
+			emp:   erasableEmployee("emp-1", domain.RoleStaff, nil),
+			setup: func(emp *domain.Employee) {
+				mockRepo.On("FindByIDIncludingDeleted", mock.Anything, "emp-1").Return(emp, nil).Once()
+				mockAttendanceRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return([]*domain.Attendance{}, nil).Once()
+				mockAttendanceRepo.On("PseudonymizeEmployee", mock.Anything, "emp-1", "erased-generated-id").Return(int64(0), nil).Once()
+				mockStorageRepo.On("DeleteFile", mock.Anything, emp.Photo()).Return(assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+			check: func(t *testing.T, _ *domain.ErasureRecord, emp *domain.Employee) {
+				assert.False(t, emp.IsAnonymized())
+			},
+		},
+	}
+
+	mockIDGen.On("NewID").Return("generated-id", nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(tt.emp)
+
+			record, err := uc.Erase(context.Background(), tt.actor, "emp-1", "employee request")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.check != nil {
+				tt.check(t, record, tt.emp)
+			}
+			mockRepo.AssertExpectations(t)
+			mockAttendanceRepo.AssertExpectations(t)
+			mockStorageRepo.AssertExpectations(t)
+			mockDocumentRepo.AssertExpectations(t)
+			mockAuditRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestErasureUsecase_ApplyRetention(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	mockRepo := new(MockEmployeeRepo)
+	mockAttendanceRepo := new(MockAttendanceRepo)
+	mockStorageRepo := new(MockStorageRepo)
+	mockTwoFactorRepo := new(MockTwoFactorRepo)
+	mockThrottleRepo := new(MockLoginThrottleRepo)
+	mockExportRepo := new(MockDataExportRepo)
+	mockDocumentRepo := new(MockDocumentRepo)
+	mockKioskPINRepo := new(MockKioskPINRepo)
+	mockAuditRepo := new(MockErasureAuditRepo)
+	mockIDGen := new(MockIDGenerator)
+	uc := usecase.NewErasureUsecase(mockRepo, mockAttendanceRepo, mockStorageRepo, mockTwoFactorRepo, mockThrottleRepo, mockExportRepo, mockDocumentRepo, mockKioskPINRepo, mockAuditRepo, authorizer, mockIDGen, clk, 365*24*time.Hour, 2*time.Second)
+
+	terminatedAt := now.AddDate(-2, 0, 0)
+	emp := erasableEmployee("emp-1", domain.RoleStaff, &terminatedAt)
+	mockRepo.On("FindRetentionExpired", mock.Anything, now.Add(-365*24*time.Hour)).Return([]*domain.Employee{emp}, nil).Once()
+	expectErasure(emp, mockRepo, mockAttendanceRepo, mockStorageRepo, mockTwoFactorRepo, mockThrottleRepo, mockExportRepo, mockDocumentRepo, mockKioskPINRepo, mockAuditRepo, mockIDGen)
+
+	erased, err := uc.ApplyRetention(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, erased)
+	assert.True(t, emp.IsAnonymized())
+	mockAuditRepo.AssertCalled(t, "Save", mock.Anything, mock.MatchedBy(func(r *domain.ErasureRecord) bool {
+		return r.Trigger == domain.ErasureTriggerRetention && r.RequestedBy == ""
+	}))
+}
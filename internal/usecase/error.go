@@ -1,7 +1,88 @@
 package usecase
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
-	EmployeeNotFoundError = errors.New("employee not found")
+	EmployeeNotFoundError   = errors.New("employee not found")
+	InvalidCredentialsError = errors.New("invalid email or password")
+	ForbiddenError          = errors.New("access denied: insufficient permissions")
+
+	EmployeeVersionConflictError = errors.New("employee has been modified since it was read")
+	EmployeeNotActiveError       = errors.New("employee is not active")
+	EmployeePhotoNotFoundError   = errors.New("employee has no photo")
+	InvalidPhotoError            = errors.New("invalid photo")
+	StoredFileNotFoundError      = errors.New("stored file not found")
+
+	InvalidDateRangeError = errors.New("invalid date range")
+
+	AlreadyCheckedInError   = errors.New("you are already checked in")
+	NotCheckedInError       = errors.New("you are not checked in")
+	AlreadyOnBreakError     = errors.New("you are already on a break")
+	NotOnBreakError         = errors.New("you are not on a break")
+	PunchOutOfOrderError    = errors.New("punch cannot be before the previous punch of the day")
+	AttendanceConflictError = errors.New("attendance was changed by another punch, please try again")
+
+	KioskNotFoundError      = errors.New("kiosk not found")
+	InvalidKioskError       = errors.New("invalid kiosk")
+	InvalidKioskTokenError  = errors.New("invalid or revoked kiosk token")
+	InvalidKioskQRError     = errors.New("invalid or expired kiosk QR code")
+	KioskStoreMismatchError = errors.New("kiosk belongs to another store")
+	InvalidKioskPINError    = errors.New("invalid employee or PIN")
+	InvalidKioskSyncError   = errors.New("invalid kiosk sync batch")
+	KioskClockSkewError     = errors.New("kiosk clock is too far off")
+
+	DocumentNotFoundError = errors.New("document not found")
+	InvalidDocumentError  = errors.New("invalid document")
+
+	// Inbound events failing with these are dead-lettered right away, retrying cannot help
+	UnknownInboundEventError = errors.New("unknown inbound event type")
+	InvalidInboundEventError = errors.New("invalid inbound event")
+
+	WebhookSubscriptionNotFoundError = errors.New("webhook subscription not found")
+	InvalidWebhookSubscriptionError  = errors.New("invalid webhook subscription")
+	WebhookDeliveryNotFoundError     = errors.New("webhook delivery not found")
+	WebhookDeliveryNotDeadError      = errors.New("webhook delivery is not dead")
+
+	NotificationNotFoundError           = errors.New("notification not found")
+	InvalidNotificationPreferencesError = errors.New("invalid notification preferences")
+
+	EmailUnchangedError          = errors.New("new email address is the same as the current one")
+	InvalidEmailChangeTokenError = errors.New("invalid or expired email verification link")
+
+	EmployeeAlreadyErasedError = errors.New("employee personal data has already been erased")
+
+	EmployeeNotDeletedError      = errors.New("employee is not deleted")
+	EmployeeContactConflictError = errors.New("another employee already uses the same contact details")
+
+	InvalidStatusTransitionError      = errors.New("status transition is not allowed")
+	StatusChangeAlreadyScheduledError = errors.New("another status change is already scheduled for this employee")
+	SessionRevokedError               = errors.New("session has been revoked")
+
+	DataExportNotFoundError = errors.New("data export not found")
+	DataExportNotReadyError = errors.New("data export is not ready yet")
+	DataExportExpiredError  = errors.New("data export download has expired")
+
+	InvalidTwoFactorCodeError    = errors.New("invalid two factor code")
+	TwoFactorNotEnrolledError    = errors.New("two factor authentication is not enrolled")
+	TwoFactorAlreadyEnabledError = errors.New("two factor authentication is already enabled")
+	TwoFactorRequiredError       = errors.New("two factor authentication is required for this role")
+	InvalidChallengeTokenError   = errors.New("invalid or expired two factor challenge")
+
+	RoleNotFoundError = errors.New("role not found")
+	InvalidRoleError  = errors.New("invalid role")
+	BuiltInRoleError  = errors.New("built-in roles cannot be deleted")
+	RoleInUseError    = errors.New("role is still assigned to employees")
 )
+
+// LoginLockedError is returned when an account or client IP is throttled after failed logins.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, retry after %s", e.RetryAfter.Round(time.Second))
+}
@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+)
+
+// storeClosedData is the data of a store.closed event.
+type storeClosedData struct {
+	StoreID       string                    `json:"store_id"`
+	Action        domain.StoreClosureAction `json:"action"`
+	TargetStoreID string                    `json:"target_store_id"`
+	Reason        string                    `json:"reason"`
+}
+
+// cashierSessionOpenedData is the data of a pos.cashier_session_opened event.
+type cashierSessionOpenedData struct {
+	EmployeeID string    `json:"employee_id"`
+	StoreID    string    `json:"store_id"`
+	RegisterID string    `json:"register_id"`
+	OpenedAt   time.Time `json:"opened_at"`
+}
+
+// InboundEventUsecase reacts to events published by other shop-retail services. Every
+// handler is idempotent on its own, and events already handled are skipped by ID, so
+// the broker may deliver a message more than once.
+type InboundEventUsecase struct {
+	processedRepo ProcessedEventRepository
+	employeeRepo  EmployeeRepository
+	lifecycle     *LifecycleUsecase
+	attendance    *AttendanceUsecase
+	clock         clock.Clock
+	ctxTimeout    time.Duration
+}
+
+func NewInboundEventUsecase(processedRepo ProcessedEventRepository, employeeRepo EmployeeRepository, lifecycle *LifecycleUsecase, attendance *AttendanceUsecase, clk clock.Clock, timeout time.Duration) *InboundEventUsecase {
+	return &InboundEventUsecase{
+		processedRepo: processedRepo,
+		employeeRepo:  employeeRepo,
+		lifecycle:     lifecycle,
+		attendance:    attendance,
+		clock:         clk,
+		ctxTimeout:    timeout,
+	}
+}
+
+// Handle handles one inbound event. Errors wrapping UnknownInboundEventError or
+// InvalidInboundEventError are permanent; any other error may go away on a retry.
+func (uc *InboundEventUsecase) Handle(ctx context.Context, event *domain.InboundEvent) error {
+	if event.ID == "" {
+		return fmt.Errorf("%w: missing event ID", InvalidInboundEventError)
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	processed, err := uc.processedRepo.Exists(checkCtx, event.ID)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to check processed event: %w", err)
+	}
+	if processed {
+		slog.Log(ctx, slog.LevelInfo, "Skipped inbound event handled before", "ID", event.ID, "type", event.Type)
+		return nil
+	}
+
+	switch event.Type {
+	case domain.InboundStoreClosed:
+		err = uc.handleStoreClosed(ctx, event)
+	case domain.InboundCashierSessionOpened:
+		err = uc.handleCashierSessionOpened(ctx, event)
+	default:
+		return fmt.Errorf("%w: %s", UnknownInboundEventError, event.Type)
+	}
+	if err != nil {
+		return err
+	}
+
+	saveCtx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+	if err := uc.processedRepo.Save(saveCtx, event, uc.clock.Now()); err != nil {
+		// The handlers are idempotent, handling the event again does no harm
+		return fmt.Errorf("failed to save processed event: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Handled inbound event", "ID", event.ID, "type", event.Type, "source", event.Source)
+
+	return nil
+}
+
+// handleStoreClosed suspends the employees of a closed store, or transfers them to
+// another store. Employees already suspended or transferred are left alone, so a partly
+// handled event can be retried.
+func (uc *InboundEventUsecase) handleStoreClosed(ctx context.Context, event *domain.InboundEvent) error {
+	var data storeClosedData
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return fmt.Errorf("%w: %w", InvalidInboundEventError, err)
+	}
+	if data.StoreID == "" {
+		return fmt.Errorf("%w: missing store_id", InvalidInboundEventError)
+	}
+	switch data.Action {
+	case domain.StoreClosureSuspend:
+	case domain.StoreClosureTransfer:
+		if data.TargetStoreID == "" || data.TargetStoreID == data.StoreID {
+			return fmt.Errorf("%w: transfer needs a different target_store_id", InvalidInboundEventError)
+		}
+	default:
+		return fmt.Errorf("%w: unknown store closure action %q", InvalidInboundEventError, data.Action)
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	employees, err := uc.employeeRepo.FindAll(listCtx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to find employees: %w", err)
+	}
+
+	reason := "store " + data.StoreID + " closed"
+	if data.Reason != "" {
+		reason += ": " + data.Reason
+	}
+
+	var failed []error
+	for _, employee := range employees {
+		if employee.StoreID() != data.StoreID || employee.Status() == domain.StatusTerminated {
+			continue
+		}
+
+		var err error
+		if data.Action == domain.StoreClosureSuspend {
+			err = uc.suspendForClosure(ctx, employee, reason)
+		} else {
+			err = uc.transfer(ctx, employee, data.TargetStoreID)
+		}
+		if err != nil {
+			slog.Log(ctx, slog.LevelWarn, "Failed to handle store closure for employee", "ID", employee.ID(), "storeID", data.StoreID, "error", err)
+			failed = append(failed, err)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to handle store closure for %d employees: %w", len(failed), errors.Join(failed...))
+	}
+	return nil
+}
+
+func (uc *InboundEventUsecase) suspendForClosure(ctx context.Context, employee *domain.Employee, reason string) error {
+	if !employee.Status().CanTransitionTo(domain.StatusSuspended) {
+		return nil
+	}
+
+	_, err := uc.lifecycle.ApplySystemTransition(ctx, employee, domain.StatusSuspended, reason)
+	return err
+}
+
+func (uc *InboundEventUsecase) transfer(ctx context.Context, employee *domain.Employee, storeID string) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	from := employee.StoreID()
+	employee.SetStoreID(storeID)
+	if err := uc.employeeRepo.Update(ctx, employee); err != nil {
+		return fmt.Errorf("failed to update employee store: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Transferred employee of closed store", "ID", employee.ID(), "from", from, "to", storeID)
+
+	return nil
+}
+
+// handleCashierSessionOpened checks the cashier in when the session is their first
+// sign of work that day. Cashiers who are no longer active are only logged.
+func (uc *InboundEventUsecase) handleCashierSessionOpened(ctx context.Context, event *domain.InboundEvent) error {
+	var data cashierSessionOpenedData
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return fmt.Errorf("%w: %w", InvalidInboundEventError, err)
+	}
+	if data.EmployeeID == "" {
+		return fmt.Errorf("%w: missing employee_id", InvalidInboundEventError)
+	}
+
+	openedAt := data.OpenedAt
+	if openedAt.IsZero() {
+		openedAt = event.OccurredAt
+	}
+	if openedAt.IsZero() {
+		openedAt = uc.clock.Now()
+	}
+
+	location := "POS"
+	if data.StoreID != "" {
+		location += " store " + data.StoreID
+	}
+	if data.RegisterID != "" {
+		location += " register " + data.RegisterID
+	}
+
+	_, err := uc.attendance.RecordImplicitCheckIn(ctx, data.EmployeeID, location, openedAt)
+	switch {
+	case errors.Is(err, EmployeeNotFoundError):
+		return fmt.Errorf("%w: %w", InvalidInboundEventError, err)
+	case errors.Is(err, EmployeeNotActiveError):
+		slog.Log(ctx, slog.LevelWarn, "Ignored cashier session of inactive employee", "employeeID", data.EmployeeID, "eventID", event.ID)
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to check in cashier: %w", err)
+	}
+
+	return nil
+}
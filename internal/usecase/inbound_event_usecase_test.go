@@ -0,0 +1,355 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/config"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+func inboundEvent(id string, eventType domain.InboundEventType, data string) *domain.InboundEvent {
+	return &domain.InboundEvent{
+		ID:         id,
+		Type:       eventType,
+		Source:     "store-service",
+		OccurredAt: time.Date(2026, 5, 4, 8, 0, 0, 0, time.UTC),
+		Data:       []byte(data),
+	}
+}
+
+func TestInboundEventUsecase_Handle(t *testing.T) {
+	now := time.Date(2026, 5, 4, 8, 30, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	idGen := new(MockIDGenerator)
+	idGen.On("NewID").Return("id-1", nil)
+
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	notifier := new(MockNotifier)
+	notifier.On("NotifyAboutEmployee", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	cfg := &config.Config{AppTimezone: time.UTC, OfficeStartHour: 9}
+
+	mockProcessedRepo := new(MockProcessedEventRepo)
+	mockRepo := new(MockEmployeeRepo)
+	mockChangeRepo := new(MockStatusChangeRepo)
+	mockKioskPINRepo := new(MockKioskPINRepo)
+	mockAttendanceRepo := new(MockAttendanceRepo)
+	lifecycle := usecase.NewLifecycleUsecase(mockRepo, mockChangeRepo, mockKioskPINRepo, publisher, authorizer, idGen, clk, time.UTC, 2*time.Second)
+	attendance := usecase.NewAttendanceUsecase(mockAttendanceRepo, mockRepo, new(MockStorageRepo), new(MockKioskRepo), idGen, notifier, publisher, nil, testPhotoConfig, usecase.KioskConfig{}, cfg, clk, 2*time.Second)
+	uc := usecase.NewInboundEventUsecase(mockProcessedRepo, mockRepo, lifecycle, attendance, clk, 2*time.Second)
+
+	// Only the calls a case expects are mocked, so handling an event that should
+	// be skipped or refused fails the mock.
+	tests := []struct {
+		name      string
+		event     *domain.InboundEvent
+		duplicate bool
+		processed bool
+		setup     func()
+		wantErr   error
+		check     func(t *testing.T)
+	}{
+		{
+			name:      "Success - Duplicate Event Is Skipped",
+			event:     inboundEvent("msg-1", domain.InboundStoreClosed, `{"store_id":"store-1","action":"suspend"}`),
+			duplicate: true,
+		},
+		{
+			name:    "Fail - Unknown Event Type",
+			event:   inboundEvent("msg-1", "payroll.closed", `{}`),
+			wantErr: usecase.UnknownInboundEventError,
+		},
+		{
+			name:    "Fail - Missing Event ID",
+			event:   inboundEvent("", domain.InboundStoreClosed, `{}`),
+			wantErr: usecase.InvalidInboundEventError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.event.ID != "" {
+				mockProcessedRepo.On("Exists", mock.Anything, tt.event.ID).Return(tt.duplicate, nil).Once()
+			}
+			if tt.processed {
+				mockProcessedRepo.On("Save", mock.Anything, tt.event, now).Return(nil).Once()
+			}
+			if tt.setup != nil {
+				tt.setup()
+			}
+
+			err := uc.Handle(context.Background(), tt.event)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.check != nil {
+				tt.check(t)
+			}
+			mockProcessedRepo.AssertExpectations(t)
+			mockRepo.AssertExpectations(t)
+			mockChangeRepo.AssertExpectations(t)
+			mockAttendanceRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInboundEventUsecase_StoreClosed(t *testing.T) {
+	now := time.Date(2026, 5, 4, 8, 30, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	idGen := new(MockIDGenerator)
+	idGen.On("NewID").Return("id-1", nil)
+
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	notifier := new(MockNotifier)
+	notifier.On("NotifyAboutEmployee", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	cfg := &config.Config{AppTimezone: time.UTC, OfficeStartHour: 9}
+
+	mockProcessedRepo := new(MockProcessedEventRepo)
+	mockRepo := new(MockEmployeeRepo)
+	mockChangeRepo := new(MockStatusChangeRepo)
+	mockKioskPINRepo := new(MockKioskPINRepo)
+	mockAttendanceRepo := new(MockAttendanceRepo)
+	lifecycle := usecase.NewLifecycleUsecase(mockRepo, mockChangeRepo, mockKioskPINRepo, publisher, authorizer, idGen, clk, time.UTC, 2*time.Second)
+	attendance := usecase.NewAttendanceUsecase(mockAttendanceRepo, mockRepo, new(MockStorageRepo), new(MockKioskRepo), idGen, notifier, publisher, nil, testPhotoConfig, usecase.KioskConfig{}, cfg, clk, 2*time.Second)
+	uc := usecase.NewInboundEventUsecase(mockProcessedRepo, mockRepo, lifecycle, attendance, clk, 2*time.Second)
+
+	active := storeEmployee("emp-1", domain.RoleStaff, "store-1")
+	suspended := employeeWithStatus("emp-2", domain.RoleStaff, domain.StatusSuspended)
+	otherStore := storeEmployee("emp-3", domain.RoleStaff, "store-2")
+
+	transferred := storeEmployee("emp-1", domain.RoleStaff, "store-1")
+	terminated := employeeWithStatus("emp-2", domain.RoleStaff, domain.StatusTerminated)
+
+	failing := storeEmployee("emp-1", domain.RoleStaff, "store-1")
+	second := storeEmployee("emp-2", domain.RoleStaff, "store-1")
+	errDBDown := errors.New("db down")
+
+	// A status change is only mocked for the suspension, so a transfer that
+	// also changes the status fails the mock.
+	tests := []struct {
+		name      string
+		event     *domain.InboundEvent
+		duplicate bool
+		processed bool
+		setup     func()
+		wantErr   error
+		check     func(t *testing.T)
+	}{
+		{
+			name:      "Success - Suspends Active Employees Of The Store",
+			event:     inboundEvent("msg-1", domain.InboundStoreClosed, `{"store_id":"store-1","action":"suspend","reason":"lease ended"}`),
+			processed: true,
+			setup: func() {
+				mockRepo.On("FindAll", mock.Anything).Return([]*domain.Employee{active, suspended, otherStore}, nil).Once()
+				mockRepo.On("Update", mock.Anything, active).Return(nil).Once()
+				mockChangeRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *domain.StatusChange) bool {
+					return c.EmployeeID == "emp-1" && c.ActorID == "" && c.State == domain.StatusChangeApplied &&
+						c.Reason == "store store-1 closed: lease ended"
+				})).Return(nil).Once()
+				mockKioskPINRepo.On("DeleteByEmployeeID", mock.Anything, "emp-1").Return(nil).Once()
+			},
+			check: func(t *testing.T) {
+				assert.Equal(t, domain.StatusSuspended, active.Status())
+				assert.Equal(t, domain.StatusActive, otherStore.Status())
+			},
+		},
+		{
+			name:      "Success - Transfers Employees To Another Store",
+			event:     inboundEvent("msg-2", domain.InboundStoreClosed, `{"store_id":"store-1","action":"transfer","target_store_id":"store-9"}`),
+			processed: true,
+			setup: func() {
+				mockRepo.On("FindAll", mock.Anything).Return([]*domain.Employee{transferred, terminated}, nil).Once()
+				mockRepo.On("Update", mock.Anything, transferred).Return(nil).Once()
+			},
+			check: func(t *testing.T) {
+				assert.Equal(t, "store-9", transferred.StoreID())
+				assert.Equal(t, "store-1", terminated.StoreID())
+				assert.Equal(t, domain.StatusActive, transferred.Status())
+			},
+		},
+		{
+			name:  "Fail - Partly Handled Event Is Not Marked Processed",
+			event: inboundEvent("msg-3", domain.InboundStoreClosed, `{"store_id":"store-1","action":"transfer","target_store_id":"store-9"}`),
+			setup: func() {
+				mockRepo.On("FindAll", mock.Anything).Return([]*domain.Employee{failing, second}, nil).Once()
+				mockRepo.On("Update", mock.Anything, failing).Return(errDBDown).Once()
+				mockRepo.On("Update", mock.Anything, second).Return(nil).Once()
+			},
+			wantErr: errDBDown,
+			check: func(t *testing.T) {
+				assert.Equal(t, "store-9", second.StoreID())
+			},
+		},
+		{
+			name:    "Fail - Transfer Without Target Store",
+			event:   inboundEvent("msg-4", domain.InboundStoreClosed, `{"store_id":"store-1","action":"transfer"}`),
+			wantErr: usecase.InvalidInboundEventError,
+		},
+		{
+			name:    "Fail - Malformed Data",
+			event:   inboundEvent("msg-5", domain.InboundStoreClosed, `{"store_id":`),
+			wantErr: usecase.InvalidInboundEventError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.event.ID != "" {
+				mockProcessedRepo.On("Exists", mock.Anything, tt.event.ID).Return(tt.duplicate, nil).Once()
+			}
+			if tt.processed {
+				mockProcessedRepo.On("Save", mock.Anything, tt.event, now).Return(nil).Once()
+			}
+			if tt.setup != nil {
+				tt.setup()
+			}
+
+			err := uc.Handle(context.Background(), tt.event)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.check != nil {
+				tt.check(t)
+			}
+			mockProcessedRepo.AssertExpectations(t)
+			mockRepo.AssertExpectations(t)
+			mockChangeRepo.AssertExpectations(t)
+			mockAttendanceRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInboundEventUsecase_CashierSessionOpened(t *testing.T) {
+	now := time.Date(2026, 5, 4, 9, 30, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	idGen := new(MockIDGenerator)
+	idGen.On("NewID").Return("id-1", nil)
+
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	notifier := new(MockNotifier)
+	notifier.On("NotifyAboutEmployee", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	cfg := &config.Config{AppTimezone: time.UTC, OfficeStartHour: 9}
+
+	mockProcessedRepo := new(MockProcessedEventRepo)
+	mockRepo := new(MockEmployeeRepo)
+	mockChangeRepo := new(MockStatusChangeRepo)
+	mockKioskPINRepo := new(MockKioskPINRepo)
+	mockAttendanceRepo := new(MockAttendanceRepo)
+	lifecycle := usecase.NewLifecycleUsecase(mockRepo, mockChangeRepo, mockKioskPINRepo, publisher, authorizer, idGen, clk, time.UTC, 2*time.Second)
+	attendance := usecase.NewAttendanceUsecase(mockAttendanceRepo, mockRepo, new(MockStorageRepo), new(MockKioskRepo), idGen, notifier, publisher, nil, testPhotoConfig, usecase.KioskConfig{}, cfg, clk, 2*time.Second)
+	uc := usecase.NewInboundEventUsecase(mockProcessedRepo, mockRepo, lifecycle, attendance, clk, 2*time.Second)
+
+	data := `{"employee_id":"emp-1","store_id":"store-1","register_id":"r-7","opened_at":"2026-05-04T08:45:00Z"}`
+	day := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+
+	// Attendance writes are only mocked for the implicit check-in, so touching
+	// an existing or ignored day fails the mock.
+	tests := []struct {
+		name      string
+		event     *domain.InboundEvent
+		duplicate bool
+		processed bool
+		setup     func()
+		wantErr   error
+		check     func(t *testing.T)
+	}{
+		{
+			name:      "Success - Creates Implicit Check-In",
+			event:     inboundEvent("msg-1", domain.InboundCashierSessionOpened, data),
+			processed: true,
+			setup: func() {
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(storeEmployee("emp-1", domain.RoleStaff, "store-1"), nil).Once()
+				mockAttendanceRepo.On("FindByEmployeeIDAndDate", mock.Anything, "emp-1", day).Return(nil, nil).Once()
+				mockAttendanceRepo.On("Save", mock.Anything, mock.MatchedBy(func(a *domain.Attendance) bool {
+					return a.EmployeeID == "emp-1" && a.CheckIn == "2026-05-04 08:45:00" && !a.IsLate &&
+						strings.Contains(a.Location, "register r-7")
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name:      "Success - Already Checked In",
+			event:     inboundEvent("msg-2", domain.InboundCashierSessionOpened, data),
+			processed: true,
+			setup: func() {
+				open := &domain.Attendance{ID: "att-1", Sessions: []domain.WorkSession{{CheckIn: day.Add(8 * time.Hour)}}}
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(storeEmployee("emp-1", domain.RoleStaff, "store-1"), nil).Once()
+				mockAttendanceRepo.On("FindByEmployeeIDAndDate", mock.Anything, "emp-1", day).Return(open, nil).Once()
+			},
+		},
+		{
+			name:      "Success - Inactive Employee Is Ignored",
+			event:     inboundEvent("msg-3", domain.InboundCashierSessionOpened, data),
+			processed: true,
+			setup: func() {
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusSuspended), nil).Once()
+			},
+		},
+		{
+			name:  "Fail - Unknown Employee",
+			event: inboundEvent("msg-4", domain.InboundCashierSessionOpened, data),
+			setup: func() {
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(nil, nil).Once()
+			},
+			wantErr: usecase.InvalidInboundEventError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.event.ID != "" {
+				mockProcessedRepo.On("Exists", mock.Anything, tt.event.ID).Return(tt.duplicate, nil).Once()
+			}
+			if tt.processed {
+				mockProcessedRepo.On("Save", mock.Anything, tt.event, now).Return(nil).Once()
+			}
+			if tt.setup != nil {
+				tt.setup()
+			}
+
+			err := uc.Handle(context.Background(), tt.event)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.check != nil {
+				tt.check(t)
+			}
+			mockProcessedRepo.AssertExpectations(t)
+			mockRepo.AssertExpectations(t)
+			mockChangeRepo.AssertExpectations(t)
+			mockAttendanceRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type KioskRepository interface {
+	Create(ctx context.Context, kiosk *domain.Kiosk) error
+	FindByID(ctx context.Context, id string) (*domain.Kiosk, error)
+	// FindByTokenHash returns the kiosk holding the token, revoked or not.
+	FindByTokenHash(ctx context.Context, tokenHash string) (*domain.Kiosk, error)
+	// FindByStoreID lists the kiosks of a store, or of every store when storeID is empty.
+	FindByStoreID(ctx context.Context, storeID string) ([]*domain.Kiosk, error)
+	Revoke(ctx context.Context, id string, revokedAt time.Time) error
+}
+
+type KioskPINRepository interface {
+	Save(ctx context.Context, pin *domain.KioskPIN) error
+	FindByEmployeeID(ctx context.Context, employeeID string) (*domain.KioskPIN, error)
+	DeleteByEmployeeID(ctx context.Context, employeeID string) error
+}
+
+// ProcessedKioskEventRepository remembers which offline kiosk events have been synced.
+// Event IDs are generated by the kiosks, so they are only unique per kiosk.
+type ProcessedKioskEventRepository interface {
+	Find(ctx context.Context, kioskID string, eventID string) (*domain.ProcessedKioskEvent, error)
+	// Save ignores events that are already recorded.
+	Save(ctx context.Context, event *domain.ProcessedKioskEvent) error
+	// DeleteOccurredBefore forgets events too old to be synced again.
+	DeleteOccurredBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,91 @@
+package usecase_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type MockKioskRepo struct {
+	mock.Mock
+}
+
+func (m *MockKioskRepo) Create(ctx context.Context, kiosk *domain.Kiosk) error {
+	args := m.Called(ctx, kiosk)
+	return args.Error(0)
+}
+
+func (m *MockKioskRepo) FindByID(ctx context.Context, id string) (*domain.Kiosk, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Kiosk), args.Error(1)
+}
+
+func (m *MockKioskRepo) FindByTokenHash(ctx context.Context, tokenHash string) (*domain.Kiosk, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Kiosk), args.Error(1)
+}
+
+func (m *MockKioskRepo) FindByStoreID(ctx context.Context, storeID string) ([]*domain.Kiosk, error) {
+	args := m.Called(ctx, storeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Kiosk), args.Error(1)
+}
+
+func (m *MockKioskRepo) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	args := m.Called(ctx, id, revokedAt)
+	return args.Error(0)
+}
+
+type MockKioskPINRepo struct {
+	mock.Mock
+}
+
+func (m *MockKioskPINRepo) Save(ctx context.Context, pin *domain.KioskPIN) error {
+	args := m.Called(ctx, pin)
+	return args.Error(0)
+}
+
+func (m *MockKioskPINRepo) FindByEmployeeID(ctx context.Context, employeeID string) (*domain.KioskPIN, error) {
+	args := m.Called(ctx, employeeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.KioskPIN), args.Error(1)
+}
+
+func (m *MockKioskPINRepo) DeleteByEmployeeID(ctx context.Context, employeeID string) error {
+	args := m.Called(ctx, employeeID)
+	return args.Error(0)
+}
+
+type MockProcessedKioskEventRepo struct {
+	mock.Mock
+}
+
+func (m *MockProcessedKioskEventRepo) Find(ctx context.Context, kioskID string, eventID string) (*domain.ProcessedKioskEvent, error) {
+	args := m.Called(ctx, kioskID, eventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ProcessedKioskEvent), args.Error(1)
+}
+
+func (m *MockProcessedKioskEventRepo) Save(ctx context.Context, event *domain.ProcessedKioskEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockProcessedKioskEventRepo) DeleteOccurredBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
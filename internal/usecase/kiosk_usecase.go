@@ -0,0 +1,473 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/kioskqr"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/kiosksig"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// KioskConfig signs the QR codes kiosks show, bounds wrong PIN attempts and decides
+// which offline events a kiosk may sync.
+type KioskConfig struct {
+	QRSecret       []byte
+	QRTTL          time.Duration // how long a QR code can be scanned after it was issued
+	PINThrottle    domain.LoginThrottlePolicy
+	DeviceThrottle domain.LoginThrottlePolicy
+
+	SyncMaxClockSkew time.Duration // how far the kiosk clock may be off when it syncs
+	SyncMaxEventAge  time.Duration // how long after it occurred an offline event is accepted
+	SyncMaxBatch     int
+}
+
+type KioskUsecase struct {
+	kioskRepo    KioskRepository
+	pinRepo      KioskPINRepository
+	syncRepo     ProcessedKioskEventRepository
+	employeeRepo EmployeeRepository
+	throttleRepo LoginThrottleRepository
+	attendance   *AttendanceUsecase
+	authorizer   *Authorizer
+	idGen        IDGenerator
+	cfg          KioskConfig
+	clock        clock.Clock
+	ctxTimeout   time.Duration
+}
+
+func NewKioskUsecase(kioskRepo KioskRepository, pinRepo KioskPINRepository, syncRepo ProcessedKioskEventRepository, employeeRepo EmployeeRepository, throttleRepo LoginThrottleRepository, attendance *AttendanceUsecase, authorizer *Authorizer, idGen IDGenerator, cfg KioskConfig, clk clock.Clock, timeout time.Duration) *KioskUsecase {
+	return &KioskUsecase{
+		kioskRepo:    kioskRepo,
+		pinRepo:      pinRepo,
+		syncRepo:     syncRepo,
+		employeeRepo: employeeRepo,
+		throttleRepo: throttleRepo,
+		attendance:   attendance,
+		authorizer:   authorizer,
+		idGen:        idGen,
+		cfg:          cfg,
+		clock:        clk,
+		ctxTimeout:   timeout,
+	}
+}
+
+// Register adds a kiosk device to a store and returns it with its device token. Only the
+// token hash is stored, so the token cannot be shown again. signingKey is the public key
+// of the pair the device generated to sign offline events, see package kiosksig. Actors
+// without access to all stores register kiosks of their own store, which is also the
+// default store.
+func (uc *KioskUsecase) Register(ctx context.Context, actor Actor, storeID string, name string, signingKey string) (*domain.Kiosk, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	storeID, err := uc.scopeStore(ctx, actor, strings.TrimSpace(storeID))
+	if err != nil {
+		return nil, "", err
+	}
+	if storeID == "" {
+		return nil, "", fmt.Errorf("%w: store_id is required", InvalidKioskError)
+	}
+	publicKey, err := kiosksig.ParsePublicKey(signingKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %w", InvalidKioskError, err)
+	}
+
+	id, err := uc.idGen.NewID()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate kiosk ID: %w", err)
+	}
+	token, tokenHash, err := generateKioskToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate kiosk token: %w", err)
+	}
+
+	kiosk, err := domain.NewKiosk(id, storeID, name, tokenHash, publicKey, actor.ID, uc.clock.Now())
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %w", InvalidKioskError, err)
+	}
+
+	if err := uc.kioskRepo.Create(ctx, kiosk); err != nil {
+		return nil, "", err
+	}
+	slog.Log(ctx, slog.LevelInfo, "Registered kiosk", "ID", kiosk.ID, "storeID", storeID, "actorID", actor.ID)
+
+	return kiosk, token, nil
+}
+
+// List returns the kiosks of storeID, or of every store the actor may see when empty.
+func (uc *KioskUsecase) List(ctx context.Context, actor Actor, storeID string) ([]*domain.Kiosk, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	storeID, err := uc.scopeStore(ctx, actor, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.kioskRepo.FindByStoreID(ctx, storeID)
+}
+
+// Revoke stops a kiosk from authenticating. QR codes it showed are rejected from then on.
+func (uc *KioskUsecase) Revoke(ctx context.Context, actor Actor, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	kiosk, err := uc.kioskRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if kiosk == nil {
+		return KioskNotFoundError
+	}
+	if _, err := uc.scopeStore(ctx, actor, kiosk.StoreID); err != nil {
+		return err
+	}
+	if kiosk.IsRevoked() {
+		return nil
+	}
+
+	if err := uc.kioskRepo.Revoke(ctx, id, uc.clock.Now()); err != nil {
+		return err
+	}
+	slog.Log(ctx, slog.LevelInfo, "Revoked kiosk", "ID", id, "actorID", actor.ID)
+
+	return nil
+}
+
+// Authenticate returns the active kiosk holding the device token.
+func (uc *KioskUsecase) Authenticate(ctx context.Context, token string) (*domain.Kiosk, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	kiosk, err := uc.kioskRepo.FindByTokenHash(ctx, hashKioskToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if kiosk == nil || kiosk.IsRevoked() {
+		return nil, InvalidKioskTokenError
+	}
+	return kiosk, nil
+}
+
+// IssueQR returns a fresh QR code for the kiosk to display and when it stops being
+// accepted. Kiosks fetch a new one before then, so the code on screen keeps rotating.
+func (uc *KioskUsecase) IssueQR(kiosk *domain.Kiosk) (string, time.Time) {
+	now := uc.clock.Now()
+	code := kioskqr.Sign(uc.cfg.QRSecret, kioskqr.Payload{KioskID: kiosk.ID, StoreID: kiosk.StoreID, IssuedAt: now})
+	return code, now.Truncate(time.Second).Add(uc.cfg.QRTTL)
+}
+
+// SetPIN sets the PIN the actor enters on kiosks, replacing the previous one.
+func (uc *KioskUsecase) SetPIN(ctx context.Context, actor Actor, pin string) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := domain.ValidateKioskPIN(pin); err != nil {
+		return fmt.Errorf("%w: %w", InvalidKioskPINError, err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash kiosk PIN: %w", err)
+	}
+
+	if err := uc.pinRepo.Save(ctx, &domain.KioskPIN{EmployeeID: actor.ID, PINHash: string(hash), UpdatedAt: uc.clock.Now()}); err != nil {
+		return err
+	}
+	slog.Log(ctx, slog.LevelInfo, "Set kiosk PIN", "employeeID", actor.ID)
+
+	return nil
+}
+
+// CheckIn checks in the employee who entered their PIN on the kiosk.
+func (uc *KioskUsecase) CheckIn(ctx context.Context, kiosk *domain.Kiosk, employeeID string, pin string) (string, error) {
+	if err := uc.verifyPIN(ctx, kiosk, employeeID, pin); err != nil {
+		return "", err
+	}
+	return uc.attendance.CheckInAtKiosk(ctx, kiosk, employeeID, uc.clock.Now())
+}
+
+// CheckOut checks out the employee who entered their PIN on the kiosk.
+func (uc *KioskUsecase) CheckOut(ctx context.Context, kiosk *domain.Kiosk, employeeID string, pin string) error {
+	if err := uc.verifyPIN(ctx, kiosk, employeeID, pin); err != nil {
+		return err
+	}
+	return uc.attendance.CheckOutAtKiosk(ctx, kiosk, employeeID, uc.clock.Now())
+}
+
+// StartBreak starts a break of the employee who entered their PIN on the kiosk.
+func (uc *KioskUsecase) StartBreak(ctx context.Context, kiosk *domain.Kiosk, employeeID string, pin string) error {
+	if err := uc.verifyPIN(ctx, kiosk, employeeID, pin); err != nil {
+		return err
+	}
+	return uc.attendance.StartBreakAtKiosk(ctx, kiosk, employeeID, uc.clock.Now())
+}
+
+// EndBreak ends the break of the employee who entered their PIN on the kiosk.
+func (uc *KioskUsecase) EndBreak(ctx context.Context, kiosk *domain.Kiosk, employeeID string, pin string) error {
+	if err := uc.verifyPIN(ctx, kiosk, employeeID, pin); err != nil {
+		return err
+	}
+	return uc.attendance.EndBreakAtKiosk(ctx, kiosk, employeeID, uc.clock.Now())
+}
+
+// KioskSyncResult is the outcome of one synced offline event.
+type KioskSyncResult struct {
+	EventID      string
+	Status       domain.KioskEventStatus
+	AttendanceID string
+	Error        string
+}
+
+// Sync applies the check-ins, check-outs and breaks a kiosk collected while it was offline, at
+// the time the kiosk recorded them. sentAt is the kiosk's clock when it sent the batch;
+// batches from kiosks whose clock is further off than the policy allows are refused as a
+// whole. Events are applied in the order they occurred and each must be signed with the
+// kiosk's private key. Events synced before are reported as duplicates and not applied
+// again. Results are returned in the order of events.
+func (uc *KioskUsecase) Sync(ctx context.Context, kiosk *domain.Kiosk, sentAt time.Time, events []domain.KioskEvent) ([]KioskSyncResult, error) {
+	if len(events) == 0 || len(events) > uc.cfg.SyncMaxBatch {
+		return nil, fmt.Errorf("%w: a batch holds 1 to %d events", InvalidKioskSyncError, uc.cfg.SyncMaxBatch)
+	}
+	if len(kiosk.SigningKey) == 0 {
+		return nil, fmt.Errorf("%w: the kiosk has no signing key, register it again", InvalidKioskSyncError)
+	}
+
+	skew := uc.clock.Now().Sub(sentAt)
+	if skew.Abs() > uc.cfg.SyncMaxClockSkew {
+		return nil, fmt.Errorf("%w: off by %s, at most %s is allowed", KioskClockSkewError, skew.Round(time.Second), uc.cfg.SyncMaxClockSkew)
+	}
+
+	order := make([]int, len(events))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return events[a].OccurredAt.Compare(events[b].OccurredAt)
+	})
+
+	results := make([]KioskSyncResult, len(events))
+	verified := make(map[string]string) // employee ID to the PIN already verified in this batch
+	for _, i := range order {
+		results[i] = uc.syncEvent(ctx, kiosk, sentAt, &events[i], verified)
+	}
+
+	slog.Log(ctx, slog.LevelInfo, "Synced kiosk events", "kioskID", kiosk.ID, "events", len(events), "skew", skew)
+	return results, nil
+}
+
+// syncEvent applies a single offline event. Applied and rejected events are remembered;
+// events that failed temporarily are not, so the kiosk can send them again.
+func (uc *KioskUsecase) syncEvent(ctx context.Context, kiosk *domain.Kiosk, sentAt time.Time, event *domain.KioskEvent, verified map[string]string) KioskSyncResult {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	result := KioskSyncResult{EventID: event.ID}
+	if err := event.Validate(); err != nil {
+		result.Status, result.Error = domain.KioskEventRejected, err.Error()
+		return result
+	}
+
+	signed := kiosksig.Event{ID: event.ID, Type: string(event.Type), EmployeeID: event.EmployeeID, OccurredAt: event.OccurredAt}
+	if !kiosksig.Verify(kiosk.SigningKey, signed, event.Signature) {
+		// Not remembered, whoever forged it must not use up the event ID
+		result.Status, result.Error = domain.KioskEventRejected, "signature does not match"
+		return result
+	}
+
+	previous, err := uc.syncRepo.Find(ctx, kiosk.ID, event.ID)
+	if err != nil {
+		result.Status, result.Error = domain.KioskEventFailed, "failed to look up event"
+		slog.Log(ctx, slog.LevelError, "Failed to find synced kiosk event", "kioskID", kiosk.ID, "eventID", event.ID, "error", err)
+		return result
+	}
+	if previous != nil {
+		result.Status, result.AttendanceID, result.Error = domain.KioskEventDuplicate, previous.AttendanceID, previous.Error
+		return result
+	}
+
+	attendanceID, err := uc.applyEvent(ctx, kiosk, sentAt, event, verified)
+	if err != nil && !isPermanentSyncError(err) {
+		result.Status, result.Error = domain.KioskEventFailed, "temporary failure, sync the event again"
+		var lockedErr *LoginLockedError
+		if errors.As(err, &lockedErr) {
+			result.Error = fmt.Sprintf("too many wrong PINs, retry after %s", lockedErr.RetryAfter.Round(time.Second))
+		}
+		slog.Log(ctx, slog.LevelWarn, "Failed to apply kiosk event", "kioskID", kiosk.ID, "eventID", event.ID, "error", err)
+		return result
+	}
+
+	processed := &domain.ProcessedKioskEvent{
+		KioskID:      kiosk.ID,
+		EventID:      event.ID,
+		Type:         event.Type,
+		OccurredAt:   event.OccurredAt,
+		Status:       domain.KioskEventApplied,
+		AttendanceID: attendanceID,
+		ProcessedAt:  uc.clock.Now(),
+	}
+	if err != nil {
+		processed.Status, processed.Error = domain.KioskEventRejected, err.Error()
+	}
+	if err := uc.syncRepo.Save(ctx, processed); err != nil {
+		// The attendance is recorded; a resent event is refused as a repeated or out of order punch
+		slog.Log(ctx, slog.LevelError, "Failed to remember synced kiosk event", "kioskID", kiosk.ID, "eventID", event.ID, "error", err)
+	}
+
+	result.Status, result.AttendanceID, result.Error = processed.Status, processed.AttendanceID, processed.Error
+	return result
+}
+
+func (uc *KioskUsecase) applyEvent(ctx context.Context, kiosk *domain.Kiosk, sentAt time.Time, event *domain.KioskEvent, verified map[string]string) (string, error) {
+	if event.OccurredAt.After(sentAt) {
+		return "", fmt.Errorf("%w: event occurred after the batch was sent", InvalidKioskSyncError)
+	}
+	if sentAt.Sub(event.OccurredAt) > uc.cfg.SyncMaxEventAge {
+		return "", fmt.Errorf("%w: event is older than %s", InvalidKioskSyncError, uc.cfg.SyncMaxEventAge)
+	}
+
+	if pin, ok := verified[event.EmployeeID]; !ok || pin != event.PIN {
+		if err := uc.verifyPIN(ctx, kiosk, event.EmployeeID, event.PIN); err != nil {
+			return "", err
+		}
+		verified[event.EmployeeID] = event.PIN
+	}
+
+	switch event.Type {
+	case domain.KioskEventCheckIn:
+		return uc.attendance.CheckInAtKiosk(ctx, kiosk, event.EmployeeID, event.OccurredAt)
+	case domain.KioskEventBreakStart:
+		return "", uc.attendance.StartBreakAtKiosk(ctx, kiosk, event.EmployeeID, event.OccurredAt)
+	case domain.KioskEventBreakEnd:
+		return "", uc.attendance.EndBreakAtKiosk(ctx, kiosk, event.EmployeeID, event.OccurredAt)
+	default:
+		return "", uc.attendance.CheckOutAtKiosk(ctx, kiosk, event.EmployeeID, event.OccurredAt)
+	}
+}
+
+// isPermanentSyncError tells errors that no retry of an offline event can fix.
+func isPermanentSyncError(err error) bool {
+	for _, permanent := range []error{
+		InvalidKioskSyncError, InvalidKioskPINError, EmployeeNotFoundError, EmployeeNotActiveError, KioskStoreMismatchError,
+		AlreadyCheckedInError, NotCheckedInError, AlreadyOnBreakError, NotOnBreakError, PunchOutOfOrderError,
+	} {
+		if errors.Is(err, permanent) {
+			return true
+		}
+	}
+	return false
+}
+
+// PurgeSyncedEvents forgets synced offline events that are too old to be sent again.
+func (uc *KioskUsecase) PurgeSyncedEvents(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	return uc.syncRepo.DeleteOccurredBefore(ctx, uc.clock.Now().Add(-uc.cfg.SyncMaxEventAge-uc.cfg.SyncMaxClockSkew))
+}
+
+// verifyPIN checks an employee's PIN. Wrong PINs are throttled per employee and per kiosk,
+// so neither a single PIN nor a common PIN across employees can be guessed.
+func (uc *KioskUsecase) verifyPIN(ctx context.Context, kiosk *domain.Kiosk, employeeID string, pin string) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	now := uc.clock.Now()
+	employeeThrottle, err := uc.loadThrottle(ctx, domain.ThrottleScopeKioskPIN, employeeID)
+	if err != nil {
+		return err
+	}
+	kioskThrottle, err := uc.loadThrottle(ctx, domain.ThrottleScopeKiosk, kiosk.ID)
+	if err != nil {
+		return err
+	}
+	if wait := max(employeeThrottle.RetryAfter(now), kioskThrottle.RetryAfter(now)); wait > 0 {
+		return &LoginLockedError{RetryAfter: wait}
+	}
+
+	stored, err := uc.pinRepo.FindByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return err
+	}
+	if stored == nil || bcrypt.CompareHashAndPassword([]byte(stored.PINHash), []byte(pin)) != nil {
+		if err := uc.throttleRepo.RegisterFailure(ctx, employeeThrottle, now, uc.cfg.PINThrottle); err != nil {
+			return fmt.Errorf("failed to record kiosk PIN failure: %w", err)
+		}
+		if err := uc.throttleRepo.RegisterFailure(ctx, kioskThrottle, now, uc.cfg.DeviceThrottle); err != nil {
+			return fmt.Errorf("failed to record kiosk PIN failure: %w", err)
+		}
+		slog.Log(ctx, slog.LevelWarn, "Wrong kiosk PIN", "kioskID", kiosk.ID, "employeeID", employeeID, "attempts", employeeThrottle.FailedCount)
+		return InvalidKioskPINError
+	}
+
+	if employeeThrottle.FailedCount > 0 {
+		if err := uc.throttleRepo.DeleteByKey(ctx, employeeThrottle.Key); err != nil {
+			return fmt.Errorf("failed to reset kiosk PIN throttle: %w", err)
+		}
+	}
+	return nil
+}
+
+func (uc *KioskUsecase) loadThrottle(ctx context.Context, scope domain.ThrottleScope, value string) (*domain.LoginThrottle, error) {
+	throttle, err := uc.throttleRepo.FindByKey(ctx, domain.LoginThrottleKey(scope, value))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kiosk PIN throttle: %w", err)
+	}
+	if throttle == nil {
+		throttle = domain.NewLoginThrottle(scope, value)
+	}
+	return throttle, nil
+}
+
+// scopeStore authorizes kiosk management and returns the store the actor works on.
+// Actors without access to all stores only manage their own store, which an empty
+// storeID defaults to.
+func (uc *KioskUsecase) scopeStore(ctx context.Context, actor Actor, storeID string) (string, error) {
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermKioskManage); err != nil {
+		return "", err
+	}
+
+	allStores, err := uc.authorizer.HasAny(ctx, actor.Role, domain.PermEmployeeAllStores)
+	if err != nil {
+		return "", err
+	}
+	if allStores {
+		return storeID, nil
+	}
+
+	viewer, err := uc.employeeRepo.FindByID(ctx, actor.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find viewer: %w", err)
+	}
+	if viewer == nil || viewer.StoreID() == "" || (storeID != "" && storeID != viewer.StoreID()) {
+		return "", ForbiddenError
+	}
+	return viewer.StoreID(), nil
+}
+
+func generateKioskToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashKioskToken(token), nil
+}
+
+func hashKioskToken(token string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	return hex.EncodeToString(sum[:])
+}
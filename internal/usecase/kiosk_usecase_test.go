@@ -0,0 +1,569 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/config"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/dto/attendance"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/kioskqr"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/kiosksig"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testKioskKey is the key pair of the kiosk returned by storeKiosk
+var testKioskKey = ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+
+func testKioskPublicKey() string {
+	return base64.StdEncoding.EncodeToString(testKioskKey.Public().(ed25519.PublicKey))
+}
+
+func storeKiosk() *domain.Kiosk {
+	return &domain.Kiosk{ID: "kiosk-1", StoreID: "store-1", Name: "Front desk", SigningKey: testKioskKey.Public().(ed25519.PublicKey)}
+}
+
+func TestAttendanceUsecase_CheckInWithKioskQR(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	now := time.Date(2026, 10, 10, 8, 55, 0, 0, loc)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	cfg := &config.Config{AppTimezone: now.Location(), OfficeStartHour: 9, MinBreakMinutes: 15}
+
+	today := time.Date(2026, 10, 10, 0, 0, 0, 0, loc)
+	qr := func(issuedAt time.Time) string {
+		return kioskqr.Sign(testKioskConfig.QRSecret, kioskqr.Payload{KioskID: "kiosk-1", StoreID: "store-1", IssuedAt: issuedAt})
+	}
+
+	mockAttendanceRepo := new(MockAttendanceRepo)
+	mockRepo := new(MockEmployeeRepo)
+	mockKioskRepo := new(MockKioskRepo)
+	mockIDGen := new(MockIDGenerator)
+	uc := usecase.NewAttendanceUsecase(mockAttendanceRepo, mockRepo, new(MockStorageRepo), mockKioskRepo, mockIDGen, new(MockNotifier), publisher, authorizer, testPhotoConfig, testKioskConfig, cfg, clk, time.Second)
+
+	revoked := storeKiosk()
+	revokedAt := now.Add(-time.Hour)
+	revoked.RevokedAt = &revokedAt
+
+	// The kiosk lookup and Save are only mocked where a case reaches them, so
+	// looking up a forged kiosk or recording a refused check-in fails the mock.
+	tests := []struct {
+		name     string
+		employee *domain.Employee
+		qr       string
+		setup    func()
+		wantErr  []error
+	}{
+		{
+			name:     "Success - Records Kiosk And Its Location",
+			employee: employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive),
+			qr:       qr(now.Add(-10 * time.Second)),
+			setup: func() {
+				mockKioskRepo.On("FindByID", mock.Anything, "kiosk-1").Return(storeKiosk(), nil).Once()
+				mockAttendanceRepo.On("FindByEmployeeIDAndDate", mock.Anything, "emp-1", today).Return(nil, nil).Once()
+				mockIDGen.On("NewID").Return("att-1", nil).Once()
+				mockAttendanceRepo.On("Save", mock.Anything, mock.MatchedBy(func(a *domain.Attendance) bool {
+					return a.Sessions[0].KioskID == "kiosk-1" && a.Location == "kiosk: Front desk"
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name:     "Fail - Expired Code",
+			employee: employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive),
+			qr:       qr(now.Add(-time.Minute)),
+			setup:    func() {},
+			wantErr:  []error{usecase.InvalidKioskQRError, kioskqr.ErrExpired},
+		},
+		{
+			name:     "Fail - Forged Signature",
+			employee: employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive),
+			qr:       kioskqr.Sign([]byte("other-secret"), kioskqr.Payload{KioskID: "kiosk-1", StoreID: "store-1", IssuedAt: now}),
+			setup:    func() {},
+			wantErr:  []error{usecase.InvalidKioskQRError},
+		},
+		{
+			name:     "Fail - Revoked Kiosk",
+			employee: employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive),
+			qr:       qr(now),
+			setup: func() {
+				mockKioskRepo.On("FindByID", mock.Anything, "kiosk-1").Return(revoked, nil).Once()
+			},
+			wantErr: []error{usecase.InvalidKioskQRError},
+		},
+		{
+			name:     "Fail - Kiosk Of Another Store",
+			employee: storeEmployee("emp-2", domain.RoleStaff, "store-2"),
+			qr:       qr(now),
+			setup: func() {
+				mockKioskRepo.On("FindByID", mock.Anything, "kiosk-1").Return(storeKiosk(), nil).Once()
+			},
+			wantErr: []error{usecase.KioskStoreMismatchError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employeeID := string(tt.employee.ID())
+			mockRepo.On("FindByID", mock.Anything, employeeID).Return(tt.employee, nil).Once()
+			tt.setup()
+
+			id, err := uc.CheckIn(context.Background(), employeeID, attendance.CheckInRequest{QR: tt.qr}, nil)
+
+			if tt.wantErr != nil {
+				for _, want := range tt.wantErr {
+					assert.ErrorIs(t, err, want)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "att-1", id)
+			}
+			mockRepo.AssertExpectations(t)
+			mockKioskRepo.AssertExpectations(t)
+			mockAttendanceRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestKioskUsecase_CheckInWithPIN(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	now := time.Date(2026, 10, 10, 8, 55, 0, 0, loc)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	cfg := &config.Config{AppTimezone: now.Location(), OfficeStartHour: 9, MinBreakMinutes: 15}
+
+	today := time.Date(2026, 10, 10, 0, 0, 0, 0, loc)
+	hash, _ := bcrypt.GenerateFromPassword([]byte("4821"), bcrypt.MinCost)
+	pin := &domain.KioskPIN{EmployeeID: "emp-1", PINHash: string(hash)}
+
+	mockAttendanceRepo := new(MockAttendanceRepo)
+	mockRepo := new(MockEmployeeRepo)
+	mockIDGen := new(MockIDGenerator)
+	mockPINRepo := new(MockKioskPINRepo)
+	mockThrottleRepo := new(MockLoginThrottleRepo)
+	attendanceUsecase := usecase.NewAttendanceUsecase(mockAttendanceRepo, mockRepo, new(MockStorageRepo), new(MockKioskRepo), mockIDGen, new(MockNotifier), publisher, authorizer, testPhotoConfig, testKioskConfig, cfg, clk, time.Second)
+	uc := usecase.NewKioskUsecase(new(MockKioskRepo), mockPINRepo, new(MockProcessedKioskEventRepo), mockRepo, mockThrottleRepo, attendanceUsecase, authorizer, mockIDGen, testKioskConfig, clk, time.Second)
+
+	lockedUntil := now.Add(10 * time.Minute)
+
+	// RegisterFailure and Save are only mocked where a case expects them, so
+	// throttling a good PIN or recording a refused check-in fails the mock.
+	tests := []struct {
+		name     string
+		pin      string
+		throttle *domain.LoginThrottle
+		setup    func()
+		wantErr  error
+		check    func(t *testing.T, id string, err error)
+	}{
+		{
+			name: "Success",
+			pin:  "4821",
+			setup: func() {
+				mockPINRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return(pin, nil).Once()
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive), nil).Once()
+				mockAttendanceRepo.On("FindByEmployeeIDAndDate", mock.Anything, "emp-1", today).Return(nil, nil).Once()
+				mockIDGen.On("NewID").Return("att-1", nil).Once()
+				mockAttendanceRepo.On("Save", mock.Anything, mock.MatchedBy(func(a *domain.Attendance) bool {
+					return a.Sessions[0].KioskID == "kiosk-1"
+				})).Return(nil).Once()
+			},
+			check: func(t *testing.T, id string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "att-1", id)
+			},
+		},
+		{
+			name: "Fail - Wrong PIN Is Throttled",
+			pin:  "0000",
+			setup: func() {
+				mockPINRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return(pin, nil).Once()
+				mockThrottleRepo.On("RegisterFailure", mock.Anything, mock.MatchedBy(func(th *domain.LoginThrottle) bool {
+					return th.Key == "kiosk_pin:emp-1" && th.FailedCount == 1
+				}), mock.Anything, mock.Anything).Return(nil).Once()
+				mockThrottleRepo.On("RegisterFailure", mock.Anything, mock.MatchedBy(func(th *domain.LoginThrottle) bool {
+					return th.Key == "kiosk:kiosk-1" && th.FailedCount == 1
+				}), mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			wantErr: usecase.InvalidKioskPINError,
+		},
+		{
+			name:     "Fail - Locked Employee",
+			pin:      "4821",
+			throttle: &domain.LoginThrottle{Key: "kiosk_pin:emp-1", Scope: domain.ThrottleScopeKioskPIN, FailedCount: 3, LockedUntil: &lockedUntil},
+			setup:    func() {},
+			check: func(t *testing.T, _ string, err error) {
+				var lockedErr *usecase.LoginLockedError
+				if assert.ErrorAs(t, err, &lockedErr) {
+					assert.Equal(t, 10*time.Minute, lockedErr.RetryAfter)
+				}
+			},
+		},
+		{
+			name: "Fail - Suspended Employee",
+			pin:  "4821",
+			setup: func() {
+				mockPINRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return(pin, nil).Once()
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusSuspended), nil).Once()
+			},
+			wantErr: usecase.EmployeeNotActiveError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockThrottleRepo.On("FindByKey", mock.Anything, "kiosk_pin:emp-1").Return(tt.throttle, nil).Once()
+			mockThrottleRepo.On("FindByKey", mock.Anything, "kiosk:kiosk-1").Return(nil, nil).Once()
+			tt.setup()
+
+			id, err := uc.CheckIn(context.Background(), storeKiosk(), "emp-1", tt.pin)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, id, err)
+			}
+			mockThrottleRepo.AssertExpectations(t)
+			mockPINRepo.AssertExpectations(t)
+			mockRepo.AssertExpectations(t)
+			mockAttendanceRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestKioskUsecase_Register(t *testing.T) {
+	now := time.Date(2026, 10, 10, 8, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	cfg := &config.Config{AppTimezone: now.Location(), OfficeStartHour: 9, MinBreakMinutes: 15}
+
+	mockRepo := new(MockEmployeeRepo)
+	mockKioskRepo := new(MockKioskRepo)
+	mockIDGen := new(MockIDGenerator)
+	attendanceUsecase := usecase.NewAttendanceUsecase(new(MockAttendanceRepo), mockRepo, new(MockStorageRepo), mockKioskRepo, mockIDGen, new(MockNotifier), publisher, authorizer, testPhotoConfig, testKioskConfig, cfg, clk, time.Second)
+	uc := usecase.NewKioskUsecase(mockKioskRepo, new(MockKioskPINRepo), new(MockProcessedKioskEventRepo), mockRepo, new(MockLoginThrottleRepo), attendanceUsecase, authorizer, mockIDGen, testKioskConfig, clk, time.Second)
+
+	supervisor := usecase.Actor{ID: "sup-1", Role: domain.RoleSupervisor}
+	supervisorFound := func() {
+		mockRepo.On("FindByID", mock.Anything, "sup-1").Return(employeeWithStatus("sup-1", domain.RoleSupervisor, domain.StatusActive), nil).Once()
+	}
+	var created *domain.Kiosk
+
+	// Create is only mocked for the success case, so registering a refused
+	// kiosk fails the mock.
+	tests := []struct {
+		name       string
+		actor      usecase.Actor
+		storeID    string
+		signingKey string
+		setup      func()
+		wantErr    error
+		check      func(t *testing.T, kiosk *domain.Kiosk, token string)
+	}{
+		{
+			name:       "Success - Defaults To Own Store And Stores Token Hash",
+			actor:      supervisor,
+			signingKey: testKioskPublicKey(),
+			setup: func() {
+				supervisorFound()
+				mockIDGen.On("NewID").Return("kiosk-1", nil).Once()
+				mockKioskRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					created = args.Get(1).(*domain.Kiosk)
+				}).Return(nil).Once()
+			},
+			check: func(t *testing.T, kiosk *domain.Kiosk, token string) {
+				assert.Equal(t, "store-1", kiosk.StoreID)
+				assert.NotEmpty(t, token)
+				assert.NotContains(t, created.TokenHash, token)
+				assert.Equal(t, testKioskKey.Public(), created.SigningKey)
+
+				mockKioskRepo.On("FindByTokenHash", mock.Anything, created.TokenHash).Return(created, nil).Once()
+				authenticated, err := uc.Authenticate(context.Background(), token)
+				assert.NoError(t, err)
+				assert.Equal(t, "kiosk-1", authenticated.ID)
+			},
+		},
+		{
+			name:       "Fail - Other Store",
+			actor:      supervisor,
+			storeID:    "store-2",
+			signingKey: testKioskPublicKey(),
+			setup:      supervisorFound,
+			wantErr:    usecase.ForbiddenError,
+		},
+		{
+			name:       "Fail - Invalid Signing Key",
+			actor:      supervisor,
+			signingKey: "not-a-key",
+			setup:      supervisorFound,
+			wantErr:    usecase.InvalidKioskError,
+		},
+		{
+			name:       "Fail - Staff Cannot Register",
+			actor:      usecase.Actor{ID: "emp-1", Role: domain.RoleStaff},
+			storeID:    "store-1",
+			signingKey: testKioskPublicKey(),
+			setup:      func() {},
+			wantErr:    usecase.ForbiddenError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			kiosk, token, err := uc.Register(context.Background(), tt.actor, tt.storeID, "Front desk", tt.signingKey)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				tt.check(t, kiosk, token)
+			}
+			mockRepo.AssertExpectations(t)
+			mockKioskRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func signedKioskEvent(id string, eventType domain.KioskEventType, employeeID string, pin string, at time.Time) domain.KioskEvent {
+	event := domain.KioskEvent{ID: id, Type: eventType, EmployeeID: employeeID, PIN: pin, OccurredAt: at}
+	event.Signature = kiosksig.Sign(testKioskKey, kiosksig.Event{ID: id, Type: string(eventType), EmployeeID: employeeID, OccurredAt: at})
+	return event
+}
+
+func TestKioskUsecase_Sync(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	now := time.Date(2026, 10, 10, 17, 31, 0, 0, loc)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	cfg := &config.Config{AppTimezone: now.Location(), OfficeStartHour: 9, MinBreakMinutes: 15}
+
+	sentAt := time.Date(2026, 10, 10, 17, 30, 0, 0, loc)
+	today := time.Date(2026, 10, 10, 0, 0, 0, 0, loc)
+	hash, _ := bcrypt.GenerateFromPassword([]byte("4821"), bcrypt.MinCost)
+	pin := &domain.KioskPIN{EmployeeID: "emp-1", PINHash: string(hash)}
+
+	mockAttendanceRepo := new(MockAttendanceRepo)
+	mockRepo := new(MockEmployeeRepo)
+	mockIDGen := new(MockIDGenerator)
+	mockPINRepo := new(MockKioskPINRepo)
+	mockSyncRepo := new(MockProcessedKioskEventRepo)
+	mockThrottleRepo := new(MockLoginThrottleRepo)
+	attendanceUsecase := usecase.NewAttendanceUsecase(mockAttendanceRepo, mockRepo, new(MockStorageRepo), new(MockKioskRepo), mockIDGen, new(MockNotifier), publisher, authorizer, testPhotoConfig, testKioskConfig, cfg, clk, time.Second)
+	uc := usecase.NewKioskUsecase(new(MockKioskRepo), mockPINRepo, mockSyncRepo, mockRepo, mockThrottleRepo, attendanceUsecase, authorizer, mockIDGen, testKioskConfig, clk, time.Second)
+
+	// expectPIN mocks the PIN check done once per employee and batch, and the
+	// employee lookup done for each of the employee's punches.
+	expectPIN := func(status domain.Status, punches int) {
+		mockThrottleRepo.On("FindByKey", mock.Anything, "kiosk_pin:emp-1").Return(nil, nil).Once()
+		mockThrottleRepo.On("FindByKey", mock.Anything, "kiosk:kiosk-1").Return(nil, nil).Once()
+		mockPINRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return(pin, nil).Once()
+		mockRepo.On("FindByID", mock.Anything, "emp-1").Return(employeeWithStatus("emp-1", domain.RoleStaff, status), nil).Times(punches)
+	}
+	checkedIn := func() *domain.Attendance {
+		return &domain.Attendance{ID: "att-1", EmployeeID: "emp-1", CheckIn: "2026-10-10 08:50:00", Date: today,
+			Sessions: []domain.WorkSession{{CheckIn: time.Date(2026, 10, 10, 8, 50, 0, 0, loc)}}}
+	}
+	checkIn := signedKioskEvent("ev-1", domain.KioskEventCheckIn, "emp-1", "4821", time.Date(2026, 10, 10, 8, 50, 0, 0, loc))
+
+	tampered := checkIn
+	tampered.OccurredAt = tampered.OccurredAt.Add(-time.Hour)
+
+	_, otherKey, _ := ed25519.GenerateKey(nil)
+	foreign := domain.KioskEvent{ID: "ev-1", Type: domain.KioskEventCheckIn, EmployeeID: "emp-1", PIN: "4821", OccurredAt: checkIn.OccurredAt}
+	foreign.Signature = kiosksig.Sign(otherKey, kiosksig.Event{ID: "ev-1", Type: string(domain.KioskEventCheckIn), EmployeeID: "emp-1", OccurredAt: checkIn.OccurredAt})
+
+	legacy := storeKiosk()
+	legacy.SigningKey = nil
+
+	afternoon := checkedIn()
+	lunch := checkedIn()
+
+	// Only the calls a case reaches are mocked, so remembering a rejected
+	// signature or applying a resent event fails the mock.
+	tests := []struct {
+		name    string
+		kiosk   *domain.Kiosk
+		sentAt  time.Time
+		events  []domain.KioskEvent
+		setup   func()
+		wantErr error
+		check   func(t *testing.T, results []usecase.KioskSyncResult)
+	}{
+		{
+			name:   "Success - Applies In Order Of Occurrence At Kiosk Time",
+			events: []domain.KioskEvent{signedKioskEvent("ev-2", domain.KioskEventCheckOut, "emp-1", "4821", time.Date(2026, 10, 10, 17, 0, 0, 0, loc)), checkIn},
+			setup: func() {
+				expectPIN(domain.StatusActive, 2)
+				mockSyncRepo.On("Find", mock.Anything, "kiosk-1", "ev-1").Return(nil, nil).Once()
+				mockSyncRepo.On("Find", mock.Anything, "kiosk-1", "ev-2").Return(nil, nil).Once()
+				mockAttendanceRepo.On("FindByEmployeeIDAndDate", mock.Anything, "emp-1", today).Return(nil, nil).Once()
+				mockIDGen.On("NewID").Return("att-1", nil).Once()
+				mockAttendanceRepo.On("Save", mock.Anything, mock.MatchedBy(func(a *domain.Attendance) bool {
+					return a.CheckIn == "2026-10-10 08:50:00" && !a.IsLate
+				})).Return(nil).Once()
+				mockAttendanceRepo.On("FindByEmployeeIDAndDate", mock.Anything, "emp-1", today).Return(afternoon, nil).Once()
+				mockAttendanceRepo.On("Update", mock.Anything, afternoon).Return(nil).Once()
+				mockSyncRepo.On("Save", mock.Anything, mock.MatchedBy(func(e *domain.ProcessedKioskEvent) bool {
+					return e.Status == domain.KioskEventApplied
+				})).Return(nil).Twice()
+			},
+			check: func(t *testing.T, results []usecase.KioskSyncResult) {
+				assert.Equal(t, []usecase.KioskSyncResult{
+					{EventID: "ev-2", Status: domain.KioskEventApplied},
+					{EventID: "ev-1", Status: domain.KioskEventApplied, AttendanceID: "att-1"},
+				}, results)
+				assert.Equal(t, "2026-10-10 17:00:00", *afternoon.CheckOut)
+			},
+		},
+		{
+			name:   "Success - Resent Event Is Not Applied Again",
+			events: []domain.KioskEvent{checkIn},
+			setup: func() {
+				mockSyncRepo.On("Find", mock.Anything, "kiosk-1", "ev-1").Return(&domain.ProcessedKioskEvent{KioskID: "kiosk-1", EventID: "ev-1", Status: domain.KioskEventApplied, AttendanceID: "att-1"}, nil).Once()
+			},
+			check: func(t *testing.T, results []usecase.KioskSyncResult) {
+				assert.Equal(t, []usecase.KioskSyncResult{{EventID: "ev-1", Status: domain.KioskEventDuplicate, AttendanceID: "att-1"}}, results)
+			},
+		},
+		{
+			name:   "Fail - Tampered Event Is Rejected And Not Remembered",
+			events: []domain.KioskEvent{tampered},
+			setup:  func() {},
+			check: func(t *testing.T, results []usecase.KioskSyncResult) {
+				assert.Equal(t, domain.KioskEventRejected, results[0].Status)
+			},
+		},
+		{
+			name: "Success - Applies Breaks",
+			events: []domain.KioskEvent{
+				signedKioskEvent("ev-5", domain.KioskEventBreakEnd, "emp-1", "4821", time.Date(2026, 10, 10, 12, 30, 0, 0, loc)),
+				signedKioskEvent("ev-4", domain.KioskEventBreakStart, "emp-1", "4821", time.Date(2026, 10, 10, 12, 0, 0, 0, loc)),
+			},
+			setup: func() {
+				expectPIN(domain.StatusActive, 2)
+				mockSyncRepo.On("Find", mock.Anything, "kiosk-1", "ev-4").Return(nil, nil).Once()
+				mockSyncRepo.On("Find", mock.Anything, "kiosk-1", "ev-5").Return(nil, nil).Once()
+				mockAttendanceRepo.On("FindByEmployeeIDAndDate", mock.Anything, "emp-1", today).Return(lunch, nil).Twice()
+				mockAttendanceRepo.On("Update", mock.Anything, lunch).Return(nil).Twice()
+				mockSyncRepo.On("Save", mock.Anything, mock.Anything).Return(nil).Twice()
+			},
+			check: func(t *testing.T, results []usecase.KioskSyncResult) {
+				assert.Equal(t, domain.KioskEventApplied, results[0].Status)
+				assert.Equal(t, domain.KioskEventApplied, results[1].Status)
+				if assert.Len(t, lunch.Sessions[0].Breaks, 1) {
+					assert.Equal(t, 30*time.Minute, lunch.Sessions[0].Breaks[0].Duration())
+				}
+			},
+		},
+		{
+			name:   "Fail - Check-In While Checked In Is Rejected And Remembered",
+			events: []domain.KioskEvent{signedKioskEvent("ev-3", domain.KioskEventCheckIn, "emp-1", "4821", time.Date(2026, 10, 10, 13, 0, 0, 0, loc))},
+			setup: func() {
+				expectPIN(domain.StatusActive, 1)
+				mockSyncRepo.On("Find", mock.Anything, "kiosk-1", "ev-3").Return(nil, nil).Once()
+				mockAttendanceRepo.On("FindByEmployeeIDAndDate", mock.Anything, "emp-1", today).Return(checkedIn(), nil).Once()
+				mockSyncRepo.On("Save", mock.Anything, mock.MatchedBy(func(e *domain.ProcessedKioskEvent) bool {
+					return e.EventID == "ev-3" && e.Status == domain.KioskEventRejected && e.Error == usecase.AlreadyCheckedInError.Error()
+				})).Return(nil).Once()
+			},
+			check: func(t *testing.T, results []usecase.KioskSyncResult) {
+				assert.Equal(t, domain.KioskEventRejected, results[0].Status)
+			},
+		},
+		{
+			name:   "Fail - Event Of Onboarding Employee Is Rejected And Remembered",
+			events: []domain.KioskEvent{checkIn},
+			setup: func() {
+				expectPIN(domain.StatusOnboarding, 1)
+				mockSyncRepo.On("Find", mock.Anything, "kiosk-1", "ev-1").Return(nil, nil).Once()
+				mockSyncRepo.On("Save", mock.Anything, mock.MatchedBy(func(e *domain.ProcessedKioskEvent) bool {
+					return e.EventID == "ev-1" && e.Status == domain.KioskEventRejected && e.Error == usecase.EmployeeNotActiveError.Error()
+				})).Return(nil).Once()
+			},
+			check: func(t *testing.T, results []usecase.KioskSyncResult) {
+				assert.Equal(t, domain.KioskEventRejected, results[0].Status)
+			},
+		},
+		{
+			name:    "Fail - Kiosk Clock Too Far Off",
+			sentAt:  now.Add(-time.Hour),
+			events:  []domain.KioskEvent{checkIn},
+			setup:   func() {},
+			wantErr: usecase.KioskClockSkewError,
+		},
+		{
+			name:   "Fail - Event Signed With Another Key Is Rejected",
+			events: []domain.KioskEvent{foreign},
+			setup:  func() {},
+			check: func(t *testing.T, results []usecase.KioskSyncResult) {
+				assert.Equal(t, domain.KioskEventRejected, results[0].Status)
+			},
+		},
+		{
+			name:    "Fail - Kiosk Without Signing Key",
+			kiosk:   legacy,
+			events:  []domain.KioskEvent{checkIn},
+			setup:   func() {},
+			wantErr: usecase.InvalidKioskSyncError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kiosk, at := tt.kiosk, tt.sentAt
+			if kiosk == nil {
+				kiosk = storeKiosk()
+			}
+			if at.IsZero() {
+				at = sentAt
+			}
+			tt.setup()
+
+			results, err := uc.Sync(context.Background(), kiosk, at, tt.events)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				tt.check(t, results)
+			}
+			mockThrottleRepo.AssertExpectations(t)
+			mockPINRepo.AssertExpectations(t)
+			mockRepo.AssertExpectations(t)
+			mockAttendanceRepo.AssertExpectations(t)
+			mockSyncRepo.AssertExpectations(t)
+		})
+	}
+}
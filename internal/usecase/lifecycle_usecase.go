@@ -0,0 +1,309 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+)
+
+// LifecycleUsecase moves employees through onboarding → active ↔ suspended → terminated.
+// Every change needs a reason and an effective date; changes dated in the future are
+// scheduled and applied by ApplyDue once their date is reached.
+type LifecycleUsecase struct {
+	employeeRepo EmployeeRepository
+	changeRepo   StatusChangeRepository
+	kioskPINRepo KioskPINRepository
+	publisher    EventPublisher
+	authorizer   *Authorizer
+	idGen        IDGenerator
+	clock        clock.Clock
+	location     *time.Location
+	ctxTimeout   time.Duration
+}
+
+func NewLifecycleUsecase(employeeRepo EmployeeRepository, changeRepo StatusChangeRepository, kioskPINRepo KioskPINRepository, publisher EventPublisher, authorizer *Authorizer, idGen IDGenerator, clk clock.Clock, location *time.Location, timeout time.Duration) *LifecycleUsecase {
+	return &LifecycleUsecase{
+		employeeRepo: employeeRepo,
+		changeRepo:   changeRepo,
+		kioskPINRepo: kioskPINRepo,
+		publisher:    publisher,
+		authorizer:   authorizer,
+		idGen:        idGen,
+		clock:        clk,
+		location:     location,
+		ctxTimeout:   timeout,
+	}
+}
+
+// Transition changes the status of an employee as of effectiveDate (YYYY-MM-DD in the
+// application timezone). Past and current dates apply immediately. A non-empty from
+// restricts the change to employees in that status, e.g. to tell activating an
+// onboarding employee from reactivating a suspended one.
+func (uc *LifecycleUsecase) Transition(ctx context.Context, actor Actor, employeeID string, from, to domain.Status, reason string, effectiveDate string) (*domain.StatusChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermEmployeeLifecycle); err != nil {
+		return nil, err
+	}
+
+	// Nobody can suspend, reactivate or terminate themselves
+	if actor.IsSelf(employeeID) {
+		return nil, ForbiddenError
+	}
+
+	effectiveAt, err := time.ParseInLocation("2006-01-02", effectiveDate, uc.location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid effective date: %w", err)
+	}
+
+	employee, err := uc.employeeRepo.FindByID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find employee: %w", err)
+	}
+	if employee == nil {
+		return nil, EmployeeNotFoundError
+	}
+
+	if err := uc.authorizer.AuthorizeHierarchy(ctx, actor, employee); err != nil {
+		return nil, err
+	}
+
+	id, err := uc.idGen.NewID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ID: %w", err)
+	}
+
+	now := uc.clock.Now()
+	change := domain.NewStatusChange(id, employeeID, to, reason, effectiveAt, actor.ID, now)
+	change.From = from
+
+	if effectiveAt.After(now) {
+		return change, uc.schedule(ctx, employee, change)
+	}
+
+	if err := uc.applyNow(ctx, employee, change); err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// ApplySystemTransition changes the status of an employee right away on behalf of the
+// system, e.g. when another service reports that the employee's store has closed. The
+// change is recorded without an actor.
+func (uc *LifecycleUsecase) ApplySystemTransition(ctx context.Context, employee *domain.Employee, to domain.Status, reason string) (*domain.StatusChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	id, err := uc.idGen.NewID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ID: %w", err)
+	}
+
+	now := uc.clock.Now()
+	change := domain.NewStatusChange(id, string(employee.ID()), to, reason, now, "", now)
+
+	if err := uc.applyNow(ctx, employee, change); err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// History returns the status changes of an employee, newest first.
+func (uc *LifecycleUsecase) History(ctx context.Context, actor Actor, employeeID string) ([]*domain.StatusChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermEmployeeLifecycle); err != nil {
+		return nil, err
+	}
+
+	changes, err := uc.changeRepo.FindByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find status changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// ApplyDue applies the scheduled status changes whose effective date has been reached.
+// Changes that are no longer allowed (e.g. the employee was terminated in the meantime)
+// are marked as failed. It returns the number of applied changes.
+func (uc *LifecycleUsecase) ApplyDue(ctx context.Context) (int, error) {
+	listCtx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	due, err := uc.changeRepo.FindDue(listCtx, uc.clock.Now())
+	cancel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to find due status changes: %w", err)
+	}
+
+	applied := 0
+	for _, change := range due {
+		applyCtx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+		err := uc.applyScheduled(applyCtx, change)
+		cancel()
+		if err != nil {
+			// Left scheduled, so the next run tries again
+			slog.Log(ctx, slog.LevelWarn, "Failed to apply scheduled status change", "ID", change.EmployeeID, "changeID", change.ID, "error", err)
+			continue
+		}
+		if change.State == domain.StatusChangeApplied {
+			applied++
+		}
+	}
+
+	return applied, nil
+}
+
+func (uc *LifecycleUsecase) schedule(ctx context.Context, employee *domain.Employee, change *domain.StatusChange) error {
+	// Checked again when the change is applied, the status may change until then
+	if err := change.CheckFrom(employee.Status()); err != nil {
+		return statusTransitionError(err)
+	}
+	if !employee.Status().CanTransitionTo(change.To) {
+		return fmt.Errorf("%w: %s to %s", InvalidStatusTransitionError, employee.Status(), change.To)
+	}
+
+	existing, err := uc.changeRepo.FindByEmployeeID(ctx, change.EmployeeID)
+	if err != nil {
+		return fmt.Errorf("failed to find status changes: %w", err)
+	}
+	for _, c := range existing {
+		if c.State == domain.StatusChangeScheduled {
+			return StatusChangeAlreadyScheduledError
+		}
+	}
+
+	if err := uc.changeRepo.Save(ctx, change); err != nil {
+		return fmt.Errorf("failed to save status change: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Scheduled employee status change", "ID", change.EmployeeID, "to", change.To, "effectiveAt", change.EffectiveAt, "actorID", change.ActorID)
+
+	return nil
+}
+
+func (uc *LifecycleUsecase) applyNow(ctx context.Context, employee *domain.Employee, change *domain.StatusChange) error {
+	now := uc.clock.Now()
+
+	from := employee.Status()
+	if err := change.CheckFrom(from); err != nil {
+		return statusTransitionError(err)
+	}
+	if err := employee.TransitionTo(change.To, change.EffectiveAt, now); err != nil {
+		return statusTransitionError(err)
+	}
+
+	if err := uc.employeeRepo.Update(ctx, employee); err != nil {
+		return fmt.Errorf("failed to update employee status: %w", err)
+	}
+
+	change.MarkApplied(from, now)
+	if err := uc.changeRepo.Save(ctx, change); err != nil {
+		return fmt.Errorf("failed to save status change: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Changed employee status", "ID", change.EmployeeID, "from", from, "to", change.To, "actorID", change.ActorID)
+	uc.dropKioskPIN(ctx, change)
+	uc.publishStatusChange(ctx, employee, change)
+
+	return nil
+}
+
+func (uc *LifecycleUsecase) applyScheduled(ctx context.Context, change *domain.StatusChange) error {
+	now := uc.clock.Now()
+
+	employee, err := uc.employeeRepo.FindByID(ctx, change.EmployeeID)
+	if err != nil && !errors.Is(err, domain.ErrEmployeeNotFound) {
+		return fmt.Errorf("failed to find employee: %w", err)
+	}
+	if employee == nil {
+		change.MarkFailed("employee not found", now)
+		return uc.saveChange(ctx, change)
+	}
+
+	from := employee.Status()
+	err = change.CheckFrom(from)
+	if err == nil {
+		err = employee.TransitionTo(change.To, change.EffectiveAt, now)
+	}
+	if err != nil {
+		change.MarkFailed(err.Error(), now)
+		return uc.saveChange(ctx, change)
+	}
+
+	if err := uc.employeeRepo.Update(ctx, employee); err != nil {
+		return fmt.Errorf("failed to update employee status: %w", err)
+	}
+
+	change.MarkApplied(from, now)
+	slog.Log(ctx, slog.LevelInfo, "Applied scheduled employee status change", "ID", change.EmployeeID, "from", from, "to", change.To)
+
+	if err := uc.saveChange(ctx, change); err != nil {
+		return err
+	}
+	uc.dropKioskPIN(ctx, change)
+	uc.publishStatusChange(ctx, employee, change)
+
+	return nil
+}
+
+// dropKioskPIN deletes the kiosk PIN of an employee who may no longer sign in, so it
+// cannot be used at a store kiosk; the employee sets a new one once reactivated. Kiosks
+// also check the status, so a failure is logged instead of returned.
+func (uc *LifecycleUsecase) dropKioskPIN(ctx context.Context, change *domain.StatusChange) {
+	if change.To.CanSignIn() {
+		return
+	}
+	if err := uc.kioskPINRepo.DeleteByEmployeeID(ctx, change.EmployeeID); err != nil {
+		slog.Log(ctx, slog.LevelError, "Failed to delete kiosk PIN", "ID", change.EmployeeID, "error", err)
+	}
+}
+
+// statusChangedEvent is the data of the employee.* webhook events.
+type statusChangedEvent struct {
+	EmployeeID  string    `json:"employee_id"`
+	StoreID     string    `json:"store_id,omitempty"`
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	EffectiveAt time.Time `json:"effective_at"`
+}
+
+// publishStatusChange tells integration partners about an applied change. The change
+// is already stored, so a failure is logged instead of returned.
+func (uc *LifecycleUsecase) publishStatusChange(ctx context.Context, employee *domain.Employee, change *domain.StatusChange) {
+	event, ok := domain.WebhookEventForStatus(change.To)
+	if !ok {
+		return
+	}
+
+	data := statusChangedEvent{
+		EmployeeID:  change.EmployeeID,
+		StoreID:     employee.StoreID(),
+		From:        string(change.From),
+		To:          string(change.To),
+		EffectiveAt: change.EffectiveAt.UTC(),
+	}
+	if err := uc.publisher.Publish(ctx, event, data); err != nil {
+		slog.Log(ctx, slog.LevelError, "Failed to publish status change", "ID", change.EmployeeID, "event", event, "error", err)
+	}
+}
+
+func (uc *LifecycleUsecase) saveChange(ctx context.Context, change *domain.StatusChange) error {
+	if err := uc.changeRepo.Save(ctx, change); err != nil {
+		return fmt.Errorf("failed to save status change: %w", err)
+	}
+	return nil
+}
+
+func statusTransitionError(err error) error {
+	if errors.Is(err, domain.ErrInvalidStatusTransition) {
+		return fmt.Errorf("%w: %v", InvalidStatusTransitionError, err)
+	}
+	return err
+}
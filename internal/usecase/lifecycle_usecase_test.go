@@ -0,0 +1,345 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+func employeeWithStatus(id string, role domain.Role, status domain.Status) *domain.Employee {
+	emp, _ := domain.ReconstituteEmployee(domain.ReconstituteEmployeeParams{
+		ID:      id,
+		Name:    "Jane Doe",
+		Email:   "jane@shop.local",
+		Role:    string(role),
+		Status:  string(status),
+		StoreID: "store-1",
+	})
+	return emp
+}
+
+func TestLifecycleUsecase_Transition(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	idGen := new(MockIDGenerator)
+	idGen.On("NewID").Return("change-1", nil)
+
+	mockRepo := new(MockEmployeeRepo)
+	mockChangeRepo := new(MockStatusChangeRepo)
+	mockKioskPINRepo := new(MockKioskPINRepo)
+	mockPublisher := new(MockEventPublisher)
+	uc := usecase.NewLifecycleUsecase(mockRepo, mockChangeRepo, mockKioskPINRepo, mockPublisher, authorizer, idGen, clk, time.UTC, 2*time.Second)
+
+	admin := usecase.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+	supervisor := usecase.Actor{ID: "spv-1", Role: domain.RoleSupervisor}
+	staff := usecase.Actor{ID: "staff-1", Role: domain.RoleStaff}
+
+	// applied expects the writes of a change that takes effect now.
+	applied := func(emp *domain.Employee, event domain.WebhookEvent) {
+		mockRepo.On("FindByID", mock.Anything, "emp-1").Return(emp, nil).Once()
+		mockRepo.On("Update", mock.Anything, emp).Return(nil).Once()
+		mockChangeRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.StatusChange")).Return(nil).Once()
+		mockKioskPINRepo.On("DeleteByEmployeeID", mock.Anything, "emp-1").Return(nil).Once()
+		mockPublisher.On("Publish", mock.Anything, event, mock.Anything).Return(nil).Once()
+	}
+	found := func(emp *domain.Employee) {
+		mockRepo.On("FindByID", mock.Anything, "emp-1").Return(emp, nil).Once()
+	}
+
+	// Writes are only mocked for the cases that make them, so an Update, Save or
+	// Publish in a refused or scheduled case fails the mock.
+	tests := []struct {
+		name          string
+		actor         usecase.Actor
+		employeeID    string
+		emp           *domain.Employee
+		from, to      domain.Status
+		effectiveDate string
+		setup         func(emp *domain.Employee)
+		wantErr       error
+		check         func(t *testing.T, change *domain.StatusChange, emp *domain.Employee)
+	}{
+		{
+			name:          "Success - Suspend Revokes Sessions",
+			actor:         admin,
+			employeeID:    "emp-1",
+			emp:           employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive),
+			to:            domain.StatusSuspended,
+			effectiveDate: "2026-03-01",
+			setup: func(emp *domain.Employee) {
+				applied(emp, domain.WebhookEmployeeSuspended)
+			},
+			check: func(t *testing.T, change *domain.StatusChange, emp *domain.Employee) {
+				assert.Equal(t, domain.StatusChangeApplied, change.State)
+				assert.Equal(t, domain.StatusActive, change.From)
+				assert.Equal(t, domain.StatusSuspended, emp.Status())
+				assert.NotNil(t, emp.SessionsRevokedAt())
+				assert.False(t, emp.IsSessionValid(now.Add(-time.Minute)))
+			},
+		},
+		{
+			name:          "Success - Terminate Sets Termination Date",
+			actor:         admin,
+			employeeID:    "emp-1",
+			emp:           employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusSuspended),
+			to:            domain.StatusTerminated,
+			effectiveDate: "2026-02-28",
+			setup: func(emp *domain.Employee) {
+				applied(emp, domain.WebhookEmployeeTerminated)
+			},
+			check: func(t *testing.T, _ *domain.StatusChange, emp *domain.Employee) {
+				assert.Equal(t, domain.StatusTerminated, emp.Status())
+				if assert.NotNil(t, emp.TerminatedAt()) {
+					assert.Equal(t, time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC), *emp.TerminatedAt())
+				}
+			},
+		},
+		{
+			name:          "Success - Future Date Is Scheduled",
+			actor:         admin,
+			employeeID:    "emp-1",
+			emp:           employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive),
+			to:            domain.StatusTerminated,
+			effectiveDate: "2026-03-31",
+			setup: func(emp *domain.Employee) {
+				found(emp)
+				mockChangeRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return([]*domain.StatusChange{}, nil).Once()
+				mockChangeRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.StatusChange")).Return(nil).Once()
+			},
+			check: func(t *testing.T, change *domain.StatusChange, emp *domain.Employee) {
+				assert.Equal(t, domain.StatusChangeScheduled, change.State)
+				assert.Equal(t, domain.StatusActive, emp.Status())
+			},
+		},
+		{
+			name:          "Fail - Second Scheduled Change",
+			actor:         admin,
+			employeeID:    "emp-1",
+			emp:           employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive),
+			to:            domain.StatusSuspended,
+			effectiveDate: "2026-03-05",
+			setup: func(emp *domain.Employee) {
+				pending := domain.NewStatusChange("change-0", "emp-1", domain.StatusTerminated, "resignation", now.AddDate(0, 0, 10), "admin-1", now)
+				found(emp)
+				mockChangeRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return([]*domain.StatusChange{pending}, nil).Once()
+			},
+			wantErr: usecase.StatusChangeAlreadyScheduledError,
+		},
+		{
+			name:          "Fail - Invalid Transition",
+			actor:         admin,
+			employeeID:    "emp-1",
+			emp:           employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusTerminated),
+			to:            domain.StatusActive,
+			effectiveDate: "2026-03-01",
+			setup:         found,
+			wantErr:       usecase.InvalidStatusTransitionError,
+		},
+		{
+			name:          "Fail - Reactivate Onboarding Employee",
+			actor:         admin,
+			employeeID:    "emp-1",
+			emp:           employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusOnboarding),
+			from:          domain.StatusSuspended,
+			to:            domain.StatusActive,
+			effectiveDate: "2026-03-01",
+			setup:         found,
+			wantErr:       usecase.InvalidStatusTransitionError,
+			check: func(t *testing.T, _ *domain.StatusChange, emp *domain.Employee) {
+				assert.Equal(t, domain.StatusOnboarding, emp.Status())
+			},
+		},
+		{
+			name:          "Fail - Activate Suspended Employee",
+			actor:         admin,
+			employeeID:    "emp-1",
+			emp:           employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusSuspended),
+			from:          domain.StatusOnboarding,
+			to:            domain.StatusActive,
+			effectiveDate: "2026-03-10",
+			setup:         found,
+			wantErr:       usecase.InvalidStatusTransitionError,
+		},
+		{
+			name:          "Fail - Own Status",
+			actor:         admin,
+			employeeID:    "admin-1",
+			to:            domain.StatusSuspended,
+			effectiveDate: "2026-03-01",
+			setup:         func(*domain.Employee) {},
+			wantErr:       usecase.ForbiddenError,
+		},
+		{
+			name:          "Fail - Supervisor On Higher Role",
+			actor:         supervisor,
+			employeeID:    "emp-1",
+			emp:           employeeWithStatus("emp-1", domain.RoleAdmin, domain.StatusActive),
+			to:            domain.StatusSuspended,
+			effectiveDate: "2026-03-01",
+			setup:         found,
+			wantErr:       usecase.ForbiddenError,
+		},
+		{
+			name:          "Fail - Staff Not Allowed",
+			actor:         staff,
+			employeeID:    "emp-1",
+			to:            domain.StatusSuspended,
+			effectiveDate: "2026-03-01",
+			setup:         func(*domain.Employee) {},
+			wantErr:       usecase.ForbiddenError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(tt.emp)
+
+			change, err := uc.Transition(context.Background(), tt.actor, tt.employeeID, tt.from, tt.to, "reason", tt.effectiveDate)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.check != nil {
+				tt.check(t, change, tt.emp)
+			}
+			mockRepo.AssertExpectations(t)
+			mockChangeRepo.AssertExpectations(t)
+			mockKioskPINRepo.AssertExpectations(t)
+			mockPublisher.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLifecycleUsecase_ApplyDue(t *testing.T) {
+	now := time.Date(2026, 3, 31, 0, 5, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+	effective := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	mockRepo := new(MockEmployeeRepo)
+	mockChangeRepo := new(MockStatusChangeRepo)
+	mockKioskPINRepo := new(MockKioskPINRepo)
+	mockPublisher := new(MockEventPublisher)
+	uc := usecase.NewLifecycleUsecase(mockRepo, mockChangeRepo, mockKioskPINRepo, mockPublisher, authorizer, new(MockIDGenerator), clk, time.UTC, 2*time.Second)
+
+	scheduled := func(id, employeeID string, from, to domain.Status) *domain.StatusChange {
+		change := domain.NewStatusChange(id, employeeID, to, "reason", effective, "admin-1", now.AddDate(0, 0, -30))
+		change.From = from
+		return change
+	}
+	resigning := employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusActive)
+
+	tests := []struct {
+		name        string
+		changes     []*domain.StatusChange
+		setup       func(changes []*domain.StatusChange)
+		wantApplied int
+		wantStates  []domain.StatusChangeState
+		check       func(t *testing.T, changes []*domain.StatusChange)
+	}{
+		{
+			name:    "Success - Applies Due Change",
+			changes: []*domain.StatusChange{scheduled("change-1", "emp-1", "", domain.StatusTerminated)},
+			setup: func(changes []*domain.StatusChange) {
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(resigning, nil).Once()
+				mockRepo.On("Update", mock.Anything, resigning).Return(nil).Once()
+				mockChangeRepo.On("Save", mock.Anything, changes[0]).Return(nil).Once()
+				mockKioskPINRepo.On("DeleteByEmployeeID", mock.Anything, "emp-1").Return(nil).Once()
+				mockPublisher.On("Publish", mock.Anything, domain.WebhookEmployeeTerminated, mock.Anything).Return(nil).Once()
+			},
+			wantApplied: 1,
+			wantStates:  []domain.StatusChangeState{domain.StatusChangeApplied},
+			check: func(t *testing.T, _ []*domain.StatusChange) {
+				assert.Equal(t, domain.StatusTerminated, resigning.Status())
+				assert.NotNil(t, resigning.SessionsRevokedAt())
+			},
+		},
+		{
+			name:    "Success - Marks No Longer Allowed Change As Failed",
+			changes: []*domain.StatusChange{scheduled("change-1", "emp-1", "", domain.StatusSuspended)},
+			setup: func(changes []*domain.StatusChange) {
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusTerminated), nil).Once()
+				mockChangeRepo.On("Save", mock.Anything, changes[0]).Return(nil).Once()
+			},
+			wantStates: []domain.StatusChangeState{domain.StatusChangeFailed},
+			check: func(t *testing.T, changes []*domain.StatusChange) {
+				assert.NotEmpty(t, changes[0].Error)
+			},
+		},
+		{
+			// An update here would fail the mock, so the suspension stays in place.
+			name:    "Success - Scheduled Activation Does Not Lift A Suspension",
+			changes: []*domain.StatusChange{scheduled("change-1", "emp-1", domain.StatusOnboarding, domain.StatusActive)},
+			setup: func(changes []*domain.StatusChange) {
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(employeeWithStatus("emp-1", domain.RoleStaff, domain.StatusSuspended), nil).Once()
+				mockChangeRepo.On("Save", mock.Anything, changes[0]).Return(nil).Once()
+			},
+			wantStates: []domain.StatusChangeState{domain.StatusChangeFailed},
+		},
+		{
+			name: "Success - Lookup Error Leaves Change Scheduled And Continues",
+			changes: []*domain.StatusChange{
+				scheduled("change-1", "emp-1", "", domain.StatusTerminated),
+				scheduled("change-2", "emp-2", "", domain.StatusTerminated),
+			},
+			setup: func(changes []*domain.StatusChange) {
+				emp := employeeWithStatus("emp-2", domain.RoleStaff, domain.StatusActive)
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(nil, errors.New("timeout")).Once()
+				mockRepo.On("FindByID", mock.Anything, "emp-2").Return(emp, nil).Once()
+				mockRepo.On("Update", mock.Anything, emp).Return(nil).Once()
+				mockChangeRepo.On("Save", mock.Anything, changes[1]).Return(nil).Once()
+				mockKioskPINRepo.On("DeleteByEmployeeID", mock.Anything, "emp-2").Return(nil).Once()
+				mockPublisher.On("Publish", mock.Anything, domain.WebhookEmployeeTerminated, mock.Anything).Return(nil).Once()
+			},
+			wantApplied: 1,
+			wantStates:  []domain.StatusChangeState{domain.StatusChangeScheduled, domain.StatusChangeApplied},
+		},
+		{
+			name:    "Success - Missing Employee Marks Change As Failed",
+			changes: []*domain.StatusChange{scheduled("change-1", "emp-1", "", domain.StatusTerminated)},
+			setup: func(changes []*domain.StatusChange) {
+				mockRepo.On("FindByID", mock.Anything, "emp-1").Return(nil, domain.ErrEmployeeNotFound).Once()
+				mockChangeRepo.On("Save", mock.Anything, changes[0]).Return(nil).Once()
+			},
+			wantStates: []domain.StatusChangeState{domain.StatusChangeFailed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockChangeRepo.On("FindDue", mock.Anything, now).Return(tt.changes, nil).Once()
+			tt.setup(tt.changes)
+
+			applied, err := uc.ApplyDue(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantApplied, applied)
+			for i, change := range tt.changes {
+				assert.Equal(t, tt.wantStates[i], change.State, change.ID)
+			}
+			if tt.check != nil {
+				tt.check(t, tt.changes)
+			}
+			mockRepo.AssertExpectations(t)
+			mockChangeRepo.AssertExpectations(t)
+			mockKioskPINRepo.AssertExpectations(t)
+			mockPublisher.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type LoginThrottleRepository interface {
+	FindByKey(ctx context.Context, key string) (*domain.LoginThrottle, error)
+	// RegisterFailure records a failed attempt on throttle under policy. Concurrent
+	// failures on the same key must all be counted, so the repository applies the
+	// failure to the stored state, not to what the caller read, and copies the result
+	// back into throttle.
+	RegisterFailure(ctx context.Context, throttle *domain.LoginThrottle, now time.Time, policy domain.LoginThrottlePolicy) error
+	DeleteByKey(ctx context.Context, key string) error
+}
@@ -0,0 +1,34 @@
+package usecase_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type MockLoginThrottleRepo struct {
+	mock.Mock
+}
+
+func (m *MockLoginThrottleRepo) FindByKey(ctx context.Context, key string) (*domain.LoginThrottle, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LoginThrottle), args.Error(1)
+}
+
+// RegisterFailure applies the failure like the repository does, before the expectation
+// is matched, so tests can check the resulting count.
+func (m *MockLoginThrottleRepo) RegisterFailure(ctx context.Context, throttle *domain.LoginThrottle, now time.Time, policy domain.LoginThrottlePolicy) error {
+	throttle.RegisterFailure(now, policy)
+	args := m.Called(ctx, throttle, now, policy)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottleRepo) DeleteByKey(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+// MailSender delivers email messages.
+type MailSender interface {
+	Send(ctx context.Context, message domain.MailMessage) error
+}
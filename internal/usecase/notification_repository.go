@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type NotificationRepository interface {
+	// Create stores a notification together with its deliveries.
+	Create(ctx context.Context, notification *domain.Notification, deliveries []*domain.NotificationDelivery) error
+	FindByID(ctx context.Context, id string) (*domain.Notification, error)
+	FindInbox(ctx context.Context, recipientID string, unreadOnly bool, limit int) ([]*domain.Notification, error)
+	CountUnread(ctx context.Context, recipientID string) (int, error)
+	MarkRead(ctx context.Context, notification *domain.Notification) error
+	MarkAllRead(ctx context.Context, recipientID string, now time.Time) (int64, error)
+	// ClaimDueDeliveries returns up to limit pending deliveries that are due and hides
+	// them from other workers until lease has passed.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.NotificationDelivery, error)
+	SaveDelivery(ctx context.Context, delivery *domain.NotificationDelivery) error
+}
+
+type NotificationPreferenceRepository interface {
+	FindByEmployeeID(ctx context.Context, employeeID string) (*domain.NotificationPreferences, error)
+	Save(ctx context.Context, preferences *domain.NotificationPreferences) error
+}
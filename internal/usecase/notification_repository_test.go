@@ -0,0 +1,102 @@
+package usecase_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type MockNotificationRepo struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepo) Create(ctx context.Context, notification *domain.Notification, deliveries []*domain.NotificationDelivery) error {
+	args := m.Called(ctx, notification, deliveries)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepo) FindByID(ctx context.Context, id string) (*domain.Notification, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepo) FindInbox(ctx context.Context, recipientID string, unreadOnly bool, limit int) ([]*domain.Notification, error) {
+	args := m.Called(ctx, recipientID, unreadOnly, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepo) CountUnread(ctx context.Context, recipientID string) (int, error) {
+	args := m.Called(ctx, recipientID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWebhookSender) CheckTarget(ctx context.Context, url string) error {
+	args := m.Called(ctx, url)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepo) MarkRead(ctx context.Context, notification *domain.Notification) error {
+	args := m.Called(ctx, notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepo) MarkAllRead(ctx context.Context, recipientID string, now time.Time) (int64, error) {
+	args := m.Called(ctx, recipientID, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepo) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.NotificationDelivery, error) {
+	args := m.Called(ctx, now, lease, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.NotificationDelivery), args.Error(1)
+}
+
+func (m *MockNotificationRepo) SaveDelivery(ctx context.Context, delivery *domain.NotificationDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+type MockNotificationPreferenceRepo struct {
+	mock.Mock
+}
+
+func (m *MockNotificationPreferenceRepo) FindByEmployeeID(ctx context.Context, employeeID string) (*domain.NotificationPreferences, error) {
+	args := m.Called(ctx, employeeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockNotificationPreferenceRepo) Save(ctx context.Context, preferences *domain.NotificationPreferences) error {
+	args := m.Called(ctx, preferences)
+	return args.Error(0)
+}
+
+type MockWebhookSender struct {
+	mock.Mock
+}
+
+func (m *MockWebhookSender) Post(ctx context.Context, url string, payload []byte, headers map[string]string) (int, error) {
+	args := m.Called(ctx, url, payload, headers)
+	return args.Int(0), args.Error(1)
+}
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) NotifyAboutEmployee(ctx context.Context, event domain.NotificationEvent, employee *domain.Employee, data map[string]string) error {
+	args := m.Called(ctx, event, employee, data)
+	return args.Error(0)
+}
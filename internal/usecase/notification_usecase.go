@@ -0,0 +1,396 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+)
+
+const (
+	// inboxLimit caps how many notifications one inbox request returns.
+	inboxLimit = 50
+	// deliveryBatchSize is how many deliveries one run of DeliverDue claims at a time.
+	deliveryBatchSize = 20
+	// deliveryLease hides a claimed delivery from other workers while it is being sent.
+	deliveryLease = 2 * time.Minute
+)
+
+// Notifier raises notifications about an employee. Modules that emit events depend on
+// it instead of on NotificationUsecase.
+type Notifier interface {
+	NotifyAboutEmployee(ctx context.Context, event domain.NotificationEvent, employee *domain.Employee, data map[string]string) error
+}
+
+// NotificationUsecase renders event notifications from templates, routes them to the
+// channels each recipient chose, and keeps the in-app inbox. Email and webhook deliveries
+// are sent in the background by DeliverDue and retried with backoff.
+type NotificationUsecase struct {
+	notificationRepo NotificationRepository
+	preferenceRepo   NotificationPreferenceRepository
+	employeeRepo     EmployeeRepository
+	mailSender       MailSender
+	webhookSender    WebhookSender
+	authorizer       *Authorizer
+	idGen            IDGenerator
+	clock            clock.Clock
+	retry            domain.DeliveryRetryPolicy
+	ctxTimeout       time.Duration
+}
+
+func NewNotificationUsecase(notificationRepo NotificationRepository, preferenceRepo NotificationPreferenceRepository, employeeRepo EmployeeRepository, mailSender MailSender, webhookSender WebhookSender, authorizer *Authorizer, idGen IDGenerator, clk clock.Clock, retry domain.DeliveryRetryPolicy, timeout time.Duration) *NotificationUsecase {
+	return &NotificationUsecase{
+		notificationRepo: notificationRepo,
+		preferenceRepo:   preferenceRepo,
+		employeeRepo:     employeeRepo,
+		mailSender:       mailSender,
+		webhookSender:    webhookSender,
+		authorizer:       authorizer,
+		idGen:            idGen,
+		clock:            clk,
+		retry:            retry,
+		ctxTimeout:       timeout,
+	}
+}
+
+// NotifyAboutEmployee notifies the audience of event: the employee, the active
+// supervisors of the employee's store, or both.
+func (uc *NotificationUsecase) NotifyAboutEmployee(ctx context.Context, event domain.NotificationEvent, employee *domain.Employee, data map[string]string) error {
+	audience := event.Audience()
+
+	var recipients []*domain.Employee
+	if audience.Employee {
+		recipients = append(recipients, employee)
+	}
+	if audience.Supervisors {
+		supervisors, err := uc.storeSupervisors(ctx, employee)
+		if err != nil {
+			return err
+		}
+		recipients = append(recipients, supervisors...)
+	}
+
+	return uc.notify(ctx, event, recipients, data)
+}
+
+// Notify sends event to the given employees, for events that concern someone other than
+// the recipients.
+func (uc *NotificationUsecase) Notify(ctx context.Context, event domain.NotificationEvent, recipientIDs []string, data map[string]string) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	recipients := make([]*domain.Employee, 0, len(recipientIDs))
+	for _, id := range recipientIDs {
+		employee, err := uc.employeeRepo.FindByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to find recipient: %w", err)
+		}
+		if employee != nil {
+			recipients = append(recipients, employee)
+		}
+	}
+
+	return uc.notify(ctx, event, recipients, data)
+}
+
+func (uc *NotificationUsecase) notify(ctx context.Context, event domain.NotificationEvent, recipients []*domain.Employee, data map[string]string) error {
+	subject, body, err := domain.RenderNotification(event, data)
+	if err != nil {
+		return fmt.Errorf("failed to render notification: %w", err)
+	}
+
+	var errs []error
+	for _, recipient := range recipients {
+		if err := uc.notifyOne(ctx, event, recipient, subject, body); err != nil {
+			errs = append(errs, fmt.Errorf("recipient %s: %w", recipient.ID(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (uc *NotificationUsecase) notifyOne(ctx context.Context, event domain.NotificationEvent, recipient *domain.Employee, subject, body string) error {
+	recipientID := string(recipient.ID())
+
+	prefs, err := uc.preferences(ctx, recipientID)
+	if err != nil {
+		return err
+	}
+
+	channels := prefs.ChannelsFor(event)
+	if len(channels) == 0 {
+		return nil // muted
+	}
+
+	id, err := uc.idGen.NewID()
+	if err != nil {
+		return fmt.Errorf("failed to generate ID: %w", err)
+	}
+
+	now := uc.clock.Now()
+	inInbox := false
+	var deliveries []*domain.NotificationDelivery
+	for _, channel := range channels {
+		var target string
+		switch channel {
+		case domain.ChannelInApp:
+			inInbox = true
+			continue
+		case domain.ChannelEmail:
+			target = string(recipient.Email())
+		case domain.ChannelWebhook:
+			target = prefs.WebhookURL
+		}
+
+		deliveryID, err := uc.idGen.NewID()
+		if err != nil {
+			return fmt.Errorf("failed to generate ID: %w", err)
+		}
+		deliveries = append(deliveries, domain.NewNotificationDelivery(deliveryID, id, channel, target, now))
+	}
+
+	notification := domain.NewNotification(id, recipientID, event, subject, body, inInbox, now)
+	if err := uc.notificationRepo.Create(ctx, notification, deliveries); err != nil {
+		return fmt.Errorf("failed to save notification: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Created notification", "ID", id, "event", event, "recipientID", recipientID)
+
+	return nil
+}
+
+// storeSupervisors returns the active employees of the same store whose role may
+// approve attendance, other than the employee.
+func (uc *NotificationUsecase) storeSupervisors(ctx context.Context, employee *domain.Employee) ([]*domain.Employee, error) {
+	if employee.StoreID() == "" {
+		return nil, nil
+	}
+
+	policy, err := uc.authorizer.Policy(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	employees, err := uc.employeeRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find employees: %w", err)
+	}
+
+	var supervisors []*domain.Employee
+	for _, candidate := range employees {
+		if candidate.ID() == employee.ID() ||
+			candidate.StoreID() != employee.StoreID() ||
+			candidate.Status() != domain.StatusActive ||
+			!policy.Allows(candidate.Role(), domain.PermAttendanceApprove) {
+			continue
+		}
+		supervisors = append(supervisors, candidate)
+	}
+
+	return supervisors, nil
+}
+
+// Inbox returns the newest in-app notifications of the actor and how many are unread.
+func (uc *NotificationUsecase) Inbox(ctx context.Context, actor Actor, unreadOnly bool) ([]*domain.Notification, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	notifications, err := uc.notificationRepo.FindInbox(ctx, actor.ID, unreadOnly, inboxLimit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find notifications: %w", err)
+	}
+
+	unread, err := uc.notificationRepo.CountUnread(ctx, actor.ID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return notifications, unread, nil
+}
+
+// MarkRead marks one of the actor's notifications as read. Notifications of other
+// employees are reported as not found.
+func (uc *NotificationUsecase) MarkRead(ctx context.Context, actor Actor, id string) (*domain.Notification, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	notification, err := uc.notificationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification: %w", err)
+	}
+	if notification == nil || !notification.InInbox || !actor.IsSelf(notification.RecipientID) {
+		return nil, NotificationNotFoundError
+	}
+
+	if notification.IsRead() {
+		return notification, nil
+	}
+
+	notification.MarkRead(uc.clock.Now())
+	if err := uc.notificationRepo.MarkRead(ctx, notification); err != nil {
+		return nil, fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+
+	return notification, nil
+}
+
+func (uc *NotificationUsecase) MarkAllRead(ctx context.Context, actor Actor) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	marked, err := uc.notificationRepo.MarkAllRead(ctx, actor.ID, uc.clock.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+
+	return marked, nil
+}
+
+// Preferences returns the actor's notification preferences, defaults included.
+func (uc *NotificationUsecase) Preferences(ctx context.Context, actor Actor) (*domain.NotificationPreferences, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	return uc.preferences(ctx, actor.ID)
+}
+
+// UpdatePreferences sets the webhook URL of the actor and replaces the channels of the
+// given events. Events left out keep their channels.
+func (uc *NotificationUsecase) UpdatePreferences(ctx context.Context, actor Actor, webhookURL string, channels map[domain.NotificationEvent][]domain.NotificationChannel) (*domain.NotificationPreferences, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	prefs, err := uc.preferences(ctx, actor.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := prefs.SetWebhookURL(webhookURL); err != nil {
+		return nil, fmt.Errorf("%w: %w", InvalidNotificationPreferencesError, err)
+	}
+	if webhookURL != "" {
+		if err := uc.webhookSender.CheckTarget(ctx, webhookURL); err != nil {
+			return nil, fmt.Errorf("%w: %w", InvalidNotificationPreferencesError, err)
+		}
+	}
+	for event, eventChannels := range channels {
+		if err := prefs.SetChannels(event, eventChannels); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", InvalidNotificationPreferencesError, event, err)
+		}
+	}
+
+	prefs.UpdatedAt = uc.clock.Now()
+	if err := uc.preferenceRepo.Save(ctx, prefs); err != nil {
+		return nil, fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Updated notification preferences", "employeeID", actor.ID)
+
+	return prefs, nil
+}
+
+func (uc *NotificationUsecase) preferences(ctx context.Context, employeeID string) (*domain.NotificationPreferences, error) {
+	prefs, err := uc.preferenceRepo.FindByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification preferences: %w", err)
+	}
+	if prefs == nil {
+		prefs = domain.NewNotificationPreferences(employeeID)
+	}
+	return prefs, nil
+}
+
+// DeliverDue sends the email and webhook deliveries that are due. Failed attempts are
+// rescheduled with backoff until the retry policy gives up.
+func (uc *NotificationUsecase) DeliverDue(ctx context.Context) (int, error) {
+	delivered := 0
+	for {
+		claimCtx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+		deliveries, err := uc.notificationRepo.ClaimDueDeliveries(claimCtx, uc.clock.Now(), deliveryLease, deliveryBatchSize)
+		cancel()
+		if err != nil {
+			return delivered, fmt.Errorf("failed to claim deliveries: %w", err)
+		}
+		if len(deliveries) == 0 {
+			return delivered, nil
+		}
+
+		for _, delivery := range deliveries {
+			if ctx.Err() != nil {
+				return delivered, ctx.Err()
+			}
+			if uc.deliver(ctx, delivery) {
+				delivered++
+			}
+		}
+	}
+}
+
+// deliver makes one attempt and records its outcome. It reports whether the delivery
+// was sent.
+func (uc *NotificationUsecase) deliver(ctx context.Context, delivery *domain.NotificationDelivery) bool {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	err := uc.send(ctx, delivery)
+	now := uc.clock.Now()
+	if err != nil {
+		delivery.MarkAttemptFailed(err.Error(), now, uc.retry)
+		slog.Log(ctx, slog.LevelWarn, "Failed to deliver notification", "ID", delivery.ID, "channel", delivery.Channel, "attempts", delivery.Attempts, "error", err)
+	} else {
+		delivery.MarkSent(now)
+	}
+
+	if err := uc.notificationRepo.SaveDelivery(ctx, delivery); err != nil {
+		slog.Log(ctx, slog.LevelError, "Failed to save notification delivery", "ID", delivery.ID, "error", err)
+	}
+
+	return delivery.Status == domain.DeliverySent
+}
+
+func (uc *NotificationUsecase) send(ctx context.Context, delivery *domain.NotificationDelivery) error {
+	notification, err := uc.notificationRepo.FindByID(ctx, delivery.NotificationID)
+	if err != nil {
+		return fmt.Errorf("failed to find notification: %w", err)
+	}
+	if notification == nil {
+		return NotificationNotFoundError
+	}
+
+	switch delivery.Channel {
+	case domain.ChannelEmail:
+		return uc.mailSender.Send(ctx, domain.MailMessage{
+			To:      delivery.Target,
+			Subject: notification.Subject,
+			Body:    notification.Body + "\n",
+		})
+	case domain.ChannelWebhook:
+		payload, err := json.Marshal(webhookPayload{
+			ID:          notification.ID,
+			Event:       notification.Event,
+			RecipientID: notification.RecipientID,
+			Subject:     notification.Subject,
+			Body:        notification.Body,
+			CreatedAt:   notification.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = uc.webhookSender.Post(ctx, delivery.Target, payload, nil)
+		return err
+	default:
+		return fmt.Errorf("%w: %s", domain.ErrUnknownNotificationChannel, delivery.Channel)
+	}
+}
+
+type webhookPayload struct {
+	ID          string                   `json:"id"`
+	Event       domain.NotificationEvent `json:"event"`
+	RecipientID string                   `json:"recipient_id"`
+	Subject     string                   `json:"subject"`
+	Body        string                   `json:"body"`
+	CreatedAt   time.Time                `json:"created_at"`
+}
@@ -0,0 +1,372 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/notification"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+var testDeliveryRetry = domain.DeliveryRetryPolicy{MaxAttempts: 3, BaseDelay: 30 * time.Second, MaxDelay: time.Hour}
+
+func storeEmployee(id string, role domain.Role, storeID string) *domain.Employee {
+	emp, _ := domain.ReconstituteEmployee(domain.ReconstituteEmployeeParams{
+		ID:      id,
+		Name:    "Employee " + id,
+		Email:   id + "@shop.local",
+		Role:    string(role),
+		Status:  string(domain.StatusActive),
+		StoreID: storeID,
+	})
+	return emp
+}
+
+func TestNotificationUsecase_NotifyAboutEmployee(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 20, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	idGen := new(MockIDGenerator)
+	idGen.On("NewID").Return("notif-1", nil)
+
+	mockNotificationRepo := new(MockNotificationRepo)
+	mockPreferenceRepo := new(MockNotificationPreferenceRepo)
+	mockRepo := new(MockEmployeeRepo)
+	uc := usecase.NewNotificationUsecase(mockNotificationRepo, mockPreferenceRepo, mockRepo, notification.NewMemorySender(), new(MockWebhookSender), authorizer, idGen, clk, testDeliveryRetry, 2*time.Second)
+
+	emp := storeEmployee("emp-1", domain.RoleStaff, "store-1")
+
+	var created *domain.Notification
+	var deliveries []*domain.NotificationDelivery
+
+	// Create is only mocked for the cases that notify someone, so a muted event
+	// that still creates a notification fails the mock.
+	tests := []struct {
+		name  string
+		event domain.NotificationEvent
+		data  map[string]string
+		setup func()
+		check func(t *testing.T)
+	}{
+		{
+			name:  "Success - Late Check-In Goes To Store Supervisors",
+			event: domain.EventLateCheckIn,
+			data:  map[string]string{"EmployeeName": "Employee emp-1", "CheckIn": "09:20", "Date": "2026-03-02", "Location": "Store 1"},
+			setup: func() {
+				mockRepo.On("FindAll", mock.Anything).Return([]*domain.Employee{
+					emp,
+					storeEmployee("spv-1", domain.RoleSupervisor, "store-1"),
+					storeEmployee("spv-2", domain.RoleSupervisor, "store-2"),
+					storeEmployee("emp-2", domain.RoleStaff, "store-1"),
+				}, nil).Once()
+				mockPreferenceRepo.On("FindByEmployeeID", mock.Anything, "spv-1").Return(nil, nil).Once()
+				mockNotificationRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					created = args.Get(1).(*domain.Notification)
+					deliveries = args.Get(2).([]*domain.NotificationDelivery)
+				}).Return(nil).Once()
+			},
+			check: func(t *testing.T) {
+				if assert.NotNil(t, created) {
+					assert.Equal(t, "spv-1", created.RecipientID)
+					assert.Equal(t, "Late check-in: Employee emp-1", created.Subject)
+					assert.Contains(t, created.Body, "checked in late at 09:20 on 2026-03-02")
+					assert.True(t, created.InInbox)
+				}
+				if assert.Len(t, deliveries, 1) {
+					assert.Equal(t, domain.ChannelEmail, deliveries[0].Channel)
+					assert.Equal(t, "spv-1@shop.local", deliveries[0].Target)
+					assert.Equal(t, domain.DeliveryPending, deliveries[0].Status)
+					assert.Equal(t, now, deliveries[0].NextAttemptAt)
+				}
+			},
+		},
+		{
+			name:  "Success - Muted Event Creates Nothing",
+			event: domain.EventLeaveApproved,
+			setup: func() {
+				prefs := domain.NewNotificationPreferences("emp-1")
+				_ = prefs.SetChannels(domain.EventLeaveApproved, nil)
+				mockPreferenceRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return(prefs, nil).Once()
+			},
+		},
+		{
+			name:  "Success - Webhook Only, Kept Out Of Inbox",
+			event: domain.EventCorrectionApproved,
+			data:  map[string]string{"Date": "2026-03-01"},
+			setup: func() {
+				prefs := domain.NewNotificationPreferences("emp-1")
+				_ = prefs.SetWebhookURL("https://hooks.example.com/jane")
+				_ = prefs.SetChannels(domain.EventCorrectionApproved, []domain.NotificationChannel{domain.ChannelWebhook})
+				mockPreferenceRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return(prefs, nil).Once()
+				mockNotificationRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *domain.Notification) bool {
+					return !n.InInbox
+				}), mock.MatchedBy(func(d []*domain.NotificationDelivery) bool {
+					return len(d) == 1 && d[0].Channel == domain.ChannelWebhook && d[0].Target == "https://hooks.example.com/jane"
+				})).Return(nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			err := uc.NotifyAboutEmployee(context.Background(), tt.event, emp, tt.data)
+
+			assert.NoError(t, err)
+			if tt.check != nil {
+				tt.check(t)
+			}
+			mockNotificationRepo.AssertExpectations(t)
+			mockPreferenceRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationUsecase_MarkRead(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	idGen := new(MockIDGenerator)
+	idGen.On("NewID").Return("notif-1", nil)
+
+	mockNotificationRepo := new(MockNotificationRepo)
+	uc := usecase.NewNotificationUsecase(mockNotificationRepo, new(MockNotificationPreferenceRepo), new(MockEmployeeRepo), notification.NewMemorySender(), new(MockWebhookSender), authorizer, idGen, clk, testDeliveryRetry, 2*time.Second)
+
+	actor := usecase.Actor{ID: "emp-1", Role: domain.RoleStaff}
+
+	tests := []struct {
+		name         string
+		notification *domain.Notification
+		wantErr      error
+	}{
+		{
+			name:         "Success - Own Notification",
+			notification: domain.NewNotification("notif-1", "emp-1", domain.EventLeaveApproved, "s", "b", true, now.Add(-time.Hour)),
+		},
+		{
+			name:         "Fail - Someone Else's Notification",
+			notification: domain.NewNotification("notif-1", "spv-1", domain.EventLateCheckIn, "s", "b", true, now),
+			wantErr:      usecase.NotificationNotFoundError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockNotificationRepo.On("FindByID", mock.Anything, "notif-1").Return(tt.notification, nil).Once()
+			if tt.wantErr == nil {
+				mockNotificationRepo.On("MarkRead", mock.Anything, tt.notification).Return(nil).Once()
+			}
+
+			got, err := uc.MarkRead(context.Background(), actor, "notif-1")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, got.IsRead())
+				assert.Equal(t, now, *got.ReadAt)
+			}
+			mockNotificationRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationUsecase_UpdatePreferences(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	idGen := new(MockIDGenerator)
+	idGen.On("NewID").Return("notif-1", nil)
+
+	mockPreferenceRepo := new(MockNotificationPreferenceRepo)
+	mockWebhookSender := new(MockWebhookSender)
+	uc := usecase.NewNotificationUsecase(new(MockNotificationRepo), mockPreferenceRepo, new(MockEmployeeRepo), notification.NewMemorySender(), mockWebhookSender, authorizer, idGen, clk, testDeliveryRetry, 2*time.Second)
+
+	actor := usecase.Actor{ID: "emp-1", Role: domain.RoleStaff}
+
+	// Save is only mocked for the success case, so storing refused preferences
+	// fails the mock.
+	tests := []struct {
+		name       string
+		webhookURL string
+		channels   map[domain.NotificationEvent][]domain.NotificationChannel
+		setup      func()
+		wantErr    []error
+	}{
+		{
+			name:       "Success - Replaces Listed Events Only",
+			webhookURL: "https://hooks.example.com/jane",
+			channels: map[domain.NotificationEvent][]domain.NotificationChannel{
+				domain.EventMissedCheckOut: {domain.ChannelWebhook, domain.ChannelInApp, domain.ChannelWebhook},
+			},
+			setup: func() {
+				mockWebhookSender.On("CheckTarget", mock.Anything, "https://hooks.example.com/jane").Return(nil).Once()
+				mockPreferenceRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.NotificationPreferences")).Return(nil).Once()
+			},
+		},
+		{
+			name: "Fail - Unknown Channel",
+			channels: map[domain.NotificationEvent][]domain.NotificationChannel{
+				domain.EventLeaveApproved: {"sms"},
+			},
+			setup:   func() {},
+			wantErr: []error{usecase.InvalidNotificationPreferencesError},
+		},
+		{
+			name:       "Fail - Webhook URL Not HTTP",
+			webhookURL: "file:///etc/passwd",
+			setup:      func() {},
+			wantErr:    []error{usecase.InvalidNotificationPreferencesError},
+		},
+		{
+			name:       "Fail - Webhook URL On Internal Network",
+			webhookURL: "http://169.254.169.254/latest/meta-data",
+			setup: func() {
+				mockWebhookSender.On("CheckTarget", mock.Anything, "http://169.254.169.254/latest/meta-data").Return(domain.ErrPrivateWebhookTarget).Once()
+			},
+			wantErr: []error{usecase.InvalidNotificationPreferencesError, domain.ErrPrivateWebhookTarget},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPreferenceRepo.On("FindByEmployeeID", mock.Anything, "emp-1").Return(nil, nil).Once()
+			tt.setup()
+
+			prefs, err := uc.UpdatePreferences(context.Background(), actor, tt.webhookURL, tt.channels)
+
+			if tt.wantErr != nil {
+				for _, want := range tt.wantErr {
+					assert.ErrorIs(t, err, want)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []domain.NotificationChannel{domain.ChannelWebhook, domain.ChannelInApp}, prefs.ChannelsFor(domain.EventMissedCheckOut))
+				assert.Equal(t, domain.DefaultNotificationChannels, prefs.ChannelsFor(domain.EventLeaveApproved))
+				assert.Equal(t, now, prefs.UpdatedAt)
+			}
+			mockPreferenceRepo.AssertExpectations(t)
+			mockWebhookSender.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationUsecase_DeliverDue(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	idGen := new(MockIDGenerator)
+	idGen.On("NewID").Return("notif-1", nil)
+
+	mockNotificationRepo := new(MockNotificationRepo)
+	mockWebhookSender := new(MockWebhookSender)
+	mailSender := notification.NewMemorySender()
+	uc := usecase.NewNotificationUsecase(mockNotificationRepo, new(MockNotificationPreferenceRepo), new(MockEmployeeRepo), mailSender, mockWebhookSender, authorizer, idGen, clk, testDeliveryRetry, 2*time.Second)
+
+	n := domain.NewNotification("notif-1", "emp-1", domain.EventLeaveApproved, "Your leave request was approved", "Your leave was approved.", true, now)
+
+	tests := []struct {
+		name          string
+		channel       domain.NotificationChannel
+		target        string
+		attempts      int
+		setup         func()
+		wantDelivered int
+		wantStatus    domain.DeliveryStatus
+		wantAttempts  int
+		check         func(t *testing.T, delivery *domain.NotificationDelivery, sent []domain.MailMessage)
+	}{
+		{
+			name:          "Success - Sends Email And Records It",
+			channel:       domain.ChannelEmail,
+			target:        "jane@shop.local",
+			setup:         func() {},
+			wantDelivered: 1,
+			wantStatus:    domain.DeliverySent,
+			wantAttempts:  1,
+			check: func(t *testing.T, _ *domain.NotificationDelivery, sent []domain.MailMessage) {
+				if assert.Len(t, sent, 1) {
+					assert.Equal(t, "jane@shop.local", sent[0].To)
+					assert.Equal(t, n.Subject, sent[0].Subject)
+				}
+			},
+		},
+		{
+			name:     "Fail - Webhook Error Is Retried With Backoff",
+			channel:  domain.ChannelWebhook,
+			target:   "https://hooks.example.com/jane",
+			attempts: 1,
+			setup: func() {
+				mockWebhookSender.On("Post", mock.Anything, "https://hooks.example.com/jane", mock.MatchedBy(func(payload []byte) bool {
+					var body map[string]any
+					return json.Unmarshal(payload, &body) == nil && body["event"] == string(domain.EventLeaveApproved) && body["id"] == "notif-1"
+				}), mock.Anything).Return(503, errors.New("webhook responded with status 503")).Once()
+			},
+			wantStatus:   domain.DeliveryPending,
+			wantAttempts: 2,
+			check: func(t *testing.T, delivery *domain.NotificationDelivery, _ []domain.MailMessage) {
+				assert.Equal(t, now.Add(time.Minute), delivery.NextAttemptAt)
+				assert.Contains(t, delivery.LastError, "503")
+			},
+		},
+		{
+			name:     "Fail - Gives Up After Max Attempts",
+			channel:  domain.ChannelWebhook,
+			target:   "https://hooks.example.com/jane",
+			attempts: testDeliveryRetry.MaxAttempts - 1,
+			setup: func() {
+				mockWebhookSender.On("Post", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(0, errors.New("connection refused")).Once()
+			},
+			wantStatus:   domain.DeliveryFailed,
+			wantAttempts: testDeliveryRetry.MaxAttempts,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delivery := domain.NewNotificationDelivery("del-1", "notif-1", tt.channel, tt.target, now)
+			delivery.Attempts = tt.attempts
+			mockNotificationRepo.On("ClaimDueDeliveries", mock.Anything, now, mock.Anything, mock.Anything).Return([]*domain.NotificationDelivery{delivery}, nil).Once()
+			mockNotificationRepo.On("ClaimDueDeliveries", mock.Anything, now, mock.Anything, mock.Anything).Return([]*domain.NotificationDelivery{}, nil).Once()
+			mockNotificationRepo.On("FindByID", mock.Anything, "notif-1").Return(n, nil).Once()
+			mockNotificationRepo.On("SaveDelivery", mock.Anything, delivery).Return(nil).Once()
+			tt.setup()
+			before := len(mailSender.Sent())
+
+			delivered, err := uc.DeliverDue(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantDelivered, delivered)
+			assert.Equal(t, tt.wantStatus, delivery.Status)
+			assert.Equal(t, tt.wantAttempts, delivery.Attempts)
+			if tt.check != nil {
+				tt.check(t, delivery, mailSender.Sent()[before:])
+			}
+			mockNotificationRepo.AssertExpectations(t)
+			mockWebhookSender.AssertExpectations(t)
+		})
+	}
+}
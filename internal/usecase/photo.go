@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/imageproc"
+)
+
+// PhotoConfig bounds photo uploads and sets the sizes photos are stored at.
+type PhotoConfig struct {
+	MaxUploadBytes int64
+	MaxDimension   int // widest or tallest accepted upload, in pixels
+	Size           int // edge of the stored square avatar, in pixels
+	ThumbnailSize  int // edge of the stored square thumbnail, in pixels
+	URLTTL         time.Duration
+}
+
+// PhotoUpload is an uploaded photo as received from the client. Size, ContentType and
+// FileName are what the client claims, the content is checked when it is decoded.
+type PhotoUpload struct {
+	Content     io.Reader
+	Size        int64
+	ContentType string
+	FileName    string
+}
+
+// check rejects uploads whose declared size, file name or content type is not
+// accepted, before anything is read.
+func (c PhotoConfig) check(upload PhotoUpload) error {
+	if upload.Size > c.MaxUploadBytes {
+		return fmt.Errorf("%w: size exceeds the maximum of %d MB", InvalidPhotoError, c.MaxUploadBytes>>20)
+	}
+
+	ext := strings.ToLower(filepath.Ext(upload.FileName))
+	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+		return fmt.Errorf("%w: unsupported file format %s", InvalidPhotoError, ext)
+	}
+
+	if upload.ContentType != "image/jpeg" && upload.ContentType != "image/png" {
+		return fmt.Errorf("%w: unsupported content type %s", InvalidPhotoError, upload.ContentType)
+	}
+
+	return nil
+}
+
+// decode reads and decodes the uploaded photo with its orientation applied.
+func (c PhotoConfig) decode(upload PhotoUpload) (*imageproc.Photo, error) {
+	// Read one byte past the limit so a body larger than its header claims is caught
+	photo, err := imageproc.Decode(io.LimitReader(upload.Content, c.MaxUploadBytes+1), c.MaxDimension)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", InvalidPhotoError, err)
+	}
+	return photo, nil
+}
+
+// presignPhoto presigns a stored photo key for a response. A failure only costs the
+// photo, so it is logged instead of failing the whole response.
+func presignPhoto(ctx context.Context, storageRepo StorageRepository, key string, ttl time.Duration) string {
+	if key == "" {
+		return ""
+	}
+
+	url, err := storageRepo.PresignGetURL(ctx, key, ttl)
+	if err != nil {
+		slog.Log(ctx, slog.LevelWarn, "Failed to presign photo URL", "key", key, "error", err)
+		return ""
+	}
+	return url
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+)
+
+// PhotoCleanupUsecase removes photo objects no employee references, such as those left
+// behind when an upload failed half way or a replaced photo could not be deleted.
+type PhotoCleanupUsecase struct {
+	employeeRepo EmployeeRepository
+	storageRepo  StorageRepository
+	grace        time.Duration
+	clock        clock.Clock
+	ctxTimeout   time.Duration
+}
+
+// NewPhotoCleanupUsecase creates the cleanup. Objects younger than grace are kept, so an
+// upload that has stored its objects but not yet saved the employee is left alone.
+func NewPhotoCleanupUsecase(employeeRepo EmployeeRepository, storageRepo StorageRepository, grace time.Duration, clk clock.Clock, timeout time.Duration) *PhotoCleanupUsecase {
+	return &PhotoCleanupUsecase{
+		employeeRepo: employeeRepo,
+		storageRepo:  storageRepo,
+		grace:        grace,
+		clock:        clk,
+		ctxTimeout:   timeout,
+	}
+}
+
+// RemoveOrphans deletes the unreferenced photo objects older than the grace period and
+// returns how many were deleted. Objects failing to delete are retried on the next run.
+func (uc *PhotoCleanupUsecase) RemoveOrphans(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	// Listed before the references are read, so an object uploaded in between is at
+	// most unreferenced for a moment and still inside the grace period
+	files, err := uc.storageRepo.ListFiles(ctx, domain.PhotoKeyPrefix)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list photo objects: %w", err)
+	}
+
+	photos, err := uc.employeeRepo.FindPhotoKeys(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find photo keys: %w", err)
+	}
+	referenced := make(map[string]bool, 2*len(photos))
+	for _, photo := range photos {
+		referenced[photo] = true
+		if thumbnail := domain.PhotoThumbnailKey(photo); thumbnail != "" {
+			referenced[thumbnail] = true
+		}
+	}
+
+	cutoff := uc.clock.Now().Add(-uc.grace)
+	removed := 0
+	for _, file := range files {
+		if referenced[file.Key] || file.LastModified.After(cutoff) {
+			continue
+		}
+
+		if err := uc.storageRepo.DeleteFile(ctx, file.Key); err != nil {
+			slog.Log(ctx, slog.LevelError, "Failed to delete orphaned photo", "key", file.Key, "error", err)
+			continue
+		}
+		slog.Log(ctx, slog.LevelInfo, "Deleted orphaned photo", "key", file.Key, "lastModified", file.LastModified)
+		removed++
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,77 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+func TestPhotoCleanupUsecase_RemoveOrphans(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-48 * time.Hour)
+
+	setup := func() (*usecase.PhotoCleanupUsecase, *MockEmployeeRepo, *MockStorageRepo) {
+		mockRepo := new(MockEmployeeRepo)
+		mockStorage := new(MockStorageRepo)
+		uc := usecase.NewPhotoCleanupUsecase(mockRepo, mockStorage, 24*time.Hour, MockClock{currentTime: now}, time.Second)
+		return uc, mockRepo, mockStorage
+	}
+
+	t.Run("Success - Deletes Old Unreferenced Objects Only", func(t *testing.T) {
+		uc, mockRepo, mockStorage := setup()
+
+		mockStorage.On("ListFiles", mock.Anything, "photos/").Return([]usecase.StoredFile{
+			{Key: "photos/kept.jpg", LastModified: old},
+			{Key: "photos/kept_thumb.jpg", LastModified: old},
+			{Key: "photos/replaced.jpg", LastModified: old},
+			{Key: "photos/replaced_thumb.jpg", LastModified: old},
+			{Key: "photos/uploading.jpg", LastModified: now.Add(-time.Minute)},
+		}, nil).Once()
+		mockRepo.On("FindPhotoKeys", mock.Anything).Return([]string{"photos/kept.jpg", "legacy.png"}, nil).Once()
+		mockStorage.On("DeleteFile", mock.Anything, "photos/replaced.jpg").Return(nil).Once()
+		mockStorage.On("DeleteFile", mock.Anything, "photos/replaced_thumb.jpg").Return(nil).Once()
+
+		removed, err := uc.RemoveOrphans(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, removed)
+		mockStorage.AssertExpectations(t)
+		mockStorage.AssertNotCalled(t, "DeleteFile", mock.Anything, "photos/kept.jpg")
+		mockStorage.AssertNotCalled(t, "DeleteFile", mock.Anything, "photos/kept_thumb.jpg")
+		mockStorage.AssertNotCalled(t, "DeleteFile", mock.Anything, "photos/uploading.jpg")
+	})
+
+	t.Run("Success - Failed Delete Is Skipped", func(t *testing.T) {
+		uc, mockRepo, mockStorage := setup()
+
+		mockStorage.On("ListFiles", mock.Anything, "photos/").Return([]usecase.StoredFile{
+			{Key: "photos/a.jpg", LastModified: old},
+			{Key: "photos/b.jpg", LastModified: old},
+		}, nil).Once()
+		mockRepo.On("FindPhotoKeys", mock.Anything).Return([]string{}, nil).Once()
+		mockStorage.On("DeleteFile", mock.Anything, "photos/a.jpg").Return(errors.New("minio down")).Once()
+		mockStorage.On("DeleteFile", mock.Anything, "photos/b.jpg").Return(nil).Once()
+
+		removed, err := uc.RemoveOrphans(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, removed)
+	})
+
+	t.Run("Fail - References Unavailable Deletes Nothing", func(t *testing.T) {
+		uc, mockRepo, mockStorage := setup()
+
+		mockStorage.On("ListFiles", mock.Anything, "photos/").Return([]usecase.StoredFile{{Key: "photos/a.jpg", LastModified: old}}, nil).Once()
+		mockRepo.On("FindPhotoKeys", mock.Anything).Return(nil, errors.New("db down")).Once()
+
+		_, err := uc.RemoveOrphans(context.Background())
+
+		assert.Error(t, err)
+		mockStorage.AssertNotCalled(t, "DeleteFile", mock.Anything, mock.Anything)
+	})
+}
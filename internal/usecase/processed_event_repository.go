@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+// ProcessedEventRepository remembers which inbound events have been handled, so a
+// redelivered message is not handled twice.
+type ProcessedEventRepository interface {
+	Exists(ctx context.Context, id string) (bool, error)
+	// Save ignores events that are already recorded.
+	Save(ctx context.Context, event *domain.InboundEvent, processedAt time.Time) error
+}
@@ -0,0 +1,23 @@
+package usecase_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type MockProcessedEventRepo struct {
+	mock.Mock
+}
+
+func (m *MockProcessedEventRepo) Exists(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockProcessedEventRepo) Save(ctx context.Context, event *domain.InboundEvent, processedAt time.Time) error {
+	args := m.Called(ctx, event, processedAt)
+	return args.Error(0)
+}
@@ -12,39 +12,62 @@ type EmployeeResponse struct {
 	Email       string     `json:"email"`
 	Role        string     `json:"role"`
 	Position    string     `json:"position"`
-	Salary      int64      `json:"salary"`
+	Salary      *int64     `json:"salary,omitempty"`
 	Status      string     `json:"status"`
-	BirthDate   *time.Time `json:"birth_date"`
-	Address     string     `json:"address"`
+	BirthDate   *time.Time `json:"birth_date,omitempty"`
+	Address     string     `json:"address,omitempty"`
 	City        string     `json:"city"`
 	Province    string     `json:"province"`
-	PhoneNumber string     `json:"phone_number"`
+	PhoneNumber string     `json:"phone_number,omitempty"`
 	Photo       string     `json:"photo,omitempty"`
+	PhotoThumb  string     `json:"photo_thumbnail,omitempty"`
+	StoreID     string     `json:"store_id,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at,omitempty"`
+	Version     int64      `json:"version"`
 }
 
-// FromDomain maps domain.Employee to EmployeeResponse
+// FromDomain maps domain.Employee to EmployeeResponse with every field visible
 func FromDomain(e *domain.Employee) *EmployeeResponse {
+	return FromDomainWithVisibility(e, domain.FullVisibility())
+}
+
+// FromDomainWithVisibility maps domain.Employee to EmployeeResponse, leaving out the
+// sensitive fields that are not visible
+func FromDomainWithVisibility(e *domain.Employee, v domain.FieldVisibility) *EmployeeResponse {
 	if e == nil {
 		return nil
 	}
 
-	return &EmployeeResponse{
-		ID:          string(e.ID()),
-		Name:        e.Name(),
-		Email:       string(e.Email()),
-		Role:        string(e.Role()),
-		Position:    e.Position(),
-		Salary:      e.Salary(),
-		Status:      string(e.Status()),
-		BirthDate:   e.BirthDate(),
-		Address:     e.Address(),
-		City:        e.City(),
-		Province:    e.Province(),
-		PhoneNumber: string(e.PhoneNumber()),
-		Photo:       e.Photo(),
-		CreatedAt:   e.CreatedAt(),
-		UpdatedAt:   e.UpdatedAt(),
+	resp := &EmployeeResponse{
+		ID:        string(e.ID()),
+		Name:      e.Name(),
+		Email:     string(e.Email()),
+		Role:      string(e.Role()),
+		Position:  e.Position(),
+		Status:    string(e.Status()),
+		City:      e.City(),
+		Province:  e.Province(),
+		Photo:     e.Photo(),
+		StoreID:   e.StoreID(),
+		CreatedAt: e.CreatedAt(),
+		UpdatedAt: e.UpdatedAt(),
+		Version:   e.Version(),
+	}
+
+	if v.Salary {
+		salary := e.Salary()
+		resp.Salary = &salary
 	}
+	if v.BirthDate {
+		resp.BirthDate = e.BirthDate()
+	}
+	if v.Address {
+		resp.Address = e.Address()
+	}
+	if v.PhoneNumber {
+		resp.PhoneNumber = e.PhoneNumber()
+	}
+
+	return resp
 }
@@ -0,0 +1,17 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type RoleRepository interface {
+	FindAll(ctx context.Context) ([]*domain.RoleDefinition, error)
+	FindByName(ctx context.Context, name string) (*domain.RoleDefinition, error)
+	Save(ctx context.Context, role *domain.RoleDefinition) error
+	Delete(ctx context.Context, name string) error
+	// CountHolders counts the employees holding the role, including deleted ones that
+	// can still be restored.
+	CountHolders(ctx context.Context, name string) (int, error)
+}
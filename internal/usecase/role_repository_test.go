@@ -0,0 +1,62 @@
+package usecase_test
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type MockRoleRepo struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepo) FindAll(ctx context.Context) ([]*domain.RoleDefinition, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RoleDefinition), args.Error(1)
+}
+
+func (m *MockRoleRepo) FindByName(ctx context.Context, name string) (*domain.RoleDefinition, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RoleDefinition), args.Error(1)
+}
+
+func (m *MockRoleRepo) Save(ctx context.Context, role *domain.RoleDefinition) error {
+	args := m.Called(ctx, role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepo) Delete(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepo) CountHolders(ctx context.Context, name string) (int, error) {
+	args := m.Called(ctx, name)
+	return args.Int(0), args.Error(1)
+}
+
+// defaultRoles mirrors the built-in roles seeded by migrations 005 to 007.
+func defaultRoles() []*domain.RoleDefinition {
+	return []*domain.RoleDefinition{
+		{Name: domain.RoleAdmin, Rank: 100, BuiltIn: true, Permissions: domain.AllPermissions},
+		{Name: domain.RoleSupervisor, Rank: 50, BuiltIn: true, Permissions: []domain.Permission{
+			domain.PermEmployeeReadSelf, domain.PermEmployeeRead, domain.PermEmployeePersonalRead,
+			domain.PermEmployeeCreate, domain.PermEmployeeUpdateSelf, domain.PermEmployeeUpdate,
+			domain.PermEmployeeDelete, domain.PermEmployeePhotoUploadSelf, domain.PermEmployeePhotoUpload,
+			domain.PermAttendanceRecord, domain.PermAttendanceApprove, domain.PermEmployeeExportSelf,
+			domain.PermEmployeeLifecycle, domain.PermDocumentReadSelf, domain.PermDocumentUploadSelf,
+			domain.PermComplianceRead, domain.PermKioskManage,
+		}},
+		{Name: domain.RoleStaff, Rank: 10, BuiltIn: true, Permissions: []domain.Permission{
+			domain.PermEmployeeReadSelf, domain.PermEmployeePhotoUploadSelf, domain.PermAttendanceRecord,
+			domain.PermEmployeeExportSelf, domain.PermDocumentReadSelf, domain.PermDocumentUploadSelf,
+		}},
+	}
+}
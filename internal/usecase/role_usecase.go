@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type RoleUsecase struct {
+	repo       RoleRepository
+	authorizer *Authorizer
+	ctxTimeout time.Duration
+}
+
+func NewRoleUsecase(repo RoleRepository, authorizer *Authorizer, timeout time.Duration) *RoleUsecase {
+	return &RoleUsecase{
+		repo:       repo,
+		authorizer: authorizer,
+		ctxTimeout: timeout,
+	}
+}
+
+func (uc *RoleUsecase) GetAll(ctx context.Context) ([]*domain.RoleDefinition, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	roles, err := uc.repo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+// Save creates the role or replaces the description, rank and permissions of an existing one.
+// Roles can only be defined below the actor's own rank and with permissions the actor holds,
+// so nobody can mint a role above themselves. The actor's own role cannot be edited.
+func (uc *RoleUsecase) Save(ctx context.Context, actor Actor, name, description string, rank int, permissions []string) (*domain.RoleDefinition, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	policy, err := uc.authorizer.Policy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	actorRole, ok := policy[actor.Role]
+	if !ok {
+		return nil, ForbiddenError
+	}
+
+	if domain.Role(name) == actor.Role {
+		return nil, fmt.Errorf("%w: cannot modify your own role", ForbiddenError)
+	}
+
+	perms := make([]domain.Permission, 0, len(permissions))
+	for _, p := range permissions {
+		if !actorRole.Has(domain.Permission(p)) {
+			return nil, fmt.Errorf("%w: cannot grant permission %s you do not hold", ForbiddenError, p)
+		}
+		perms = append(perms, domain.Permission(p))
+	}
+
+	role, err := uc.repo.FindByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find role: %w", err)
+	}
+
+	if role == nil {
+		role, err = domain.NewRoleDefinition(domain.Role(name), description, rank, perms)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if role.Rank >= actorRole.Rank {
+			return nil, fmt.Errorf("%w: cannot modify a role at or above your own", ForbiddenError)
+		}
+		if err := role.SetRank(rank); err != nil {
+			return nil, err
+		}
+		if err := role.SetPermissions(perms); err != nil {
+			return nil, err
+		}
+		role.SetDescription(description)
+	}
+
+	if role.Rank >= actorRole.Rank {
+		return nil, fmt.Errorf("%w: role rank must be below your own", ForbiddenError)
+	}
+
+	// Never let the policy lose the ability to manage itself
+	if role.Name == domain.RoleAdmin && !role.Has(domain.PermRoleManage) {
+		return nil, errors.New("admin role must keep the role.manage permission")
+	}
+
+	if err := uc.repo.Save(ctx, role); err != nil {
+		return nil, fmt.Errorf("failed to save role: %w", err)
+	}
+	uc.authorizer.Invalidate()
+	slog.Log(ctx, slog.LevelInfo, "Saved role", "role", role.Name, "permissions", role.Permissions)
+
+	return role, nil
+}
+
+func (uc *RoleUsecase) Delete(ctx context.Context, actor Actor, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	policy, err := uc.authorizer.Policy(ctx)
+	if err != nil {
+		return err
+	}
+
+	role, err := uc.repo.FindByName(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to find role: %w", err)
+	}
+	if role == nil {
+		return RoleNotFoundError
+	}
+	if role.BuiltIn {
+		return BuiltInRoleError
+	}
+	if !policy.Outranks(actor.Role, role.Name) {
+		return fmt.Errorf("%w: cannot delete a role at or above your own", ForbiddenError)
+	}
+
+	holders, err := uc.repo.CountHolders(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to count role holders: %w", err)
+	}
+	if holders > 0 {
+		return fmt.Errorf("%w: %d employees", RoleInUseError, holders)
+	}
+
+	if err := uc.repo.Delete(ctx, name); err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+	uc.authorizer.Invalidate()
+	slog.Log(ctx, slog.LevelInfo, "Deleted role", "role", name)
+
+	return nil
+}
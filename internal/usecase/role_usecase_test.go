@@ -0,0 +1,92 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+)
+
+func TestRoleUsecase_Delete(t *testing.T) {
+	admin := usecase.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+	cashier := &domain.RoleDefinition{Name: "cashier", Rank: 5, Permissions: []domain.Permission{domain.PermEmployeeReadSelf}}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRoleRepo := new(MockRoleRepo)
+		mockRoleRepo.On("FindAll", mock.Anything).Return(append(defaultRoles(), cashier), nil)
+		authorizer := usecase.NewAuthorizer(mockRoleRepo, MockClock{currentTime: time.Now()}, time.Minute)
+		uc := usecase.NewRoleUsecase(mockRoleRepo, authorizer, time.Second)
+
+		mockRoleRepo.On("FindByName", mock.Anything, "cashier").Return(cashier, nil).Once()
+		mockRoleRepo.On("CountHolders", mock.Anything, "cashier").Return(0, nil).Once()
+		mockRoleRepo.On("Delete", mock.Anything, "cashier").Return(nil).Once()
+
+		err := uc.Delete(context.Background(), admin, "cashier")
+
+		assert.NoError(t, err)
+		mockRoleRepo.AssertExpectations(t)
+	})
+
+	t.Run("Fail - Role Still Held", func(t *testing.T) {
+		mockRoleRepo := new(MockRoleRepo)
+		mockRoleRepo.On("FindAll", mock.Anything).Return(append(defaultRoles(), cashier), nil)
+		authorizer := usecase.NewAuthorizer(mockRoleRepo, MockClock{currentTime: time.Now()}, time.Minute)
+		uc := usecase.NewRoleUsecase(mockRoleRepo, authorizer, time.Second)
+
+		mockRoleRepo.On("FindByName", mock.Anything, "cashier").Return(cashier, nil).Once()
+		mockRoleRepo.On("CountHolders", mock.Anything, "cashier").Return(2, nil).Once()
+
+		err := uc.Delete(context.Background(), admin, "cashier")
+
+		assert.ErrorIs(t, err, usecase.RoleInUseError)
+		mockRoleRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRoleUsecase_Save(t *testing.T) {
+	supervisor := usecase.Actor{ID: "spv-1", Role: domain.RoleSupervisor}
+
+	t.Run("Success - Role Below Actor With Held Permissions", func(t *testing.T) {
+		mockRoleRepo := new(MockRoleRepo)
+		mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+		authorizer := usecase.NewAuthorizer(mockRoleRepo, MockClock{currentTime: time.Now()}, time.Minute)
+		uc := usecase.NewRoleUsecase(mockRoleRepo, authorizer, time.Second)
+
+		mockRoleRepo.On("FindByName", mock.Anything, "cashier").Return(nil, nil).Once()
+		mockRoleRepo.On("Save", mock.Anything, mock.Anything).Return(nil).Once()
+
+		role, err := uc.Save(context.Background(), supervisor, "cashier", "Cashier", 5, []string{string(domain.PermAttendanceRecord)})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Role("cashier"), role.Name)
+		mockRoleRepo.AssertExpectations(t)
+	})
+
+	t.Run("Fail - Own Role", func(t *testing.T) {
+		mockRoleRepo := new(MockRoleRepo)
+		mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+		authorizer := usecase.NewAuthorizer(mockRoleRepo, MockClock{currentTime: time.Now()}, time.Minute)
+		uc := usecase.NewRoleUsecase(mockRoleRepo, authorizer, time.Second)
+
+		_, err := uc.Save(context.Background(), supervisor, string(domain.RoleSupervisor), "", 50, []string{string(domain.PermRoleManage)})
+
+		assert.ErrorIs(t, err, usecase.ForbiddenError)
+		mockRoleRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Fail - Permission Actor Does Not Hold", func(t *testing.T) {
+		mockRoleRepo := new(MockRoleRepo)
+		mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+		authorizer := usecase.NewAuthorizer(mockRoleRepo, MockClock{currentTime: time.Now()}, time.Minute)
+		uc := usecase.NewRoleUsecase(mockRoleRepo, authorizer, time.Second)
+
+		_, err := uc.Save(context.Background(), supervisor, "cashier", "Cashier", 5, []string{string(domain.PermRoleManage)})
+
+		assert.ErrorIs(t, err, usecase.ForbiddenError)
+		mockRoleRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type StatusChangeRepository interface {
+	Save(ctx context.Context, change *domain.StatusChange) error
+	FindByEmployeeID(ctx context.Context, employeeID string) ([]*domain.StatusChange, error)
+	FindDue(ctx context.Context, now time.Time) ([]*domain.StatusChange, error)
+}
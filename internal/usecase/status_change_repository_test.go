@@ -0,0 +1,34 @@
+package usecase_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type MockStatusChangeRepo struct {
+	mock.Mock
+}
+
+func (m *MockStatusChangeRepo) Save(ctx context.Context, change *domain.StatusChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+func (m *MockStatusChangeRepo) FindByEmployeeID(ctx context.Context, employeeID string) ([]*domain.StatusChange, error) {
+	args := m.Called(ctx, employeeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.StatusChange), args.Error(1)
+}
+
+func (m *MockStatusChangeRepo) FindDue(ctx context.Context, now time.Time) ([]*domain.StatusChange, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.StatusChange), args.Error(1)
+}
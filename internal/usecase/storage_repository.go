@@ -3,8 +3,22 @@ package usecase
 import (
 	"context"
 	"io"
+	"time"
 )
 
+// StorageRepository keeps uploaded files. UploadFile returns the object key, which is
+// what gets stored; clients are only ever handed short-lived presigned URLs.
 type StorageRepository interface {
 	UploadFile(ctx context.Context, fileName string, contentType string, content io.Reader, size int64) (string, error)
+	DeleteFile(ctx context.Context, key string) error
+	DownloadFile(ctx context.Context, key string) (io.ReadCloser, error)
+	PresignGetURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+	ListFiles(ctx context.Context, prefix string) ([]StoredFile, error)
+}
+
+// StoredFile describes an object in storage.
+type StoredFile struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
 }
@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type TwoFactorRepository interface {
+	FindByEmployeeID(ctx context.Context, employeeID string) (*domain.TwoFactor, error)
+	Save(ctx context.Context, twoFactor *domain.TwoFactor) error
+	Delete(ctx context.Context, employeeID string) error
+}
@@ -0,0 +1,30 @@
+package usecase_test
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type MockTwoFactorRepo struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorRepo) FindByEmployeeID(ctx context.Context, employeeID string) (*domain.TwoFactor, error) {
+	args := m.Called(ctx, employeeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TwoFactor), args.Error(1)
+}
+
+func (m *MockTwoFactorRepo) Save(ctx context.Context, twoFactor *domain.TwoFactor) error {
+	args := m.Called(ctx, twoFactor)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepo) Delete(ctx context.Context, employeeID string) error {
+	args := m.Called(ctx, employeeID)
+	return args.Error(0)
+}
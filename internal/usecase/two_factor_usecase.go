@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/totp"
+)
+
+const (
+	recoveryCodeCount = 10
+	// totpSkew accepts codes from one step before and after the current one
+	totpSkew = 1
+)
+
+type TwoFactorEnrollment struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+type TwoFactorUsecase struct {
+	repo          TwoFactorRepository
+	employeeRepo  EmployeeRepository
+	throttleRepo  LoginThrottleRepository
+	authorizer    *Authorizer
+	issuer        string
+	requiredRoles []domain.Role
+	// throttle is the account policy of the login throttle; wrong codes count against
+	// the same account as wrong passwords
+	throttle   domain.LoginThrottlePolicy
+	clock      clock.Clock
+	ctxTimeout time.Duration
+}
+
+func NewTwoFactorUsecase(repo TwoFactorRepository, employeeRepo EmployeeRepository, throttleRepo LoginThrottleRepository, authorizer *Authorizer, issuer string, requiredRoles []domain.Role, throttle domain.LoginThrottlePolicy, clk clock.Clock, timeout time.Duration) *TwoFactorUsecase {
+	return &TwoFactorUsecase{
+		repo:          repo,
+		employeeRepo:  employeeRepo,
+		throttleRepo:  throttleRepo,
+		authorizer:    authorizer,
+		issuer:        issuer,
+		requiredRoles: requiredRoles,
+		throttle:      throttle,
+		clock:         clk,
+		ctxTimeout:    timeout,
+	}
+}
+
+// Enroll starts (or restarts) an enrollment with a fresh secret. The enrollment
+// only becomes active once Confirm receives a valid code for it.
+func (uc *TwoFactorUsecase) Enroll(ctx context.Context, employeeID string) (*TwoFactorEnrollment, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	employee, err := uc.employeeRepo.FindByID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find employee: %w", err)
+	}
+	if employee == nil {
+		return nil, EmployeeNotFoundError
+	}
+
+	existing, err := uc.repo.FindByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load two factor: %w", err)
+	}
+	if existing != nil && existing.IsEnabled() {
+		return nil, TwoFactorAlreadyEnabledError
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate totp secret: %w", err)
+	}
+
+	if err := uc.repo.Save(ctx, domain.NewTwoFactor(employeeID, secret)); err != nil {
+		return nil, fmt.Errorf("failed to save two factor enrollment: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Started two factor enrollment", "employeeID", employeeID)
+
+	return &TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(uc.issuer, string(employee.Email()), secret),
+	}, nil
+}
+
+// Confirm activates a pending enrollment and returns the recovery codes. They are
+// only shown once; the database keeps their hashes.
+func (uc *TwoFactorUsecase) Confirm(ctx context.Context, employeeID, code string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	employee, err := uc.employeeRepo.FindByID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find employee: %w", err)
+	}
+	if employee == nil {
+		return nil, EmployeeNotFoundError
+	}
+
+	twoFactor, err := uc.repo.FindByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load two factor: %w", err)
+	}
+	if twoFactor == nil {
+		return nil, TwoFactorNotEnrolledError
+	}
+	if twoFactor.IsEnabled() {
+		return nil, TwoFactorAlreadyEnabledError
+	}
+
+	now := uc.clock.Now()
+	if err := uc.verifyCode(ctx, now, string(employee.Email()), twoFactor, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+
+	twoFactor.Confirm(now.UTC(), hashes)
+
+	if err := uc.repo.Save(ctx, twoFactor); err != nil {
+		return nil, fmt.Errorf("failed to confirm two factor: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Enabled two factor authentication", "employeeID", employeeID)
+
+	return codes, nil
+}
+
+// Disable turns off two-factor authentication for the caller after checking a current code.
+// Roles that are required to use 2FA cannot disable it themselves.
+func (uc *TwoFactorUsecase) Disable(ctx context.Context, employeeID, code string) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	employee, err := uc.employeeRepo.FindByID(ctx, employeeID)
+	if err != nil {
+		return fmt.Errorf("failed to find employee: %w", err)
+	}
+	if employee == nil {
+		return EmployeeNotFoundError
+	}
+	if requiresTwoFactor(uc.requiredRoles, employee.Role()) {
+		return TwoFactorRequiredError
+	}
+
+	twoFactor, err := uc.repo.FindByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return fmt.Errorf("failed to load two factor: %w", err)
+	}
+	if twoFactor == nil || !twoFactor.IsEnabled() {
+		return TwoFactorNotEnrolledError
+	}
+
+	if err := uc.verifyCode(ctx, uc.clock.Now(), string(employee.Email()), twoFactor, code); err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, employeeID); err != nil {
+		return fmt.Errorf("failed to disable two factor: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Disabled two factor authentication", "employeeID", employeeID)
+
+	return nil
+}
+
+// Reset removes the enrollment of another employee below the actor, e.g. after a lost
+// phone. The employee has to enroll again on their next login.
+func (uc *TwoFactorUsecase) Reset(ctx context.Context, actor Actor, employeeID string) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermAccountTwoFactorReset); err != nil {
+		return err
+	}
+
+	// Your own enrollment can only be removed with a current code through Disable
+	if actor.IsSelf(employeeID) {
+		return ForbiddenError
+	}
+
+	employee, err := uc.employeeRepo.FindByID(ctx, employeeID)
+	if err != nil {
+		return fmt.Errorf("failed to find employee: %w", err)
+	}
+	if employee == nil {
+		return EmployeeNotFoundError
+	}
+
+	if err := uc.authorizer.AuthorizeHierarchy(ctx, actor, employee); err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, employeeID); err != nil {
+		return fmt.Errorf("failed to reset two factor: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Reset two factor authentication", "employeeID", employeeID, "actorID", actor.ID)
+
+	return nil
+}
+
+// verifyCode checks a code and records its time step so it cannot be replayed. Wrong
+// codes are throttled on the account of the employee like failed logins.
+func (uc *TwoFactorUsecase) verifyCode(ctx context.Context, now time.Time, email string, twoFactor *domain.TwoFactor, code string) error {
+	key := domain.LoginThrottleKey(domain.ThrottleScopeAccount, email)
+	throttle, err := uc.throttleRepo.FindByKey(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to load login throttle: %w", err)
+	}
+	if throttle == nil {
+		throttle = domain.NewLoginThrottle(domain.ThrottleScopeAccount, email)
+	}
+	if wait := throttle.RetryAfter(now); wait > 0 {
+		return &LoginLockedError{RetryAfter: wait}
+	}
+
+	step, ok := totp.Validate(twoFactor.Secret, code, now, totpSkew)
+	if !ok || twoFactor.UseStep(step) != nil {
+		if err := uc.throttleRepo.RegisterFailure(ctx, throttle, now, uc.throttle); err != nil {
+			return fmt.Errorf("failed to record two factor failure: %w", err)
+		}
+		slog.Log(ctx, slog.LevelWarn, "Wrong two factor code", "employeeID", twoFactor.EmployeeID, "attempts", throttle.FailedCount)
+		return InvalidTwoFactorCodeError
+	}
+
+	if throttle.FailedCount > 0 {
+		if err := uc.throttleRepo.DeleteByKey(ctx, throttle.Key); err != nil {
+			return fmt.Errorf("failed to reset login throttle: %w", err)
+		}
+	}
+	return nil
+}
+
+func requiresTwoFactor(requiredRoles []domain.Role, role domain.Role) bool {
+	return slices.Contains(requiredRoles, role)
+}
+
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, 6)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(encoding.EncodeToString(buf))
+		code := raw[:5] + "-" + raw[5:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
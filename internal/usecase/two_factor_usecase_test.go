@@ -0,0 +1,141 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/totp"
+)
+
+func TestTwoFactorUsecase_Disable(t *testing.T) {
+	now := time.Date(2026, 10, 10, 8, 0, 0, 0, time.UTC)
+	mockClock := MockClock{currentTime: now}
+
+	policy := domain.LoginThrottlePolicy{
+		MaxAttempts:     3,
+		BaseDelay:       time.Second,
+		MaxDelay:        time.Minute,
+		LockoutDuration: 15 * time.Minute,
+	}
+
+	emp, _ := domain.ReconstituteEmployee(domain.ReconstituteEmployeeParams{
+		ID:     "emp-123",
+		Name:   "Test User",
+		Email:  "test@example.com",
+		Role:   string(domain.RoleStaff),
+		Status: string(domain.StatusActive),
+	})
+	accountKey := domain.LoginThrottleKey(domain.ThrottleScopeAccount, "test@example.com")
+
+	secret, _ := totp.GenerateSecret()
+	code, _ := totp.CodeAt(secret, totp.Step(now))
+	confirmedAt := now.Add(-24 * time.Hour)
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockTwoFactorRepo)
+		mockEmployeeRepo := new(MockEmployeeRepo)
+		mockThrottleRepo := new(MockLoginThrottleRepo)
+		uc := usecase.NewTwoFactorUsecase(mockRepo, mockEmployeeRepo, mockThrottleRepo, nil, "test", nil, policy, mockClock, time.Second)
+
+		twoFactor := &domain.TwoFactor{EmployeeID: "emp-123", Secret: secret, ConfirmedAt: &confirmedAt}
+		mockEmployeeRepo.On("FindByID", mock.Anything, "emp-123").Return(emp, nil).Once()
+		mockRepo.On("FindByEmployeeID", mock.Anything, "emp-123").Return(twoFactor, nil).Once()
+		mockThrottleRepo.On("FindByKey", mock.Anything, accountKey).Return(nil, nil).Once()
+		mockRepo.On("Delete", mock.Anything, "emp-123").Return(nil).Once()
+
+		err := uc.Disable(context.Background(), "emp-123", code)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockThrottleRepo.AssertExpectations(t)
+	})
+
+	t.Run("Fail - Replayed Code Is Refused", func(t *testing.T) {
+		mockRepo := new(MockTwoFactorRepo)
+		mockEmployeeRepo := new(MockEmployeeRepo)
+		mockThrottleRepo := new(MockLoginThrottleRepo)
+		uc := usecase.NewTwoFactorUsecase(mockRepo, mockEmployeeRepo, mockThrottleRepo, nil, "test", nil, policy, mockClock, time.Second)
+
+		twoFactor := &domain.TwoFactor{EmployeeID: "emp-123", Secret: secret, ConfirmedAt: &confirmedAt, LastUsedStep: totp.Step(now)}
+		mockEmployeeRepo.On("FindByID", mock.Anything, "emp-123").Return(emp, nil).Once()
+		mockRepo.On("FindByEmployeeID", mock.Anything, "emp-123").Return(twoFactor, nil).Once()
+		mockThrottleRepo.On("FindByKey", mock.Anything, accountKey).Return(nil, nil).Once()
+		mockThrottleRepo.On("RegisterFailure", mock.Anything, mock.Anything, now, policy).Return(nil).Once()
+
+		err := uc.Disable(context.Background(), "emp-123", code)
+
+		assert.ErrorIs(t, err, usecase.InvalidTwoFactorCodeError)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+		mockThrottleRepo.AssertExpectations(t)
+	})
+
+	t.Run("Fail - Locked Account", func(t *testing.T) {
+		mockRepo := new(MockTwoFactorRepo)
+		mockEmployeeRepo := new(MockEmployeeRepo)
+		mockThrottleRepo := new(MockLoginThrottleRepo)
+		uc := usecase.NewTwoFactorUsecase(mockRepo, mockEmployeeRepo, mockThrottleRepo, nil, "test", nil, policy, mockClock, time.Second)
+
+		lockedUntil := now.Add(10 * time.Minute)
+		locked := &domain.LoginThrottle{Key: accountKey, Scope: domain.ThrottleScopeAccount, FailedCount: 3, LastFailedAt: now, LockedUntil: &lockedUntil}
+		twoFactor := &domain.TwoFactor{EmployeeID: "emp-123", Secret: secret, ConfirmedAt: &confirmedAt}
+		mockEmployeeRepo.On("FindByID", mock.Anything, "emp-123").Return(emp, nil).Once()
+		mockRepo.On("FindByEmployeeID", mock.Anything, "emp-123").Return(twoFactor, nil).Once()
+		mockThrottleRepo.On("FindByKey", mock.Anything, accountKey).Return(locked, nil).Once()
+
+		err := uc.Disable(context.Background(), "emp-123", code)
+
+		var lockedErr *usecase.LoginLockedError
+		assert.ErrorAs(t, err, &lockedErr)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTwoFactorUsecase_Reset(t *testing.T) {
+	now := time.Date(2026, 10, 10, 8, 0, 0, 0, time.UTC)
+	mockClock := MockClock{currentTime: now}
+
+	admin := usecase.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+	staff, _ := domain.ReconstituteEmployee(domain.ReconstituteEmployeeParams{ID: "emp-123", Name: "Staff", Email: "staff@example.com", Role: string(domain.RoleStaff), Status: string(domain.StatusActive)})
+	peer, _ := domain.ReconstituteEmployee(domain.ReconstituteEmployeeParams{ID: "admin-2", Name: "Peer", Email: "peer@example.com", Role: string(domain.RoleAdmin), Status: string(domain.StatusActive)})
+
+	tests := []struct {
+		name    string
+		actor   usecase.Actor
+		target  *domain.Employee
+		wantErr error
+	}{
+		{"Success - Employee Below Actor", admin, staff, nil},
+		{"Fail - Peer Of The Actor", admin, peer, usecase.ForbiddenError},
+		{"Fail - Own Enrollment", usecase.Actor{ID: "emp-123", Role: domain.RoleAdmin}, staff, usecase.ForbiddenError},
+		{"Fail - Missing Permission", usecase.Actor{ID: "spv-1", Role: domain.RoleSupervisor}, staff, usecase.ForbiddenError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTwoFactorRepo)
+			mockEmployeeRepo := new(MockEmployeeRepo)
+			mockRoleRepo := new(MockRoleRepo)
+			mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+			authorizer := usecase.NewAuthorizer(mockRoleRepo, mockClock, time.Minute)
+			uc := usecase.NewTwoFactorUsecase(mockRepo, mockEmployeeRepo, new(MockLoginThrottleRepo), authorizer, "test", nil, domain.LoginThrottlePolicy{}, mockClock, time.Second)
+
+			mockEmployeeRepo.On("FindByID", mock.Anything, string(tt.target.ID())).Return(tt.target, nil).Maybe()
+			mockRepo.On("Delete", mock.Anything, string(tt.target.ID())).Return(nil).Maybe()
+
+			err := uc.Reset(context.Background(), tt.actor, string(tt.target.ID()))
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			mockRepo.AssertCalled(t, "Delete", mock.Anything, "emp-123")
+		})
+	}
+}
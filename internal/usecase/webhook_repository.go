@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+)
+
+type WebhookSubscriptionRepository interface {
+	Save(ctx context.Context, subscription *domain.WebhookSubscription) error
+	FindByID(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	FindAll(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	FindActiveByEvent(ctx context.Context, event domain.WebhookEvent) ([]*domain.WebhookSubscription, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type WebhookDeliveryRepository interface {
+	// Create stores the deliveries of one event together.
+	Create(ctx context.Context, deliveries []*domain.WebhookDelivery) error
+	Save(ctx context.Context, delivery *domain.WebhookDelivery) error
+	FindByID(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+	// FindBySubscriptionID returns the newest deliveries of a subscription, only those
+	// in status unless it is empty.
+	FindBySubscriptionID(ctx context.Context, subscriptionID string, status domain.WebhookDeliveryStatus, limit int) ([]*domain.WebhookDelivery, error)
+	// ClaimDue returns up to limit pending deliveries that are due and hides them from
+	// other workers until lease has passed.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error)
+}
@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"context"
+)
+
+// WebhookSender posts a JSON payload to a URL with the given extra headers. It returns
+// the HTTP status of the response, also when it reports an error for a non-2xx status.
+// CheckTarget refuses URLs the sender will not post to, e.g. ones pointing to the
+// internal network.
+type WebhookSender interface {
+	Post(ctx context.Context, url string, payload []byte, headers map[string]string) (int, error)
+	CheckTarget(ctx context.Context, url string) error
+}
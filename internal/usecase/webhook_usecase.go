@@ -0,0 +1,381 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/clock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/webhooksig"
+)
+
+// webhookDeliveryLogLimit caps how many deliveries the delivery log returns.
+const webhookDeliveryLogLimit = 100
+
+// EventPublisher announces events to integration partners. Modules that emit events
+// depend on it instead of on WebhookUsecase.
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.WebhookEvent, data any) error
+}
+
+// webhookEnvelope is the body of every webhook request. ID identifies the event and is
+// the same for all subscriptions, so receivers can drop duplicates.
+type webhookEnvelope struct {
+	ID         string              `json:"id"`
+	Event      domain.WebhookEvent `json:"event"`
+	OccurredAt time.Time           `json:"occurred_at"`
+	Data       any                 `json:"data"`
+}
+
+// WebhookUsecase manages the webhook subscriptions of integration partners and delivers
+// events to them. Publish only records deliveries; DeliverDue sends them signed with
+// HMAC-SHA256, retries with backoff and leaves deliveries that keep failing dead.
+type WebhookUsecase struct {
+	subscriptionRepo WebhookSubscriptionRepository
+	deliveryRepo     WebhookDeliveryRepository
+	sender           WebhookSender
+	authorizer       *Authorizer
+	idGen            IDGenerator
+	clock            clock.Clock
+	retry            domain.DeliveryRetryPolicy
+	ctxTimeout       time.Duration
+}
+
+func NewWebhookUsecase(subscriptionRepo WebhookSubscriptionRepository, deliveryRepo WebhookDeliveryRepository, sender WebhookSender, authorizer *Authorizer, idGen IDGenerator, clk clock.Clock, retry domain.DeliveryRetryPolicy, timeout time.Duration) *WebhookUsecase {
+	return &WebhookUsecase{
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+		sender:           sender,
+		authorizer:       authorizer,
+		idGen:            idGen,
+		clock:            clk,
+		retry:            retry,
+		ctxTimeout:       timeout,
+	}
+}
+
+// Create adds a subscription with a generated secret. The secret is only ever returned
+// here and by RotateSecret.
+func (uc *WebhookUsecase) Create(ctx context.Context, actor Actor, url string, events []domain.WebhookEvent) (*domain.WebhookSubscription, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermWebhookManage); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	id, err := uc.idGen.NewID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ID: %w", err)
+	}
+
+	subscription, err := domain.NewWebhookSubscription(id, url, events, secret, actor.ID, uc.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", InvalidWebhookSubscriptionError, err)
+	}
+	if err := uc.sender.CheckTarget(ctx, url); err != nil {
+		return nil, fmt.Errorf("%w: %w", InvalidWebhookSubscriptionError, err)
+	}
+
+	if err := uc.subscriptionRepo.Save(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Created webhook subscription", "ID", id, "url", url, "actorID", actor.ID)
+
+	return subscription, nil
+}
+
+func (uc *WebhookUsecase) List(ctx context.Context, actor Actor) ([]*domain.WebhookSubscription, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermWebhookManage); err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := uc.subscriptionRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (uc *WebhookUsecase) Get(ctx context.Context, actor Actor, id string) (*domain.WebhookSubscription, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermWebhookManage); err != nil {
+		return nil, err
+	}
+
+	return uc.find(ctx, id)
+}
+
+// Update replaces the URL, events and active flag of a subscription. Deliveries already
+// recorded keep going to the subscription's current URL.
+func (uc *WebhookUsecase) Update(ctx context.Context, actor Actor, id string, url string, events []domain.WebhookEvent, active bool) (*domain.WebhookSubscription, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermWebhookManage); err != nil {
+		return nil, err
+	}
+
+	subscription, err := uc.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := subscription.Update(url, events, active, uc.clock.Now()); err != nil {
+		return nil, fmt.Errorf("%w: %w", InvalidWebhookSubscriptionError, err)
+	}
+	if err := uc.sender.CheckTarget(ctx, url); err != nil {
+		return nil, fmt.Errorf("%w: %w", InvalidWebhookSubscriptionError, err)
+	}
+
+	if err := uc.subscriptionRepo.Save(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Updated webhook subscription", "ID", id, "actorID", actor.ID)
+
+	return subscription, nil
+}
+
+// RotateSecret replaces the signing secret; requests are signed with the new one from
+// the next attempt on.
+func (uc *WebhookUsecase) RotateSecret(ctx context.Context, actor Actor, id string) (*domain.WebhookSubscription, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermWebhookManage); err != nil {
+		return nil, err
+	}
+
+	subscription, err := uc.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+	subscription.RotateSecret(secret, uc.clock.Now())
+
+	if err := uc.subscriptionRepo.Save(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Rotated webhook secret", "ID", id, "actorID", actor.ID)
+
+	return subscription, nil
+}
+
+// Delete removes a subscription together with its delivery log.
+func (uc *WebhookUsecase) Delete(ctx context.Context, actor Actor, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermWebhookManage); err != nil {
+		return err
+	}
+
+	if _, err := uc.find(ctx, id); err != nil {
+		return err
+	}
+
+	if err := uc.subscriptionRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Deleted webhook subscription", "ID", id, "actorID", actor.ID)
+
+	return nil
+}
+
+// Deliveries returns the delivery log of a subscription, newest first, optionally only
+// deliveries in status.
+func (uc *WebhookUsecase) Deliveries(ctx context.Context, actor Actor, id string, status domain.WebhookDeliveryStatus) ([]*domain.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermWebhookManage); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.find(ctx, id); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := uc.deliveryRepo.FindBySubscriptionID(ctx, id, status, webhookDeliveryLogLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver moves a dead delivery back into the queue.
+func (uc *WebhookUsecase) Redeliver(ctx context.Context, actor Actor, id string, deliveryID string) (*domain.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	if err := uc.authorizer.Authorize(ctx, actor, domain.PermWebhookManage); err != nil {
+		return nil, err
+	}
+
+	delivery, err := uc.deliveryRepo.FindByID(ctx, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook delivery: %w", err)
+	}
+	if delivery == nil || delivery.SubscriptionID != id {
+		return nil, WebhookDeliveryNotFoundError
+	}
+
+	if err := delivery.Redeliver(uc.clock.Now()); err != nil {
+		return nil, fmt.Errorf("%w: %w", WebhookDeliveryNotDeadError, err)
+	}
+
+	if err := uc.deliveryRepo.Save(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+	slog.Log(ctx, slog.LevelInfo, "Requeued dead webhook delivery", "ID", deliveryID, "subscriptionID", id, "actorID", actor.ID)
+
+	return delivery, nil
+}
+
+// Publish records a delivery of event for every active subscription to it. Sending
+// happens later in DeliverDue, so publishing never waits for a partner.
+func (uc *WebhookUsecase) Publish(ctx context.Context, event domain.WebhookEvent, data any) error {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	subscriptions, err := uc.subscriptionRepo.FindActiveByEvent(ctx, event)
+	if err != nil {
+		return fmt.Errorf("failed to find webhook subscriptions: %w", err)
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	eventID, err := uc.idGen.NewID()
+	if err != nil {
+		return fmt.Errorf("failed to generate ID: %w", err)
+	}
+
+	now := uc.clock.Now()
+	payload, err := json.Marshal(webhookEnvelope{ID: eventID, Event: event, OccurredAt: now.UTC(), Data: data})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	deliveries := make([]*domain.WebhookDelivery, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		id, err := uc.idGen.NewID()
+		if err != nil {
+			return fmt.Errorf("failed to generate ID: %w", err)
+		}
+		deliveries = append(deliveries, domain.NewWebhookDelivery(id, subscription.ID, eventID, event, payload, now))
+	}
+
+	if err := uc.deliveryRepo.Create(ctx, deliveries); err != nil {
+		return fmt.Errorf("failed to save webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// DeliverDue sends the deliveries that are due and records the outcome of every attempt.
+func (uc *WebhookUsecase) DeliverDue(ctx context.Context) (int, error) {
+	delivered := 0
+	for {
+		claimCtx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+		deliveries, err := uc.deliveryRepo.ClaimDue(claimCtx, uc.clock.Now(), deliveryLease, deliveryBatchSize)
+		cancel()
+		if err != nil {
+			return delivered, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+		}
+		if len(deliveries) == 0 {
+			return delivered, nil
+		}
+
+		for _, delivery := range deliveries {
+			if ctx.Err() != nil {
+				return delivered, ctx.Err()
+			}
+			if uc.deliver(ctx, delivery) {
+				delivered++
+			}
+		}
+	}
+}
+
+func (uc *WebhookUsecase) deliver(ctx context.Context, delivery *domain.WebhookDelivery) bool {
+	ctx, cancel := context.WithTimeout(ctx, uc.ctxTimeout)
+	defer cancel()
+
+	status, err := uc.send(ctx, delivery)
+	now := uc.clock.Now()
+	if err != nil {
+		delivery.MarkAttemptFailed(status, err.Error(), now, uc.retry)
+		slog.Log(ctx, slog.LevelWarn, "Failed to deliver webhook", "ID", delivery.ID, "subscriptionID", delivery.SubscriptionID, "attempts", delivery.Attempts, "status", delivery.Status, "error", err)
+	} else {
+		delivery.MarkDelivered(status, now)
+	}
+
+	if err := uc.deliveryRepo.Save(ctx, delivery); err != nil {
+		slog.Log(ctx, slog.LevelError, "Failed to save webhook delivery", "ID", delivery.ID, "error", err)
+	}
+
+	return delivery.Status == domain.WebhookDeliveryDelivered
+}
+
+func (uc *WebhookUsecase) send(ctx context.Context, delivery *domain.WebhookDelivery) (int, error) {
+	subscription, err := uc.subscriptionRepo.FindByID(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find webhook subscription: %w", err)
+	}
+	if subscription == nil {
+		return 0, WebhookSubscriptionNotFoundError
+	}
+	if !subscription.Active {
+		return 0, errors.New("webhook subscription is disabled")
+	}
+
+	headers := map[string]string{
+		webhooksig.Header:    webhooksig.Sign([]byte(subscription.Secret), uc.clock.Now(), delivery.Payload),
+		"X-Webhook-Event":    string(delivery.Event),
+		"X-Webhook-Delivery": delivery.ID,
+	}
+
+	return uc.sender.Post(ctx, subscription.URL, delivery.Payload, headers)
+}
+
+func (uc *WebhookUsecase) find(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	subscription, err := uc.subscriptionRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook subscription: %w", err)
+	}
+	if subscription == nil {
+		return nil, WebhookSubscriptionNotFoundError
+	}
+	return subscription, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(buf), nil
+}
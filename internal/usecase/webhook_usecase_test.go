@@ -0,0 +1,360 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zuyatna/shop-retail-employee-service/internal/adapter/notification"
+	"github.com/zuyatna/shop-retail-employee-service/internal/domain"
+	"github.com/zuyatna/shop-retail-employee-service/internal/usecase"
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/webhooksig"
+)
+
+var testWebhookRetry = domain.DeliveryRetryPolicy{MaxAttempts: 2, BaseDelay: 30 * time.Second, MaxDelay: time.Hour}
+
+type webhookReceiver struct {
+	server   *httptest.Server
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newWebhookReceiver(t *testing.T, status int) *webhookReceiver {
+	rcv := &webhookReceiver{status: status}
+	rcv.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rcv.requests = append(rcv.requests, r)
+		rcv.bodies = append(rcv.bodies, body)
+		w.WriteHeader(rcv.status)
+	}))
+	t.Cleanup(rcv.server.Close)
+	return rcv
+}
+
+func partnerSubscription(url string, events ...domain.WebhookEvent) *domain.WebhookSubscription {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sub, _ := domain.NewWebhookSubscription("sub-1", url, events, "whsec_test", "admin-1", now)
+	return sub
+}
+
+func TestWebhookUsecase_Create(t *testing.T) {
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	mockIDGen := new(MockIDGenerator)
+	mockIDGen.On("NewID").Return("sub-1", nil)
+
+	mockSubscriptionRepo := new(MockWebhookSubscriptionRepo)
+	sender := notification.NewHTTPWebhookSender(2*time.Second, true)
+	uc := usecase.NewWebhookUsecase(mockSubscriptionRepo, new(MockWebhookDeliveryRepo), sender, authorizer, mockIDGen, clk, testWebhookRetry, 2*time.Second)
+
+	admin := usecase.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+	supervisor := usecase.Actor{ID: "sup-1", Role: domain.RoleSupervisor}
+
+	// Save is only mocked for the success case, so storing a refused subscription
+	// fails the mock.
+	tests := []struct {
+		name    string
+		actor   usecase.Actor
+		events  []domain.WebhookEvent
+		wantErr error
+	}{
+		{
+			name:   "Success - Generates Secret",
+			actor:  admin,
+			events: []domain.WebhookEvent{domain.WebhookEmployeeTerminated, domain.WebhookEmployeeTerminated},
+		},
+		{
+			name:    "Fail - Unknown Event",
+			actor:   admin,
+			events:  []domain.WebhookEvent{"payroll.closed"},
+			wantErr: usecase.InvalidWebhookSubscriptionError,
+		},
+		{
+			name:    "Fail - Not An Admin",
+			actor:   supervisor,
+			events:  []domain.WebhookEvent{domain.WebhookEmployeeTerminated},
+			wantErr: usecase.ForbiddenError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				mockSubscriptionRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.WebhookSubscription")).Return(nil).Once()
+			}
+
+			sub, err := uc.Create(context.Background(), tt.actor, "https://partner.example/hooks", tt.events)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "sub-1", sub.ID)
+				assert.True(t, strings.HasPrefix(sub.Secret, "whsec_"))
+				assert.Equal(t, []domain.WebhookEvent{domain.WebhookEmployeeTerminated}, sub.Events)
+				assert.True(t, sub.Active)
+			}
+			mockSubscriptionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookUsecase_Publish(t *testing.T) {
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	mockSubscriptionRepo := new(MockWebhookSubscriptionRepo)
+	mockDeliveryRepo := new(MockWebhookDeliveryRepo)
+	mockIDGen := new(MockIDGenerator)
+	sender := notification.NewHTTPWebhookSender(2*time.Second, true)
+	uc := usecase.NewWebhookUsecase(mockSubscriptionRepo, mockDeliveryRepo, sender, authorizer, mockIDGen, clk, testWebhookRetry, 2*time.Second)
+
+	first := partnerSubscription("https://a.example/hooks", domain.WebhookAttendanceCheckedIn)
+	second := partnerSubscription("https://b.example/hooks", domain.WebhookAttendanceCheckedIn)
+	second.ID = "sub-2"
+
+	var created []*domain.WebhookDelivery
+
+	// Create is only mocked when someone is subscribed, so recording deliveries
+	// for nobody fails the mock.
+	tests := []struct {
+		name          string
+		event         domain.WebhookEvent
+		subscriptions []*domain.WebhookSubscription
+		setup         func()
+		check         func(t *testing.T)
+	}{
+		{
+			name:          "Success - Records A Delivery Per Subscription",
+			event:         domain.WebhookAttendanceCheckedIn,
+			subscriptions: []*domain.WebhookSubscription{first, second},
+			setup: func() {
+				mockIDGen.On("NewID").Return("event-1", nil).Once()
+				mockIDGen.On("NewID").Return("delivery-1", nil).Once()
+				mockIDGen.On("NewID").Return("delivery-2", nil).Once()
+				mockDeliveryRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					created = args.Get(1).([]*domain.WebhookDelivery)
+				}).Return(nil).Once()
+			},
+			check: func(t *testing.T) {
+				if assert.Len(t, created, 2) {
+					assert.Equal(t, "sub-1", created[0].SubscriptionID)
+					assert.Equal(t, "sub-2", created[1].SubscriptionID)
+					assert.Equal(t, "event-1", created[1].EventID)
+					assert.Equal(t, created[0].Payload, created[1].Payload)
+
+					var envelope map[string]any
+					assert.NoError(t, json.Unmarshal(created[0].Payload, &envelope))
+					assert.Equal(t, "event-1", envelope["id"])
+					assert.Equal(t, "attendance.checked_in", envelope["event"])
+					assert.Equal(t, map[string]any{"employee_id": "emp-1"}, envelope["data"])
+				}
+			},
+		},
+		{
+			name:          "Success - Nobody Subscribed",
+			event:         domain.WebhookEmployeeSuspended,
+			subscriptions: []*domain.WebhookSubscription{},
+			setup:         func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSubscriptionRepo.On("FindActiveByEvent", mock.Anything, tt.event).Return(tt.subscriptions, nil).Once()
+			tt.setup()
+
+			err := uc.Publish(context.Background(), tt.event, map[string]string{"employee_id": "emp-1"})
+
+			assert.NoError(t, err)
+			if tt.check != nil {
+				tt.check(t)
+			}
+			mockDeliveryRepo.AssertExpectations(t)
+			mockIDGen.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookUsecase_DeliverDue(t *testing.T) {
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	mockSubscriptionRepo := new(MockWebhookSubscriptionRepo)
+	mockDeliveryRepo := new(MockWebhookDeliveryRepo)
+	sender := notification.NewHTTPWebhookSender(2*time.Second, true)
+	uc := usecase.NewWebhookUsecase(mockSubscriptionRepo, mockDeliveryRepo, sender, authorizer, new(MockIDGenerator), clk, testWebhookRetry, 2*time.Second)
+
+	payload := []byte(`{"id":"event-1","event":"employee.terminated","data":{}}`)
+
+	tests := []struct {
+		name          string
+		status        int
+		disabled      bool
+		attempts      int
+		wantDelivered int
+		wantStatus    domain.WebhookDeliveryStatus
+		wantAttempts  int
+		wantRequests  int
+		check         func(t *testing.T, delivery *domain.WebhookDelivery, rcv *webhookReceiver)
+	}{
+		{
+			name:          "Success - Signed Request",
+			status:        http.StatusNoContent,
+			wantDelivered: 1,
+			wantStatus:    domain.WebhookDeliveryDelivered,
+			wantAttempts:  1,
+			wantRequests:  1,
+			check: func(t *testing.T, delivery *domain.WebhookDelivery, rcv *webhookReceiver) {
+				assert.Equal(t, http.StatusNoContent, delivery.ResponseStatus)
+
+				req := rcv.requests[0]
+				assert.Equal(t, payload, rcv.bodies[0])
+				assert.Equal(t, "employee.terminated", req.Header.Get("X-Webhook-Event"))
+				assert.Equal(t, "delivery-1", req.Header.Get("X-Webhook-Delivery"))
+				assert.NoError(t, webhooksig.Verify([]byte("whsec_test"), req.Header.Get(webhooksig.Header), rcv.bodies[0], now, 5*time.Minute))
+				assert.ErrorIs(t, webhooksig.Verify([]byte("whsec_other"), req.Header.Get(webhooksig.Header), rcv.bodies[0], now, 5*time.Minute), webhooksig.ErrMismatch)
+			},
+		},
+		{
+			name:         "Success - Failing Receiver Is Retried With Backoff",
+			status:       http.StatusInternalServerError,
+			wantStatus:   domain.WebhookDeliveryPending,
+			wantAttempts: 1,
+			wantRequests: 1,
+			check: func(t *testing.T, delivery *domain.WebhookDelivery, _ *webhookReceiver) {
+				assert.Equal(t, http.StatusInternalServerError, delivery.ResponseStatus)
+				assert.Equal(t, now.Add(30*time.Second), delivery.NextAttemptAt)
+			},
+		},
+		{
+			name:         "Success - Failing Receiver Is Dead After Last Attempt",
+			status:       http.StatusInternalServerError,
+			attempts:     testWebhookRetry.MaxAttempts - 1,
+			wantStatus:   domain.WebhookDeliveryDead,
+			wantAttempts: testWebhookRetry.MaxAttempts,
+			wantRequests: 1,
+			check: func(t *testing.T, delivery *domain.WebhookDelivery, _ *webhookReceiver) {
+				assert.Contains(t, delivery.LastError, "status 500")
+			},
+		},
+		{
+			name:         "Success - Disabled Subscription Is Not Called",
+			status:       http.StatusOK,
+			disabled:     true,
+			wantStatus:   domain.WebhookDeliveryPending,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rcv := newWebhookReceiver(t, tt.status)
+			sub := partnerSubscription(rcv.server.URL, domain.WebhookEmployeeTerminated)
+			sub.Active = !tt.disabled
+			delivery := domain.NewWebhookDelivery("delivery-1", sub.ID, "event-1", domain.WebhookEmployeeTerminated, payload, now)
+			delivery.Attempts = tt.attempts
+
+			mockDeliveryRepo.On("ClaimDue", mock.Anything, now, mock.Anything, mock.Anything).Return([]*domain.WebhookDelivery{delivery}, nil).Once()
+			mockDeliveryRepo.On("ClaimDue", mock.Anything, now, mock.Anything, mock.Anything).Return([]*domain.WebhookDelivery{}, nil).Once()
+			mockSubscriptionRepo.On("FindByID", mock.Anything, sub.ID).Return(sub, nil).Once()
+			mockDeliveryRepo.On("Save", mock.Anything, delivery).Return(nil).Once()
+
+			delivered, err := uc.DeliverDue(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantDelivered, delivered)
+			assert.Equal(t, tt.wantStatus, delivery.Status)
+			assert.Equal(t, tt.wantAttempts, delivery.Attempts)
+			if assert.Len(t, rcv.requests, tt.wantRequests) && tt.check != nil {
+				tt.check(t, delivery, rcv)
+			}
+			mockDeliveryRepo.AssertExpectations(t)
+			mockSubscriptionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookUsecase_Redeliver(t *testing.T) {
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	clk := MockClock{currentTime: now}
+
+	mockRoleRepo := new(MockRoleRepo)
+	mockRoleRepo.On("FindAll", mock.Anything).Return(defaultRoles(), nil)
+	authorizer := usecase.NewAuthorizer(mockRoleRepo, clk, time.Minute)
+
+	mockDeliveryRepo := new(MockWebhookDeliveryRepo)
+	sender := notification.NewHTTPWebhookSender(2*time.Second, true)
+	uc := usecase.NewWebhookUsecase(new(MockWebhookSubscriptionRepo), mockDeliveryRepo, sender, authorizer, new(MockIDGenerator), clk, testWebhookRetry, 2*time.Second)
+
+	admin := usecase.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+	dead := domain.NewWebhookDelivery("delivery-1", "sub-1", "event-1", domain.WebhookEmployeeTerminated, []byte(`{}`), now.Add(-time.Hour))
+	dead.Status = domain.WebhookDeliveryDead
+	dead.Attempts = 2
+
+	// Save is only mocked for the success case, so requeuing a refused delivery
+	// fails the mock.
+	tests := []struct {
+		name     string
+		delivery *domain.WebhookDelivery
+		wantErr  error
+	}{
+		{
+			name:     "Success - Dead Delivery Is Requeued",
+			delivery: dead,
+		},
+		{
+			name:     "Fail - Delivery Is Not Dead",
+			delivery: domain.NewWebhookDelivery("delivery-1", "sub-1", "event-1", domain.WebhookEmployeeTerminated, []byte(`{}`), now),
+			wantErr:  usecase.WebhookDeliveryNotDeadError,
+		},
+		{
+			name:     "Fail - Delivery Of Another Subscription",
+			delivery: domain.NewWebhookDelivery("delivery-1", "sub-2", "event-1", domain.WebhookEmployeeTerminated, []byte(`{}`), now),
+			wantErr:  usecase.WebhookDeliveryNotFoundError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDeliveryRepo.On("FindByID", mock.Anything, "delivery-1").Return(tt.delivery, nil).Once()
+			if tt.wantErr == nil {
+				mockDeliveryRepo.On("Save", mock.Anything, tt.delivery).Return(nil).Once()
+			}
+
+			got, err := uc.Redeliver(context.Background(), admin, "sub-1", "delivery-1")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.WebhookDeliveryPending, got.Status)
+				assert.Equal(t, 0, got.Attempts)
+				assert.Equal(t, now, got.NextAttemptAt)
+			}
+			mockDeliveryRepo.AssertExpectations(t)
+		})
+	}
+}
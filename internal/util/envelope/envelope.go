@@ -0,0 +1,138 @@
+package envelope
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/zuyatna/shop-retail-employee-service/internal/util/secretbox"
+)
+
+const dataKeySize = 32
+
+// Keyring holds the key-encryption keys (KEKs) by ID. New data keys are always
+// wrapped with the current KEK; older KEKs are kept so existing records stay
+// readable until they are re-encrypted.
+type Keyring struct {
+	currentID string
+	keks      map[string]*secretbox.Box
+}
+
+// ParseKeyring builds a keyring from "id:base64key" pairs separated by commas,
+// e.g. "v2:AAAA...,v1:BBBB...".
+func ParseKeyring(currentID, spec string) (*Keyring, error) {
+	keks := make(map[string]*secretbox.Box)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		id, key, ok := strings.Cut(pair, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("invalid key-encryption key entry %q, expected id:base64key", id)
+		}
+
+		box, err := secretbox.NewFromBase64(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key-encryption key %s: %w", id, err)
+		}
+		keks[id] = box
+	}
+
+	if _, ok := keks[currentID]; !ok {
+		return nil, fmt.Errorf("current key-encryption key %q not found in keyring", currentID)
+	}
+
+	return &Keyring{currentID: currentID, keks: keks}, nil
+}
+
+func (k *Keyring) CurrentID() string {
+	return k.currentID
+}
+
+// DataKey is a per-record key used to encrypt the fields of a single record.
+type DataKey struct {
+	box     *secretbox.Box
+	Wrapped string
+	KEKID   string
+}
+
+// NewDataKey generates a fresh data key wrapped with the current KEK.
+func (k *Keyring) NewDataKey() (*DataKey, error) {
+	raw := make([]byte, dataKeySize)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+
+	wrapped, err := k.keks[k.currentID].Seal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	box, err := secretbox.New(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DataKey{box: box, Wrapped: wrapped, KEKID: k.currentID}, nil
+}
+
+// OpenDataKey unwraps a stored data key with the KEK it was wrapped with.
+func (k *Keyring) OpenDataKey(wrapped, kekID string) (*DataKey, error) {
+	kek, ok := k.keks[kekID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key-encryption key %q", kekID)
+	}
+
+	raw, err := kek.Open(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	box, err := secretbox.New(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DataKey{box: box, Wrapped: wrapped, KEKID: kekID}, nil
+}
+
+func (d *DataKey) Encrypt(plaintext string) (string, error) {
+	return d.box.Seal([]byte(plaintext))
+}
+
+func (d *DataKey) Decrypt(ciphertext string) (string, error) {
+	plaintext, err := d.box.Open(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// BlindIndex produces deterministic keyed hashes so encrypted values can still be
+// looked up and kept unique without storing them in plaintext.
+type BlindIndex struct {
+	key []byte
+}
+
+func NewBlindIndexFromBase64(encodedKey string) (*BlindIndex, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blind index key encoding: %w", err)
+	}
+	if len(key) < 32 {
+		return nil, fmt.Errorf("blind index key must be at least 32 bytes")
+	}
+	return &BlindIndex{key: key}, nil
+}
+
+func (b *BlindIndex) Index(value string) string {
+	mac := hmac.New(sha256.New, b.key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
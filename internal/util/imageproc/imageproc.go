@@ -0,0 +1,189 @@
+// Package imageproc normalises uploaded photos: it decodes JPEG and PNG images, turns
+// them upright according to their EXIF orientation, crops and scales them to squares and
+// re-encodes them as JPEG. The encoder writes no metadata, so EXIF data such as the GPS
+// position a phone recorded is dropped on the way.
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+
+	"golang.org/x/image/draw"
+)
+
+// jpegQuality balances size and artefacts for faces at avatar sizes.
+const jpegQuality = 85
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported or corrupted image")
+	ErrTooLarge          = errors.New("image dimensions exceed the limit")
+)
+
+// Photo is a decoded upload.
+type Photo struct {
+	img         image.Image
+	orientation int // EXIF orientation, 1 when upright or unknown
+}
+
+// Decode reads a JPEG or PNG image. Images wider or taller than maxDimension are
+// rejected before their pixels are decoded.
+func Decode(r io.Reader, maxDimension int) (*Photo, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	if cfg.Width > maxDimension || cfg.Height > maxDimension {
+		return nil, fmt.Errorf("%w: %dx%d, at most %dx%d", ErrTooLarge, cfg.Width, cfg.Height, maxDimension, maxDimension)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+
+	orientation := 1
+	if format == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+
+	return &Photo{img: img, orientation: orientation}, nil
+}
+
+// Square crops the largest centred square out of the photo, scales it to size x size
+// and turns it upright. Transparent areas become white.
+func (p *Photo) Square(size int) image.Image {
+	b := p.img.Bounds()
+	side := min(b.Dx(), b.Dy())
+	crop := image.Rect(0, 0, side, side).Add(b.Min).Add(image.Pt((b.Dx()-side)/2, (b.Dy()-side)/2))
+
+	// The crop is centred, so cropping before turning the photo upright picks the same
+	// pixels and only the small result has to be turned
+	scaled := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(scaled, scaled.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), p.img, crop, draw.Over, nil)
+
+	return orient(scaled, p.orientation)
+}
+
+// EncodeJPEG encodes img without any metadata.
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// orient returns the square src as it should be displayed for an EXIF orientation.
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation == 1 {
+		return src
+	}
+
+	n := src.Bounds().Dx() - 1
+	dst := image.NewRGBA(src.Bounds())
+	for y := 0; y <= n; y++ {
+		for x := 0; x <= n; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // flip horizontally
+				sx, sy = n-x, y
+			case 3: // rotate 180°
+				sx, sy = n-x, n-y
+			case 4: // flip vertically
+				sx, sy = x, n-y
+			case 5: // transpose
+				sx, sy = y, x
+			case 6: // rotate 90° clockwise
+				sx, sy = y, n-x
+			case 7: // transverse
+				sx, sy = n-y, n-x
+			case 8: // rotate 90° counter-clockwise
+				sx, sy = n-y, x
+			default:
+				return src
+			}
+			dst.SetRGBA(x, y, src.RGBAAt(sx, sy))
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the orientation tag from the EXIF segment of a JPEG file. It
+// returns 1 when there is none or the segment cannot be read.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 {
+			// Start of scan or end of image, metadata segments come before
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// exifOrientation finds the orientation tag (0x0112) in the first IFD of a TIFF
+// structure.
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for n := range count {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
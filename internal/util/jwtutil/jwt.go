@@ -6,10 +6,18 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Token purposes. Access tokens carry no purpose; the others are restricted
+// to the two-factor endpoints and cannot be used on the regular API.
+const (
+	PurposeTwoFactorChallenge  = "2fa_challenge"
+	PurposeTwoFactorEnrollment = "2fa_enrollment"
+)
+
 type Claims struct {
-	UserID string `json:"uid"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID  string `json:"uid"`
+	Email   string `json:"email"`
+	Role    string `json:"role"`
+	Purpose string `json:"pur,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -20,14 +28,19 @@ type Signer struct {
 }
 
 func (s *Signer) Generate(userID, email, role string) (string, error) {
+	return s.GenerateWithPurpose(userID, email, role, "", s.TTL)
+}
+
+func (s *Signer) GenerateWithPurpose(userID, email, role, purpose string, ttl time.Duration) (string, error) {
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:  userID,
+		Email:   email,
+		Role:    role,
+		Purpose: purpose,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    s.Issuer,
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.TTL)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 		},
 	}
 
@@ -0,0 +1,85 @@
+// Package kioskqr signs the rotating QR codes store kiosks display.
+//
+// A code has the form "<base64url payload>.<base64url HMAC>", where the payload is a small
+// JSON object naming the kiosk, its store and when the code was issued, and the HMAC-SHA256
+// is computed over the encoded payload. Codes are only accepted for a short time after they
+// were issued, so a photo of an old code cannot be used from elsewhere.
+package kioskqr
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// clockSkew is how far in the future an issue time may lie, for kiosks whose clock runs
+// slightly ahead of the server.
+const clockSkew = 5 * time.Second
+
+var (
+	ErrMalformed = errors.New("malformed kiosk QR code")
+	ErrMismatch  = errors.New("kiosk QR code signature does not match")
+	ErrExpired   = errors.New("kiosk QR code has expired")
+)
+
+// Payload is what a QR code vouches for: it was shown by KioskID of StoreID at IssuedAt.
+type Payload struct {
+	KioskID  string
+	StoreID  string
+	IssuedAt time.Time
+}
+
+type payloadJSON struct {
+	KioskID  string `json:"k"`
+	StoreID  string `json:"s"`
+	IssuedAt int64  `json:"t"`
+}
+
+// Sign returns the QR code content for p.
+func Sign(secret []byte, p Payload) string {
+	data, _ := json.Marshal(payloadJSON{KioskID: p.KioskID, StoreID: p.StoreID, IssuedAt: p.IssuedAt.Unix()})
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(mac(secret, encoded))
+}
+
+// Verify checks the signature of a QR code and that it was issued at most ttl before now.
+func Verify(secret []byte, code string, now time.Time, ttl time.Duration) (Payload, error) {
+	encoded, signature, ok := strings.Cut(strings.TrimSpace(code), ".")
+	if !ok {
+		return Payload{}, ErrMalformed
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return Payload{}, ErrMalformed
+	}
+	if !hmac.Equal(sig, mac(secret, encoded)) {
+		return Payload{}, ErrMismatch
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Payload{}, ErrMalformed
+	}
+	var p payloadJSON
+	if err := json.Unmarshal(data, &p); err != nil || p.KioskID == "" || p.StoreID == "" {
+		return Payload{}, ErrMalformed
+	}
+
+	issuedAt := time.Unix(p.IssuedAt, 0)
+	if age := now.Sub(issuedAt); age > ttl || age < -clockSkew {
+		return Payload{}, ErrExpired
+	}
+
+	return Payload{KioskID: p.KioskID, StoreID: p.StoreID, IssuedAt: issuedAt}, nil
+}
+
+func mac(secret []byte, encoded string) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(encoded))
+	return h.Sum(nil)
+}
@@ -0,0 +1,63 @@
+// Package kiosksig verifies the attendance events a store kiosk collects while offline.
+//
+// Every kiosk generates an Ed25519 key pair when it is registered and hands over only the
+// public key; the private key never leaves the device, so the device token alone is not
+// enough to forge events. The signature is the hex encoded Ed25519 signature over the
+// fields of the event joined by newlines:
+//
+//	<id>\n<type>\n<employee id>\n<occurred at, unix seconds>
+//
+// The PIN the employee entered is not signed: a changed PIN only fails the PIN check.
+// Kiosks sign each event when it is recorded, so events edited in the offline queue
+// afterwards are rejected when the queue is synced.
+package kiosksig
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidPublicKey = errors.New("kiosk signing key must be a base64 encoded Ed25519 public key")
+
+// Event holds the signed fields of an offline attendance event.
+type Event struct {
+	ID         string
+	Type       string
+	EmployeeID string
+	OccurredAt time.Time
+}
+
+// ParsePublicKey decodes the standard base64 encoding of an Ed25519 public key.
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, ErrInvalidPublicKey
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// Sign returns the signature of e, as a kiosk makes it.
+func Sign(key ed25519.PrivateKey, e Event) string {
+	return hex.EncodeToString(ed25519.Sign(key, message(e)))
+}
+
+// Verify reports whether signature was made for e with the private half of key.
+func Verify(key ed25519.PublicKey, e Event, signature string) bool {
+	if len(key) != ed25519.PublicKeySize {
+		return false
+	}
+	sig, err := hex.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(key, message(e), sig)
+}
+
+func message(e Event) []byte {
+	return []byte(strings.Join([]string{e.ID, e.Type, e.EmployeeID, strconv.FormatInt(e.OccurredAt.Unix(), 10)}, "\n"))
+}
@@ -0,0 +1,80 @@
+// Package webhooksig signs outgoing webhook requests with HMAC-SHA256.
+//
+// The signature header has the form "t=<unix seconds>,v1=<hex HMAC>", where the HMAC is
+// computed over "<unix seconds>.<request body>" with the subscription secret. Receivers
+// recompute it and reject requests whose timestamp is too old to stop replays.
+package webhooksig
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header carries the signature of a webhook request.
+const Header = "X-Webhook-Signature"
+
+var (
+	ErrMalformed        = errors.New("malformed webhook signature")
+	ErrMismatch         = errors.New("webhook signature does not match")
+	ErrTimestampExpired = errors.New("webhook signature timestamp is outside the tolerance")
+)
+
+// Sign returns the signature header value for body sent at timestamp.
+func Sign(secret []byte, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac(secret, t, body))
+}
+
+// Verify checks a signature header value against body. Timestamps further than
+// tolerance from now are rejected.
+func Verify(secret []byte, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var t string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return ErrMalformed
+		}
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			sig, err := hex.DecodeString(value)
+			if err != nil {
+				return ErrMalformed
+			}
+			signatures = append(signatures, sig)
+		}
+	}
+
+	seconds, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrMalformed
+	}
+
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrTimestampExpired
+	}
+
+	expected := mac(secret, t, body)
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			return nil
+		}
+	}
+	return ErrMismatch
+}
+
+func mac(secret []byte, timestamp string, body []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
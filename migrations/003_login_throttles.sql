@@ -0,0 +1,17 @@
+-- Failed login tracking (per account and per client IP)
+CREATE TABLE login_throttles (
+    key VARCHAR(255) PRIMARY KEY,
+    scope VARCHAR(20) NOT NULL,
+    failed_count INT NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE login_throttles
+ADD CONSTRAINT chk_login_throttle_scope
+CHECK (scope IN ('account', 'ip'));
+
+CREATE INDEX idx_login_throttles_locked_until ON login_throttles(locked_until);
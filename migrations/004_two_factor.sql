@@ -0,0 +1,11 @@
+-- TOTP two-factor authentication (secret is AES-GCM encrypted by the application)
+CREATE TABLE employee_two_factors (
+    employee_id UUID PRIMARY KEY REFERENCES employees(id),
+    secret_ciphertext TEXT NOT NULL,
+    recovery_code_hashes TEXT[] NOT NULL DEFAULT '{}',
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    confirmed_at TIMESTAMP,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
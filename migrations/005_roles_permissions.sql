@@ -0,0 +1,54 @@
+-- Roles and their permissions (authorization policy)
+CREATE TABLE roles (
+    name VARCHAR(50) PRIMARY KEY,
+    description TEXT,
+    built_in BOOLEAN NOT NULL DEFAULT FALSE,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE role_permissions (
+    role VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL,
+
+    PRIMARY KEY (role, permission)
+);
+
+-- Built-in roles
+INSERT INTO roles (name, description, built_in) VALUES
+    ('admin', 'Full access', TRUE),
+    ('supervisor', 'Store supervisor', TRUE),
+    ('staff', 'Store staff', TRUE);
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'employee.read.self'),
+    ('admin', 'employee.read'),
+    ('admin', 'employee.salary.read'),
+    ('admin', 'employee.create'),
+    ('admin', 'employee.update.self'),
+    ('admin', 'employee.update'),
+    ('admin', 'employee.delete'),
+    ('admin', 'employee.photo.upload.self'),
+    ('admin', 'employee.photo.upload'),
+    ('admin', 'attendance.record'),
+    ('admin', 'attendance.approve'),
+    ('admin', 'account.unlock'),
+    ('admin', 'account.2fa.reset'),
+    ('admin', 'role.manage'),
+
+    ('supervisor', 'employee.read.self'),
+    ('supervisor', 'employee.read'),
+    ('supervisor', 'employee.salary.read'),
+    ('supervisor', 'employee.create'),
+    ('supervisor', 'employee.update.self'),
+    ('supervisor', 'employee.update'),
+    ('supervisor', 'employee.delete'),
+    ('supervisor', 'employee.photo.upload.self'),
+    ('supervisor', 'employee.photo.upload'),
+    ('supervisor', 'attendance.record'),
+    ('supervisor', 'attendance.approve'),
+
+    ('staff', 'employee.read.self'),
+    ('staff', 'employee.photo.upload.self'),
+    ('staff', 'attendance.record');
@@ -0,0 +1,7 @@
+-- Role hierarchy: higher ranks can create and manage employees with lower ranks
+ALTER TABLE roles ADD COLUMN rank INT NOT NULL DEFAULT 0;
+
+UPDATE roles SET rank = 100 WHERE name = 'admin';
+UPDATE roles SET rank = 50 WHERE name = 'supervisor';
+UPDATE roles SET rank = 10 WHERE name = 'staff';
+
@@ -0,0 +1,12 @@
+-- Store assignment, used to scope what supervisors can see
+ALTER TABLE employees ADD COLUMN store_id VARCHAR(50);
+
+CREATE INDEX idx_employees_store_id ON employees(store_id);
+
+-- Field-level visibility: salary for admins only, personal data within the same store
+DELETE FROM role_permissions WHERE role = 'supervisor' AND permission = 'employee.salary.read';
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'employee.personal.read'),
+    ('admin', 'employee.scope.all_stores'),
+    ('supervisor', 'employee.personal.read');
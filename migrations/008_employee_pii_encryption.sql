@@ -0,0 +1,16 @@
+-- PII columns (birthdate, address, phone_number) are stored encrypted with a
+-- per-record data key; the data key itself is wrapped with a key-encryption key.
+ALTER TABLE employees ALTER COLUMN birthdate TYPE TEXT USING birthdate::TEXT;
+ALTER TABLE employees ALTER COLUMN phone_number TYPE TEXT;
+
+ALTER TABLE employees ADD COLUMN data_key TEXT;
+ALTER TABLE employees ADD COLUMN data_key_id VARCHAR(50);
+
+-- Ciphertexts are randomised, so phone uniqueness moves to a keyed blind index
+ALTER TABLE employees DROP CONSTRAINT IF EXISTS employees_phone_number_key;
+ALTER TABLE employees ADD COLUMN phone_number_bidx VARCHAR(64);
+ALTER TABLE employees ADD CONSTRAINT employees_phone_number_bidx_key UNIQUE (phone_number_bidx);
+
+-- Existing rows stay readable as plaintext until the service encrypts them, and fills
+-- their phone_number_bidx, when it starts
+CREATE INDEX idx_employees_data_key_id ON employees(data_key_id);
@@ -0,0 +1,26 @@
+-- Anonymised employees keep their row (and ID) so history stays consistent
+ALTER TABLE employees ADD COLUMN anonymized_at TIMESTAMP;
+
+-- Audit trail of erasures; it records what was erased, never the erased values
+CREATE TABLE erasure_audits (
+    id UUID PRIMARY KEY,
+    employee_id UUID NOT NULL REFERENCES employees(id),
+    trigger VARCHAR(20) NOT NULL,
+    requested_by UUID,
+    reason TEXT,
+    erased_fields TEXT[] NOT NULL DEFAULT '{}',
+    attendance_records BIGINT NOT NULL DEFAULT 0,
+    photo_deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    erased_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE erasure_audits
+ADD CONSTRAINT chk_erasure_audits_trigger
+CHECK (trigger IN ('retention', 'request'));
+
+CREATE INDEX idx_erasure_audits_employee_id ON erasure_audits(employee_id);
+CREATE INDEX idx_employees_retention ON employees(deleted_at) WHERE anonymized_at IS NULL;
+
+-- Only admins may trigger an erasure on request
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'employee.erase');
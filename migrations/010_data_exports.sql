@@ -0,0 +1,28 @@
+-- Subject access requests: generated archives with all data held about an employee
+CREATE TABLE data_exports (
+    id UUID PRIMARY KEY,
+    employee_id UUID NOT NULL REFERENCES employees(id),
+    requested_by UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    file_url TEXT,
+    size BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP,
+    expires_at TIMESTAMP
+);
+
+ALTER TABLE data_exports
+ADD CONSTRAINT chk_data_exports_status
+CHECK (status IN ('pending', 'processing', 'ready', 'failed', 'expired'));
+
+CREATE INDEX idx_data_exports_employee_id ON data_exports(employee_id, created_at DESC);
+CREATE INDEX idx_data_exports_status ON data_exports(status);
+
+-- Everyone may export their own data; admins may export on behalf of an employee
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'employee.export'),
+    ('admin', 'employee.export.self'),
+    ('supervisor', 'employee.export.self'),
+    ('staff', 'employee.export.self');
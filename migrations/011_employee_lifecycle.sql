@@ -0,0 +1,45 @@
+-- Employee lifecycle: onboarding -> active <-> suspended -> terminated
+ALTER TABLE employees ADD COLUMN terminated_at TIMESTAMP;
+ALTER TABLE employees ADD COLUMN sessions_revoked_at TIMESTAMP;
+
+ALTER TABLE employees DROP CONSTRAINT chk_employee_status;
+
+UPDATE employees
+SET status = 'terminated', terminated_at = COALESCE(deleted_at, updated_at)
+WHERE status = 'inactive' OR deleted_at IS NOT NULL;
+
+ALTER TABLE employees
+ADD CONSTRAINT chk_employee_status
+CHECK (status IN ('onboarding', 'active', 'suspended', 'terminated'));
+
+ALTER TABLE employees ALTER COLUMN status SET DEFAULT 'onboarding';
+
+DROP INDEX IF EXISTS idx_employees_retention;
+CREATE INDEX idx_employees_retention ON employees(terminated_at) WHERE anonymized_at IS NULL;
+
+-- Status changes with their reason; future effective dates are applied by a background job
+CREATE TABLE employee_status_changes (
+    id UUID PRIMARY KEY,
+    employee_id UUID NOT NULL REFERENCES employees(id),
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL,
+    effective_at TIMESTAMP NOT NULL,
+    actor_id UUID NOT NULL,
+    state VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    error TEXT,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    applied_at TIMESTAMP
+);
+
+ALTER TABLE employee_status_changes
+ADD CONSTRAINT chk_employee_status_changes_state
+CHECK (state IN ('scheduled', 'applied', 'failed'));
+
+CREATE INDEX idx_employee_status_changes_employee_id ON employee_status_changes(employee_id, created_at DESC);
+CREATE INDEX idx_employee_status_changes_due ON employee_status_changes(effective_at) WHERE state = 'scheduled';
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'employee.lifecycle'),
+    ('supervisor', 'employee.lifecycle');
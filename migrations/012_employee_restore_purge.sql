@@ -0,0 +1,32 @@
+-- Email and phone number only need to be unique among employees that are not deleted,
+-- a restore re-checks them against the current workforce
+ALTER TABLE employees DROP CONSTRAINT IF EXISTS employees_email_key;
+ALTER TABLE employees DROP CONSTRAINT IF EXISTS employees_phone_number_bidx_key;
+CREATE UNIQUE INDEX uq_employees_email_active ON employees(email) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX uq_employees_phone_number_bidx_active ON employees(phone_number_bidx) WHERE deleted_at IS NULL;
+
+-- Soft deleted employees keep their status, retention also applies to them
+CREATE INDEX idx_employees_retention_deleted ON employees(deleted_at) WHERE anonymized_at IS NULL;
+
+-- Hard delete cascade: rows that only make sense with the employee go with it
+ALTER TABLE employee_two_factors DROP CONSTRAINT employee_two_factors_employee_id_fkey;
+ALTER TABLE employee_two_factors
+ADD CONSTRAINT employee_two_factors_employee_id_fkey
+FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE;
+
+ALTER TABLE data_exports DROP CONSTRAINT data_exports_employee_id_fkey;
+ALTER TABLE data_exports
+ADD CONSTRAINT data_exports_employee_id_fkey
+FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE;
+
+ALTER TABLE employee_status_changes DROP CONSTRAINT employee_status_changes_employee_id_fkey;
+ALTER TABLE employee_status_changes
+ADD CONSTRAINT employee_status_changes_employee_id_fkey
+FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE;
+
+-- The erasure audit trail outlives the employee row
+ALTER TABLE erasure_audits DROP CONSTRAINT erasure_audits_employee_id_fkey;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'employee.restore'),
+    ('admin', 'employee.purge');
@@ -0,0 +1,3 @@
+-- Optimistic concurrency: every write increments the version, updates only apply to the
+-- version the client read (exposed as ETag / If-Match)
+ALTER TABLE employees ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,15 @@
+-- Email changes are applied once the new address is verified with the emailed token
+CREATE TABLE email_changes (
+    id UUID PRIMARY KEY,
+    employee_id UUID NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    new_email VARCHAR(150) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    requested_by UUID NOT NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    confirmed_at TIMESTAMP,
+    cancelled_at TIMESTAMP
+);
+
+CREATE INDEX idx_email_changes_employee_id ON email_changes(employee_id) WHERE confirmed_at IS NULL AND cancelled_at IS NULL;
@@ -0,0 +1,49 @@
+-- Event notifications: one row per recipient, shown in the in-app inbox when in_inbox is set
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY,
+    recipient_id UUID NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    event VARCHAR(50) NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    in_inbox BOOLEAN NOT NULL DEFAULT TRUE,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    read_at TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_inbox ON notifications(recipient_id, created_at DESC) WHERE in_inbox;
+
+-- Email and webhook deliveries of a notification; failed attempts are retried with backoff
+CREATE TABLE notification_deliveries (
+    id UUID PRIMARY KEY,
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL,
+    target TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP
+);
+
+ALTER TABLE notification_deliveries
+ADD CONSTRAINT chk_notification_deliveries_channel
+CHECK (channel IN ('email', 'webhook'));
+
+ALTER TABLE notification_deliveries
+ADD CONSTRAINT chk_notification_deliveries_status
+CHECK (status IN ('pending', 'sent', 'failed'));
+
+CREATE INDEX idx_notification_deliveries_notification_id ON notification_deliveries(notification_id);
+CREATE INDEX idx_notification_deliveries_due ON notification_deliveries(next_attempt_at) WHERE status = 'pending';
+
+-- Channels per event, e.g. {"attendance.late_check_in": ["in_app", "email"]}; events
+-- without an entry use in_app and email
+CREATE TABLE notification_preferences (
+    employee_id UUID PRIMARY KEY REFERENCES employees(id) ON DELETE CASCADE,
+    webhook_url TEXT,
+    channels JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,13 @@
+-- Inbound events from other shop-retail services that have been handled; IDs are chosen
+-- by the publishers, so they are not UUIDs
+CREATE TABLE processed_events (
+    id VARCHAR(100) PRIMARY KEY,
+    type VARCHAR(100) NOT NULL,
+    source VARCHAR(100) NOT NULL DEFAULT '',
+    processed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_processed_events_processed_at ON processed_events(processed_at);
+
+-- Status changes made by the system (e.g. a store closure) have no actor
+ALTER TABLE employee_status_changes ALTER COLUMN actor_id DROP NOT NULL;
@@ -0,0 +1,9 @@
+-- Files are stored by object key and served through presigned URLs; strip the public
+-- "<scheme>://<endpoint>/<bucket>/" prefix earlier versions stored
+UPDATE employees
+SET photo = regexp_replace(photo, '^https?://[^/]+/[^/]+/', '')
+WHERE photo LIKE 'http%';
+
+UPDATE data_exports
+SET file_url = regexp_replace(file_url, '^https?://[^/]+/[^/]+/', '')
+WHERE file_url LIKE 'http%';
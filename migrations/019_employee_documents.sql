@@ -0,0 +1,35 @@
+-- Documents HR keeps per employee. Every upload is a new version of the employee's
+-- document of that type; deleted versions keep their row so version numbers are not reused
+CREATE TABLE employee_documents (
+    id UUID PRIMARY KEY,
+    employee_id UUID NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL,
+    version INT NOT NULL,
+    file_name TEXT NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key TEXT NOT NULL,
+    expires_at DATE,
+    uploaded_by UUID NOT NULL,
+    uploaded_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP,
+
+    UNIQUE (employee_id, type, version)
+);
+
+ALTER TABLE employee_documents
+ADD CONSTRAINT chk_employee_documents_type
+CHECK (type IN ('contract', 'id_card', 'tax_card', 'health_certificate'));
+
+CREATE INDEX idx_employee_documents_expires_at ON employee_documents(expires_at) WHERE deleted_at IS NULL;
+
+-- Employees see and upload their own documents; admins (and any HR role granted
+-- employee.document.manage) manage everyone's
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'employee.document.manage'),
+    ('admin', 'employee.document.read.self'),
+    ('admin', 'employee.document.upload.self'),
+    ('supervisor', 'employee.document.read.self'),
+    ('supervisor', 'employee.document.upload.self'),
+    ('staff', 'employee.document.read.self'),
+    ('staff', 'employee.document.upload.self');
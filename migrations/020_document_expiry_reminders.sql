@@ -0,0 +1,11 @@
+-- Smallest expiry reminder window, in days, already sent for a document version, so the
+-- daily reminder job sends every window once
+ALTER TABLE employee_documents
+ADD COLUMN reminder_window INT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_employee_documents_current ON employee_documents(employee_id, type, version DESC) WHERE deleted_at IS NULL;
+
+-- Supervisors see the report for their own store, admins for every store
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'compliance.read'),
+    ('supervisor', 'compliance.read');
@@ -0,0 +1,31 @@
+-- Store devices employees check in at. A kiosk authenticates with a device token, only its
+-- SHA-256 hash is kept; revoked kiosks keep their row so their check-ins stay attributable
+CREATE TABLE kiosks (
+    id UUID PRIMARY KEY,
+    store_id VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX idx_kiosks_store_id ON kiosks(store_id);
+
+-- PINs employees without a phone enter on a kiosk, bcrypt hashed
+CREATE TABLE employee_kiosk_pins (
+    employee_id UUID PRIMARY KEY REFERENCES employees(id) ON DELETE CASCADE,
+    pin_hash TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Wrong PINs are throttled per employee and per kiosk like logins
+ALTER TABLE login_throttles DROP CONSTRAINT chk_login_throttle_scope;
+ALTER TABLE login_throttles
+ADD CONSTRAINT chk_login_throttle_scope
+CHECK (scope IN ('account', 'ip', 'kiosk_pin', 'kiosk'));
+
+-- Supervisors register the kiosks of their own store, admins those of every store
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'kiosk.manage'),
+    ('supervisor', 'kiosk.manage');
@@ -0,0 +1,16 @@
+-- Attendance events kiosks collected while offline and synced later. Event IDs are
+-- generated by the kiosk, a resent event is answered from here instead of being applied
+-- again. Events that failed temporarily are not kept so the kiosk can retry them.
+CREATE TABLE kiosk_sync_events (
+    kiosk_id UUID NOT NULL REFERENCES kiosks(id),
+    event_id VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('check_in', 'check_out')),
+    occurred_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('applied', 'rejected')),
+    attendance_id VARCHAR(50),
+    error TEXT,
+    processed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (kiosk_id, event_id)
+);
+
+CREATE INDEX idx_kiosk_sync_events_occurred_at ON kiosk_sync_events(occurred_at);
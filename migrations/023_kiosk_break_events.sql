@@ -0,0 +1,6 @@
+-- Kiosks also punch the start and end of breaks, offline included
+ALTER TABLE kiosk_sync_events DROP CONSTRAINT kiosk_sync_events_type_check;
+
+ALTER TABLE kiosk_sync_events
+ADD CONSTRAINT chk_kiosk_sync_events_type
+CHECK (type IN ('check_in', 'check_out', 'break_start', 'break_end'));
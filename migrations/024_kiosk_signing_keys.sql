@@ -0,0 +1,4 @@
+-- Offline events are signed with a key pair the kiosk generates at registration instead
+-- of its device token. Kiosks registered before have no key and must be registered again
+-- before they can sync.
+ALTER TABLE kiosks ADD COLUMN signing_key BYTEA;